@@ -32,6 +32,8 @@ type Account struct {
 	PublicKey  keypair.PublicKey
 	Address    common.Address
 	SigScheme  s.SignatureScheme
+
+	remote *RemoteSigner //the signing daemon keeps the private key if not nil
 }
 
 func NewAccount(encrypt string) *Account {
@@ -95,15 +97,16 @@ func (this *Account) Scheme() s.SignatureScheme {
 
 //AccountMetadata all account info without private key
 type AccountMetadata struct {
-	IsDefault bool   //Is default account
-	Label     string //Lable of account
-	KeyType   string //KeyType ECDSA,SM2 or EDDSA
-	Curve     string //Curve of key type
-	Address   string //Address(base58) of account
-	PubKey    string //Public  key
-	SigSch    string //Signature scheme
-	Salt      []byte //Salt
-	Key       []byte //PrivateKey in encrypted
-	EncAlg    string //Encrypt alg of private key
-	Hash      string //Hash alg
+	IsDefault  bool   //Is default account
+	Label      string //Lable of account
+	KeyType    string //KeyType ECDSA,SM2 or EDDSA
+	Curve      string //Curve of key type
+	Address    string //Address(base58) of account
+	PubKey     string //Public  key
+	SigSch     string //Signature scheme
+	Salt       []byte //Salt
+	Key        []byte //PrivateKey in encrypted
+	EncAlg     string //Encrypt alg of private key
+	Hash       string //Hash alg
+	DerivePath string //BIP-44 path of HD account
 }
@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -74,6 +75,16 @@ type Client interface {
 	ChangeSigScheme(address string, sigScheme s.SignatureScheme) error
 	//Get the underlying wallet data
 	GetWalletData() *WalletData
+	//HasMnemonic return whether the wallet has a HD seed
+	HasMnemonic() bool
+	//SetMnemonic save the mnemonic to wallet in encrypted. The HD seed of wallet can only be set once
+	SetMnemonic(mnemonic string, passwd []byte) error
+	//GetMnemonic return the mnemonic of wallet
+	GetMnemonic(passwd []byte) (string, error)
+	//NewHDAccount derive the next account of coin type from the HD seed of wallet
+	NewHDAccount(label string, coinType uint32, passwd []byte) (*Account, error)
+	//NewAccountFromPath derive account by BIP-44 path from the HD seed of wallet
+	NewAccountFromPath(label string, path string, passwd []byte) (*Account, error)
 }
 
 func Open(path string) (Client, error) {
@@ -214,6 +225,7 @@ func (this *ClientImpl) ImportAccount(accMeta *AccountMetadata) error {
 	accData.Hash = accMeta.Hash
 	accData.Salt = accMeta.Salt
 	accData.Param = map[string]string{"curve": accMeta.Curve}
+	accData.DerivePath = accMeta.DerivePath
 
 	oldAccMeta := this.GetAccountMetadataByLabel(accData.Label)
 	if oldAccMeta != nil {
@@ -314,12 +326,15 @@ func (this *ClientImpl) getAccount(accData *AccountData, passwd []byte) (*Accoun
 	if err != nil {
 		return nil, err
 	}
-	publicKey := privateKey.Public()
-	addr := types.AddressFromPubKey(publicKey)
 	scheme, err := s.GetScheme(accData.SigSch)
 	if err != nil {
 		return nil, fmt.Errorf("signature scheme error: %s", err)
 	}
+	privateKey, publicKey, err := fromStoredKey(privateKey, scheme)
+	if err != nil {
+		return nil, err
+	}
+	addr := types.AddressFromPubKey(publicKey)
 	return &Account{
 		PrivateKey: privateKey,
 		PublicKey:  publicKey,
@@ -341,6 +356,7 @@ func (this *ClientImpl) getAccountMetadata(accData *AccountData) *AccountMetadat
 	accMeta.Hash = accData.Hash
 	accMeta.Curve = accData.Param["curve"]
 	accMeta.Salt = accData.Salt
+	accMeta.DerivePath = accData.DerivePath
 	return accMeta
 }
 
@@ -515,6 +531,9 @@ func (this *ClientImpl) ChangeSigScheme(address string, sigScheme s.SignatureSch
 	if !this.checkSigScheme(accData.Alg, sigScheme.Name()) {
 		return fmt.Errorf("sigScheme: %s does not match KeyType: %s", sigScheme.Name(), accData.Alg)
 	}
+	if (accData.SigSch == s.KECCAK256WithECDSA.Name()) != (sigScheme == s.KECCAK256WithECDSA) {
+		return fmt.Errorf("cannot change sigScheme between ethereum and ontology account")
+	}
 
 	oldSigScheme := accData.SigSch
 	accData.SigSch = sigScheme.Name()
@@ -543,6 +562,7 @@ func (this *ClientImpl) checkSigScheme(keyType, sigScheme string) bool {
 		case "SHA3-384WITHECDSA":
 		case "SHA3-512WITHECDSA":
 		case "RIPEMD160WITHECDSA":
+		case "KECCAK256WITHECDSA":
 		default:
 			return false
 		}
@@ -567,3 +587,95 @@ func (this *ClientImpl) checkSigScheme(keyType, sigScheme string) bool {
 func (this *ClientImpl) GetWalletData() *WalletData {
 	return this.walletData
 }
+
+func (this *ClientImpl) HasMnemonic() bool {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.walletData.Seed != nil
+}
+
+func (this *ClientImpl) SetMnemonic(mnemonic string, passwd []byte) error {
+	mnemonic, err := NormalizeMnemonic(mnemonic)
+	if err != nil {
+		return err
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if this.walletData.Seed != nil {
+		return fmt.Errorf("wallet already has a seed")
+	}
+	seed, err := encryptMnemonic(mnemonic, passwd, this.walletData.Scrypt)
+	if err != nil {
+		return fmt.Errorf("encrypt mnemonic error: %s", err)
+	}
+	this.walletData.Seed = seed
+	err = this.save()
+	if err != nil {
+		this.walletData.Seed = nil
+		return fmt.Errorf("save error: %s", err)
+	}
+	return nil
+}
+
+func (this *ClientImpl) GetMnemonic(passwd []byte) (string, error) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.getMnemonic(passwd)
+}
+
+func (this *ClientImpl) getMnemonic(passwd []byte) (string, error) {
+	if this.walletData.Seed == nil {
+		return "", fmt.Errorf("wallet has no seed")
+	}
+	return decryptMnemonic(this.walletData.Seed, passwd)
+}
+
+func (this *ClientImpl) NewHDAccount(label string, coinType uint32, passwd []byte) (*Account, error) {
+	this.lock.RLock()
+	index := uint32(0)
+	prefix := fmt.Sprintf("m/44'/%d'/0'/0/", coinType)
+	for _, accData := range this.walletData.Accounts {
+		if !strings.HasPrefix(accData.DerivePath, prefix) {
+			continue
+		}
+		i, err := strconv.ParseUint(strings.TrimPrefix(accData.DerivePath, prefix), 10, 32)
+		if err == nil && uint32(i) >= index {
+			index = uint32(i) + 1
+		}
+	}
+	this.lock.RUnlock()
+	return this.NewAccountFromPath(label, Bip44Path(coinType, index), passwd)
+}
+
+func (this *ClientImpl) NewAccountFromPath(label string, path string, passwd []byte) (*Account, error) {
+	this.lock.RLock()
+	mnemonic, err := this.getMnemonic(passwd)
+	this.lock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	acc, err := DeriveAccount(mnemonic, path)
+	if err != nil {
+		return nil, err
+	}
+	addressBase58 := acc.Address.ToBase58()
+	if this.GetAccountMetadataByAddress(addressBase58) != nil {
+		return nil, fmt.Errorf("account %s of path %s already exists", addressBase58, path)
+	}
+	prvSecret, err := keypair.EncryptWithCustomScrypt(toStoredKey(acc.PrivateKey), addressBase58, passwd, this.walletData.Scrypt)
+	if err != nil {
+		return nil, fmt.Errorf("encryptPrivateKey error: %s", err)
+	}
+	accData := &AccountData{}
+	accData.Label = label
+	accData.SetKeyPair(prvSecret)
+	accData.SigSch = acc.SigScheme.Name()
+	accData.PubKey = hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey))
+	accData.DerivePath = path
+
+	err = this.addAccountData(accData)
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
@@ -33,11 +33,12 @@ import (
 type AccountData struct {
 	keypair.ProtectedKey
 
-	Label     string `json:"label"`
-	PubKey    string `json:"publicKey"`
-	SigSch    string `json:"signatureScheme"`
-	IsDefault bool   `json:"isDefault"`
-	Lock      bool   `json:"lock"`
+	Label      string `json:"label"`
+	PubKey     string `json:"publicKey"`
+	SigSch     string `json:"signatureScheme"`
+	IsDefault  bool   `json:"isDefault"`
+	Lock       bool   `json:"lock"`
+	DerivePath string `json:"derivePath,omitempty"` //BIP-44 path of the account derived from wallet seed
 }
 
 func (this *AccountData) SetKeyPair(keyinfo *keypair.ProtectedKey) {
@@ -72,6 +73,7 @@ type WalletData struct {
 	Scrypt     *keypair.ScryptParam `json:"scrypt"`
 	Identities []Identity           `json:"identities,omitempty"`
 	Accounts   []*AccountData       `json:"accounts,omitempty"`
+	Seed       *SeedData            `json:"seed,omitempty"`
 	Extra      string               `json:"extra,omitempty"`
 }
 
@@ -99,6 +101,10 @@ func (this *WalletData) Clone() *WalletData {
 		w.Accounts[i] = &ac
 	}
 	w.Identities = this.Identities
+	if this.Seed != nil {
+		seed := *this.Seed
+		w.Seed = &seed
+	}
 	w.Extra = this.Extra
 	return &w
 }
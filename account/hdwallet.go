@@ -0,0 +1,282 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ontio/ontology-crypto/ec"
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/core/types"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	ONT_COIN_TYPE = 1024 //BIP-44 coin type of ONT
+	ETH_COIN_TYPE = 60   //BIP-44 coin type of ETH
+
+	MNEMONIC_ENTROPY_BITS = 128 //12 words mnemonic, the same as the ontology SDKs
+
+	hardenedKeyStart = 0x80000000
+)
+
+//SeedData is the encrypted mnemonic of a HD wallet
+type SeedData struct {
+	EncAlg string               `json:"enc-alg"`
+	Key    []byte               `json:"key"`
+	Salt   []byte               `json:"salt"`
+	Scrypt *keypair.ScryptParam `json:"scrypt"`
+}
+
+//Bip44Path return the BIP-44 derivation path of the account index: m/44'/coinType'/0'/0/index
+func Bip44Path(coinType uint32, index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/0'/0/%d", coinType, index)
+}
+
+//GenerateMnemonic return a new random BIP-39 mnemonic
+func GenerateMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(MNEMONIC_ENTROPY_BITS)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+//NormalizeMnemonic trim the extra whitespaces of mnemonic and check its checksum
+func NormalizeMnemonic(mnemonic string) (string, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return "", fmt.Errorf("invalid mnemonic")
+	}
+	return mnemonic, nil
+}
+
+//DeriveAccount derive the account of the path from mnemonic. Accounts of ETH coin type are secp256k1 keys with
+//ethereum address, others are ECDSA P-256 keys derived the same way as the ontology SDKs.
+func DeriveAccount(mnemonic string, path string) (*Account, error) {
+	indexes, err := parseDerivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %s", err)
+	}
+	key, err := deriveKey(seed, indexes)
+	if err != nil {
+		return nil, err
+	}
+	defer clearBytes(key)
+
+	var prvkey keypair.PrivateKey
+	var pubkey keypair.PublicKey
+	var scheme s.SignatureScheme
+	if len(indexes) > 1 && indexes[1] == hardenedKeyStart+ETH_COIN_TYPE {
+		ethKey, err := ethcrypto.ToECDSA(key)
+		if err != nil {
+			return nil, err
+		}
+		prvkey, pubkey = keypair.FromEthereumPrivateKey(ethKey)
+		scheme = s.KECCAK256WithECDSA
+	} else {
+		if new(big.Int).SetBytes(key).Cmp(elliptic.P256().Params().N) >= 0 {
+			return nil, fmt.Errorf("derived key of %s is out of range", path)
+		}
+		prvkey = &ec.PrivateKey{Algorithm: ec.ECDSA, PrivateKey: ec.ConstructPrivateKey(key, elliptic.P256())}
+		pubkey = prvkey.Public()
+		scheme = s.SHA256withECDSA
+	}
+	return &Account{
+		PrivateKey: prvkey,
+		PublicKey:  pubkey,
+		Address:    types.AddressFromPubKey(pubkey),
+		SigScheme:  scheme,
+	}, nil
+}
+
+//parseDerivePath parse path like m/44'/1024'/0'/0/0 to child indexes
+func parseDerivePath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) < 2 || parts[0] != "m" {
+		return nil, fmt.Errorf("invalid derive path: %s", path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'")
+		part = strings.TrimSuffix(part, "'")
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || index >= hardenedKeyStart {
+			return nil, fmt.Errorf("invalid derive path: %s", path)
+		}
+		if hardened {
+			index += hardenedKeyStart
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, nil
+}
+
+//deriveKey derive the BIP-32 secp256k1 private key from seed
+func deriveKey(seed []byte, indexes []uint32) ([]byte, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+	curve := btcec.S256()
+	if !isValidKey(key) {
+		return nil, fmt.Errorf("invalid master key")
+	}
+
+	for _, index := range indexes {
+		data := make([]byte, 0, 37)
+		if index >= hardenedKeyStart {
+			data = append(data, 0)
+			data = append(data, key...)
+		} else {
+			_, pub := btcec.PrivKeyFromBytes(curve, key)
+			data = append(data, pub.SerializeCompressed()...)
+		}
+		data = append(data, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(data[len(data)-4:], index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+		clearBytes(data)
+		il := new(big.Int).SetBytes(sum[:32])
+		if il.Cmp(curve.N) >= 0 {
+			return nil, fmt.Errorf("invalid child key at index %d", index)
+		}
+		child := il.Add(il, new(big.Int).SetBytes(key))
+		child.Mod(child, curve.N)
+		if child.Sign() == 0 {
+			return nil, fmt.Errorf("invalid child key at index %d", index)
+		}
+		clearBytes(key)
+		key = make([]byte, 32)
+		child.FillBytes(key)
+		chainCode = sum[32:]
+	}
+	return key, nil
+}
+
+func isValidKey(key []byte) bool {
+	k := new(big.Int).SetBytes(key)
+	return k.Sign() > 0 && k.Cmp(btcec.S256().N) < 0
+}
+
+//toStoredKey convert the ethereum private key to secp256k1 ECDSA key, which can be encrypted in wallet
+func toStoredKey(prvkey keypair.PrivateKey) keypair.PrivateKey {
+	if ethKey, ok := prvkey.(*ec.EthereumPrivateKey); ok {
+		return &ec.PrivateKey{
+			Algorithm:  ec.ECDSA,
+			PrivateKey: ec.ConstructPrivateKey(ethcrypto.FromECDSA(ethKey.PrivateKey), btcec.S256()),
+		}
+	}
+	return prvkey
+}
+
+//fromStoredKey convert the secp256k1 key decrypted from wallet back to ethereum private key
+func fromStoredKey(prvkey keypair.PrivateKey, scheme s.SignatureScheme) (keypair.PrivateKey, keypair.PublicKey, error) {
+	if scheme != s.KECCAK256WithECDSA {
+		return prvkey, prvkey.Public(), nil
+	}
+	ecKey, ok := prvkey.(*ec.PrivateKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid ethereum private key")
+	}
+	ethKey, err := ethcrypto.ToECDSA(ecKey.D.FillBytes(make([]byte, 32)))
+	if err != nil {
+		return nil, nil, err
+	}
+	prv, pub := keypair.FromEthereumPrivateKey(ethKey)
+	return prv, pub, nil
+}
+
+func encryptMnemonic(mnemonic string, passwd []byte, param *keypair.ScryptParam) (*SeedData, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, nonce, err := seedCipher(passwd, salt, param)
+	if err != nil {
+		return nil, err
+	}
+	sp := *param
+	return &SeedData{
+		EncAlg: "aes-256-gcm",
+		Key:    gcm.Seal(nil, nonce, []byte(mnemonic), nil),
+		Salt:   salt,
+		Scrypt: &sp,
+	}, nil
+}
+
+func decryptMnemonic(seed *SeedData, passwd []byte) (string, error) {
+	if seed.EncAlg != "aes-256-gcm" || seed.Scrypt == nil {
+		return "", fmt.Errorf("unsupported seed encryption")
+	}
+	gcm, nonce, err := seedCipher(passwd, seed.Salt, seed.Scrypt)
+	if err != nil {
+		return "", err
+	}
+	plain, err := gcm.Open(nil, nonce, seed.Key, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt seed error: %s", err)
+	}
+	return string(plain), nil
+}
+
+func seedCipher(passwd []byte, salt []byte, param *keypair.ScryptParam) (cipher.AEAD, []byte, error) {
+	if len(passwd) == 0 {
+		return nil, nil, fmt.Errorf("password cannot empty")
+	}
+	dkey, err := scrypt.Key(passwd, salt, param.N, param.R, param.P, param.DKLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(dkey[len(dkey)-32:])
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gcm, dkey[:12], nil
+}
+
+func clearBytes(buf []byte) {
+	for i := range buf {
+		buf[i] = 0
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ontio/ontology-crypto/ec"
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestDeriveKey(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	cases := map[string]string{
+		"m/0'":                      "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		"m/0'/1/2'/2/1000000000":    "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+		"m/0'/1/2'/2/1000000000/ ":  "",
+		"m/0'/1/2'/2/2147483648'":   "",
+		"n/0'/1/2'/2/1000000000'":   "",
+		"m/0'/1/2'/2/1000000000''":  "",
+		"m/0'/1/2'/2/-1000000000'":  "",
+		"m/0'/1/2'/2/1000000000'/x": "",
+	}
+	for path, expect := range cases {
+		indexes, err := parseDerivePath(path)
+		if expect == "" {
+			assert.NotNil(t, err, path)
+			continue
+		}
+		assert.Nil(t, err)
+		key, err := deriveKey(seed, indexes)
+		assert.Nil(t, err)
+		assert.Equal(t, expect, hex.EncodeToString(key))
+	}
+}
+
+func TestDeriveAccount(t *testing.T) {
+	acc, err := DeriveAccount(testMnemonic, Bip44Path(ETH_COIN_TYPE, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, s.KECCAK256WithECDSA, acc.SigScheme)
+	assert.Equal(t, common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94").Bytes(), acc.Address[:])
+
+	//m/44'/1024'/0'/0/0, the default path of ontology sdk, which takes the BIP-32 secp256k1 key as P-256 key
+	acc, err = DeriveAccount(testMnemonic, Bip44Path(ONT_COIN_TYPE, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, s.SHA256withECDSA, acc.SigScheme)
+	assert.Equal(t, keypair.PK_ECDSA, keypair.GetKeyType(acc.PublicKey))
+	assert.Equal(t, "7eca735081c1de776e35fc2b20a74940e6eb0076d9fcfc84981276089c6046dd",
+		hex.EncodeToString(acc.PrivateKey.(*ec.PrivateKey).D.Bytes()))
+	assert.Equal(t, "03f81267951e4529d9aafd95c14d9a10debb0c30c386cbea03cdd374116f96b611",
+		hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey)))
+	assert.Equal(t, "ATrZAzHPV5mwLPviRe99XyNcGK5awupry7", acc.Address.ToBase58())
+	acc2, err := DeriveAccount(testMnemonic, Bip44Path(ONT_COIN_TYPE, 1))
+	assert.Nil(t, err)
+	assert.NotEqual(t, acc.Address, acc2.Address)
+
+	_, err = DeriveAccount(strings.Replace(testMnemonic, "about", "abandon", 1), Bip44Path(ONT_COIN_TYPE, 0))
+	assert.NotNil(t, err)
+}
+
+func TestHDWallet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hdwallet")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "wallet.dat")
+	wallet, err := Open(path)
+	assert.Nil(t, err)
+	assert.False(t, wallet.HasMnemonic())
+	_, err = wallet.NewHDAccount("", ONT_COIN_TYPE, testPasswd)
+	assert.NotNil(t, err)
+
+	mnemonic, err := GenerateMnemonic()
+	assert.Nil(t, err)
+	assert.Equal(t, 12, len(strings.Fields(mnemonic)))
+	assert.NotNil(t, wallet.SetMnemonic("invalid mnemonic", testPasswd))
+	assert.Nil(t, wallet.SetMnemonic(" "+strings.Replace(mnemonic, " ", "  ", -1)+"\n", testPasswd))
+	assert.NotNil(t, wallet.SetMnemonic(mnemonic, testPasswd))
+	phrase, err := wallet.GetMnemonic(testPasswd)
+	assert.Nil(t, err)
+	assert.Equal(t, mnemonic, phrase)
+	_, err = wallet.GetMnemonic([]byte("wrong"))
+	assert.NotNil(t, err)
+
+	ont0, err := wallet.NewHDAccount("ont0", ONT_COIN_TYPE, testPasswd)
+	assert.Nil(t, err)
+	ont1, err := wallet.NewHDAccount("ont1", ONT_COIN_TYPE, testPasswd)
+	assert.Nil(t, err)
+	eth0, err := wallet.NewHDAccount("eth0", ETH_COIN_TYPE, testPasswd)
+	assert.Nil(t, err)
+	_, err = wallet.NewAccountFromPath("", Bip44Path(ONT_COIN_TYPE, 1), testPasswd)
+	assert.NotNil(t, err)
+	assert.Equal(t, Bip44Path(ONT_COIN_TYPE, 1), wallet.GetAccountMetadataByLabel("ont1").DerivePath)
+	assert.NotNil(t, wallet.ChangeSigScheme(eth0.Address.ToBase58(), s.SHA256withECDSA))
+
+	//reopen the wallet file
+	wallet, err = Open(path)
+	assert.Nil(t, err)
+	assert.True(t, wallet.HasMnemonic())
+	acc, err := wallet.GetAccountByAddress(eth0.Address.ToBase58(), testPasswd)
+	assert.Nil(t, err)
+	assert.Equal(t, eth0.Address, acc.Address)
+	assert.Equal(t, keypair.PK_ETHECDSA, keypair.GetKeyType(acc.PublicKey))
+	assert.Equal(t, s.KECCAK256WithECDSA, acc.SigScheme)
+	acc, err = wallet.GetAccountByLabel("ont1", testPasswd)
+	assert.Nil(t, err)
+	assert.Equal(t, ont1.Address, acc.Address)
+
+	//restore from mnemonic
+	restored, err := Open(filepath.Join(dir, "restored.dat"))
+	assert.Nil(t, err)
+	assert.Nil(t, restored.SetMnemonic(mnemonic, testPasswd))
+	for _, expect := range []*Account{ont0, ont1} {
+		acc, err := restored.NewHDAccount("", ONT_COIN_TYPE, testPasswd)
+		assert.Nil(t, err)
+		assert.Equal(t, expect.Address, acc.Address)
+	}
+	acc, err = restored.NewHDAccount("", ETH_COIN_TYPE, testPasswd)
+	assert.Nil(t, err)
+	assert.Equal(t, eth0.Address, acc.Address)
+}
@@ -0,0 +1,447 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology-crypto/vrf"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+)
+
+const (
+	REMOTE_SIGNER_PATH      = "/signer" //http path of the remote signer
+	REMOTE_SIGNER_TIMEOUT   = 10        //request timeout of the remote signer in second
+	REMOTE_SIGNER_TOKEN_LEN = 32        //byte length of the generated auth token
+
+	REMOTE_METHOD_ACCOUNT = "account"
+	REMOTE_METHOD_SIGN    = "sign"
+	REMOTE_METHOD_VRF     = "vrf"
+)
+
+//RemoteSignRequest is the request of remote signer protocol, data is in hex
+type RemoteSignRequest struct {
+	Method  string                `json:"method"`
+	Address string                `json:"address"`
+	Type    signature.PayloadType `json:"type,omitempty"`
+	Data    string                `json:"data,omitempty"`
+}
+
+//RemoteSignResponse is the response of remote signer protocol, all the binary fields are in hex
+type RemoteSignResponse struct {
+	Address   string `json:"address,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+	Scheme    string `json:"scheme,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Value     string `json:"value,omitempty"`
+	Proof     string `json:"proof,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+//RemoteSigner is the client of the signing daemon which keeps the private keys out of node process
+type RemoteSigner struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+//NewRemoteSigner create the client of signing daemon. endpoint is unix socket path like unix:///path/to/signer.sock
+//or http url like http://127.0.0.1:20339, the auth token is required by http endpoint
+func NewRemoteSigner(endpoint string, token string) (*RemoteSigner, error) {
+	client := &http.Client{Timeout: REMOTE_SIGNER_TIMEOUT * time.Second}
+	switch {
+	case strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://"):
+		if token == "" {
+			return nil, fmt.Errorf("auth token is required by remote signer endpoint: %s", endpoint)
+		}
+		return &RemoteSigner{url: strings.TrimSuffix(endpoint, "/") + REMOTE_SIGNER_PATH, token: token,
+			client: client}, nil
+	case strings.HasPrefix(endpoint, "unix://") || strings.HasPrefix(endpoint, "/"):
+		path := strings.TrimPrefix(endpoint, "unix://")
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		}
+		return &RemoteSigner{url: "http://unix" + REMOTE_SIGNER_PATH, token: token, client: client}, nil
+	default:
+		return nil, fmt.Errorf("invalid remote signer endpoint: %s", endpoint)
+	}
+}
+
+//GetAccount return the account whose signing requests are sent to the remote signer. The first account of
+//signer is returned if address is empty
+func (this *RemoteSigner) GetAccount(address string) (*Account, error) {
+	rsp, err := this.call(&RemoteSignRequest{Method: REMOTE_METHOD_ACCOUNT, Address: address})
+	if err != nil {
+		return nil, err
+	}
+	buf, err := hex.DecodeString(rsp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key of remote signer: %s", err)
+	}
+	pubKey, err := keypair.DeserializePublicKey(buf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key of remote signer: %s", err)
+	}
+	scheme, err := s.GetScheme(rsp.Scheme)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature scheme of remote signer: %s", err)
+	}
+	addr := types.AddressFromPubKey(pubKey)
+	if address != "" && addr.ToBase58() != address {
+		return nil, fmt.Errorf("remote signer returns account %s, expect %s", addr.ToBase58(), address)
+	}
+	return &Account{
+		PublicKey: pubKey,
+		Address:   addr,
+		SigScheme: scheme,
+		remote:    this,
+	}, nil
+}
+
+func (this *RemoteSigner) sign(address string, payloadType signature.PayloadType, data []byte) ([]byte, error) {
+	rsp, err := this.call(&RemoteSignRequest{
+		Method:  REMOTE_METHOD_SIGN,
+		Address: address,
+		Type:    payloadType,
+		Data:    hex.EncodeToString(data),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(rsp.Signature)
+}
+
+func (this *RemoteSigner) vrf(address string, data []byte) ([]byte, []byte, error) {
+	rsp, err := this.call(&RemoteSignRequest{
+		Method:  REMOTE_METHOD_VRF,
+		Address: address,
+		Type:    signature.PAYLOAD_VRF,
+		Data:    hex.EncodeToString(data),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := hex.DecodeString(rsp.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err := hex.DecodeString(rsp.Proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, proof, nil
+}
+
+func (this *RemoteSigner) call(req *RemoteSignRequest) (*RemoteSignResponse, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, this.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if this.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+this.token)
+	}
+	resp, err := this.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer request error: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read remote signer response error: %s", err)
+	}
+	rsp := &RemoteSignResponse{}
+	err = json.Unmarshal(body, rsp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signer response: %s", err)
+	}
+	if rsp.Error != "" {
+		return nil, fmt.Errorf("remote signer %s error: %s", req.Method, rsp.Error)
+	}
+	return rsp, nil
+}
+
+//RemoteSignerHandler serves the remote signer protocol with the unlocked accounts of signing daemon,
+//only the payload types in allow-list are signed. The requests must carry the auth token if it is not empty
+type RemoteSignerHandler struct {
+	accounts []*Account
+	allow    map[signature.PayloadType]bool
+	token    string
+}
+
+func NewRemoteSignerHandler(accounts []*Account, allow []signature.PayloadType, token string) *RemoteSignerHandler {
+	handler := &RemoteSignerHandler{
+		accounts: accounts,
+		allow:    make(map[signature.PayloadType]bool),
+		token:    token,
+	}
+	for _, t := range allow {
+		handler.allow[t] = true
+	}
+	return handler
+}
+
+//ParsePayloadTypes parse the comma separated payload types of allow-list
+func ParsePayloadTypes(types string) ([]signature.PayloadType, error) {
+	var result []signature.PayloadType
+	for _, t := range strings.Split(types, ",") {
+		t = strings.TrimSpace(t)
+		switch payloadType := signature.PayloadType(t); payloadType {
+		case "":
+		case signature.PAYLOAD_BLOCK, signature.PAYLOAD_CROSS_CHAIN, signature.PAYLOAD_CONSENSUS,
+			signature.PAYLOAD_TRANSACTION, signature.PAYLOAD_VRF, signature.PAYLOAD_DATA, signature.PAYLOAD_SUBNET,
+			signature.PAYLOAD_WITNESS:
+			result = append(result, payloadType)
+		default:
+			return nil, fmt.Errorf("unknown payload type: %s", t)
+		}
+	}
+	return result, nil
+}
+
+func (this *RemoteSignerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rsp := &RemoteSignResponse{}
+	defer func() {
+		w.Header().Set("content-type", "application/json;charset=utf-8")
+		data, _ := json.Marshal(rsp)
+		w.Write(data)
+	}()
+
+	if r.Method != http.MethodPost {
+		rsp.Error = "invalid http method"
+		return
+	}
+	if this.token != "" {
+		auth := r.Header.Get("Authorization")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte("Bearer "+this.token)) != 1 {
+			log.Warnf("[remote signer] unauthorized request from %s", r.RemoteAddr)
+			rsp.Error = "unauthorized"
+			return
+		}
+	}
+	req := &RemoteSignRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		rsp.Error = "invalid request"
+		return
+	}
+	err = this.handle(req, rsp)
+	if err != nil {
+		log.Warnf("[remote signer] %s %s type:%s error: %s", req.Method, req.Address, req.Type, err)
+		*rsp = RemoteSignResponse{Error: err.Error()}
+		return
+	}
+	log.Infof("[remote signer] %s %s type:%s", req.Method, req.Address, req.Type)
+}
+
+func (this *RemoteSignerHandler) handle(req *RemoteSignRequest, rsp *RemoteSignResponse) error {
+	acc := this.getAccount(req.Address)
+	if acc == nil {
+		return fmt.Errorf("cannot find account: %s", req.Address)
+	}
+	switch req.Method {
+	case REMOTE_METHOD_ACCOUNT:
+		rsp.Address = acc.Address.ToBase58()
+		rsp.PublicKey = hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey))
+		rsp.Scheme = acc.SigScheme.Name()
+		return nil
+	case REMOTE_METHOD_SIGN, REMOTE_METHOD_VRF:
+	default:
+		return fmt.Errorf("unsupported method: %s", req.Method)
+	}
+
+	if (req.Method == REMOTE_METHOD_VRF) != (req.Type == signature.PAYLOAD_VRF) {
+		return fmt.Errorf("invalid payload type %s of method %s", req.Type, req.Method)
+	}
+	if !this.allow[req.Type] {
+		return fmt.Errorf("payload type %s is not allowed", req.Type)
+	}
+	data, err := hex.DecodeString(req.Data)
+	if err != nil {
+		return fmt.Errorf("invalid data: %s", err)
+	}
+	err = checkPayload(req.Type, data)
+	if err != nil {
+		return err
+	}
+	if req.Method == REMOTE_METHOD_VRF {
+		value, proof, err := acc.Vrf(data)
+		if err != nil {
+			return err
+		}
+		rsp.Value = hex.EncodeToString(value)
+		rsp.Proof = hex.EncodeToString(proof)
+		return nil
+	}
+	sig, err := acc.SignPayload(req.Type, data)
+	if err != nil {
+		return err
+	}
+	rsp.Signature = hex.EncodeToString(sig)
+	return nil
+}
+
+//checkPayload check the structure of payload, so that the message signed for one payload type can never be the
+//message of another type. Block, cross chain msg and transaction are signed by the double sha256 hash of payload,
+//witness by the sha256 hash, consensus and subnet payloads are signed as they are
+func checkPayload(payloadType signature.PayloadType, payload []byte) error {
+	switch payloadType {
+	case signature.PAYLOAD_BLOCK, signature.PAYLOAD_CROSS_CHAIN, signature.PAYLOAD_TRANSACTION:
+		return types.CheckSignPayload(payloadType, payload)
+	case signature.PAYLOAD_CONSENSUS:
+		//version, prev hash, height, bookkeeper index, timestamp and data of unsigned consensus payload
+		source := common.NewZeroCopySource(payload)
+		_, eof := source.NextBytes(4 + common.UINT256_SIZE + 4 + 2 + 4)
+		if !eof {
+			_, _, irregular, e := source.NextVarBytes()
+			eof = e || irregular
+		}
+		if eof || source.Len() != 0 || bytes.HasPrefix(payload, []byte(signature.DATA_PAYLOAD_PREFIX)) {
+			return fmt.Errorf("payload is not an unsigned consensus payload")
+		}
+	case signature.PAYLOAD_SUBNET:
+		//from, to and timestamp of subnet members request
+		if len(payload) != 2*common.ADDR_LEN+4 || bytes.HasPrefix(payload, []byte(signature.DATA_PAYLOAD_PREFIX)) {
+			return fmt.Errorf("payload is not a subnet members request")
+		}
+	case signature.PAYLOAD_WITNESS:
+		//the sha256 hash of a payload in hash size may be the hash of a block or transaction
+		if len(payload) == common.UINT256_SIZE {
+			return fmt.Errorf("payload is not an unsigned offline witness msg")
+		}
+	case signature.PAYLOAD_STATE_ROOT:
+		return fmt.Errorf("state root can not be told from other hashes, it is never signed by remote signer")
+	}
+	return nil
+}
+
+func (this *RemoteSignerHandler) getAccount(address string) *Account {
+	if address == "" && len(this.accounts) > 0 {
+		return this.accounts[0]
+	}
+	for _, acc := range this.accounts {
+		if acc.Address.ToBase58() == address {
+			return acc
+		}
+	}
+	return nil
+}
+
+//ListenRemoteSigner listen on the unix socket or loopback address, the signing daemon must not be exposed to network.
+//The handler of loopback address should be protected by auth token, as any local process can connect to it
+func ListenRemoteSigner(endpoint string) (net.Listener, error) {
+	switch {
+	case strings.HasPrefix(endpoint, "unix://") || strings.HasPrefix(endpoint, "/"):
+		path := strings.TrimPrefix(endpoint, "unix://")
+		os.Remove(path)
+		listener, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, err
+		}
+		err = os.Chmod(path, 0600)
+		if err != nil {
+			listener.Close()
+			return nil, err
+		}
+		return listener, nil
+	case strings.HasPrefix(endpoint, "http://"):
+		addr := strings.TrimSuffix(strings.TrimPrefix(endpoint, "http://"), "/")
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		ip := net.ParseIP(host)
+		if host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return nil, fmt.Errorf("remote signer can only listen on loopback address, got %s", host)
+		}
+		return net.Listen("tcp", addr)
+	default:
+		return nil, fmt.Errorf("invalid remote signer endpoint: %s", endpoint)
+	}
+}
+
+//LoadRemoteSignerToken read the auth token of remote signer from file. A random token is generated and saved
+//to the file readable by owner only if it does not exist and create is true
+func LoadRemoteSignerToken(path string, create bool) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && create {
+		buf := make([]byte, REMOTE_SIGNER_TOKEN_LEN)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		token := hex.EncodeToString(buf)
+		if err := ioutil.WriteFile(path, []byte(token), 0600); err != nil {
+			return "", fmt.Errorf("save remote signer token error: %s", err)
+		}
+		return token, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read remote signer token error: %s", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("empty remote signer token in %s", path)
+	}
+	return token, nil
+}
+
+//IsRemote return whether the private key is kept by remote signer
+func (this *Account) IsRemote() bool {
+	return this.remote != nil
+}
+
+//SignPayload implement signature.PayloadSigner
+func (this *Account) SignPayload(payloadType signature.PayloadType, payload []byte) ([]byte, error) {
+	if this.remote != nil {
+		return this.remote.sign(this.Address.ToBase58(), payloadType, payload)
+	}
+	return signature.SignWithPrivKey(this.SigScheme, this.PrivateKey, signature.PayloadMessage(payloadType, payload))
+}
+
+//Vrf compute the vrf value and proof of data
+func (this *Account) Vrf(data []byte) ([]byte, []byte, error) {
+	if this.remote != nil {
+		return this.remote.vrf(this.Address.ToBase58(), data)
+	}
+	return vrf.Vrf(this.PrivateKey, data)
+}
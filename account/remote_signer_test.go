@@ -0,0 +1,177 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/vrf"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote-signer")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	acc := NewAccount("")
+	allow, err := ParsePayloadTypes("block,crosschain,consensus,vrf")
+	assert.Nil(t, err)
+
+	endpoint := "unix://" + filepath.Join(dir, "signer.sock")
+	listener, err := ListenRemoteSigner(endpoint)
+	assert.Nil(t, err)
+	defer listener.Close()
+	mux := http.NewServeMux()
+	mux.Handle(REMOTE_SIGNER_PATH, NewRemoteSignerHandler([]*Account{acc}, allow, ""))
+	go http.Serve(listener, mux)
+
+	remote, err := NewRemoteSigner(endpoint, "")
+	assert.Nil(t, err)
+	remoteAcc, err := remote.GetAccount(acc.Address.ToBase58())
+	assert.Nil(t, err)
+	assert.True(t, remoteAcc.IsRemote())
+	assert.Nil(t, remoteAcc.PrivateKey)
+	assert.Equal(t, acc.Address, remoteAcc.Address)
+
+	_, err = remote.GetAccount(NewAccount("").Address.ToBase58())
+	assert.NotNil(t, err)
+
+	header := &types.Header{Height: 1, Timestamp: 1, ConsensusData: 1}
+	sig, err := types.SignHeader(remoteAcc, header)
+	assert.Nil(t, err)
+	blkHash := header.Hash()
+	assert.Nil(t, signature.Verify(acc.PublicKey, blkHash[:], sig))
+
+	msg := &types.CrossChainMsg{Version: types.CURR_CROSS_STATES_VERSION, Height: 1}
+	sig, err = types.SignCrossChainMsg(remoteAcc, msg)
+	assert.Nil(t, err)
+	msgHash := msg.Hash()
+	assert.Nil(t, signature.Verify(acc.PublicKey, msgHash[:], sig))
+
+	//the tx hash submitted as block or consensus payload is refused, the signer only signs the hash of header
+	tx := &types.MutableTransaction{TxType: types.InvokeNeo, Payload: &payload.InvokeCode{Code: []byte{1}}}
+	txHash := tx.Hash()
+	_, err = signature.SignPayload(remoteAcc, signature.PAYLOAD_BLOCK, txHash[:])
+	assert.NotNil(t, err)
+	_, err = signature.SignPayload(remoteAcc, signature.PAYLOAD_CROSS_CHAIN, txHash[:])
+	assert.NotNil(t, err)
+	_, err = signature.SignPayload(remoteAcc, signature.PAYLOAD_CONSENSUS, txHash[:])
+	assert.NotNil(t, err)
+	_, err = types.SignTransaction(remoteAcc, tx)
+	assert.NotNil(t, err)
+	_, err = signature.SignPayload(remoteAcc, signature.PAYLOAD_STATE_ROOT, txHash[:])
+	assert.NotNil(t, err)
+	_, err = signature.Sign(remoteAcc, txHash[:])
+	assert.NotNil(t, err)
+
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteUint32(0)
+	sink.WriteHash(blkHash)
+	sink.WriteUint32(1)
+	sink.WriteUint16(0)
+	sink.WriteUint32(1)
+	sink.WriteVarBytes([]byte("consensus msg"))
+	sig, err = signature.SignPayload(remoteAcc, signature.PAYLOAD_CONSENSUS, sink.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, signature.Verify(acc.PublicKey, sink.Bytes(), sig))
+	_, err = signature.SignPayload(remoteAcc, signature.PAYLOAD_CONSENSUS, append(sink.Bytes(), 0))
+	assert.NotNil(t, err)
+
+	value, proof, err := remoteAcc.Vrf([]byte("vrf data"))
+	assert.Nil(t, err)
+	ok, err := vrf.Verify(acc.PublicKey, []byte("vrf data"), value, proof)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestRemoteSignerAuth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote-signer")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	tokenFile := filepath.Join(dir, "signer.token")
+	_, err = LoadRemoteSignerToken(tokenFile, false)
+	assert.NotNil(t, err)
+	token, err := LoadRemoteSignerToken(tokenFile, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 2*REMOTE_SIGNER_TOKEN_LEN, len(token))
+	info, err := os.Stat(tokenFile)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	loaded, err := LoadRemoteSignerToken(tokenFile, false)
+	assert.Nil(t, err)
+	assert.Equal(t, token, loaded)
+
+	acc := NewAccount("")
+	allow, err := ParsePayloadTypes("data,subnet")
+	assert.Nil(t, err)
+	listener, err := ListenRemoteSigner("http://127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	mux := http.NewServeMux()
+	mux.Handle(REMOTE_SIGNER_PATH, NewRemoteSignerHandler([]*Account{acc}, allow, token))
+	go http.Serve(listener, mux)
+	endpoint := "http://" + listener.Addr().String()
+
+	_, err = NewRemoteSigner(endpoint, "")
+	assert.NotNil(t, err)
+	remote, err := NewRemoteSigner(endpoint, "invalid token")
+	assert.Nil(t, err)
+	_, err = remote.GetAccount("")
+	assert.NotNil(t, err)
+
+	remote, err = NewRemoteSigner(endpoint, token)
+	assert.Nil(t, err)
+	remoteAcc, err := remote.GetAccount("")
+	assert.Nil(t, err)
+	assert.Equal(t, acc.Address, remoteAcc.Address)
+
+	//subnet members request is signed as it is, while data is signed with the prefix
+	data := make([]byte, 2*common.ADDR_LEN+4)
+	sig, err := signature.SignPayload(remoteAcc, signature.PAYLOAD_SUBNET, data)
+	assert.Nil(t, err)
+	assert.Nil(t, signature.Verify(acc.PublicKey, data, sig))
+	sig, err = signature.SignPayload(remoteAcc, signature.PAYLOAD_DATA, data)
+	assert.Nil(t, err)
+	assert.Nil(t, signature.VerifyPayload(acc.PublicKey, signature.PAYLOAD_DATA, data, sig))
+	assert.NotNil(t, signature.Verify(acc.PublicKey, data, sig))
+	_, err = signature.SignPayload(remoteAcc, signature.PAYLOAD_SUBNET, data[1:])
+	assert.NotNil(t, err)
+}
+
+func TestListenRemoteSigner(t *testing.T) {
+	_, err := ListenRemoteSigner("http://0.0.0.0:20339")
+	assert.NotNil(t, err)
+	_, err = ListenRemoteSigner("tcp://127.0.0.1:20339")
+	assert.NotNil(t, err)
+
+	_, err = ParsePayloadTypes("block,unknown")
+	assert.NotNil(t, err)
+	_, err = ParsePayloadTypes("block,stateroot")
+	assert.NotNil(t, err)
+}
@@ -21,8 +21,10 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"syscall"
 
+	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/cmd"
 	"github.com/ontio/ontology/cmd/abi"
 	cmdsvr "github.com/ontio/ontology/cmd/sigsvr"
@@ -47,6 +49,11 @@ func setupSigSvr() *cli.App {
 		utils.CliAddressFlag,
 		utils.CliRpcPortFlag,
 		utils.CliABIPathFlag,
+		//remote signer setting
+		utils.CliSignerListenFlag,
+		utils.CliSignerAccountsFlag,
+		utils.CliSignerAllowFlag,
+		utils.CliSignerTokenFlag,
 	}
 	app.Commands = []cli.Command{
 		cmdsvr.ImportWalletCommand,
@@ -82,6 +89,27 @@ func startSigSvr(ctx *cli.Context) {
 	}
 	log.Infof("Load wallet data success. Account number:%d", accountNum)
 
+	signerEndpoint := ctx.String(utils.GetFlagName(utils.CliSignerListenFlag))
+	if signerEndpoint != "" {
+		allow, err := account.ParsePayloadTypes(ctx.String(utils.GetFlagName(utils.CliSignerAllowFlag)))
+		if err != nil {
+			log.Errorf("Invalid --%s: %s", utils.GetFlagName(utils.CliSignerAllowFlag), err)
+			return
+		}
+		var addresses []string
+		for _, addr := range strings.Split(ctx.String(utils.GetFlagName(utils.CliSignerAccountsFlag)), ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				addresses = append(addresses, addr)
+			}
+		}
+		tokenFile := ctx.String(utils.GetFlagName(utils.CliSignerTokenFlag))
+		err = cmdsvr.StartRemoteSigner(signerEndpoint, tokenFile, addresses, allow)
+		if err != nil {
+			log.Errorf("StartRemoteSigner error:%s", err)
+			return
+		}
+	}
+
 	rpcAddress := ctx.String(utils.GetFlagName(utils.CliAddressFlag))
 	rpcPort := ctx.Uint(utils.GetFlagName(utils.CliRpcPortFlag))
 	if rpcPort == 0 {
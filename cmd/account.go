@@ -25,6 +25,7 @@ import (
 
 	"github.com/ontio/ontology-crypto/keypair"
 	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/cmd/common"
 	"github.com/ontio/ontology/cmd/utils"
 	"github.com/ontio/ontology/common/config"
@@ -153,6 +154,20 @@ func chooseCurve(reader *bufio.Reader) string {
 	return ""
 }
 
+var coinTypeMap = map[string]uint32{
+	"ont": account.ONT_COIN_TYPE,
+	"eth": account.ETH_COIN_TYPE,
+}
+
+func checkCoinType(ctx *cli.Context) (uint32, error) {
+	coin := strings.ToLower(ctx.String(utils.GetFlagName(utils.AccountCoinTypeFlag)))
+	coinType, ok := coinTypeMap[coin]
+	if !ok {
+		return 0, fmt.Errorf("%s is not a valid content for option --coin", coin)
+	}
+	return coinType, nil
+}
+
 func checkFileName(ctx *cli.Context) string {
 	if ctx.IsSet(utils.GetFlagName(utils.WalletFileFlag)) {
 		return ctx.String(utils.GetFlagName(utils.WalletFileFlag))
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"os"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-crypto/signature"
 	"github.com/ontio/ontology/account"
@@ -56,9 +57,14 @@ You can use ./Ontology account --help command to view help information of wallet
 					utils.AccountDefaultFlag,
 					utils.AccountLabelFlag,
 					utils.IdentityFlag,
+					utils.AccountMnemonicFlag,
+					utils.AccountCoinTypeFlag,
+					utils.AccountDerivePathFlag,
 					utils.WalletFileFlag,
 				},
 				Description: ` Add a new account to wallet.
+   With --mnemonic option, accounts are derived from the BIP-39 mnemonic of wallet by BIP-44 path, a new mnemonic is generated if the wallet does not have one.
+   Use --coin to derive ONT accounts (m/44'/1024'/0'/0/index, ECDSA P-256 key) or ETH accounts (m/44'/60'/0'/0/index, secp256k1 key with ethereum address).
    Ontology support three type of key: ecdsa, sm2 and ed25519, and support 224、256、384、521 bits length of key in ecdsa, but only support 256 bits length of key in sm2 and ed25519.
    Ontology support multiple signature scheme.
    For ECDSA support SHA224withECDSA、SHA256withECDSA、SHA384withECDSA、SHA512withEdDSA、SHA3-224withECDSA、SHA3-256withECDSA、SHA3-384withECDSA、SHA3-512withECDSA、RIPEMD160withECDSA;
@@ -133,8 +139,12 @@ You can use ./Ontology account --help command to view help information of wallet
 					utils.WalletFileFlag,
 					utils.AccountSourceFileFlag,
 					utils.AccountWIFFlag,
+					utils.AccountMnemonicFlag,
+					utils.AccountCoinTypeFlag,
+					utils.AccountDerivePathFlag,
+					utils.AccountQuantityFlag,
 				},
-				Description: "Import accounts of wallet to another. If not specific accounts in args, all account in source will be import",
+				Description: "Import accounts of wallet to another. If not specific accounts in args, all account in source will be import. With --mnemonic option, restore the first --number HD accounts (or the account of --derive-path) from the input mnemonic",
 			},
 			{
 				Action:    accountExport,
@@ -151,6 +161,9 @@ You can use ./Ontology account --help command to view help information of wallet
 )
 
 func accountCreate(ctx *cli.Context) error {
+	if ctx.Bool(utils.GetFlagName(utils.AccountMnemonicFlag)) {
+		return accountCreateHD(ctx)
+	}
 	reader := bufio.NewReader(os.Stdin)
 	optionType := ""
 	optionCurve := ""
@@ -215,6 +228,80 @@ func accountCreate(ctx *cli.Context) error {
 	return nil
 }
 
+func accountCreateHD(ctx *cli.Context) error {
+	coinType, err := checkCoinType(ctx)
+	if err != nil {
+		return err
+	}
+	optionFile := checkFileName(ctx)
+	wallet, err := account.Open(optionFile)
+	if err != nil {
+		return fmt.Errorf("error opening wallet: %s", err)
+	}
+	var pass []byte
+	if wallet.HasMnemonic() {
+		pass, err = password.GetPassword()
+	} else {
+		pass, err = password.GetConfirmedPassword()
+	}
+	if err != nil {
+		return fmt.Errorf("input password error: %s", err)
+	}
+	defer common.ClearPasswd(pass)
+	if !wallet.HasMnemonic() {
+		mnemonic, err := account.GenerateMnemonic()
+		if err != nil {
+			return fmt.Errorf("error generating mnemonic: %s", err)
+		}
+		err = wallet.SetMnemonic(mnemonic, pass)
+		if err != nil {
+			return fmt.Errorf("error saving mnemonic: %s", err)
+		}
+		PrintWarnMsg("New mnemonic of wallet, please write it down and keep it safe:")
+		PrintInfoMsg("%s", mnemonic)
+	}
+
+	label := checkLabel(ctx)
+	pathFlag := utils.GetFlagName(utils.AccountDerivePathFlag)
+	if ctx.IsSet(pathFlag) {
+		acc, err := wallet.NewAccountFromPath(label, ctx.String(pathFlag), pass)
+		if err != nil {
+			return fmt.Errorf("error deriving account: %s", err)
+		}
+		printHDAccount(wallet, acc, label)
+	} else {
+		number := checkNumber(ctx)
+		for i := 0; i < number; i++ {
+			accLabel := label
+			if accLabel != "" && number > 1 {
+				accLabel = fmt.Sprintf("%s%d", label, i+1)
+			}
+			acc, err := wallet.NewHDAccount(accLabel, coinType, pass)
+			if err != nil {
+				return fmt.Errorf("error deriving account: %s", err)
+			}
+			printHDAccount(wallet, acc, accLabel)
+		}
+	}
+	PrintInfoMsg("Create account successfully.")
+	return nil
+}
+
+func printHDAccount(wallet account.Client, acc *account.Account, label string) {
+	accMeta := wallet.GetAccountMetadataByAddress(acc.Address.ToBase58())
+	PrintInfoMsg("Index:%d", wallet.GetAccountNum())
+	PrintInfoMsg("Label:%s", label)
+	PrintInfoMsg("Address:%s", acc.Address.ToBase58())
+	if acc.SigScheme == signature.KECCAK256WithECDSA {
+		PrintInfoMsg("Ethereum address:%s", ethcommon.Address(acc.Address).Hex())
+	}
+	if accMeta != nil {
+		PrintInfoMsg("Derive path:%s", accMeta.DerivePath)
+	}
+	PrintInfoMsg("Public key:%s", hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey)))
+	PrintInfoMsg("Signature scheme:%s", acc.SigScheme.Name())
+}
+
 func accountList(ctx *cli.Context) error {
 	optionFile := checkFileName(ctx)
 	wallet, err := account.Open(optionFile)
@@ -392,6 +479,9 @@ func accountDelete(ctx *cli.Context) error {
 }
 
 func accountImport(ctx *cli.Context) error {
+	if ctx.Bool(utils.GetFlagName(utils.AccountMnemonicFlag)) {
+		return accountImportMnemonic(ctx)
+	}
 	source := ctx.String(utils.GetFlagName(utils.AccountSourceFileFlag))
 	if source == "" {
 		PrintErrorMsg("Missing source wallet path argument to import.")
@@ -531,6 +621,85 @@ func accountImport(ctx *cli.Context) error {
 	return nil
 }
 
+func accountImportMnemonic(ctx *cli.Context) error {
+	coinType, err := checkCoinType(ctx)
+	if err != nil {
+		return err
+	}
+	fn := checkFileName(ctx)
+	wallet, err := account.Open(fn)
+	if err != nil {
+		return err
+	}
+
+	PrintInfoMsg("Please input the mnemonic:")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return fmt.Errorf("read mnemonic error: %s", err)
+	}
+	mnemonic, err := account.NormalizeMnemonic(line)
+	if err != nil {
+		return err
+	}
+	var pass []byte
+	if wallet.HasMnemonic() {
+		pass, err = password.GetPassword()
+	} else {
+		PrintInfoMsg("Please input a password to encrypt the mnemonic and imported key(s)")
+		pass, err = password.GetConfirmedPassword()
+	}
+	if err != nil {
+		return fmt.Errorf("input password error: %s", err)
+	}
+	defer common.ClearPasswd(pass)
+	if wallet.HasMnemonic() {
+		old, err := wallet.GetMnemonic(pass)
+		if err != nil {
+			return err
+		}
+		if old != mnemonic {
+			return fmt.Errorf("wallet %s already has a different mnemonic", fn)
+		}
+	} else {
+		err = wallet.SetMnemonic(mnemonic, pass)
+		if err != nil {
+			return fmt.Errorf("error saving mnemonic: %s", err)
+		}
+	}
+
+	var paths []string
+	pathFlag := utils.GetFlagName(utils.AccountDerivePathFlag)
+	if ctx.IsSet(pathFlag) {
+		paths = append(paths, ctx.String(pathFlag))
+	} else {
+		for i := 0; i < checkNumber(ctx); i++ {
+			paths = append(paths, account.Bip44Path(coinType, uint32(i)))
+		}
+	}
+	succ := 0
+	for _, path := range paths {
+		acc, err := account.DeriveAccount(mnemonic, path)
+		if err != nil {
+			return fmt.Errorf("error deriving account: %s", err)
+		}
+		if wallet.GetAccountMetadataByAddress(acc.Address.ToBase58()) != nil {
+			PrintWarnMsg("Account: %s (path: %s) already exists in wallet, skip.", acc.Address.ToBase58(), path)
+			continue
+		}
+		acc, err = wallet.NewAccountFromPath("", path, pass)
+		if err != nil {
+			PrintWarnMsg("Import account of path %s failed, %s", path, err)
+			continue
+		}
+		succ++
+		PrintInfoMsg("Import account: %s (path: %s) successfully.", acc.Address.ToBase58(), path)
+	}
+	PrintInfoMsg("Import from mnemonic to %s complete.", fn)
+	PrintInfoMsg("Total:\t%d", len(paths))
+	PrintInfoMsg("Success:%d", succ)
+	return nil
+}
+
 func accountExport(ctx *cli.Context) error {
 	if ctx.NArg() <= 0 {
 		PrintErrorMsg("Missing target file argument to export.")
@@ -52,6 +52,8 @@ var AssetCommand = cli.Command{
 				utils.TransactionAmountFlag,
 				utils.ForceSendTxFlag,
 				utils.WalletFileFlag,
+				utils.RemoteSignerFlag,
+				utils.RemoteSignerTokenFlag,
 			},
 		},
 		{
@@ -68,6 +70,8 @@ var AssetCommand = cli.Command{
 				utils.ApproveAssetToFlag,
 				utils.ApproveAmountFlag,
 				utils.WalletFileFlag,
+				utils.RemoteSignerFlag,
+				utils.RemoteSignerTokenFlag,
 			},
 		},
 		{
@@ -86,6 +90,8 @@ var AssetCommand = cli.Command{
 				utils.TransferFromAmountFlag,
 				utils.ForceSendTxFlag,
 				utils.WalletFileFlag,
+				utils.RemoteSignerFlag,
+				utils.RemoteSignerTokenFlag,
 			},
 		},
 		{
@@ -130,6 +136,8 @@ var AssetCommand = cli.Command{
 				utils.TransactionGasPriceFlag,
 				utils.TransactionGasLimitFlag,
 				utils.WalletFileFlag,
+				utils.RemoteSignerFlag,
+				utils.RemoteSignerTokenFlag,
 			},
 		},
 	},
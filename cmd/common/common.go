@@ -118,6 +118,15 @@ func GetAccountMetadataMulti(wallet account.Client, accAddr string) *account.Acc
 }
 
 func GetAccount(ctx *cli.Context, address ...string) (*account.Account, error) {
+	accAddr := ""
+	if len(address) > 0 {
+		accAddr = address[0]
+	} else {
+		accAddr = ctx.String(utils.GetFlagName(utils.AccountAddressFlag))
+	}
+	if endpoint := ctx.String(utils.GetFlagName(utils.RemoteSignerFlag)); endpoint != "" {
+		return GetRemoteAccount(endpoint, ctx.String(utils.GetFlagName(utils.RemoteSignerTokenFlag)), accAddr)
+	}
 	wallet, err := OpenWallet(ctx)
 	if err != nil {
 		return nil, err
@@ -127,15 +136,34 @@ func GetAccount(ctx *cli.Context, address ...string) (*account.Account, error) {
 		return nil, err
 	}
 	defer ClearPasswd(passwd)
-	accAddr := ""
-	if len(address) > 0 {
-		accAddr = address[0]
-	} else {
-		accAddr = ctx.String(utils.GetFlagName(utils.AccountAddressFlag))
-	}
 	return GetAccountMulti(wallet, passwd, accAddr)
 }
 
+//GetRemoteAccount return the account kept by remote signer, account should be specified by base58 address.
+//The auth token of remote signer is read from tokenFile if not empty
+func GetRemoteAccount(endpoint string, tokenFile string, accAddr string) (*account.Account, error) {
+	if accAddr != "" && !IsBase58Address(accAddr) {
+		return nil, fmt.Errorf("account of remote signer should be base58 address, got: %s", accAddr)
+	}
+	token := ""
+	if tokenFile != "" {
+		var err error
+		token, err = account.LoadRemoteSignerToken(tokenFile, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	signer, err := account.NewRemoteSigner(endpoint, token)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := signer.GetAccount(accAddr)
+	if err != nil {
+		return nil, fmt.Errorf("get account from remote signer error: %s", err)
+	}
+	return acc, nil
+}
+
 func IsBase58Address(address string) bool {
 	if address == "" {
 		return false
@@ -20,6 +20,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/ontio/ontology/cmd/utils"
 	"github.com/ontio/ontology/common"
@@ -37,11 +38,13 @@ func SetOntologyConfig(ctx *cli.Context) (*config.OntologyConfig, error) {
 	}
 	setCommonConfig(ctx, cfg.Common)
 	setConsensusConfig(ctx, cfg.Consensus)
+	setTxPoolConfig(ctx, cfg.TxPool)
 	setP2PNodeConfig(ctx, cfg.P2PNode)
 	setRpcConfig(ctx, cfg.Rpc)
 	setRestfulConfig(ctx, cfg.Restful)
 	setGraphQLConfig(ctx, cfg.GraphQL)
 	setWebSocketConfig(ctx, cfg.Ws)
+	setMetricsConfig(ctx, cfg.Metrics)
 	if cfg.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
 		cfg.Ws.EnableHttpWs = true
 		cfg.Restful.EnableHttpRestful = true
@@ -91,6 +94,9 @@ func setGenesis(ctx *cli.Context, cfg *config.OntologyConfig) error {
 		if cfg.Genesis.SOLO.GenBlockTime <= 1 {
 			cfg.Genesis.SOLO.GenBlockTime = config.DEFAULT_GEN_BLOCK_TIME
 		}
+		cfg.Genesis.SOLO.AutoMine = ctx.Bool(utils.GetFlagName(utils.TestModeAutoMineFlag))
+		cfg.Genesis.SOLO.DevAccounts = ctx.Uint(utils.GetFlagName(utils.TestModeDevAccountsFlag))
+		cfg.Genesis.SOLO.DevChain = true
 		return nil
 	}
 
@@ -119,6 +125,13 @@ func setGenesis(ctx *cli.Context, cfg *config.OntologyConfig) error {
 		if cfg.Genesis.DBFT.GenBlockTime <= 0 {
 			cfg.Genesis.DBFT.GenBlockTime = config.DEFAULT_GEN_BLOCK_TIME
 		}
+	case config.CONSENSUS_TYPE_SBFT:
+		if len(cfg.Genesis.SBFT.Bookkeepers) < config.SBFT_MIN_NODE_NUM {
+			return fmt.Errorf("SBFT consensus at least need %d bookkeepers in config", config.SBFT_MIN_NODE_NUM)
+		}
+		if cfg.Genesis.SBFT.GenBlockTime <= 0 {
+			cfg.Genesis.SBFT.GenBlockTime = config.DEFAULT_GEN_BLOCK_TIME
+		}
 	case config.CONSENSUS_TYPE_VBFT:
 		err = governance.CheckVBFTConfig(cfg.Genesis.VBFT)
 		if err != nil {
@@ -143,6 +156,8 @@ func setCommonConfig(ctx *cli.Context, cfg *config.CommonConfig) {
 	//add new flag for ethgaslimit
 	cfg.ETHTxGasLimit = ctx.Uint64(utils.GetFlagName(utils.ETHTxGasLimitFlag))
 	cfg.TraceTxPool = ctx.Bool(utils.GetFlagName(utils.TraceTxPoolFlag))
+	cfg.EnableStateHistory = ctx.Bool(utils.GetFlagName(utils.EnableStateHistoryFlag))
+	cfg.EnableAddressIndex = ctx.Bool(utils.GetFlagName(utils.EnableAddressIndexFlag))
 }
 
 func setConsensusConfig(ctx *cli.Context, cfg *config.ConsensusConfig) {
@@ -150,6 +165,17 @@ func setConsensusConfig(ctx *cli.Context, cfg *config.ConsensusConfig) {
 	cfg.MaxTxInBlock = ctx.Uint(utils.GetFlagName(utils.MaxTxInBlockFlag))
 }
 
+func setTxPoolConfig(ctx *cli.Context, cfg *config.TxPoolConfig) {
+	cfg.GlobalSlots = ctx.Uint(utils.GetFlagName(utils.TxPoolGlobalSlotsFlag))
+	cfg.AccountSlots = ctx.Uint(utils.GetFlagName(utils.TxPoolAccountSlotsFlag))
+	cfg.GlobalQueue = ctx.Uint(utils.GetFlagName(utils.TxPoolGlobalQueueFlag))
+	cfg.AccountQueue = ctx.Uint(utils.GetFlagName(utils.TxPoolAccountQueueFlag))
+	cfg.PriceBump = ctx.Uint(utils.GetFlagName(utils.TxPoolPriceBumpFlag))
+	cfg.EnableNativeReplace = ctx.Bool(utils.GetFlagName(utils.TxPoolNativeReplaceFlag))
+	cfg.EnableJournal = ctx.Bool(utils.GetFlagName(utils.TxPoolJournalFlag))
+	cfg.JournalMaxAge = ctx.Uint(utils.GetFlagName(utils.TxPoolJournalMaxAgeFlag))
+}
+
 func setP2PNodeConfig(ctx *cli.Context, cfg *config.P2PNodeConfig) {
 	cfg.NetworkId = uint32(ctx.Uint(utils.GetFlagName(utils.NetworkIdFlag)))
 	cfg.NetworkMagic = config.GetNetworkMagic(cfg.NetworkId)
@@ -161,6 +187,7 @@ func setP2PNodeConfig(ctx *cli.Context, cfg *config.P2PNodeConfig) {
 	cfg.MaxConnInBound = ctx.Uint(utils.GetFlagName(utils.MaxConnInBoundFlag))
 	cfg.MaxConnOutBound = ctx.Uint(utils.GetFlagName(utils.MaxConnOutBoundFlag))
 	cfg.MaxConnInBoundForSingleIP = ctx.Uint(utils.GetFlagName(utils.MaxConnInBoundForSingleIPFlag))
+	cfg.SecureTransport = ctx.Bool(utils.GetFlagName(utils.SecureTransportFlag))
 
 	rsvfile := ctx.String(utils.GetFlagName(utils.ReservedPeersFileFlag))
 	if cfg.ReservedPeersOnly {
@@ -188,6 +215,18 @@ func setRpcConfig(ctx *cli.Context, cfg *config.RpcConfig) {
 	cfg.HttpJsonPort = ctx.Uint(utils.GetFlagName(utils.RPCPortFlag))
 	cfg.HttpLocalPort = ctx.Uint(utils.GetFlagName(utils.RPCLocalProtFlag))
 	cfg.EthJsonPort = ctx.Uint(utils.GetFlagName(utils.ETHRPCPortFlag))
+	cfg.MaxBatchSize = ctx.Uint(utils.GetFlagName(utils.RPCMaxBatchSizeFlag))
+	cfg.JsonRpc2 = ctx.Bool(utils.GetFlagName(utils.RPCJsonRpc2Flag))
+	cfg.EnableEthWs = ctx.Bool(utils.GetFlagName(utils.ETHWsEnableFlag))
+	cfg.EthWsPort = ctx.Uint(utils.GetFlagName(utils.ETHWsPortFlag))
+	cfg.EthWsOrigins = nil
+	for _, origin := range strings.Split(ctx.String(utils.GetFlagName(utils.ETHWsOriginsFlag)), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			cfg.EthWsOrigins = append(cfg.EthWsOrigins, origin)
+		}
+	}
+	cfg.EthWsMaxConnections = ctx.Uint(utils.GetFlagName(utils.ETHWsMaxConnsFlag))
+	cfg.EthWsMaxSubscriptions = ctx.Uint(utils.GetFlagName(utils.ETHWsMaxSubscriptionsFlag))
 }
 
 func setRestfulConfig(ctx *cli.Context, cfg *config.RestfulConfig) {
@@ -207,6 +246,11 @@ func setWebSocketConfig(ctx *cli.Context, cfg *config.WebSocketConfig) {
 	cfg.HttpWsPort = ctx.Uint(utils.GetFlagName(utils.WsPortFlag))
 }
 
+func setMetricsConfig(ctx *cli.Context, cfg *config.MetricsConfig) {
+	cfg.EnableMetrics = ctx.Bool(utils.GetFlagName(utils.MetricsEnableFlag))
+	cfg.MetricsPort = ctx.Uint(utils.GetFlagName(utils.MetricsPortFlag))
+}
+
 func SetRpcPort(ctx *cli.Context) {
 	if ctx.IsSet(utils.GetFlagName(utils.RPCPortFlag)) {
 		config.DefConfig.Rpc.HttpJsonPort = ctx.Uint(utils.GetFlagName(utils.RPCPortFlag))
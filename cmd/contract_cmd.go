@@ -59,6 +59,8 @@ var (
 					utils.ContractDescFlag,
 					utils.ContractPrepareDeployFlag,
 					utils.WalletFileFlag,
+					utils.RemoteSignerFlag,
+					utils.RemoteSignerTokenFlag,
 					utils.AccountAddressFlag,
 				},
 			},
@@ -94,6 +96,8 @@ var (
 					utils.ContractPrepareInvokeFlag,
 					utils.ContractReturnTypeFlag,
 					utils.WalletFileFlag,
+					utils.RemoteSignerFlag,
+					utils.RemoteSignerTokenFlag,
 					utils.AccountAddressFlag,
 				},
 			},
@@ -108,6 +112,8 @@ var (
 					utils.TransactionGasPriceFlag,
 					utils.TransactionGasLimitFlag,
 					utils.WalletFileFlag,
+					utils.RemoteSignerFlag,
+					utils.RemoteSignerTokenFlag,
 					utils.ContractPrepareInvokeFlag,
 					utils.AccountAddressFlag,
 				},
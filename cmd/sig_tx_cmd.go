@@ -51,6 +51,8 @@ var MultiSigTxCommand = cli.Command{
 	Flags: []cli.Flag{
 		utils.RPCPortFlag,
 		utils.WalletFileFlag,
+		utils.RemoteSignerFlag,
+		utils.RemoteSignerTokenFlag,
 		utils.AccountMultiMFlag,
 		utils.AccountMultiPubKeyFlag,
 		utils.AccountAddressFlag,
@@ -68,6 +70,8 @@ var SigTxCommand = cli.Command{
 	Flags: []cli.Flag{
 		utils.RPCPortFlag,
 		utils.WalletFileFlag,
+		utils.RemoteSignerFlag,
+		utils.RemoteSignerTokenFlag,
 		utils.AccountAddressFlag,
 		utils.SendTxFlag,
 		utils.PrepareExecTransactionFlag,
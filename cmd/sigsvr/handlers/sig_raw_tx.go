@@ -24,7 +24,6 @@ import (
 
 	"github.com/ontio/ontology-crypto/keypair"
 	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
-	cliutil "github.com/ontio/ontology/cmd/utils"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/types"
@@ -74,8 +73,7 @@ func SigRawTransaction(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcRespo
 		mutable.Payer = signer.Address
 	}
 
-	txHash := mutable.Hash()
-	sigData, err := cliutil.Sign(txHash.ToArray(), signer)
+	sigData, err := types.SignTransaction(signer, mutable)
 	if err != nil {
 		log.Infof("Cli Qid:%s SigRawTransaction Sign error:%s", req.Qid, err)
 		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sigsvr
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ontio/ontology/account"
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/common/password"
+	"github.com/ontio/ontology/core/signature"
+)
+
+//StartRemoteSigner unlock the accounts of wallet store, and serve the remote signer protocol for node and CLI.
+//The requests are authenticated by the token in tokenFile, which is required by the http endpoint
+func StartRemoteSigner(endpoint string, tokenFile string, addresses []string, allow []signature.PayloadType) error {
+	if len(addresses) == 0 {
+		return fmt.Errorf("no account for remote signer")
+	}
+	token := ""
+	if tokenFile != "" {
+		var err error
+		token, err = account.LoadRemoteSignerToken(tokenFile, true)
+		if err != nil {
+			return err
+		}
+	} else if !strings.HasPrefix(endpoint, "unix://") && !strings.HasPrefix(endpoint, "/") {
+		return fmt.Errorf("auth token file is required by remote signer endpoint: %s", endpoint)
+	}
+	accounts := make([]*account.Account, 0, len(addresses))
+	for _, address := range addresses {
+		fmt.Printf("Unlock account %s for remote signer\n", address)
+		pwd, err := password.GetAccountPassword()
+		if err != nil {
+			return fmt.Errorf("input password error: %s", err)
+		}
+		acc, err := clisvrcom.DefWalletStore.GetAccountByAddress(address, pwd)
+		for i := range pwd {
+			pwd[i] = 0
+		}
+		if err != nil {
+			return fmt.Errorf("unlock account %s error: %s", address, err)
+		}
+		if acc == nil {
+			return fmt.Errorf("cannot find account by address: %s", address)
+		}
+		accounts = append(accounts, acc)
+	}
+
+	listener, err := account.ListenRemoteSigner(endpoint)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(account.REMOTE_SIGNER_PATH, account.NewRemoteSignerHandler(accounts, allow, token))
+	go func() {
+		err := http.Serve(listener, mux)
+		if err != nil {
+			log.Errorf("remote signer stopped: %s", err)
+		}
+	}()
+	log.Infof("Remote signer listening on: %s, allowed payload types: %v", endpoint, allow)
+	return nil
+}
@@ -114,6 +114,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.WalletFileFlag,
 			utils.AccountAddressFlag,
 			utils.AccountPassFlag,
+			utils.RemoteSignerFlag,
+			utils.RemoteSignerTokenFlag,
 			utils.AccountDefaultFlag,
 			utils.AccountKeylenFlag,
 			utils.AccountSetDefaultFlag,
@@ -126,6 +128,9 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.AccountSourceFileFlag,
 			utils.AccountWIFFlag,
 			utils.AccountLowSecurityFlag,
+			utils.AccountMnemonicFlag,
+			utils.AccountCoinTypeFlag,
+			utils.AccountDerivePathFlag,
 			utils.AccountMultiMFlag,
 			utils.AccountMultiPubKeyFlag,
 			utils.IdentityFlag,
@@ -146,6 +151,14 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxpoolPreExecDisableFlag,
 			utils.DisableSyncVerifyTxFlag,
 			utils.DisableBroadcastNetTxFlag,
+			utils.TxPoolGlobalSlotsFlag,
+			utils.TxPoolAccountSlotsFlag,
+			utils.TxPoolGlobalQueueFlag,
+			utils.TxPoolAccountQueueFlag,
+			utils.TxPoolPriceBumpFlag,
+			utils.TxPoolNativeReplaceFlag,
+			utils.TxPoolJournalFlag,
+			utils.TxPoolJournalMaxAgeFlag,
 		},
 	},
 	{
@@ -159,6 +172,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MaxConnInBoundFlag,
 			utils.MaxConnOutBoundFlag,
 			utils.MaxConnInBoundForSingleIPFlag,
+			utils.SecureTransportFlag,
 		},
 	},
 	{
@@ -169,6 +183,13 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.RPCLocalEnableFlag,
 			utils.RPCLocalProtFlag,
 			utils.ETHRPCPortFlag,
+			utils.RPCMaxBatchSizeFlag,
+			utils.RPCJsonRpc2Flag,
+			utils.ETHWsEnableFlag,
+			utils.ETHWsPortFlag,
+			utils.ETHWsOriginsFlag,
+			utils.ETHWsMaxConnsFlag,
+			utils.ETHWsMaxSubscriptionsFlag,
 		},
 	},
 	{
@@ -194,11 +215,20 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.WsPortFlag,
 		},
 	},
+	{
+		Name: "METRICS",
+		Flags: []cli.Flag{
+			utils.MetricsEnableFlag,
+			utils.MetricsPortFlag,
+		},
+	},
 	{
 		Name: "TEST MODE",
 		Flags: []cli.Flag{
 			utils.EnableTestModeFlag,
 			utils.TestModeGenBlockTimeFlag,
+			utils.TestModeAutoMineFlag,
+			utils.TestModeDevAccountsFlag,
 		},
 	},
 	{
@@ -87,6 +87,14 @@ var (
 		Usage: "ETH block total gas limit",
 		Value: config.DEFAULT_ETH_TX_MAX_GAS_LIMIT,
 	}
+	EnableStateHistoryFlag = cli.BoolFlag{
+		Name:  "enable-state-history",
+		Usage: "Keep the state of every block to serve state queries at past heights",
+	}
+	EnableAddressIndexFlag = cli.BoolFlag{
+		Name:  "enable-address-index",
+		Usage: "Index the transactions and token transfers of every block by address",
+	}
 	//Consensus setting
 	EnableConsensusFlag = cli.BoolFlag{
 		Name:  "enable-consensus",
@@ -117,6 +125,15 @@ var (
 		Usage: "Block-out `<time>`(s) in test mode.",
 		Value: config.DEFAULT_GEN_BLOCK_TIME,
 	}
+	TestModeAutoMineFlag = cli.BoolFlag{
+		Name:  "testmode-auto-mine",
+		Usage: "Mine a block as soon as a transaction enters the tx pool in test mode, instead of every block-out time.",
+	}
+	TestModeDevAccountsFlag = cli.UintFlag{
+		Name:  "testmode-dev-accounts",
+		Usage: "Number `<count>` of pre-funded dev accounts created in test mode.",
+		Value: config.DEFAULT_DEV_ACCOUNTS,
+	}
 
 	//P2P setting
 	ReservedPeersOnlyFlag = cli.BoolFlag{
@@ -158,6 +175,10 @@ var (
 		Usage: "Max connection `<number>` in bound for single ip",
 		Value: config.DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP,
 	}
+	SecureTransportFlag = cli.BoolFlag{
+		Name:  "secure-transport",
+		Usage: "Encrypt p2p links and authenticate the peer key id with peers which support it, legacy peers still connect in plaintext",
+	}
 	// RPC settings
 	RPCDisabledFlag = cli.BoolFlag{
 		Name:  "disable-rpc",
@@ -173,6 +194,39 @@ var (
 		Usage: "Eth json rpc server listening port `<number>`",
 		Value: config.DEFAULT_ETH_RPC_PORT,
 	}
+	RPCMaxBatchSizeFlag = cli.UintFlag{
+		Name:  "rpc-max-batch-size",
+		Usage: "Max requests `<number>` in a json rpc batch, 0 means no limit",
+		Value: config.DEFAULT_RPC_MAX_BATCH_SIZE,
+	}
+	RPCJsonRpc2Flag = cli.BoolFlag{
+		Name:  "rpc-jsonrpc2",
+		Usage: "Respond json rpc 2.0 error objects instead of ontology error codes and descriptions, and skip notifications",
+	}
+	ETHWsEnableFlag = cli.BoolFlag{
+		Name:  "ethws",
+		Usage: "Enable eth json rpc websocket server, which supports eth_subscribe",
+	}
+	ETHWsPortFlag = cli.UintFlag{
+		Name:  "ethws-port",
+		Usage: "Eth json rpc websocket server listening port `<number>`",
+		Value: config.DEFAULT_ETH_WS_PORT,
+	}
+	ETHWsOriginsFlag = cli.StringFlag{
+		Name:  "ethws-origins",
+		Usage: "Comma separated origins `<list>` allowed to connect eth websocket server and graphql subscriptions, * means any",
+		Value: "*",
+	}
+	ETHWsMaxConnsFlag = cli.UintFlag{
+		Name:  "ethws-max-connection",
+		Usage: "Eth json rpc websocket server maximum connections `<number>`",
+		Value: config.DEFAULT_HTTP_MAX_CONN,
+	}
+	ETHWsMaxSubscriptionsFlag = cli.UintFlag{
+		Name:  "ethws-max-subscription",
+		Usage: "Max subscriptions `<number>` of an eth websocket connection, 0 means no limit",
+		Value: config.DEFAULT_ETH_WS_MAX_SUBSCRIPTIONS,
+	}
 	RPCLocalEnableFlag = cli.BoolFlag{
 		Name:  "localrpc",
 		Usage: "Enable local rpc server",
@@ -226,6 +280,17 @@ var (
 		Value: config.DEFAULT_HTTP_MAX_CONN,
 	}
 
+	//Metrics setting
+	MetricsEnableFlag = cli.BoolFlag{
+		Name:  "metrics",
+		Usage: "Enable prometheus metrics server",
+	}
+	MetricsPortFlag = cli.UintFlag{
+		Name:  "metrics-port",
+		Usage: "Prometheus metrics server listening port `<number>`",
+		Value: config.DEFAULT_METRICS_PORT,
+	}
+
 	//Account setting
 	AccountPassFlag = cli.StringFlag{
 		Name:   "password,p",
@@ -236,6 +301,14 @@ var (
 		Name:  "account,a",
 		Usage: "Account `<address>` when the Ontology node starts. If not specific, using default account instead",
 	}
+	RemoteSignerFlag = cli.StringFlag{
+		Name:  "remote-signer",
+		Usage: "Sign with the account kept by the signing daemon at `<endpoint>` instead of wallet file, unix:///path/to/signer.sock or http://127.0.0.1:port",
+	}
+	RemoteSignerTokenFlag = cli.StringFlag{
+		Name:  "remote-signer-token",
+		Usage: "Read the auth token of the remote signer from `<file>`, required by the http endpoint",
+	}
 	AccountDefaultFlag = cli.BoolFlag{
 		Name:  "default,d",
 		Usage: "Default settings to create a new account (equal to '-t ecdsa -b 256 -s SHA256withECDSA')",
@@ -289,6 +362,19 @@ var (
 		Name:  "wif",
 		Usage: "Import WIF keys from the source file specified by --source option",
 	}
+	AccountMnemonicFlag = cli.BoolFlag{
+		Name:  "mnemonic",
+		Usage: "Derive HD accounts from the BIP-39 mnemonic of wallet. 'add' generates a new mnemonic if the wallet does not have one, 'import' restores accounts from the input mnemonic",
+	}
+	AccountCoinTypeFlag = cli.StringFlag{
+		Name:  "coin",
+		Value: "ont",
+		Usage: "Coin `<type>` of HD accounts, ont or eth",
+	}
+	AccountDerivePathFlag = cli.StringFlag{
+		Name:  "derive-path",
+		Usage: "BIP-44 derive `<path>` of HD account, e.g. m/44'/1024'/0'/0/0. If not specific, using the next index of coin type",
+	}
 	AccountMultiMFlag = cli.UintFlag{
 		Name:  "m",
 		Usage: "Min signature `<number>` of multi signature address",
@@ -480,6 +566,23 @@ var (
 		Usage: "Wallet data `<path>`",
 		Value: DEFAULT_WALLET_PATH,
 	}
+	CliSignerListenFlag = cli.StringFlag{
+		Name:  "signer-listen",
+		Usage: "Serve the remote signer for node and CLI at `<endpoint>`, unix:///path/to/signer.sock or http://127.0.0.1:port",
+	}
+	CliSignerAccountsFlag = cli.StringFlag{
+		Name:  "signer-accounts",
+		Usage: "Comma separated `<addresses>` of the accounts unlocked for the remote signer",
+	}
+	CliSignerAllowFlag = cli.StringFlag{
+		Name:  "signer-allow",
+		Usage: "Comma separated payload `<types>` the remote signer will sign, supports block, crosschain, consensus, vrf, transaction, data, subnet and witness",
+		Value: config.DEFAULT_REMOTE_SIGNER_ALLOW,
+	}
+	CliSignerTokenFlag = cli.StringFlag{
+		Name:  "signer-token",
+		Usage: "Auth token `<file>` of the remote signer, a random token is generated if the file does not exist. Required by the http endpoint",
+	}
 
 	//Export setting
 	ExportFileFlag = cli.StringFlag{
@@ -525,6 +628,52 @@ var (
 		Usage: "trace info log in tx pool",
 	}
 
+	TxPoolGlobalSlotsFlag = cli.UintFlag{
+		Name:  "txpool-global-slots",
+		Usage: "Max executable transactions `<number>` in tx pool, 0 means no limit",
+		Value: config.DEFAULT_TXPOOL_GLOBAL_SLOTS,
+	}
+
+	TxPoolAccountSlotsFlag = cli.UintFlag{
+		Name:  "txpool-account-slots",
+		Usage: "Max executable transactions `<number>` of a payer in tx pool, 0 means no limit",
+		Value: config.DEFAULT_TXPOOL_ACCOUNT_SLOTS,
+	}
+
+	TxPoolGlobalQueueFlag = cli.UintFlag{
+		Name:  "txpool-global-queue",
+		Usage: "Max EIP155 transactions `<number>` waiting for a missing nonce in tx pool, 0 means no limit",
+		Value: config.DEFAULT_TXPOOL_GLOBAL_QUEUE,
+	}
+
+	TxPoolAccountQueueFlag = cli.UintFlag{
+		Name:  "txpool-account-queue",
+		Usage: "Max EIP155 transactions `<number>` of a payer waiting for a missing nonce in tx pool, 0 means no limit",
+		Value: config.DEFAULT_TXPOOL_ACCOUNT_QUEUE,
+	}
+
+	TxPoolPriceBumpFlag = cli.UintFlag{
+		Name:  "txpool-price-bump",
+		Usage: "Min gas price bump `<percent>` to evict a native transaction with the same payer and nonce in tx pool",
+		Value: config.DEFAULT_TXPOOL_PRICE_BUMP,
+	}
+
+	TxPoolNativeReplaceFlag = cli.BoolFlag{
+		Name:  "txpool-native-replace",
+		Usage: "Evict the native transaction with the same payer and nonce from local tx pool if the gas price is bumped, best effort only",
+	}
+
+	TxPoolJournalFlag = cli.BoolFlag{
+		Name:  "txpool-journal",
+		Usage: "Persist the transactions in tx pool to a journal under data dir, and re-submit them after restart",
+	}
+
+	TxPoolJournalMaxAgeFlag = cli.UintFlag{
+		Name:  "txpool-journal-max-age",
+		Usage: "Max `<hours>` to keep a transaction in tx pool journal, 0 means no limit",
+		Value: config.DEFAULT_TXPOOL_JOURNAL_MAX_AGE,
+	}
+
 	NonOptionFlag = cli.StringFlag{
 		Name:  "option",
 		Usage: "this command does not need option, please run directly",
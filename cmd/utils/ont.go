@@ -33,7 +33,6 @@ import (
 
 	"github.com/laizy/bigint"
 	"github.com/ontio/ontology-crypto/keypair"
-	sig "github.com/ontio/ontology-crypto/signature"
 	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/constants"
@@ -337,7 +336,7 @@ func SignTransaction(signer *account.Account, tx *types.MutableTransaction) erro
 		tx.Payer = signer.Address
 	}
 	txHash := tx.Hash()
-	sigData, err := Sign(txHash.ToArray(), signer)
+	sigData, err := types.SignTransaction(signer, tx)
 	if err != nil {
 		return fmt.Errorf("sign error:%s", err)
 	}
@@ -391,7 +390,7 @@ func MultiSigTransaction(mutTx *types.MutableTransaction, m uint16, pubKeys []ke
 	}
 
 	txHash := mutTx.Hash()
-	sigData, err := Sign(txHash.ToArray(), signer)
+	sigData, err := types.SignTransaction(signer, mutTx)
 	if err != nil {
 		return fmt.Errorf("sign error:%s", err)
 	}
@@ -455,17 +454,12 @@ func pubKeysEqual(pks1, pks2 []keypair.PublicKey) bool {
 	return true
 }
 
-//Sign sign return the signature to the data of private key
+//Sign sign return the raw signature to the data of private key, which can be verified by contract
 func Sign(data []byte, signer *account.Account) ([]byte, error) {
-	s, err := sig.Sign(signer.SigScheme, signer.PrivateKey, data, nil)
-	if err != nil {
-		return nil, err
-	}
-	sigData, err := sig.Serialize(s)
-	if err != nil {
-		return nil, fmt.Errorf("sig.Serialize error:%s", err)
+	if signer.IsRemote() {
+		return nil, fmt.Errorf("remote signer does not sign raw data")
 	}
-	return sigData, nil
+	return signature.SignWithPrivKey(signer.SigScheme, signer.PrivateKey, data)
 }
 
 //SendRawTransaction send a transaction to ontology network, and return hash of the transaction
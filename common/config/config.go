@@ -48,19 +48,23 @@ const (
 	DEFAULT_WALLET_FILE_NAME = "./wallet.dat"
 	MIN_GEN_BLOCK_TIME       = 2
 	DEFAULT_GEN_BLOCK_TIME   = 6
+	DEFAULT_DEV_ACCOUNTS     = 10
 	DBFT_MIN_NODE_NUM        = 4 //min node number of dbft consensus
 	SOLO_MIN_NODE_NUM        = 1 //min node number of solo consensus
 	VBFT_MIN_NODE_NUM        = 4 //min node number of vbft consensus
+	SBFT_MIN_NODE_NUM        = 4 //min node number of sbft consensus
 
 	CONSENSUS_TYPE_DBFT = "dbft"
 	CONSENSUS_TYPE_SOLO = "solo"
 	CONSENSUS_TYPE_VBFT = "vbft"
+	CONSENSUS_TYPE_SBFT = "sbft"
 
 	DEFAULT_LOG_LEVEL                       = log.InfoLog
 	DEFAULT_ETH_RPC_PORT                    = 20339
 	DEFAULT_NODE_PORT                       = 20338
 	DEFAULT_RPC_PORT                        = 20336
 	DEFAULT_RPC_LOCAL_PORT                  = 20337
+	DEFAULT_RPC_MAX_BATCH_SIZE              = 100
 	DEFAULT_GRAPHQL_PORT                    = 20333
 	DEFAULT_REST_PORT                       = 20334
 	DEFAULT_WS_PORT                         = 20335
@@ -69,11 +73,21 @@ const (
 	DEFAULT_MAX_CONN_OUT_BOUND              = 1024
 	DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP = 16
 	DEFAULT_HTTP_INFO_PORT                  = 0
+	DEFAULT_METRICS_PORT                    = 20340
+	DEFAULT_ETH_WS_PORT                     = 20341
+	DEFAULT_ETH_WS_MAX_SUBSCRIPTIONS        = 100
+	DEFAULT_TXPOOL_GLOBAL_SLOTS             = 100000
+	DEFAULT_TXPOOL_ACCOUNT_SLOTS            = 1000
+	DEFAULT_TXPOOL_GLOBAL_QUEUE             = 10000
+	DEFAULT_TXPOOL_ACCOUNT_QUEUE            = 100
+	DEFAULT_TXPOOL_PRICE_BUMP               = 10
+	DEFAULT_TXPOOL_JOURNAL_MAX_AGE          = 24
 	DEFAULT_MAX_TX_IN_BLOCK                 = 60000
 	DEFAULT_MAX_SYNC_HEADER                 = 500
 	DEFAULT_ENABLE_EVENT_LOG                = true
 	DEFAULT_CLI_RPC_PORT                    = uint(20000)
 	DEFUALT_CLI_RPC_ADDRESS                 = "127.0.0.1"
+	DEFAULT_REMOTE_SIGNER_ALLOW             = "block,crosschain,consensus,vrf"
 	DEFAULT_MIN_GAS_LIMIT                   = 20000
 	DEFAULT_GAS_PRICE                       = 500
 	DEFAULT_WASM_GAS_FACTOR                 = uint64(10)
@@ -281,6 +295,17 @@ func GetUint64WrappingHeight() uint32 {
 	}
 }
 
+func GetReportFaultyHeight() uint32 {
+	switch DefConfig.P2PNode.NetworkId {
+	case NETWORK_ID_MAIN_NET:
+		return constants.BLOCKHEIGHT_REPORT_FAULTY_MAINNET
+	case NETWORK_ID_POLARIS_NET:
+		return constants.BLOCKHEIGHT_REPORT_FAULTY_POLARIS
+	default:
+		return 0
+	}
+}
+
 // the end of unbound timestamp offset from genesis block's timestamp
 func GetGovUnboundDeadline() (uint32, uint64) {
 	count := uint64(0)
@@ -369,6 +394,7 @@ var PolarisConfig = &GenesisConfig{
 	},
 	DBFT: &DBFTConfig{},
 	SOLO: &SOLOConfig{},
+	SBFT: &SBFTConfig{},
 }
 
 var MainNetConfig = &GenesisConfig{
@@ -432,6 +458,7 @@ var MainNetConfig = &GenesisConfig{
 	},
 	DBFT: &DBFTConfig{},
 	SOLO: &SOLOConfig{},
+	SBFT: &SBFTConfig{},
 }
 
 var DefConfig = NewOntologyConfig()
@@ -442,6 +469,12 @@ type GenesisConfig struct {
 	VBFT          *VBFTConfig
 	DBFT          *DBFTConfig
 	SOLO          *SOLOConfig
+	SBFT          *SBFTConfig
+}
+
+//IsDevChain return whether the node runs the dev chain of solo consensus in test mode
+func (this *GenesisConfig) IsDevChain() bool {
+	return this.ConsensusType == CONSENSUS_TYPE_SOLO && this.SOLO != nil && this.SOLO.DevChain
 }
 
 func NewGenesisConfig() *GenesisConfig {
@@ -451,6 +484,7 @@ func NewGenesisConfig() *GenesisConfig {
 		VBFT:          &VBFTConfig{},
 		DBFT:          &DBFTConfig{},
 		SOLO:          &SOLOConfig{},
+		SBFT:          &SBFTConfig{},
 	}
 }
 
@@ -640,6 +674,14 @@ type DBFTConfig struct {
 type SOLOConfig struct {
 	GenBlockTime uint
 	Bookkeepers  []string
+	AutoMine     bool //mine a block once a transaction enters the tx pool, instead of on GenBlockTime
+	DevAccounts  uint //number of dev accounts funded at the first block
+	DevChain     bool //enable the dev chain methods of rpc servers, set by test mode
+}
+
+type SBFTConfig struct {
+	GenBlockTime uint
+	Bookkeepers  []string
 }
 
 type CommonConfig struct {
@@ -652,8 +694,25 @@ type CommonConfig struct {
 	DataDir        string
 	ETHTxGasLimit  uint64
 	//NGasLimit        uint64
-	WasmVerifyMethod VerifyMethod
-	TraceTxPool      bool
+	WasmVerifyMethod   VerifyMethod
+	TraceTxPool        bool
+	EnableStateHistory bool
+	EnableAddressIndex bool
+}
+
+//TxPoolConfig limits the verified transactions in tx pool, 0 means no limit. The executable txs
+//are limited by slots, and the eip155 txs waiting for a missing nonce are limited by queue
+type TxPoolConfig struct {
+	GlobalSlots  uint
+	AccountSlots uint
+	GlobalQueue  uint
+	AccountQueue uint
+	PriceBump    uint //min gas price bump in percent to evict a native tx with the same payer and nonce
+
+	EnableNativeReplace bool //evict the native tx with the same payer and nonce if the gas price is bumped, local only
+
+	EnableJournal bool //persist the accepted txs to re-submit them after restart
+	JournalMaxAge uint //hours to keep a tx in journal, 0 means no limit
 }
 
 type ConsensusConfig struct {
@@ -674,6 +733,7 @@ type P2PNodeConfig struct {
 	NetworkName               string
 	NodePort                  uint16
 	IsTLS                     bool
+	SecureTransport           bool //encrypt and authenticate links with the kad key id when peer supports it
 	CertPath                  string
 	KeyPath                   string
 	CAPath                    string
@@ -690,6 +750,14 @@ type RpcConfig struct {
 	HttpJsonPort      uint
 	HttpLocalPort     uint
 	EthJsonPort       uint
+	MaxBatchSize      uint //max requests in a batch, 0 means no limit
+	JsonRpc2          bool //respond json rpc 2.0 error objects instead of ontology error codes, and skip notifications
+
+	EnableEthWs           bool
+	EthWsPort             uint
+	EthWsOrigins          []string //allowed origins of eth websocket connections, "*" means any
+	EthWsMaxConnections   uint
+	EthWsMaxSubscriptions uint //max subscriptions of an eth websocket connection, 0 means no limit
 }
 
 type RestfulConfig struct {
@@ -713,15 +781,22 @@ type WebSocketConfig struct {
 	HttpKeyPath  string
 }
 
+type MetricsConfig struct {
+	EnableMetrics bool
+	MetricsPort   uint
+}
+
 type OntologyConfig struct {
 	Genesis   *GenesisConfig
 	Common    *CommonConfig
 	Consensus *ConsensusConfig
+	TxPool    *TxPoolConfig
 	P2PNode   *P2PNodeConfig
 	Rpc       *RpcConfig
 	Restful   *RestfulConfig
 	GraphQL   *GraphQLConfig
 	Ws        *WebSocketConfig
+	Metrics   *MetricsConfig
 }
 
 func NewOntologyConfig() *OntologyConfig {
@@ -740,6 +815,15 @@ func NewOntologyConfig() *OntologyConfig {
 			EnableConsensus: true,
 			MaxTxInBlock:    DEFAULT_MAX_TX_IN_BLOCK,
 		},
+		TxPool: &TxPoolConfig{
+			GlobalSlots:  DEFAULT_TXPOOL_GLOBAL_SLOTS,
+			AccountSlots: DEFAULT_TXPOOL_ACCOUNT_SLOTS,
+			GlobalQueue:  DEFAULT_TXPOOL_GLOBAL_QUEUE,
+			AccountQueue: DEFAULT_TXPOOL_ACCOUNT_QUEUE,
+			PriceBump:    DEFAULT_TXPOOL_PRICE_BUMP,
+
+			JournalMaxAge: DEFAULT_TXPOOL_JOURNAL_MAX_AGE,
+		},
 		P2PNode: &P2PNodeConfig{
 			ReservedCfg:               &P2PRsvConfig{},
 			ReservedPeersOnly:         false,
@@ -749,6 +833,7 @@ func NewOntologyConfig() *OntologyConfig {
 			EVMChainId:                GetEip155ChainID(NETWORK_ID_MAIN_NET),
 			NodePort:                  DEFAULT_NODE_PORT,
 			IsTLS:                     false,
+			SecureTransport:           false,
 			CertPath:                  "",
 			KeyPath:                   "",
 			CAPath:                    "",
@@ -762,6 +847,12 @@ func NewOntologyConfig() *OntologyConfig {
 			EnableHttpJsonRpc: true,
 			HttpJsonPort:      DEFAULT_RPC_PORT,
 			HttpLocalPort:     DEFAULT_RPC_LOCAL_PORT,
+			MaxBatchSize:      DEFAULT_RPC_MAX_BATCH_SIZE,
+
+			EthWsPort:             DEFAULT_ETH_WS_PORT,
+			EthWsOrigins:          []string{"*"},
+			EthWsMaxConnections:   DEFAULT_HTTP_MAX_CONN,
+			EthWsMaxSubscriptions: DEFAULT_ETH_WS_MAX_SUBSCRIPTIONS,
 		},
 		Restful: &RestfulConfig{
 			EnableHttpRestful: true,
@@ -775,6 +866,10 @@ func NewOntologyConfig() *OntologyConfig {
 			EnableHttpWs: true,
 			HttpWsPort:   DEFAULT_WS_PORT,
 		},
+		Metrics: &MetricsConfig{
+			EnableMetrics: false,
+			MetricsPort:   DEFAULT_METRICS_PORT,
+		},
 	}
 }
 
@@ -789,6 +884,8 @@ func (this *OntologyConfig) GetBookkeepers() ([]keypair.PublicKey, error) {
 		bookKeepers = this.Genesis.DBFT.Bookkeepers
 	case CONSENSUS_TYPE_SOLO:
 		bookKeepers = this.Genesis.SOLO.Bookkeepers
+	case CONSENSUS_TYPE_SBFT:
+		bookKeepers = this.Genesis.SBFT.Bookkeepers
 	default:
 		return nil, fmt.Errorf("Does not support %s consensus", this.Genesis.ConsensusType)
 	}
@@ -839,6 +936,8 @@ func (this *OntologyConfig) getDefNetworkIDFromGenesisConfig(genCfg *GenesisConf
 		configData, err = json.Marshal(genCfg.VBFT)
 	case CONSENSUS_TYPE_DBFT:
 		configData, err = json.Marshal(genCfg.DBFT)
+	case CONSENSUS_TYPE_SBFT:
+		configData, err = json.Marshal(genCfg.SBFT)
 	case CONSENSUS_TYPE_SOLO:
 		return NETWORK_ID_SOLO_NET, nil
 	default:
@@ -19,6 +19,7 @@
 package constants
 
 import (
+	"math"
 	"time"
 
 	"github.com/laizy/bigint"
@@ -120,6 +121,10 @@ const USER_FEE_SPLIT_OVERFLOW_POLARIS = 0
 
 const UINT64_WRAPPING_MAINNET = 17370000
 
+// report faulty consensus node height, not scheduled yet
+const BLOCKHEIGHT_REPORT_FAULTY_MAINNET = math.MaxUint32
+const BLOCKHEIGHT_REPORT_FAULTY_POLARIS = math.MaxUint32
+
 var (
 	BLOCKHEIGHT_ADD_DECIMALS_MAINNET = uint32(13920000)
 	BLOCKHEIGHT_ADD_DECIMALS_POLARIS = uint32(0)
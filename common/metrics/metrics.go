@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package metrics defines the prometheus metrics of node subsystems, which are registered to the default registry
+package metrics
+
+import (
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+//txnpool metrics
+var (
+	TxPoolPendingTxs = prom.NewGauge(prom.GaugeOpts{
+		Name: "ontology_txpool_pending_txs",
+		Help: "number of transactions under verification in txpool",
+	})
+
+	TxPoolTxs = prom.NewGauge(prom.GaugeOpts{
+		Name: "ontology_txpool_txs",
+		Help: "number of verified transactions in txpool",
+	})
+
+	TxPoolRejected = prom.NewCounterVec(prom.CounterOpts{
+		Name: "ontology_txpool_rejected_total",
+		Help: "number of transactions rejected by txpool",
+	}, []string{"reason"})
+
+	TxPoolEvicted = prom.NewCounter(prom.CounterOpts{
+		Name: "ontology_txpool_evicted_total",
+		Help: "number of verified transactions evicted by the ones with higher gas price when txpool is full",
+	})
+)
+
+//consensus metrics
+var (
+	ConsensusRounds = prom.NewCounter(prom.CounterOpts{
+		Name: "ontology_consensus_rounds_total",
+		Help: "number of consensus rounds started",
+	})
+
+	ConsensusRoundDuration = prom.NewHistogram(prom.HistogramOpts{
+		Name:    "ontology_consensus_round_duration_seconds",
+		Help:    "duration of consensus rounds",
+		Buckets: prom.ExponentialBuckets(0.05, 2, 10),
+	})
+
+	ConsensusProposalLatency = prom.NewHistogram(prom.HistogramOpts{
+		Name:    "ontology_consensus_proposal_latency_seconds",
+		Help:    "latency from the start of round to the proposal of the round received",
+		Buckets: prom.ExponentialBuckets(0.01, 2, 12),
+	})
+
+	ConsensusView = prom.NewGauge(prom.GaugeOpts{
+		Name: "ontology_consensus_view",
+		Help: "view of the current consensus chain config",
+	})
+
+	ConsensusTimeouts = prom.NewCounterVec(prom.CounterOpts{
+		Name: "ontology_consensus_timeouts_total",
+		Help: "number of consensus timeouts by event",
+	}, []string{"event"})
+
+	ConsensusProposals = prom.NewCounterVec(prom.CounterOpts{
+		Name: "ontology_consensus_proposals_total",
+		Help: "number of block proposals made by this node",
+	}, []string{"empty"})
+
+	ConsensusFaulty = prom.NewCounterVec(prom.CounterOpts{
+		Name: "ontology_consensus_faulty_total",
+		Help: "number of equivocations of consensus peers detected by type",
+	}, []string{"type"})
+)
+
+//ledger and vm metrics
+var (
+	LedgerHeight = prom.NewGauge(prom.GaugeOpts{
+		Name: "ontology_ledger_height",
+		Help: "height of the latest block saved to ledger",
+	})
+
+	LedgerBlockExecuteDuration = prom.NewHistogram(prom.HistogramOpts{
+		Name:    "ontology_ledger_block_execute_seconds",
+		Help:    "time of executing the transactions of block",
+		Buckets: prom.ExponentialBuckets(0.005, 2, 12),
+	})
+
+	LedgerBlockCommitDuration = prom.NewHistogram(prom.HistogramOpts{
+		Name:    "ontology_ledger_block_commit_seconds",
+		Help:    "time of saving the executed block to stores",
+		Buckets: prom.ExponentialBuckets(0.005, 2, 12),
+	})
+
+	VmTxs = prom.NewCounterVec(prom.CounterOpts{
+		Name: "ontology_vm_txs_total",
+		Help: "number of transactions executed by vm and state",
+	}, []string{"vm", "state"})
+
+	VmGasUsed = prom.NewCounterVec(prom.CounterOpts{
+		Name: "ontology_vm_gas_used_total",
+		Help: "gas used by transactions of vm",
+	}, []string{"vm"})
+)
+
+//p2p metrics
+var (
+	P2PMessages = prom.NewCounterVec(prom.CounterOpts{
+		Name: "ontology_p2p_messages_total",
+		Help: "number of p2p messages by type and direction",
+	}, []string{"type", "direction"})
+
+	P2PMessageBytes = prom.NewCounterVec(prom.CounterOpts{
+		Name: "ontology_p2p_message_bytes_total",
+		Help: "bytes of p2p messages by type and direction",
+	}, []string{"type", "direction"})
+)
+
+const (
+	DIRECTION_IN  = "in"
+	DIRECTION_OUT = "out"
+)
+
+func init() {
+	prom.MustRegister(
+		TxPoolPendingTxs, TxPoolTxs, TxPoolRejected, TxPoolEvicted,
+		ConsensusRounds, ConsensusRoundDuration, ConsensusProposalLatency, ConsensusView, ConsensusTimeouts, ConsensusProposals,
+		ConsensusFaulty,
+		LedgerHeight, LedgerBlockExecuteDuration, LedgerBlockCommitDuration, VmTxs, VmGasUsed,
+		P2PMessages, P2PMessageBytes,
+	)
+}
+
+//ObserveSince observe the seconds elapsed since start
+func ObserveSince(observer prom.Observer, start time.Time) {
+	observer.Observe(time.Since(start).Seconds())
+}
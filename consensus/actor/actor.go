@@ -44,6 +44,10 @@ func (self *TxPoolActor) GetTxnPool(byCount bool, height uint32) []*txpool.Verif
 	return txs
 }
 
+func (self *TxPoolActor) AppendTransaction(tx *types.Transaction) {
+	self.Pool.Tell(&txpool.AppendTxReq{Sender: txpool.ConsensusSender, Tx: tx})
+}
+
 func (self *TxPoolActor) VerifyBlock(txs []*types.Transaction, height uint32) error {
 	poolmsg := &txpool.VerifyBlockReq{Txs: txs, Height: height}
 	future := self.Pool.RequestFuture(poolmsg, time.Second*10)
@@ -28,3 +28,28 @@ type TimeOut struct{}
 type BlockCompleted struct {
 	Block *types.Block
 }
+
+//dev chain controls handled by solo consensus, replied with DevChainRsp
+type DevMine struct {
+	Blocks uint32
+}
+type DevSnapshot struct{}
+type DevRevert struct {
+	Id uint32
+}
+type DevIncreaseTime struct {
+	Seconds uint64
+}
+type DevSetNextBlockTimestamp struct {
+	Timestamp uint32
+}
+type DevChainRsp struct {
+	Result uint64
+	Err    error
+}
+
+//diagnosis snapshot of consensus internal state, replied with ConsensusStatusRsp
+type GetConsensusStatus struct{}
+type ConsensusStatusRsp struct {
+	Status interface{}
+}
@@ -483,7 +483,7 @@ func (ds *DbftService) PrepareRequestReceived(payload *p2pmsg.ConsensusPayload,
 		return
 	}
 
-	sig, err := signature.Sign(ds.Account, blockHash[:])
+	sig, err := types.SignHeader(ds.Account, ds.context.MakeHeader().Header)
 	if err != nil {
 		log.Error("[DbftService] signing failed")
 		return
@@ -601,7 +601,7 @@ func (ds *DbftService) RequestChangeView() {
 func (ds *DbftService) SignAndRelay(payload *p2pmsg.ConsensusPayload) {
 	sink := common.NewZeroCopySink(nil)
 	payload.SerializationUnsigned(sink)
-	payload.Signature, _ = signature.Sign(ds.Account, sink.Bytes())
+	payload.Signature, _ = signature.SignPayload(ds.Account, signature.PAYLOAD_CONSENSUS, sink.Bytes())
 
 	msg := msgpack.NewConsensus(payload)
 	ds.p2p.Broadcast(msg)
@@ -693,8 +693,7 @@ func (ds *DbftService) Timeout() {
 			ds.context.header = nil
 			//build block and sign
 			block := ds.context.MakeHeader()
-			blockHash := block.Hash()
-			ds.context.Signatures[ds.context.BookkeeperIndex], _ = signature.Sign(ds.Account, blockHash[:])
+			ds.context.Signatures[ds.context.BookkeeperIndex], _ = types.SignHeader(ds.Account, block.Header)
 		}
 		payload := ds.context.MakePrepareRequest()
 		ds.SignAndRelay(payload)
@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sbft
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	actorTypes "github.com/ontio/ontology/consensus/actor"
+	"github.com/ontio/ontology/core/ledger"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/validator/increment"
+)
+
+//max time of block timestamp ahead of local time
+const maxTimestampAhead = 10 * time.Minute
+
+//ledgerChain proposes the blocks with the transactions of txpool, and persists the committed blocks to ledger
+type ledgerChain struct {
+	ledger         *ledger.Ledger
+	poolActor      *actorTypes.TxPoolActor
+	incrValidator  *increment.IncrementValidator
+	nextBookkeeper common.Address
+}
+
+func (self *ledgerChain) CurrentHeader() (*types.Header, error) {
+	return self.ledger.GetHeaderByHash(self.ledger.GetCurrentBlockHash())
+}
+
+//validHeight returns the start height of transaction verifying with the increment validator
+func (self *ledgerChain) validHeight(height uint32) uint32 {
+	start, end := self.incrValidator.BlockRange()
+	if height+1 == end {
+		return start
+	}
+	self.incrValidator.Clean()
+	log.Infof("increment validator block height %v != ledger block height %v", int(end)-1, height)
+	return height
+}
+
+func (self *ledgerChain) MakeBlock(timestamp uint32) (*types.Block, error) {
+	prevHash := self.ledger.GetCurrentBlockHash()
+	height := self.ledger.GetCurrentBlockHeight()
+	validHeight := self.validHeight(height)
+
+	txs := self.poolActor.GetTxnPool(true, validHeight)
+	transactions := make([]*types.Transaction, 0, len(txs))
+	nonceCtx := make(map[common.Address]uint64)
+	for _, txEntry := range txs {
+		if err := self.incrValidator.Verify(txEntry.Tx, validHeight, nonceCtx); err != nil {
+			log.Errorf("increment verify failed: %s", err)
+			continue
+		}
+		transactions = append(transactions, txEntry.Tx)
+	}
+
+	txHash := make([]common.Uint256, 0, len(transactions))
+	for _, t := range transactions {
+		txHash = append(txHash, t.Hash())
+	}
+	txRoot := common.ComputeMerkleRoot(txHash)
+	blockRoot := self.ledger.GetBlockRootWithNewTxRoots(height+1, []common.Uint256{txRoot})
+	header := &types.Header{
+		Version:          ContextVersion,
+		PrevBlockHash:    prevHash,
+		TransactionsRoot: txRoot,
+		BlockRoot:        blockRoot,
+		Timestamp:        timestamp,
+		Height:           height + 1,
+		ConsensusData:    common.GetNonce(),
+		NextBookkeeper:   self.nextBookkeeper,
+	}
+	return &types.Block{
+		Header:       header,
+		Transactions: transactions,
+	}, nil
+}
+
+func (self *ledgerChain) VerifyBlock(block *types.Block) error {
+	header := block.Header
+	if header.Timestamp > uint32(time.Now().Add(maxTimestampAhead).Unix()) {
+		return fmt.Errorf("block timestamp %d too far in the future", header.Timestamp)
+	}
+	if header.NextBookkeeper != self.nextBookkeeper {
+		return fmt.Errorf("unmatched next bookkeeper %s", header.NextBookkeeper.ToBase58())
+	}
+	txHash := make([]common.Uint256, 0, len(block.Transactions))
+	for _, t := range block.Transactions {
+		txHash = append(txHash, t.Hash())
+	}
+	txRoot := common.ComputeMerkleRoot(txHash)
+	if txRoot != header.TransactionsRoot {
+		return fmt.Errorf("unmatched transactions root")
+	}
+	if self.ledger.GetBlockRootWithNewTxRoots(header.Height, []common.Uint256{txRoot}) != header.BlockRoot {
+		return fmt.Errorf("unmatched block root")
+	}
+	if len(block.Transactions) == 0 {
+		return nil
+	}
+
+	validHeight := self.validHeight(header.Height - 1)
+	if err := self.poolActor.VerifyBlock(block.Transactions, validHeight); err != nil {
+		return fmt.Errorf("transaction verification failed: %s", err)
+	}
+	nonceCtx := make(map[common.Address]uint64)
+	for _, tx := range block.Transactions {
+		if err := self.incrValidator.Verify(tx, validHeight, nonceCtx); err != nil {
+			return fmt.Errorf("transaction increment verification failed: %s", err)
+		}
+	}
+	return nil
+}
+
+func (self *ledgerChain) CommitBlock(block *types.Block) error {
+	result, err := self.ledger.ExecuteBlock(block)
+	if err != nil {
+		return fmt.Errorf("execute block %d error: %s", block.Header.Height, err)
+	}
+	return self.ledger.SubmitBlock(block, nil, result)
+}
@@ -0,0 +1,700 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sbft
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	msgpack "github.com/ontio/ontology/p2pserver/message/msg_pack"
+	p2pmsg "github.com/ontio/ontology/p2pserver/message/types"
+	p2p "github.com/ontio/ontology/p2pserver/net/protocol"
+)
+
+const (
+	ContextVersion uint32 = 0
+
+	maxProofs        = 1024 //max proofs carried in one msg, bounded by the number of validators
+	maxFutureHeights = 10   //msgs of the heights at most ahead of current height are buffered
+	maxFutureMsgs    = 1024 //max buffered msgs of future heights
+	maxFutureViews   = 16   //votes of the views at most ahead of current view are kept
+	maxBlocks        = 16   //max proposed blocks kept in one height
+	maxTimeoutShift  = 6    //view timeout doubles with view, up to 64 times
+	msgChanSize      = 1024
+)
+
+//Chain provides the blocks to propose, and persists the blocks committed by validators
+type Chain interface {
+	//CurrentHeader returns the header of the latest persisted block
+	CurrentHeader() (*types.Header, error)
+	//MakeBlock makes an unsigned block on top of the latest block
+	MakeBlock(timestamp uint32) (*types.Block, error)
+	//VerifyBlock verifies the block proposed on top of the latest block
+	VerifyBlock(block *types.Block) error
+	//CommitBlock persists the block sealed with the signatures of validators
+	CommitBlock(block *types.Block) error
+}
+
+//preparedCert proves that a quorum of validators accepted the block in the view
+type preparedCert struct {
+	view   uint32
+	block  *types.Block
+	proofs [][]byte
+}
+
+type viewChange struct {
+	raw []byte
+	msg *sbftMsg
+}
+
+/*
+*engine runs the three-phase consensus of pbft for each height. The primary of view 0 proposes
+*the block in pre-prepare, validators accept it by prepare, and commit it with the signature of
+*block hash after a quorum of prepares. The block is persisted with a quorum of commit signatures.
+*If no block committed before timeout, validators change view with their prepared blocks, and the
+*primary of new view re-proposes the highest prepared block with the quorum of view changes.
+ */
+type engine struct {
+	account     *account.Account
+	validators  []keypair.PublicKey
+	index       int //index of account in validators, -1 if not a validator
+	chain       Chain
+	p2p         p2p.P2P
+	blockTime   time.Duration
+	viewTimeout time.Duration
+
+	msgC   chan *p2pmsg.ConsensusPayload
+	blockC chan struct{}
+	quitC  chan struct{}
+	quitWg sync.WaitGroup
+
+	//round state, only accessed in the loop
+	height        uint32
+	prevHash      common.Uint256
+	prevTimestamp uint32
+	view          uint32
+	viewChanging  bool
+	newViewSent   uint32
+	proposal      *types.Block
+	prepared      *preparedCert
+	blocks        map[common.Uint256]*types.Block
+	prepares      map[uint32]map[common.Uint256]map[uint16][]byte
+	commits       map[uint32]map[common.Uint256]map[uint16][]byte
+	viewChanges   map[uint32]map[uint16]*viewChange
+	future        []*p2pmsg.ConsensusPayload
+	proposeTimer  *time.Timer
+	viewTimer     *time.Timer
+}
+
+func newEngine(acc *account.Account, validators []keypair.PublicKey, chain Chain, net p2p.P2P,
+	blockTime, viewTimeout time.Duration) *engine {
+	index := -1
+	for i, pubkey := range validators {
+		if samePubKey(pubkey, acc.PublicKey) {
+			index = i
+			break
+		}
+	}
+	return &engine{
+		account:      acc,
+		validators:   validators,
+		index:        index,
+		chain:        chain,
+		p2p:          net,
+		blockTime:    blockTime,
+		viewTimeout:  viewTimeout,
+		msgC:         make(chan *p2pmsg.ConsensusPayload, msgChanSize),
+		blockC:       make(chan struct{}, 1),
+		proposeTimer: newStoppedTimer(),
+		viewTimer:    newStoppedTimer(),
+	}
+}
+
+func (self *engine) start() error {
+	if self.index < 0 {
+		return fmt.Errorf("account %s is not a sbft validator", self.account.Address.ToBase58())
+	}
+	if self.quitC != nil {
+		return nil
+	}
+	self.quitC = make(chan struct{})
+	self.quitWg.Add(1)
+	go self.loop(self.quitC)
+	return nil
+}
+
+func (self *engine) stop() {
+	if self.quitC == nil {
+		return
+	}
+	close(self.quitC)
+	self.quitWg.Wait()
+	self.quitC = nil
+}
+
+//onPayload receives the consensus payload from network
+func (self *engine) onPayload(payload *p2pmsg.ConsensusPayload) {
+	select {
+	case self.msgC <- payload:
+	default:
+		log.Warnf("sbft msg channel full, drop msg of height %d from %d", payload.Height, payload.BookkeeperIndex)
+	}
+}
+
+//onBlockPersisted notifies the engine of the block saved to chain, such as the block synced from network
+func (self *engine) onBlockPersisted() {
+	select {
+	case self.blockC <- struct{}{}:
+	default:
+	}
+}
+
+func (self *engine) loop(quitC chan struct{}) {
+	defer self.quitWg.Done()
+	self.startHeight()
+	for {
+		select {
+		case payload := <-self.msgC:
+			self.handlePayload(payload)
+		case <-self.blockC:
+			self.checkHeight()
+		case <-self.proposeTimer.C:
+			self.propose()
+		case <-self.viewTimer.C:
+			self.onViewTimeout()
+		case <-quitC:
+			stopTimer(self.proposeTimer)
+			stopTimer(self.viewTimer)
+			return
+		}
+	}
+}
+
+func (self *engine) faulty() int {
+	return (len(self.validators) - 1) / 3
+}
+
+func (self *engine) quorum() int {
+	return len(self.validators) - self.faulty()
+}
+
+func (self *engine) primary(view uint32) int {
+	return int((uint64(self.height) + uint64(view)) % uint64(len(self.validators)))
+}
+
+func (self *engine) isPrimary(view uint32) bool {
+	return self.primary(view) == self.index
+}
+
+func (self *engine) roundTimeout(view uint32) time.Duration {
+	shift := view
+	if shift > maxTimeoutShift {
+		shift = maxTimeoutShift
+	}
+	return self.blockTime + self.viewTimeout<<shift
+}
+
+func (self *engine) nextTimestamp() uint32 {
+	now := uint32(time.Now().Unix())
+	if now <= self.prevTimestamp {
+		now = self.prevTimestamp + 1
+	}
+	return now
+}
+
+func (self *engine) checkHeight() {
+	header, err := self.chain.CurrentHeader()
+	if err != nil {
+		log.Errorf("sbft get current header: %s", err)
+		return
+	}
+	if header.Height >= self.height {
+		self.startHeight()
+	}
+}
+
+//startHeight starts the consensus of the block next to the latest one
+func (self *engine) startHeight() {
+	header, err := self.chain.CurrentHeader()
+	if err != nil {
+		log.Errorf("sbft get current header: %s", err)
+		return
+	}
+	self.height = header.Height + 1
+	self.prevHash = header.Hash()
+	self.prevTimestamp = header.Timestamp
+	self.newViewSent = 0
+	self.prepared = nil
+	self.blocks = make(map[common.Uint256]*types.Block)
+	self.prepares = make(map[uint32]map[common.Uint256]map[uint16][]byte)
+	self.commits = make(map[uint32]map[common.Uint256]map[uint16][]byte)
+	self.viewChanges = make(map[uint32]map[uint16]*viewChange)
+	self.enterView(0)
+	log.Debugf("sbft server %d start height %d", self.index, self.height)
+
+	future := self.future
+	self.future = nil
+	for _, payload := range future {
+		self.handlePayload(payload)
+	}
+}
+
+func (self *engine) enterView(view uint32) {
+	self.view = view
+	self.viewChanging = false
+	self.proposal = nil
+	stopTimer(self.proposeTimer)
+	if view == 0 && self.isPrimary(view) {
+		resetTimer(self.proposeTimer, self.blockTime)
+	}
+	resetTimer(self.viewTimer, self.roundTimeout(view))
+}
+
+func (self *engine) propose() {
+	if self.view != 0 || self.viewChanging || self.proposal != nil || !self.isPrimary(0) {
+		return
+	}
+	block, err := self.chain.MakeBlock(self.nextTimestamp())
+	if err != nil {
+		log.Errorf("sbft make block %d: %s", self.height, err)
+		return
+	}
+	log.Infof("sbft server %d propose block %d, txs %d", self.index, self.height, len(block.Transactions))
+	self.broadcast(&sbftMsg{Type: prePrepareMsg, View: 0, BlockHash: block.Hash(), Block: block})
+}
+
+func (self *engine) onViewTimeout() {
+	if self.height == 0 {
+		self.startHeight()
+		return
+	}
+	self.startViewChange(self.view + 1)
+}
+
+func (self *engine) startViewChange(view uint32) {
+	if view <= self.view {
+		return
+	}
+	log.Infof("sbft server %d change view to %d at height %d", self.index, view, self.height)
+	self.view = view
+	self.viewChanging = true
+	self.proposal = nil
+	stopTimer(self.proposeTimer)
+	resetTimer(self.viewTimer, self.roundTimeout(view))
+
+	msg := &sbftMsg{Type: viewChangeMsg, View: view}
+	if self.prepared != nil {
+		msg.PreparedView = self.prepared.view
+		msg.BlockHash = self.prepared.block.Hash()
+		msg.Block = self.prepared.block
+		msg.Proofs = self.prepared.proofs
+	}
+	self.broadcast(msg)
+}
+
+//broadcast signs the msg into consensus payload, sends it to peers and handles it locally
+func (self *engine) broadcast(msg *sbftMsg) {
+	payload := &p2pmsg.ConsensusPayload{
+		Version:         ContextVersion,
+		PrevHash:        self.prevHash,
+		Height:          self.height,
+		BookkeeperIndex: uint16(self.index),
+		Timestamp:       uint32(time.Now().Unix()),
+		Data:            common.SerializeToBytes(msg),
+		Owner:           self.account.PublicKey,
+	}
+	sink := common.NewZeroCopySink(nil)
+	payload.SerializationUnsigned(sink)
+	sig, err := signature.SignPayload(self.account, signature.PAYLOAD_CONSENSUS, sink.Bytes())
+	if err != nil {
+		log.Errorf("sbft sign %s msg: %s", msg.Type, err)
+		return
+	}
+	payload.Signature = sig
+	self.p2p.Broadcast(msgpack.NewConsensus(payload))
+	self.handlePayload(payload)
+}
+
+func (self *engine) handlePayload(payload *p2pmsg.ConsensusPayload) {
+	if payload.Height < self.height {
+		return
+	}
+	if payload.Height > self.height {
+		if payload.Height <= self.height+maxFutureHeights && len(self.future) < maxFutureMsgs {
+			self.future = append(self.future, payload)
+		}
+		return
+	}
+	if payload.PrevHash != self.prevHash {
+		return
+	}
+	msg, err := self.verifyPayload(payload)
+	if err != nil {
+		log.Debugf("sbft invalid msg of height %d from %d: %s", payload.Height, payload.BookkeeperIndex, err)
+		return
+	}
+	if msg.View > self.view+maxFutureViews {
+		return
+	}
+
+	idx := payload.BookkeeperIndex
+	switch msg.Type {
+	case prePrepareMsg:
+		self.onPrePrepare(idx, msg)
+	case prepareMsg:
+		self.onPrepare(idx, msg, payload)
+	case commitMsg:
+		self.onCommit(idx, msg)
+	case viewChangeMsg:
+		self.onViewChange(idx, msg, payload)
+	case newViewMsg:
+		self.onNewView(idx, msg)
+	}
+}
+
+//verifyPayload checks the payload is signed by the validator, and decodes the msg from it
+func (self *engine) verifyPayload(payload *p2pmsg.ConsensusPayload) (*sbftMsg, error) {
+	idx := int(payload.BookkeeperIndex)
+	if idx >= len(self.validators) {
+		return nil, fmt.Errorf("invalid validator index %d", idx)
+	}
+	if payload.Owner == nil || !samePubKey(payload.Owner, self.validators[idx]) {
+		return nil, fmt.Errorf("owner is not validator %d", idx)
+	}
+	if err := payload.Verify(); err != nil {
+		return nil, err
+	}
+	msg := new(sbftMsg)
+	if err := msg.Deserialization(common.NewZeroCopySource(payload.Data)); err != nil {
+		return nil, fmt.Errorf("deserialize msg: %s", err)
+	}
+	return msg, nil
+}
+
+func (self *engine) onPrePrepare(idx uint16, msg *sbftMsg) {
+	if msg.View != 0 || int(idx) != self.primary(0) {
+		return
+	}
+	if self.view != 0 || self.viewChanging || self.proposal != nil {
+		// keep the block for committing
+		if err := self.storeBlock(msg.Block, msg.BlockHash); err == nil {
+			self.checkCommittedBlock(msg.BlockHash)
+		}
+		return
+	}
+	self.acceptProposal(msg.Block, msg.BlockHash)
+}
+
+//acceptProposal accepts the block proposed by the primary of current view
+func (self *engine) acceptProposal(block *types.Block, hash common.Uint256) {
+	if err := self.storeBlock(block, hash); err != nil {
+		log.Warnf("sbft server %d reject block %d in view %d: %s", self.index, self.height, self.view, err)
+		return
+	}
+	if self.checkCommittedBlock(hash) {
+		return
+	}
+	self.proposal = block
+	self.broadcast(&sbftMsg{Type: prepareMsg, View: self.view, BlockHash: hash})
+	self.checkPrepared()
+}
+
+func (self *engine) storeBlock(block *types.Block, hash common.Uint256) error {
+	if _, present := self.blocks[hash]; present {
+		return nil
+	}
+	if len(self.blocks) >= maxBlocks {
+		return fmt.Errorf("too many blocks proposed")
+	}
+	if block == nil || block.Hash() != hash {
+		return fmt.Errorf("block mismatch with hash %s", hash.ToHexString())
+	}
+	header := block.Header
+	if header.Height != self.height || header.PrevBlockHash != self.prevHash || header.Timestamp <= self.prevTimestamp {
+		return fmt.Errorf("block header mismatch with previous block")
+	}
+	if err := self.chain.VerifyBlock(block); err != nil {
+		return err
+	}
+	self.blocks[hash] = block
+	return nil
+}
+
+func (self *engine) onPrepare(idx uint16, msg *sbftMsg, payload *p2pmsg.ConsensusPayload) {
+	votes := self.prepares[msg.View]
+	if votes == nil {
+		votes = make(map[common.Uint256]map[uint16][]byte)
+		self.prepares[msg.View] = votes
+	}
+	if hasVoted(votes, idx) {
+		return
+	}
+	if votes[msg.BlockHash] == nil {
+		votes[msg.BlockHash] = make(map[uint16][]byte)
+	}
+	votes[msg.BlockHash][idx] = payload.ToArray()
+	self.checkPrepared()
+}
+
+//checkPrepared commits the proposal after a quorum of validators accepted it
+func (self *engine) checkPrepared() {
+	if self.proposal == nil || self.viewChanging {
+		return
+	}
+	if self.prepared != nil && self.prepared.view == self.view {
+		return
+	}
+	hash := self.proposal.Hash()
+	votes := self.prepares[self.view][hash]
+	if len(votes) < self.quorum() {
+		return
+	}
+	proofs := make([][]byte, 0, len(votes))
+	for _, raw := range votes {
+		proofs = append(proofs, raw)
+	}
+	self.prepared = &preparedCert{view: self.view, block: self.proposal, proofs: proofs}
+
+	sig, err := types.SignHeader(self.account, self.proposal.Header)
+	if err != nil {
+		log.Errorf("sbft sign block %d: %s", self.height, err)
+		return
+	}
+	self.broadcast(&sbftMsg{Type: commitMsg, View: self.view, BlockHash: hash, Signature: sig})
+}
+
+func (self *engine) onCommit(idx uint16, msg *sbftMsg) {
+	if err := signature.Verify(self.validators[idx], msg.BlockHash[:], msg.Signature); err != nil {
+		log.Debugf("sbft invalid commit signature from %d: %s", idx, err)
+		return
+	}
+	votes := self.commits[msg.View]
+	if votes == nil {
+		votes = make(map[common.Uint256]map[uint16][]byte)
+		self.commits[msg.View] = votes
+	}
+	if hasVoted(votes, idx) {
+		return
+	}
+	if votes[msg.BlockHash] == nil {
+		votes[msg.BlockHash] = make(map[uint16][]byte)
+	}
+	votes[msg.BlockHash][idx] = msg.Signature
+	self.checkCommitted(msg.View, msg.BlockHash)
+}
+
+func (self *engine) checkCommittedBlock(hash common.Uint256) bool {
+	for view := range self.commits {
+		if self.checkCommitted(view, hash) {
+			return true
+		}
+	}
+	return false
+}
+
+//checkCommitted persists the block after a quorum of validators committed it in the view
+func (self *engine) checkCommitted(view uint32, hash common.Uint256) bool {
+	votes := self.commits[view][hash]
+	if len(votes) < self.quorum() {
+		return false
+	}
+	block, present := self.blocks[hash]
+	if !present {
+		log.Warnf("sbft server %d block %d %s committed but not received", self.index, self.height, hash.ToHexString())
+		return false
+	}
+	sigs := make([][]byte, 0, len(votes))
+	for _, sig := range votes {
+		sigs = append(sigs, sig)
+	}
+	block.Header.Bookkeepers = self.validators
+	block.Header.SigData = sigs
+	if err := self.chain.CommitBlock(block); err != nil {
+		log.Errorf("sbft commit block %d: %s", self.height, err)
+		return false
+	}
+	log.Infof("sbft server %d committed block %d %s in view %d", self.index, self.height, hash.ToHexString(), view)
+	self.startHeight()
+	return true
+}
+
+func (self *engine) onViewChange(idx uint16, msg *sbftMsg, payload *p2pmsg.ConsensusPayload) {
+	if msg.View < self.view {
+		return
+	}
+	if err := self.verifyViewChange(msg); err != nil {
+		log.Debugf("sbft invalid view change from %d: %s", idx, err)
+		return
+	}
+	changes := self.viewChanges[msg.View]
+	if changes == nil {
+		changes = make(map[uint16]*viewChange)
+		self.viewChanges[msg.View] = changes
+	}
+	if _, present := changes[idx]; present {
+		return
+	}
+	changes[idx] = &viewChange{raw: payload.ToArray(), msg: msg}
+
+	// at least one honest validator has timed out
+	if len(changes) > self.faulty() {
+		self.startViewChange(msg.View)
+	}
+	self.checkNewView(msg.View)
+}
+
+//verifyViewChange checks the prepared block of view change is proved by a quorum of prepares
+func (self *engine) verifyViewChange(msg *sbftMsg) error {
+	if msg.Block == nil {
+		return nil
+	}
+	if msg.PreparedView >= msg.View || msg.Block.Hash() != msg.BlockHash {
+		return fmt.Errorf("invalid prepared block")
+	}
+	_, err := self.verifyProofs(msg.Proofs, prepareMsg, msg.PreparedView, func(prepare *sbftMsg) bool {
+		return prepare.BlockHash == msg.BlockHash
+	})
+	return err
+}
+
+//verifyProofs checks the proofs are the msgs of current height signed by a quorum of validators
+func (self *engine) verifyProofs(proofs [][]byte, t msgType, view uint32, check func(*sbftMsg) bool) (map[uint16]*sbftMsg, error) {
+	msgs := make(map[uint16]*sbftMsg)
+	for _, raw := range proofs {
+		payload := new(p2pmsg.ConsensusPayload)
+		if err := payload.Deserialization(common.NewZeroCopySource(raw)); err != nil {
+			return nil, fmt.Errorf("deserialize proof: %s", err)
+		}
+		if payload.Height != self.height || payload.PrevHash != self.prevHash {
+			return nil, fmt.Errorf("proof of other height")
+		}
+		msg, err := self.verifyPayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		if msg.Type != t || msg.View != view || !check(msg) {
+			return nil, fmt.Errorf("unexpected %s proof of view %d", msg.Type, msg.View)
+		}
+		msgs[payload.BookkeeperIndex] = msg
+	}
+	if len(msgs) < self.quorum() {
+		return nil, fmt.Errorf("%s proofs %d less than quorum %d", t, len(msgs), self.quorum())
+	}
+	return msgs, nil
+}
+
+//checkNewView proposes the block of new view after a quorum of view changes
+func (self *engine) checkNewView(view uint32) {
+	if view != self.view || !self.viewChanging || !self.isPrimary(view) || self.newViewSent == view {
+		return
+	}
+	changes := self.viewChanges[view]
+	if len(changes) < self.quorum() {
+		return
+	}
+	proofs := make([][]byte, 0, len(changes))
+	var highest *sbftMsg
+	for _, change := range changes {
+		proofs = append(proofs, change.raw)
+		if change.msg.Block != nil && (highest == nil || change.msg.PreparedView > highest.PreparedView) {
+			highest = change.msg
+		}
+	}
+	var block *types.Block
+	if highest != nil {
+		block = highest.Block
+	} else {
+		var err error
+		block, err = self.chain.MakeBlock(self.nextTimestamp())
+		if err != nil {
+			log.Errorf("sbft make block %d: %s", self.height, err)
+			return
+		}
+	}
+	self.newViewSent = view
+	log.Infof("sbft server %d propose block %d in new view %d", self.index, self.height, view)
+	self.broadcast(&sbftMsg{Type: newViewMsg, View: view, BlockHash: block.Hash(), Block: block, Proofs: proofs})
+}
+
+func (self *engine) onNewView(idx uint16, msg *sbftMsg) {
+	if msg.View < self.view || (msg.View == self.view && !self.viewChanging) || int(idx) != self.primary(msg.View) {
+		return
+	}
+	changes, err := self.verifyProofs(msg.Proofs, viewChangeMsg, msg.View, func(change *sbftMsg) bool {
+		return self.verifyViewChange(change) == nil
+	})
+	if err != nil {
+		log.Warnf("sbft invalid new view %d from %d: %s", msg.View, idx, err)
+		return
+	}
+	var highest *sbftMsg
+	for _, change := range changes {
+		if change.Block != nil && (highest == nil || change.PreparedView > highest.PreparedView) {
+			highest = change
+		}
+	}
+	if highest != nil && highest.BlockHash != msg.BlockHash {
+		log.Warnf("sbft new view %d from %d does not propose the prepared block", msg.View, idx)
+		return
+	}
+	self.enterView(msg.View)
+	self.acceptProposal(msg.Block, msg.BlockHash)
+}
+
+//hasVoted checks whether the validator has voted for any block in the view
+func hasVoted(votes map[common.Uint256]map[uint16][]byte, idx uint16) bool {
+	for _, validators := range votes {
+		if _, present := validators[idx]; present {
+			return true
+		}
+	}
+	return false
+}
+
+func samePubKey(a, b keypair.PublicKey) bool {
+	return bytes.Equal(keypair.SerializePublicKey(a), keypair.SerializePublicKey(b))
+}
+
+func newStoppedTimer() *time.Timer {
+	timer := time.NewTimer(time.Hour)
+	stopTimer(timer)
+	return timer
+}
+
+func stopTimer(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
+
+func resetTimer(timer *time.Timer, d time.Duration) {
+	stopTimer(timer)
+	timer.Reset(d)
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sbft
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	p2pComm "github.com/ontio/ontology/p2pserver/common"
+	msgTypes "github.com/ontio/ontology/p2pserver/message/types"
+	"github.com/ontio/ontology/p2pserver/mock"
+	"github.com/ontio/ontology/p2pserver/net/netserver"
+	p2p "github.com/ontio/ontology/p2pserver/net/protocol"
+	"github.com/ontio/ontology/p2pserver/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+//memChain keeps the committed blocks in memory
+type memChain struct {
+	lock           sync.RWMutex
+	blocks         []*types.Block
+	validators     []keypair.PublicKey
+	nextBookkeeper common.Address
+}
+
+func newMemChain(validators []keypair.PublicKey) *memChain {
+	nextBookkeeper, _ := types.AddressFromBookkeepers(validators)
+	genesis := &types.Block{
+		Header: &types.Header{
+			Timestamp:      uint32(time.Now().Unix()) - 1,
+			NextBookkeeper: nextBookkeeper,
+		},
+	}
+	return &memChain{
+		blocks:         []*types.Block{genesis},
+		validators:     validators,
+		nextBookkeeper: nextBookkeeper,
+	}
+}
+
+func (self *memChain) CurrentHeader() (*types.Header, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.blocks[len(self.blocks)-1].Header, nil
+}
+
+func (self *memChain) MakeBlock(timestamp uint32) (*types.Block, error) {
+	prev, _ := self.CurrentHeader()
+	return &types.Block{
+		Header: &types.Header{
+			PrevBlockHash:  prev.Hash(),
+			Timestamp:      timestamp,
+			Height:         prev.Height + 1,
+			ConsensusData:  common.GetNonce(),
+			NextBookkeeper: self.nextBookkeeper,
+		},
+	}, nil
+}
+
+func (self *memChain) VerifyBlock(block *types.Block) error {
+	if block.Header.NextBookkeeper != self.nextBookkeeper {
+		return fmt.Errorf("unmatched next bookkeeper")
+	}
+	return nil
+}
+
+func (self *memChain) CommitBlock(block *types.Block) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	prev := self.blocks[len(self.blocks)-1].Header
+	if block.Header.Height != prev.Height+1 || block.Header.PrevBlockHash != prev.Hash() {
+		return fmt.Errorf("block is not next to the latest one")
+	}
+	n := len(self.validators)
+	hash := block.Hash()
+	if err := signature.VerifyMultiSignature(hash[:], block.Header.Bookkeepers, n-(n-1)/3, block.Header.SigData); err != nil {
+		return err
+	}
+	self.blocks = append(self.blocks, block)
+	return nil
+}
+
+func (self *memChain) blockHash(height uint32) (common.Uint256, bool) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	if int(height) >= len(self.blocks) {
+		return common.Uint256{}, false
+	}
+	return self.blocks[height].Hash(), true
+}
+
+//testProtocol delivers the consensus msgs from mock network to engine
+type testProtocol struct {
+	engine *engine
+}
+
+func (self *testProtocol) HandlePeerMessage(ctx *p2p.Context, msg msgTypes.Message) {
+	if cons, ok := msg.(*msgTypes.Consensus); ok {
+		self.engine.onPayload(&cons.Cons)
+	}
+}
+
+func (self *testProtocol) HandleSystemMessage(net p2p.P2P, msg p2p.SystemMessage) {}
+
+func newTestNetwork(t *testing.T, n int) ([]*engine, []*memChain) {
+	var validators []keypair.PublicKey
+	var accounts []*account.Account
+	for i := 0; i < n; i++ {
+		acc := account.NewAccount("")
+		accounts = append(accounts, acc)
+		validators = append(validators, acc.PublicKey)
+	}
+	keypair.SortPublicKeys(validators)
+
+	net := mock.NewNetwork()
+	var engines []*engine
+	var chains []*memChain
+	var nodes []*netserver.NetServer
+	for i := 0; i < n; i++ {
+		keyId := p2pComm.RandPeerKeyId()
+		info := peer.NewPeerInfo(keyId.Id, 0, 0, true, 0, 0, 0, "1.10", "")
+		logger := p2pComm.LoggerWithContext(p2pComm.NewGlobalLoggerWrapper(), fmt.Sprintf("sbft node %d: ", i))
+		protocol := &testProtocol{}
+		node := mock.NewNode(keyId, "", info, protocol, net, nil, p2p.AllAddrFilter(), logger)
+		chain := newMemChain(validators)
+		protocol.engine = newEngine(accounts[i], validators, chain, node, 50*time.Millisecond, 200*time.Millisecond)
+		assert.Nil(t, node.Start())
+		engines = append(engines, protocol.engine)
+		chains = append(chains, chain)
+		nodes = append(nodes, node)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			net.AllowConnect(nodes[i].GetID(), nodes[j].GetID())
+			nodes[i].Connect(nodes[j].GetHostInfo().Addr)
+		}
+	}
+	// the inbound connections are established asynchronously
+	deadline := time.Now().Add(5 * time.Second)
+	for i := 0; i < n; i++ {
+		for nodes[i].GetConnectionCnt() < uint32(n-1) && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, uint32(n-1), nodes[i].GetConnectionCnt(), i)
+	}
+	return engines, chains
+}
+
+func waitHeight(chains []*memChain, height uint32, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		reached := true
+		for _, chain := range chains {
+			if header, _ := chain.CurrentHeader(); header.Height < height {
+				reached = false
+				break
+			}
+		}
+		if reached {
+			return true
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return false
+}
+
+func checkSameBlocks(t *testing.T, chains []*memChain, height uint32) {
+	for h := uint32(1); h <= height; h++ {
+		hash, _ := chains[0].blockHash(h)
+		for i, chain := range chains[1:] {
+			other, present := chain.blockHash(h)
+			assert.True(t, present)
+			assert.Equal(t, hash, other, "block %d of chain %d", h, i+1)
+		}
+	}
+}
+
+func TestMsgSerialization(t *testing.T) {
+	chain := newMemChain(nil)
+	block, _ := chain.MakeBlock(uint32(time.Now().Unix()))
+	msg := &sbftMsg{
+		Type:         viewChangeMsg,
+		View:         3,
+		BlockHash:    block.Hash(),
+		Block:        block,
+		PreparedView: 2,
+		Proofs:       [][]byte{{1, 2}, {3}},
+	}
+	decoded := new(sbftMsg)
+	assert.Nil(t, decoded.Deserialization(common.NewZeroCopySource(common.SerializeToBytes(msg))))
+	assert.Equal(t, msg.Type, decoded.Type)
+	assert.Equal(t, msg.View, decoded.View)
+	assert.Equal(t, msg.BlockHash, decoded.Block.Hash())
+	assert.Equal(t, msg.PreparedView, decoded.PreparedView)
+	assert.Equal(t, msg.Proofs, decoded.Proofs)
+
+	vote := &sbftMsg{Type: commitMsg, View: 1, BlockHash: block.Hash(), Signature: []byte{1}}
+	decoded = new(sbftMsg)
+	assert.Nil(t, decoded.Deserialization(common.NewZeroCopySource(common.SerializeToBytes(vote))))
+	assert.Nil(t, decoded.Block)
+	assert.Equal(t, vote.Signature, decoded.Signature)
+}
+
+func TestConsensus(t *testing.T) {
+	engines, chains := newTestNetwork(t, 4)
+	for _, e := range engines {
+		assert.Nil(t, e.start())
+		defer e.stop()
+	}
+	assert.True(t, waitHeight(chains, 5, 20*time.Second))
+	checkSameBlocks(t, chains, 5)
+}
+
+func TestViewChange(t *testing.T) {
+	engines, chains := newTestNetwork(t, 4)
+	var online []*memChain
+	for i, e := range engines {
+		// the primary of view 0 at height 1 is offline
+		if e.index == 1 {
+			continue
+		}
+		assert.Nil(t, e.start())
+		defer e.stop()
+		online = append(online, chains[i])
+	}
+	assert.True(t, waitHeight(online, 5, 30*time.Second))
+	checkSameBlocks(t, online, 5)
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sbft
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+)
+
+type msgType byte
+
+const (
+	prePrepareMsg msgType = iota + 1 //primary proposes the block of view 0
+	prepareMsg                       //validator accepts the proposed block
+	commitMsg                        //validator has prepared the block, with the signature of block hash
+	viewChangeMsg                    //validator votes to change view, with the prepared block and its proofs
+	newViewMsg                       //primary of new view proposes the block, with the view change proofs
+)
+
+func (self msgType) String() string {
+	switch self {
+	case prePrepareMsg:
+		return "pre-prepare"
+	case prepareMsg:
+		return "prepare"
+	case commitMsg:
+		return "commit"
+	case viewChangeMsg:
+		return "view-change"
+	case newViewMsg:
+		return "new-view"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(self))
+	}
+}
+
+//sbftMsg is carried in the data of consensus payload, the height, previous block hash and
+//validator index of the msg are kept in the payload which is signed by the validator.
+type sbftMsg struct {
+	Type      msgType
+	View      uint32
+	BlockHash common.Uint256
+	Block     *types.Block //proposed block of pre-prepare and new-view, prepared block of view-change
+	Signature []byte       //signature of block hash in commit msg
+
+	PreparedView uint32   //view of the prepared block in view-change msg
+	Proofs       [][]byte //prepare payloads of view-change msg, view-change payloads of new-view msg
+}
+
+func (self *sbftMsg) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteByte(byte(self.Type))
+	sink.WriteUint32(self.View)
+	sink.WriteHash(self.BlockHash)
+	sink.WriteBool(self.Block != nil)
+	if self.Block != nil {
+		self.Block.Serialization(sink)
+	}
+	sink.WriteVarBytes(self.Signature)
+	sink.WriteUint32(self.PreparedView)
+	sink.WriteVarUint(uint64(len(self.Proofs)))
+	for _, proof := range self.Proofs {
+		sink.WriteVarBytes(proof)
+	}
+}
+
+func (self *sbftMsg) Deserialization(source *common.ZeroCopySource) error {
+	t, eof := source.NextByte()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	self.Type = msgType(t)
+	if self.View, eof = source.NextUint32(); eof {
+		return io.ErrUnexpectedEOF
+	}
+	if self.BlockHash, eof = source.NextHash(); eof {
+		return io.ErrUnexpectedEOF
+	}
+	hasBlock, irregular, eof := source.NextBool()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	if hasBlock {
+		self.Block = new(types.Block)
+		if err := self.Block.Deserialization(source); err != nil {
+			return err
+		}
+	}
+	self.Signature, _, irregular, eof = source.NextVarBytes()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	if self.PreparedView, eof = source.NextUint32(); eof {
+		return io.ErrUnexpectedEOF
+	}
+	n, _, irregular, eof := source.NextVarUint()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	if n > maxProofs {
+		return fmt.Errorf("too many proofs: %d", n)
+	}
+	self.Proofs = make([][]byte, 0, n)
+	for i := uint64(0); i < n; i++ {
+		proof, _, irregular, eof := source.NextVarBytes()
+		if irregular {
+			return common.ErrIrregularData
+		}
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		self.Proofs = append(self.Proofs, proof)
+	}
+	return nil
+}
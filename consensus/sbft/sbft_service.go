@@ -18,13 +18,127 @@
 
 package sbft
 
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	actorTypes "github.com/ontio/ontology/consensus/actor"
+	"github.com/ontio/ontology/core/ledger"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/events"
+	"github.com/ontio/ontology/events/message"
+	p2pmsg "github.com/ontio/ontology/p2pserver/message/types"
+	p2p "github.com/ontio/ontology/p2pserver/net/protocol"
+	"github.com/ontio/ontology/validator/increment"
+)
+
+/*
+*Simple bft consensus for consortium chain with the fixed validators of genesis config.
+ */
 type SbftService struct {
+	Account       *account.Account
+	poolActor     *actorTypes.TxPoolActor
+	incrValidator *increment.IncrementValidator
+	engine        *engine
+	started       bool
+	pid           *actor.PID
+	sub           *events.ActorSubscriber
+}
+
+func NewSbftService(bkAccount *account.Account, txpool *actor.PID, p2p p2p.P2P) (*SbftService, error) {
+	validators, err := config.DefConfig.GetBookkeepers()
+	if err != nil {
+		return nil, fmt.Errorf("get sbft validators error: %s", err)
+	}
+	nextBookkeeper, err := types.AddressFromBookkeepers(validators)
+	if err != nil {
+		return nil, fmt.Errorf("GetBookkeeperAddress error:%s", err)
+	}
+	service := &SbftService{
+		Account:       bkAccount,
+		poolActor:     &actorTypes.TxPoolActor{Pool: txpool},
+		incrValidator: increment.NewIncrementValidator(20),
+	}
+	chain := &ledgerChain{
+		ledger:         ledger.DefLedger,
+		poolActor:      service.poolActor,
+		incrValidator:  service.incrValidator,
+		nextBookkeeper: nextBookkeeper,
+	}
+	blockTime := time.Duration(config.DefConfig.Genesis.SBFT.GenBlockTime) * time.Second
+	if blockTime <= 0 {
+		blockTime = config.DEFAULT_GEN_BLOCK_TIME * time.Second
+	}
+	service.engine = newEngine(bkAccount, validators, chain, p2p, blockTime, blockTime)
+
+	props := actor.FromProducer(func() actor.Actor {
+		return service
+	})
+
+	pid, err := actor.SpawnNamed(props, "consensus_sbft")
+	service.pid = pid
+	service.sub = events.NewActorSubscriber(pid)
+
+	return service, err
+}
+
+func (self *SbftService) Receive(context actor.Context) {
+	switch msg := context.Message().(type) {
+	case *actor.Restarting:
+		log.Info("sbft actor restarting")
+	case *actor.Stopping:
+		log.Info("sbft actor stopping")
+	case *actor.Stopped:
+		log.Info("sbft actor stopped")
+	case *actor.Started:
+		log.Info("sbft actor started")
+	case *actor.Restart:
+		log.Info("sbft actor restart")
+	case *actorTypes.StartConsensus:
+		if self.started {
+			log.Info("consensus have started")
+			return
+		}
+		self.sub.Subscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+		if err := self.engine.start(); err != nil {
+			log.Warnf("sbft consensus not started: %s", err)
+		}
+		self.started = true
+	case *actorTypes.StopConsensus:
+		if self.started {
+			self.engine.stop()
+			self.incrValidator.Clean()
+			self.sub.Unsubscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+			self.started = false
+		}
+	case *message.SaveBlockCompleteMsg:
+		log.Infof("sbft actor receives block complete event. block height=%d txnum=%d", msg.Block.Header.Height, len(msg.Block.Transactions))
+		self.incrValidator.AddBlock(msg.Block)
+		self.engine.onBlockPersisted()
+	case *p2pmsg.ConsensusPayload:
+		if self.started {
+			self.engine.onPayload(msg)
+		}
+	default:
+		log.Info("sbft actor: Unknown msg ", msg, "type", reflect.TypeOf(msg))
+	}
+}
+
+func (self *SbftService) GetPID() *actor.PID {
+	return self.pid
 }
 
-func (ss *SbftService) Halt() error {
+func (self *SbftService) Start() error {
+	self.pid.Tell(&actorTypes.StartConsensus{})
 	return nil
 }
 
-func (ds *SbftService) Start() error {
+func (self *SbftService) Halt() error {
+	self.pid.Tell(&actorTypes.StopConsensus{})
 	return nil
 }
@@ -0,0 +1,166 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package solo
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"time"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	actorTypes "github.com/ontio/ontology/consensus/actor"
+	"github.com/ontio/ontology/core/ledger"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/utils"
+	"github.com/ontio/ontology/smartcontract/service/native/ont"
+	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const (
+	DEV_ACCOUNT_ONT  = 10000              //ont funded to every dev account
+	DEV_ACCOUNT_ONG  = 10000 * 1000000000 //ong funded to every dev account, in 9 decimals
+	devTxGasLimit    = 200000
+	maxDevMineBlocks = 1000
+)
+
+//DevAccountKey returns the private key of the index-th dev account. The keys are derived from a fixed seed so the
+//dev accounts are the same on every start, they must never be used outside a test network
+func DevAccountKey(index uint) (*ecdsa.PrivateKey, error) {
+	seed := ethcrypto.Keccak256([]byte(fmt.Sprintf("ontology dev account %d", index)))
+	return ethcrypto.ToECDSA(seed)
+}
+
+//fundDevAccounts transfers ont and ong from the bookkeeper to the dev accounts in the first block of a new chain.
+//The ont transfer settles the unbound ong of the bookkeeper, which is then sent out in the same block
+func (self *SoloService) fundDevAccounts() error {
+	if self.devAccounts == 0 || ledger.DefLedger.GetCurrentBlockHeight() != 0 {
+		return nil
+	}
+	owner := self.Account.Address
+	transfers := make([]*ont.TransferState, 0, self.devAccounts)
+	for i := uint(0); i < self.devAccounts; i++ {
+		key, err := DevAccountKey(i)
+		if err != nil {
+			return err
+		}
+		addr := common.Address(ethcrypto.PubkeyToAddress(key.PublicKey))
+		transfers = append(transfers, &ont.TransferState{From: owner, To: addr, Value: DEV_ACCOUNT_ONT})
+		log.Infof("dev account %d: %s (%s), private key: %x", i, addr.ToHexString(), addr.ToBase58(),
+			ethcrypto.FromECDSA(key))
+	}
+
+	ontTx, err := self.devTx(nutils.OntContractAddress, ont.TRANSFER_NAME, transfers)
+	if err != nil {
+		return err
+	}
+	ongTransfers := make([]*ont.TransferState, 0, len(transfers))
+	for _, transfer := range transfers {
+		ongTransfers = append(ongTransfers, &ont.TransferState{From: owner, To: transfer.To, Value: DEV_ACCOUNT_ONG})
+	}
+	ongTx, err := self.devTx(nutils.OngContractAddress, ont.TRANSFER_NAME, ongTransfers)
+	if err != nil {
+		return err
+	}
+	return self.genBlockWithTxs([]*types.Transaction{ontTx, ongTx})
+}
+
+func (self *SoloService) devTx(contract common.Address, method string, param interface{}) (*types.Transaction, error) {
+	code, err := utils.BuildNativeInvokeCode(contract, 0, method, []interface{}{param})
+	if err != nil {
+		return nil, err
+	}
+	mutable := utils.NewInvokeTransaction(code)
+	mutable.Nonce = uint32(common.GetNonce())
+	// the bookkeeper has no ong to pay before the funding, and the block is not checked by tx pool
+	mutable.GasPrice = 0
+	mutable.GasLimit = devTxGasLimit
+	mutable.Payer = self.Account.Address
+	sig, err := types.SignTransaction(self.Account, mutable)
+	if err != nil {
+		return nil, fmt.Errorf("[Signature],Sign error:%s.", err)
+	}
+	mutable.Sigs = []types.Sig{{
+		PubKeys: []keypair.PublicKey{self.Account.PublicKey},
+		M:       1,
+		SigData: [][]byte{sig},
+	}}
+	return mutable.IntoImmutable()
+}
+
+//blockTimestamp returns the timestamp of the block after height, which is the wall clock shifted by the time
+//increased, or the one set for next block. It's always greater than the timestamp of current block
+func (self *SoloService) blockTimestamp(height uint32) uint32 {
+	now := time.Now().Unix()
+	timestamp := now + self.timeOffset
+	if self.nextTimestamp != 0 {
+		timestamp = int64(self.nextTimestamp)
+		self.timeOffset = timestamp - now
+		self.nextTimestamp = 0
+	}
+	if header, err := ledger.DefLedger.GetHeaderByHeight(height); err == nil && timestamp <= int64(header.Timestamp) {
+		timestamp = int64(header.Timestamp) + 1
+	}
+	return uint32(timestamp)
+}
+
+func (self *SoloService) handleDevRequest(req interface{}) (uint64, error) {
+	switch req := req.(type) {
+	case *actorTypes.DevMine:
+		blocks := req.Blocks
+		if blocks == 0 {
+			blocks = 1
+		}
+		if blocks > maxDevMineBlocks {
+			return 0, fmt.Errorf("can not mine more than %d blocks at once", maxDevMineBlocks)
+		}
+		for i := uint32(0); i < blocks; i++ {
+			if err := self.genBlock(); err != nil {
+				return 0, err
+			}
+		}
+		return uint64(ledger.DefLedger.GetCurrentBlockHeight()), nil
+	case *actorTypes.DevSnapshot:
+		id, err := ledger.DefLedger.Snapshot()
+		return uint64(id), err
+	case *actorTypes.DevRevert:
+		if err := ledger.DefLedger.Revert(req.Id); err != nil {
+			return 0, err
+		}
+		self.incrValidator.Clean()
+		return 1, nil
+	case *actorTypes.DevIncreaseTime:
+		self.timeOffset += int64(req.Seconds)
+		return uint64(self.timeOffset), nil
+	case *actorTypes.DevSetNextBlockTimestamp:
+		header, err := ledger.DefLedger.GetHeaderByHeight(ledger.DefLedger.GetCurrentBlockHeight())
+		if err != nil {
+			return 0, err
+		}
+		if req.Timestamp <= header.Timestamp {
+			return 0, fmt.Errorf("timestamp %d is lower than or equal to current block timestamp %d",
+				req.Timestamp, header.Timestamp)
+		}
+		self.nextTimestamp = req.Timestamp
+		return uint64(req.Timestamp), nil
+	}
+	return 0, fmt.Errorf("unknown dev request %T", req)
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package solo
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	common2 "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/constants"
+	actorTypes "github.com/ontio/ontology/consensus/actor"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/ledger"
+	"github.com/ontio/ontology/core/types"
+	bcomn "github.com/ontio/ontology/http/base/common"
+	txpool "github.com/ontio/ontology/txnpool/common"
+	"github.com/ontio/ontology/validator/increment"
+	"github.com/ontio/ontology/vm/evm"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDevChain(t *testing.T, devAccounts uint) *SoloService {
+	acc := account.NewAccount("")
+	config.DefConfig.Genesis.ConsensusType = config.CONSENSUS_TYPE_SOLO
+	config.DefConfig.Genesis.SOLO.Bookkeepers = []string{hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey))}
+	bookkeepers := []keypair.PublicKey{acc.PublicKey}
+	block, err := genesis.BuildGenesisBlock(bookkeepers, config.DefConfig.Genesis)
+	assert.Nil(t, err)
+	ledger.DefLedger, err = ledger.InitLedger(t.TempDir(), 0, bookkeepers, block)
+	assert.Nil(t, err)
+	t.Cleanup(func() { ledger.DefLedger.Close() })
+
+	pool := actor.Spawn(actor.FromFunc(func(context actor.Context) {
+		if _, ok := context.Message().(*txpool.GetTxnPoolReq); ok {
+			context.Respond(&txpool.GetTxnPoolRsp{})
+		}
+	}))
+	t.Cleanup(pool.Stop)
+	return &SoloService{
+		Account:       acc,
+		poolActor:     &actorTypes.TxPoolActor{Pool: pool},
+		incrValidator: increment.NewIncrementValidator(20),
+		devAccounts:   devAccounts,
+	}
+}
+
+func TestFundDevAccounts(t *testing.T) {
+	solo := newTestDevChain(t, 2)
+	assert.Nil(t, solo.fundDevAccounts())
+	assert.Equal(t, uint32(1), ledger.DefLedger.GetCurrentBlockHeight())
+
+	for i := uint(0); i < 2; i++ {
+		key, err := DevAccountKey(i)
+		assert.Nil(t, err)
+		addr := common.Address(crypto.PubkeyToAddress(key.PublicKey))
+		balance, err := bcomn.GetBalance(addr)
+		assert.Nil(t, err)
+		assert.Equal(t, fmt.Sprint(DEV_ACCOUNT_ONT), balance.Ont)
+		assert.Equal(t, fmt.Sprint(DEV_ACCOUNT_ONG), balance.Ong)
+	}
+	// only funded on a new chain
+	assert.Nil(t, solo.fundDevAccounts())
+	assert.Equal(t, uint32(1), ledger.DefLedger.GetCurrentBlockHeight())
+}
+
+func TestDevSnapshotRevert(t *testing.T) {
+	solo := newTestDevChain(t, 0)
+	height, err := solo.handleDevRequest(&actorTypes.DevMine{Blocks: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), height)
+
+	id, err := solo.handleDevRequest(&actorTypes.DevSnapshot{})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), id)
+	hash := ledger.DefLedger.GetCurrentBlockHash()
+
+	height, err = solo.handleDevRequest(&actorTypes.DevMine{Blocks: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), height)
+
+	_, err = solo.handleDevRequest(&actorTypes.DevRevert{Id: uint32(id)})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), ledger.DefLedger.GetCurrentBlockHeight())
+	assert.Equal(t, hash, ledger.DefLedger.GetCurrentBlockHash())
+	// the snapshot is consumed by revert
+	_, err = solo.handleDevRequest(&actorTypes.DevRevert{Id: uint32(id)})
+	assert.NotNil(t, err)
+
+	height, err = solo.handleDevRequest(&actorTypes.DevMine{Blocks: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), height)
+}
+
+func TestDevRevertFailure(t *testing.T) {
+	solo := newTestDevChain(t, 0)
+	_, err := solo.handleDevRequest(&actorTypes.DevMine{Blocks: 2})
+	assert.Nil(t, err)
+	id, err := solo.handleDevRequest(&actorTypes.DevSnapshot{})
+	assert.Nil(t, err)
+	_, err = solo.handleDevRequest(&actorTypes.DevMine{Blocks: 1})
+	assert.Nil(t, err)
+
+	// the ledger is readable while reverting
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			ledger.DefLedger.GetCurrentBlockHeight()
+		}
+	}()
+	// the ledger data is kept if the snapshot is broken
+	dirs, err := filepath.Glob(filepath.Join(filepath.Dir(t.TempDir()), "*_snapshot1"))
+	assert.Equal(t, 1, len(dirs))
+	assert.Nil(t, err)
+	for _, dir := range dirs {
+		assert.Nil(t, os.RemoveAll(dir))
+	}
+	_, err = solo.handleDevRequest(&actorTypes.DevRevert{Id: uint32(id)})
+	assert.NotNil(t, err)
+	<-done
+	assert.Equal(t, uint32(3), ledger.DefLedger.GetCurrentBlockHeight())
+	height, err := solo.handleDevRequest(&actorTypes.DevMine{Blocks: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), height)
+}
+
+func TestDevTimestamp(t *testing.T) {
+	solo := newTestDevChain(t, 0)
+	_, err := solo.handleDevRequest(&actorTypes.DevMine{})
+	assert.Nil(t, err)
+	header, err := ledger.DefLedger.GetHeaderByHeight(1)
+	assert.Nil(t, err)
+
+	_, err = solo.handleDevRequest(&actorTypes.DevSetNextBlockTimestamp{Timestamp: header.Timestamp})
+	assert.NotNil(t, err)
+	next := header.Timestamp + 1000
+	_, err = solo.handleDevRequest(&actorTypes.DevSetNextBlockTimestamp{Timestamp: next})
+	assert.Nil(t, err)
+	_, err = solo.handleDevRequest(&actorTypes.DevMine{})
+	assert.Nil(t, err)
+	header, err = ledger.DefLedger.GetHeaderByHeight(2)
+	assert.Nil(t, err)
+	assert.Equal(t, next, header.Timestamp)
+
+	offset, err := solo.handleDevRequest(&actorTypes.DevIncreaseTime{Seconds: 3600})
+	assert.Nil(t, err)
+	_, err = solo.handleDevRequest(&actorTypes.DevMine{})
+	assert.Nil(t, err)
+	header, err = ledger.DefLedger.GetHeaderByHeight(3)
+	assert.Nil(t, err)
+	assert.True(t, header.Timestamp >= next+3600)
+	assert.True(t, offset >= 1000+3600)
+}
+
+func newTestEvmTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, to common.Address, value *big.Int) *types.Transaction {
+	tx := ethtypes.NewTransaction(nonce, common2.Address(to), value, 21000, big.NewInt(500*constants.GWei), nil)
+	chainId := big.NewInt(int64(config.DefConfig.P2PNode.EVMChainId))
+	signed, err := ethtypes.SignTx(tx, ethtypes.NewEIP155Signer(chainId), key)
+	assert.Nil(t, err)
+	otx, err := types.TransactionFromEIP155(signed)
+	assert.Nil(t, err)
+	return otx
+}
+
+func TestTraceBlockDependentTxs(t *testing.T) {
+	solo := newTestDevChain(t, 1)
+	assert.Nil(t, ledger.DefLedger.EnableStateHistory())
+	assert.Nil(t, solo.fundDevAccounts())
+	devKey, err := DevAccountKey(0)
+	assert.Nil(t, err)
+	key, err := crypto.GenerateKey()
+	assert.Nil(t, err)
+	addr := common.Address(crypto.PubkeyToAddress(key.PublicKey))
+
+	// the second tx is paid by the value of the first one
+	value := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	tx1 := newTestEvmTx(t, devKey, 0, addr, value)
+	tx2 := newTestEvmTx(t, key, 0, common.Address(crypto.PubkeyToAddress(devKey.PublicKey)), big.NewInt(1000))
+	assert.Nil(t, solo.genBlockWithTxs([]*types.Transaction{tx1, tx2}))
+	height := ledger.DefLedger.GetCurrentBlockHeight()
+	block, err := ledger.DefLedger.GetBlockByHeight(height)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(block.Transactions))
+	notify, err := ledger.DefLedger.GetEventNotifyByTx(tx2.Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, byte(1), notify.State)
+
+	tracer := evm.NewStructLogger(nil)
+	results, err := ledger.DefLedger.TraceBlock(block, 1, func(i int) evm.Tracer {
+		if i == 1 {
+			return tracer
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results))
+	assert.NotNil(t, results[1])
+	assert.False(t, results[1].Failed())
+	assert.Equal(t, uint64(21000), results[1].UsedGas)
+}
@@ -31,7 +31,6 @@ import (
 	"github.com/ontio/ontology/common/log"
 	actorTypes "github.com/ontio/ontology/consensus/actor"
 	"github.com/ontio/ontology/core/ledger"
-	"github.com/ontio/ontology/core/signature"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/events"
 	"github.com/ontio/ontology/events/message"
@@ -51,6 +50,10 @@ type SoloService struct {
 	genBlockInterval time.Duration
 	pid              *actor.PID
 	sub              *events.ActorSubscriber
+	autoMine         bool
+	devAccounts      uint
+	timeOffset       int64  //seconds added to the wall clock by evm_increaseTime
+	nextTimestamp    uint32 //timestamp of next block set by evm_setNextBlockTimestamp
 }
 
 func NewSoloService(bkAccount *account.Account, txpool *actor.PID) (*SoloService, error) {
@@ -59,6 +62,8 @@ func NewSoloService(bkAccount *account.Account, txpool *actor.PID) (*SoloService
 		poolActor:        &actorTypes.TxPoolActor{Pool: txpool},
 		incrValidator:    increment.NewIncrementValidator(20),
 		genBlockInterval: time.Duration(config.DefConfig.Genesis.SOLO.GenBlockTime) * time.Second,
+		autoMine:         config.DefConfig.Genesis.SOLO.AutoMine,
+		devAccounts:      config.DefConfig.Genesis.SOLO.DevAccounts,
 	}
 
 	props := actor.FromProducer(func() actor.Actor {
@@ -91,9 +96,16 @@ func (self *SoloService) Receive(context actor.Context) {
 		}
 
 		self.sub.Subscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+		if err := self.fundDevAccounts(); err != nil {
+			log.Errorf("Solo fund dev accounts error %s", err)
+		}
 
-		timer := time.NewTicker(self.genBlockInterval)
 		self.existCh = make(chan interface{})
+		if self.autoMine {
+			self.sub.Subscribe(message.TOPIC_TX_POOL_ADDED)
+			return
+		}
+		timer := time.NewTicker(self.genBlockInterval)
 		go func() {
 			defer timer.Stop()
 			existCh := self.existCh
@@ -112,10 +124,32 @@ func (self *SoloService) Receive(context actor.Context) {
 			self.existCh = nil
 			self.incrValidator.Clean()
 			self.sub.Unsubscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+			if self.autoMine {
+				self.sub.Unsubscribe(message.TOPIC_TX_POOL_ADDED)
+			}
 		}
 	case *message.SaveBlockCompleteMsg:
 		log.Infof("solo actor receives block complete event. block height=%d txnum=%d", msg.Block.Header.Height, len(msg.Block.Transactions))
-		self.incrValidator.AddBlock(msg.Block)
+		// blocks generated by self are added once submitted
+		if _, end := self.incrValidator.BlockRange(); msg.Block.Header.Height >= end {
+			self.incrValidator.AddBlock(msg.Block)
+		}
+	case *message.TxPoolAddedMsg:
+		if self.existCh == nil {
+			return
+		}
+		txs := self.collectTxs()
+		if len(txs) == 0 {
+			return
+		}
+		err := self.genBlockWithTxs(txs)
+		if err != nil {
+			log.Errorf("Solo genBlock error %s", err)
+		}
+	case *actorTypes.DevMine, *actorTypes.DevSnapshot, *actorTypes.DevRevert, *actorTypes.DevIncreaseTime,
+		*actorTypes.DevSetNextBlockTimestamp:
+		result, err := self.handleDevRequest(msg)
+		context.Respond(&actorTypes.DevChainRsp{Result: result, Err: err})
 
 	case *actorTypes.TimeOut:
 		err := self.genBlock()
@@ -142,7 +176,11 @@ func (self *SoloService) Halt() error {
 }
 
 func (self *SoloService) genBlock() error {
-	block, err := self.makeBlock()
+	return self.genBlockWithTxs(self.collectTxs())
+}
+
+func (self *SoloService) genBlockWithTxs(transactions []*types.Transaction) error {
+	block, err := self.makeBlock(transactions)
 	if err != nil {
 		return fmt.Errorf("makeBlock error %s", err)
 	}
@@ -159,8 +197,7 @@ func (self *SoloService) genBlock() error {
 			Height:     block.Header.Height,
 			StatesRoot: result.CrossStatesRoot,
 		}
-		hash := msg.Hash()
-		sig, err := signature.Sign(self.Account, hash[:])
+		sig, err := types.SignCrossChainMsg(self.Account, msg)
 		if err != nil {
 			return fmt.Errorf("[Signature],Sign error:%s.", err)
 		}
@@ -171,19 +208,12 @@ func (self *SoloService) genBlock() error {
 	if err != nil {
 		return fmt.Errorf("genBlock DefLedgerPid.RequestFuture Height:%d error:%s", block.Header.Height, err)
 	}
+	self.incrValidator.AddBlock(block)
 	return nil
 }
 
-func (self *SoloService) makeBlock() (*types.Block, error) {
-	log.Debug()
-	owner := self.Account.PublicKey
-	nextBookkeeper, err := types.AddressFromBookkeepers([]keypair.PublicKey{owner})
-	if err != nil {
-		return nil, fmt.Errorf("GetBookkeeperAddress error:%s", err)
-	}
-	prevHash := ledger.DefLedger.GetCurrentBlockHash()
+func (self *SoloService) collectTxs() []*types.Transaction {
 	height := ledger.DefLedger.GetCurrentBlockHeight()
-
 	validHeight := height
 
 	start, end := self.incrValidator.BlockRange()
@@ -211,6 +241,18 @@ func (self *SoloService) makeBlock() (*types.Block, error) {
 		}
 
 	}
+	return transactions
+}
+
+func (self *SoloService) makeBlock(transactions []*types.Transaction) (*types.Block, error) {
+	log.Debug()
+	owner := self.Account.PublicKey
+	nextBookkeeper, err := types.AddressFromBookkeepers([]keypair.PublicKey{owner})
+	if err != nil {
+		return nil, fmt.Errorf("GetBookkeeperAddress error:%s", err)
+	}
+	prevHash := ledger.DefLedger.GetCurrentBlockHash()
+	height := ledger.DefLedger.GetCurrentBlockHeight()
 
 	txHash := []common.Uint256{}
 	for _, t := range transactions {
@@ -224,7 +266,7 @@ func (self *SoloService) makeBlock() (*types.Block, error) {
 		PrevBlockHash:    prevHash,
 		TransactionsRoot: txRoot,
 		BlockRoot:        blockRoot,
-		Timestamp:        uint32(time.Now().Unix()),
+		Timestamp:        self.blockTimestamp(height),
 		Height:           height + 1,
 		ConsensusData:    common.GetNonce(),
 		NextBookkeeper:   nextBookkeeper,
@@ -234,9 +276,7 @@ func (self *SoloService) makeBlock() (*types.Block, error) {
 		Transactions: transactions,
 	}
 
-	blockHash := block.Hash()
-
-	sig, err := signature.Sign(self.Account, blockHash[:])
+	sig, err := types.SignHeader(self.Account, header)
 	if err != nil {
 		return nil, fmt.Errorf("[Signature],Sign error:%s.", err)
 	}
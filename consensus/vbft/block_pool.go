@@ -683,10 +683,14 @@ func (pool *BlockPool) setBlockSealed(block *Block, forEmpty bool, sigdata bool)
 		log.Errorf("setBlockSealed blk %d failed:%s", blkNum, err)
 		return nil
 	}
-	if blocksubmitMsg, _ := pool.server.constructBlockSubmitMsg(pool.chainStore.GetChainedBlockNum(), stateRoot); blocksubmitMsg != nil {
+	//the state root is not signed by remote signer, the block is still submitted without broadcasting the submit msg
+	blocksubmitMsg, err := pool.server.constructBlockSubmitMsg(pool.chainStore.GetChainedBlockNum(), stateRoot)
+	if err != nil {
+		log.Debugf("setBlockSealed blk %d: %s", blkNum, err)
+	} else {
 		pool.server.broadcast(blocksubmitMsg)
-		pool.server.makeBlockSubmit(pool.chainStore.GetChainedBlockNum())
 	}
+	pool.server.makeBlockSubmit(pool.chainStore.GetChainedBlockNum())
 	return nil
 }
 
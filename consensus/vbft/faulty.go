@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/common/metrics"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/utils"
+	p2pmsg "github.com/ontio/ontology/p2pserver/message/types"
+	gover "github.com/ontio/ontology/smartcontract/service/native/governance"
+	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const (
+	// number of committed blocks whose consensus msgs are kept for faulty detecting
+	faultyHistoryLen = 64
+	// gas limit of the faulty report transaction
+	faultyReportGasLimit = 200000
+)
+
+var faultyTypeNames = map[uint32]string{
+	gover.FaultyProposal: "proposal",
+	gover.FaultyEndorse:  "endorse",
+	gover.FaultyCommit:   "commit",
+}
+
+type faultyKey struct {
+	faultyType uint32
+	peerIdx    uint32
+	blockNum   uint32
+	forEmpty   bool
+}
+
+//faultySignedMsg is the evidence of one consensus msg, for proposal it is the block header signed by proposer,
+//for endorse and commit it is the unsigned consensus payload signed by the payload owner
+type faultySignedMsg struct {
+	blockHash common.Uint256
+	msg       []byte
+	sig       []byte
+}
+
+//faultyDetector keeps the first signed msg of each peer and round, and finds the conflicting msgs
+//signed by the same peer. It is accessed by the msg loops of all peers.
+type faultyDetector struct {
+	lock     sync.Mutex
+	msgs     map[faultyKey]*faultySignedMsg
+	reported map[faultyKey]*FaultyReport
+}
+
+//check records the signed msg, returns the faulty evidence if it conflicts with the recorded one.
+//Each equivocation is only reported once.
+func (self *faultyDetector) check(key faultyKey, msg *faultySignedMsg) (*faultySignedMsg, bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if self.msgs == nil {
+		self.msgs = make(map[faultyKey]*faultySignedMsg)
+		self.reported = make(map[faultyKey]*FaultyReport)
+	}
+
+	prev, present := self.msgs[key]
+	if !present {
+		self.msgs[key] = msg
+		return nil, false
+	}
+	if prev.blockHash == msg.blockHash {
+		return nil, false
+	}
+	if _, present := self.reported[key]; present {
+		return nil, false
+	}
+	if key.faultyType == gover.FaultyProposal {
+		h1, err := types.HeaderFromRawBytes(prev.msg)
+		if err != nil {
+			return nil, false
+		}
+		h2, err := types.HeaderFromRawBytes(msg.msg)
+		if err != nil || !gover.ConflictingProposals(h1, h2) {
+			return nil, false
+		}
+	}
+	self.reported[key] = &FaultyReport{
+		FaultyID:      key.peerIdx,
+		FaultyMsgHash: msg.blockHash,
+	}
+	return prev, true
+}
+
+//reports returns the faulty reports of the block with the given faulty types
+func (self *faultyDetector) reports(blkNum uint32, faultyTypes ...uint32) []*FaultyReport {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	var reports []*FaultyReport
+	for key, report := range self.reported {
+		if key.blockNum != blkNum {
+			continue
+		}
+		for _, t := range faultyTypes {
+			if key.faultyType == t {
+				reports = append(reports, report)
+				break
+			}
+		}
+	}
+	return reports
+}
+
+//prune drops the msgs of the blocks too far before the committed block
+func (self *faultyDetector) prune(committed uint32) {
+	if committed <= faultyHistoryLen {
+		return
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	for key := range self.msgs {
+		if key.blockNum < committed-faultyHistoryLen {
+			delete(self.msgs, key)
+			delete(self.reported, key)
+		}
+	}
+}
+
+//detectFaulty checks the proposal, endorse and commit msgs received from peer for equivocation,
+//and reports the faulty peer to governance contract with the conflicting msgs as evidence.
+//It is called after the msg type checked, and verifies the signature of the msg before recording it.
+func (self *Server) detectFaulty(peerIdx uint32, msg ConsensusMsg, payload *p2pmsg.ConsensusPayload) {
+	blkNum := msg.GetBlockNum()
+	if blkNum+faultyHistoryLen < self.GetCommittedBlockNo() || blkNum > self.GetCurrentBlockNo()+faultyHistoryLen {
+		return
+	}
+
+	var key faultyKey
+	var signed *faultySignedMsg
+	switch pMsg := msg.(type) {
+	case *blockProposalMsg:
+		blk := pMsg.Block.Block
+		key = faultyKey{faultyType: gover.FaultyProposal, peerIdx: pMsg.Block.getProposer(), blockNum: blkNum}
+		signed = &faultySignedMsg{blockHash: blk.Hash(), msg: blk.Header.ToArray(), sig: pMsg.BlockProposerSig}
+	case *blockEndorseMsg:
+		if pMsg.Endorser != peerIdx {
+			return
+		}
+		key = faultyKey{faultyType: gover.FaultyEndorse, peerIdx: peerIdx, blockNum: blkNum, forEmpty: pMsg.EndorseForEmpty}
+		signed = &faultySignedMsg{blockHash: pMsg.EndorsedBlockHash, msg: unsignedPayload(payload), sig: payload.Signature}
+	case *blockCommitMsg:
+		if pMsg.Committer != peerIdx {
+			return
+		}
+		key = faultyKey{faultyType: gover.FaultyCommit, peerIdx: peerIdx, blockNum: blkNum, forEmpty: pMsg.CommitForEmpty}
+		signed = &faultySignedMsg{blockHash: pMsg.CommitBlockHash, msg: unsignedPayload(payload), sig: payload.Signature}
+	default:
+		return
+	}
+
+	pubkey := self.peerPool.GetPeerPubKey(key.peerIdx)
+	if pubkey == nil {
+		log.Errorf("server %d failed to get peer %d pubkey", self.Index, key.peerIdx)
+		return
+	}
+	// only the msgs signed by the peer in valid structure are recorded, or a forged msg relayed by
+	// others could make the honest peer faulty
+	height, err := gover.VerifyFaultyMsg(key.faultyType, pubkey, signed.msg, signed.sig, key.peerIdx)
+	if err != nil {
+		log.Debugf("server %d skip faulty detecting of %s msg from %d: %s", self.Index,
+			faultyTypeNames[key.faultyType], key.peerIdx, err)
+		return
+	}
+	if height != blkNum {
+		log.Debugf("server %d skip faulty detecting of %s msg from %d: signed height %d mismatch with %d",
+			self.Index, faultyTypeNames[key.faultyType], key.peerIdx, height, blkNum)
+		return
+	}
+
+	prev, faulty := self.faulty.check(key, signed)
+	if !faulty {
+		return
+	}
+	param := &gover.ReportFaultyParam{
+		Type:       key.faultyType,
+		PeerPubkey: hex.EncodeToString(keypair.SerializePublicKey(pubkey)),
+		Msg1:       prev.msg,
+		Sig1:       prev.sig,
+		Msg2:       signed.msg,
+		Sig2:       signed.sig,
+	}
+	if _, err := gover.VerifyFaultyEvidence(param, key.peerIdx); err != nil {
+		log.Errorf("server %d invalid faulty evidence of peer %d, blk %d: %s", self.Index, key.peerIdx, blkNum, err)
+		return
+	}
+	log.Warnf("server %d detected faulty %s of peer %d, blk %d, %s vs %s", self.Index,
+		faultyTypeNames[key.faultyType], key.peerIdx, blkNum, prev.blockHash.ToHexString(), signed.blockHash.ToHexString())
+	metrics.ConsensusFaulty.WithLabelValues(faultyTypeNames[key.faultyType]).Inc()
+
+	if err := self.reportFaulty(param); err != nil {
+		log.Errorf("server %d failed to report faulty peer %d: %s", self.Index, key.peerIdx, err)
+	}
+}
+
+// reportFaulty submits the faulty evidence to governance contract
+func (self *Server) reportFaulty(param *gover.ReportFaultyParam) error {
+	if height := config.GetReportFaultyHeight(); self.GetCurrentBlockNo() < height {
+		log.Infof("server %d skip faulty report, reportFaulty is activated at height %d", self.Index, height)
+		return nil
+	}
+	tx, err := self.buildFaultyReportTx(param)
+	if err != nil {
+		return err
+	}
+	self.poolActor.AppendTransaction(tx)
+	log.Infof("server %d submitted faulty report tx %s", self.Index, tx.Hash().ToHexString())
+	return nil
+}
+
+//buildFaultyReportTx builds the reportFaulty transaction of governance contract, paid and signed by the server account
+func (self *Server) buildFaultyReportTx(param *gover.ReportFaultyParam) (*types.Transaction, error) {
+	code, err := utils.BuildNativeInvokeCode(nutils.GovernanceContractAddress, 0, gover.REPORT_FAULTY,
+		[]interface{}{param})
+	if err != nil {
+		return nil, fmt.Errorf("build faulty report tx: %s", err)
+	}
+	mutable := utils.NewInvokeTransaction(code)
+	mutable.Nonce = uint32(common.GetNonce())
+	mutable.GasPrice = config.DefConfig.Common.GasPrice
+	mutable.GasLimit = faultyReportGasLimit
+	mutable.Payer = self.account.Address
+	sig, err := types.SignTransaction(self.account, mutable)
+	if err != nil {
+		return nil, fmt.Errorf("sign faulty report tx: %s", err)
+	}
+	mutable.Sigs = []types.Sig{{
+		PubKeys: []keypair.PublicKey{self.account.PublicKey},
+		M:       1,
+		SigData: [][]byte{sig},
+	}}
+	return mutable.IntoImmutable()
+}
+
+func unsignedPayload(payload *p2pmsg.ConsensusPayload) []byte {
+	sink := common.NewZeroCopySink(nil)
+	payload.SerializationUnsigned(sink)
+	return sink.Bytes()
+}
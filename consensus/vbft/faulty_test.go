@@ -0,0 +1,237 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/signature"
+	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/core/types"
+	p2pmsg "github.com/ontio/ontology/p2pserver/message/types"
+	"github.com/ontio/ontology/smartcontract"
+	gover "github.com/ontio/ontology/smartcontract/service/native/governance"
+	_ "github.com/ontio/ontology/smartcontract/service/native/init"
+	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/smartcontract/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func signedEndorseMsg(t *testing.T, acc *account.Account, blkNum uint32, hash common.Uint256, forEmpty bool) *faultySignedMsg {
+	msg := &blockEndorseMsg{
+		Endorser:          3,
+		BlockNum:          blkNum,
+		EndorsedBlockHash: hash,
+		EndorseForEmpty:   forEmpty,
+	}
+	data, err := SerializeVbftMsg(msg)
+	assert.Nil(t, err)
+	payload := &p2pmsg.ConsensusPayload{
+		Data:  data,
+		Owner: acc.PublicKey,
+	}
+	unsigned := unsignedPayload(payload)
+	sig, err := signature.Sign(acc, unsigned)
+	assert.Nil(t, err)
+	return &faultySignedMsg{blockHash: hash, msg: unsigned, sig: sig}
+}
+
+func TestFaultyDetectEndorse(t *testing.T) {
+	acc := account.NewAccount("")
+	detector := &faultyDetector{}
+	key := faultyKey{faultyType: gover.FaultyEndorse, peerIdx: 3, blockNum: 10}
+	emptyKey := faultyKey{faultyType: gover.FaultyEndorse, peerIdx: 3, blockNum: 10, forEmpty: true}
+
+	msg1 := signedEndorseMsg(t, acc, 10, common.Uint256{1}, false)
+	_, faulty := detector.check(key, msg1)
+	assert.False(t, faulty)
+	_, faulty = detector.check(key, msg1)
+	assert.False(t, faulty)
+	// endorsing the empty block of the same round is allowed
+	_, faulty = detector.check(emptyKey, signedEndorseMsg(t, acc, 10, common.Uint256{2}, true))
+	assert.False(t, faulty)
+
+	msg2 := signedEndorseMsg(t, acc, 10, common.Uint256{3}, false)
+	prev, faulty := detector.check(key, msg2)
+	assert.True(t, faulty)
+	assert.Equal(t, msg1, prev)
+	// reported only once
+	_, faulty = detector.check(key, signedEndorseMsg(t, acc, 10, common.Uint256{4}, false))
+	assert.False(t, faulty)
+
+	reports := detector.reports(10, gover.FaultyEndorse, gover.FaultyCommit)
+	assert.Equal(t, 1, len(reports))
+	assert.Equal(t, uint32(3), reports[0].FaultyID)
+	assert.Equal(t, 0, len(detector.reports(10, gover.FaultyProposal)))
+
+	param := &gover.ReportFaultyParam{
+		Type:       gover.FaultyEndorse,
+		PeerPubkey: hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey)),
+		Msg1:       prev.msg,
+		Sig1:       prev.sig,
+		Msg2:       msg2.msg,
+		Sig2:       msg2.sig,
+	}
+	height, err := gover.VerifyFaultyEvidence(param, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), height)
+	_, err = gover.VerifyFaultyEvidence(param, 4)
+	assert.NotNil(t, err)
+
+	param.Type = gover.FaultyCommit
+	_, err = gover.VerifyFaultyEvidence(param, 3)
+	assert.NotNil(t, err)
+
+	// only the msgs signed by the peer are recorded
+	height, err = gover.VerifyFaultyMsg(gover.FaultyEndorse, acc.PublicKey, msg1.msg, msg1.sig, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), height)
+	forged := signedEndorseMsg(t, account.NewAccount(""), 10, common.Uint256{5}, false)
+	_, err = gover.VerifyFaultyMsg(gover.FaultyEndorse, acc.PublicKey, forged.msg, forged.sig, 3)
+	assert.NotNil(t, err)
+	_, err = gover.VerifyFaultyMsg(gover.FaultyCommit, acc.PublicKey, msg1.msg, msg1.sig, 3)
+	assert.NotNil(t, err)
+
+	detector.prune(10 + faultyHistoryLen + 1)
+	assert.Equal(t, 0, len(detector.reports(10, gover.FaultyEndorse)))
+}
+
+func TestFaultyReportTx(t *testing.T) {
+	acc := account.NewAccount("")
+	pubkey := hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey))
+	msg1 := signedEndorseMsg(t, acc, 10, common.Uint256{1}, false)
+	msg2 := signedEndorseMsg(t, acc, 10, common.Uint256{2}, false)
+	param := &gover.ReportFaultyParam{
+		Type:       gover.FaultyEndorse,
+		PeerPubkey: pubkey,
+		Msg1:       msg1.msg,
+		Sig1:       msg1.sig,
+		Msg2:       msg2.msg,
+		Sig2:       msg2.sig,
+	}
+	server := &Server{account: acc}
+	tx, err := server.buildFaultyReportTx(param)
+	assert.Nil(t, err)
+	assert.Equal(t, types.InvokeNeo, tx.TxType)
+
+	// the reported peer is a candidate of the current view
+	cache := storage.NewCacheDB(overlaydb.NewOverlayDB(leveldbstore.NewMemLevelDBStore()))
+	contract := nutils.GovernanceContractAddress
+	view := &gover.GovernanceView{View: 1}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, view.Serialize(bf))
+	cache.Put(nutils.ConcatKey(contract, []byte(gover.GOVERNANCE_VIEW)), cstates.GenRawStorageItem(bf.Bytes()))
+	peerPoolMap := &gover.PeerPoolMap{PeerPoolMap: map[string]*gover.PeerPoolItem{
+		pubkey: {Index: 3, PeerPubkey: pubkey, Address: acc.Address, Status: gover.CandidateStatus},
+	}}
+	sink := common.NewZeroCopySink(nil)
+	assert.Nil(t, peerPoolMap.Serialization(sink))
+	cache.Put(nutils.ConcatKey(contract, []byte(gover.PEER_POOL), gover.GetUint32Bytes(1)), cstates.GenRawStorageItem(sink.Bytes()))
+
+	invoke := func() error {
+		sc := smartcontract.SmartContract{
+			Config:  &smartcontract.Config{Height: 100, Tx: tx},
+			CacheDB: cache,
+			Gas:     math.MaxUint64,
+		}
+		engine, err := sc.NewExecuteEngine(tx.Payload.(*payload.InvokeCode).Code, tx.TxType)
+		assert.Nil(t, err)
+		_, err = engine.Invoke()
+		return err
+	}
+	// reportFaulty is not activated on mainnet yet
+	assert.NotNil(t, invoke())
+
+	networkId := config.DefConfig.P2PNode.NetworkId
+	defer func() { config.DefConfig.P2PNode.NetworkId = networkId }()
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	assert.Nil(t, invoke())
+
+	item, err := cache.Get(nutils.ConcatKey(contract, []byte(gover.PEER_POOL), gover.GetUint32Bytes(1)))
+	assert.Nil(t, err)
+	value, err := cstates.GetValueFromRawStorageItem(item)
+	assert.Nil(t, err)
+	peerPoolMap = &gover.PeerPoolMap{}
+	assert.Nil(t, peerPoolMap.Deserialization(common.NewZeroCopySource(value)))
+	assert.Equal(t, gover.BlackStatus, peerPoolMap.PeerPoolMap[pubkey].Status)
+}
+
+func signedCommitMsg(t *testing.T, acc *account.Account, blkNum uint32, hash common.Uint256, forEmpty bool) *faultySignedMsg {
+	msg := &blockCommitMsg{
+		Committer:       3,
+		BlockNum:        blkNum,
+		CommitBlockHash: hash,
+		CommitForEmpty:  forEmpty,
+	}
+	data, err := SerializeVbftMsg(msg)
+	assert.Nil(t, err)
+	payload := &p2pmsg.ConsensusPayload{
+		Data:  data,
+		Owner: acc.PublicKey,
+	}
+	unsigned := unsignedPayload(payload)
+	sig, err := signature.Sign(acc, unsigned)
+	assert.Nil(t, err)
+	return &faultySignedMsg{blockHash: hash, msg: unsigned, sig: sig}
+}
+
+func TestFaultyDetectCommit(t *testing.T) {
+	acc := account.NewAccount("")
+	detector := &faultyDetector{}
+	key := faultyKey{faultyType: gover.FaultyCommit, peerIdx: 3, blockNum: 10}
+	emptyKey := faultyKey{faultyType: gover.FaultyCommit, peerIdx: 3, blockNum: 10, forEmpty: true}
+
+	msg1 := signedCommitMsg(t, acc, 10, common.Uint256{1}, false)
+	emptyMsg := signedCommitMsg(t, acc, 10, common.Uint256{2}, true)
+	_, faulty := detector.check(key, msg1)
+	assert.False(t, faulty)
+	// committing the empty block of the same round is allowed
+	_, faulty = detector.check(emptyKey, emptyMsg)
+	assert.False(t, faulty)
+
+	param := &gover.ReportFaultyParam{
+		Type:       gover.FaultyCommit,
+		PeerPubkey: hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey)),
+		Msg1:       msg1.msg,
+		Sig1:       msg1.sig,
+		Msg2:       emptyMsg.msg,
+		Sig2:       emptyMsg.sig,
+	}
+	_, err := gover.VerifyFaultyEvidence(param, 3)
+	assert.NotNil(t, err)
+
+	msg2 := signedCommitMsg(t, acc, 10, common.Uint256{3}, false)
+	prev, faulty := detector.check(key, msg2)
+	assert.True(t, faulty)
+	param.Msg1, param.Sig1 = prev.msg, prev.sig
+	param.Msg2, param.Sig2 = msg2.msg, msg2.sig
+	height, err := gover.VerifyFaultyEvidence(param, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), height)
+}
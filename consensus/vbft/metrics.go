@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology/common/metrics"
+)
+
+var timeoutEventNames = map[TimerEventType]string{
+	EventProposeBlockTimeout:      "propose_block",
+	EventPropose2ndBlockTimeout:   "propose_2nd_block",
+	EventEndorseBlockTimeout:      "endorse_block",
+	EventEndorseEmptyBlockTimeout: "endorse_empty_block",
+	EventCommitBlockTimeout:       "commit_block",
+}
+
+//number of recent round outcomes kept for the consensus status report
+const roundHistoryLen = 64
+
+//RoundOutcome is the result of one sealed consensus round
+type RoundOutcome struct {
+	BlockNum   uint32 `json:"block_num"`
+	Proposer   uint32 `json:"proposer"`
+	Empty      bool   `json:"empty"`
+	DurationMs int64  `json:"duration_ms"`
+	SealedAt   int64  `json:"sealed_at"`
+}
+
+//roundMetrics record the timing of consensus rounds, the rounds are started in action loop
+//while the proposals are received in msg loop
+type roundMetrics struct {
+	lock         sync.Mutex
+	blockNum     uint32
+	start        time.Time
+	proposalSeen bool
+
+	//ring buffer of recent round outcomes
+	history     [roundHistoryLen]RoundOutcome
+	historyNext int
+	historySize int
+}
+
+func (self *roundMetrics) startRound(blkNum uint32, view uint32) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	now := time.Now()
+	if !self.start.IsZero() && blkNum != self.blockNum {
+		metrics.ConsensusRoundDuration.Observe(now.Sub(self.start).Seconds())
+	}
+	if blkNum != self.blockNum || self.start.IsZero() {
+		self.start = now
+		self.blockNum = blkNum
+		self.proposalSeen = false
+	}
+	metrics.ConsensusRounds.Inc()
+	metrics.ConsensusView.Set(float64(view))
+}
+
+func (self *roundMetrics) onProposal(blkNum uint32) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if blkNum != self.blockNum || self.proposalSeen || self.start.IsZero() {
+		return
+	}
+	self.proposalSeen = true
+	metrics.ObserveSince(metrics.ConsensusProposalLatency, self.start)
+}
+
+func (self *roundMetrics) onSealed(blkNum uint32, proposer uint32, empty bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	now := time.Now()
+	outcome := RoundOutcome{
+		BlockNum: blkNum,
+		Proposer: proposer,
+		Empty:    empty,
+		SealedAt: now.Unix(),
+	}
+	// blocks sealed by fast-forward have no round started locally
+	if blkNum == self.blockNum && !self.start.IsZero() {
+		outcome.DurationMs = int64(now.Sub(self.start) / time.Millisecond)
+	}
+	self.history[self.historyNext] = outcome
+	self.historyNext = (self.historyNext + 1) % roundHistoryLen
+	if self.historySize < roundHistoryLen {
+		self.historySize++
+	}
+}
+
+//recentRounds return the recorded round outcomes, oldest first
+func (self *roundMetrics) recentRounds() []RoundOutcome {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	rounds := make([]RoundOutcome, 0, self.historySize)
+	start := (self.historyNext - self.historySize + roundHistoryLen) % roundHistoryLen
+	for i := 0; i < self.historySize; i++ {
+		rounds = append(rounds, self.history[(start+i)%roundHistoryLen])
+	}
+	return rounds
+}
+
+func onTimerEventMetrics(evtType TimerEventType) {
+	if name, ok := timeoutEventNames[evtType]; ok {
+		metrics.ConsensusTimeouts.WithLabelValues(name).Inc()
+	}
+}
+
+func onProposalMadeMetrics(forEmpty bool) {
+	metrics.ConsensusProposals.WithLabelValues(strconv.FormatBool(forEmpty)).Inc()
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/signature"
 	"github.com/ontio/ontology/core/types"
+	gover "github.com/ontio/ontology/smartcontract/service/native/governance"
 )
 
 type ConsensusMsgPayload struct {
@@ -211,10 +212,9 @@ func (self *Server) constructBlock(blkNum uint32, prevBlkHash common.Uint256, tx
 		Header:       blkHeader,
 		Transactions: txs,
 	}
-	blkHash := blk.Hash()
-	sig, err := signature.Sign(self.account, blkHash[:])
+	sig, err := types.SignHeader(self.account, blkHeader)
 	if err != nil {
-		return nil, fmt.Errorf("sign block failed, block hash:%s, error: %s", blkHash.ToHexString(), err)
+		return nil, fmt.Errorf("sign block failed, block hash:%s, error: %s", blk.Hash().ToHexString(), err)
 	}
 	blkHeader.Bookkeepers = []keypair.PublicKey{self.account.PublicKey}
 	blkHeader.SigData = [][]byte{sig}
@@ -236,10 +236,9 @@ func (self *Server) constructCrossChainMsg(blkNum uint32) (*types.CrossChainMsg,
 		Height:     blkNum,
 		StatesRoot: root,
 	}
-	hash := msg.Hash()
-	sig, err := signature.Sign(self.account, hash[:])
+	sig, err := types.SignCrossChainMsg(self.account, msg)
 	if err != nil {
-		return nil, fmt.Errorf("sign cross chain msg root failed,msg hash:%s,err:%s", hash.ToHexString(), err)
+		return nil, fmt.Errorf("sign cross chain msg root failed,msg hash:%s,err:%s", msg.Hash().ToHexString(), err)
 	}
 	msg.SigData = append(msg.SigData, sig)
 	return msg, nil
@@ -256,7 +255,7 @@ func (self *Server) constructProposalMsg(blkNum uint32, sysTxs, userTxs []*types
 		blocktimestamp = prevBlk.Block.Header.Timestamp + 1
 	}
 
-	vrfValue, vrfProof, err := computeVrf(self.account.PrivateKey, blkNum, prevBlk.getVrfValue())
+	vrfValue, vrfProof, err := computeVrf(self.account, blkNum, prevBlk.getVrfValue())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get vrf and proof: %s", err)
 	}
@@ -280,6 +279,11 @@ func (self *Server) constructProposalMsg(blkNum uint32, sysTxs, userTxs []*types
 		return nil, err
 	}
 
+	// the block and empty block of the proposal are signed as one proposal
+	if err := self.guardSigning(gover.FaultyProposal, false, blkNum,
+		proposalHash(prevBlkHash, blocktimestamp, consensusPayload)); err != nil {
+		return nil, err
+	}
 	emptyBlk, err := self.constructBlock(blkNum, prevBlkHash, sysTxs, consensusPayload, blocktimestamp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct empty block: %s", err)
@@ -310,14 +314,12 @@ func (self *Server) constructProposalMsg(blkNum uint32, sysTxs, userTxs []*types
 
 func (self *Server) constructEndorseMsg(proposal *blockProposalMsg, forEmpty bool) (*blockEndorseMsg, error) {
 
-	// TODO, support faultyMsg reporting
-
 	var proposerSig, endorserSig []byte
-	var blkHash common.Uint256
+	var blk *types.Block
 	var err error
 	if !forEmpty {
 		proposerSig = proposal.BlockProposerSig
-		blkHash = proposal.Block.Block.Hash()
+		blk = proposal.Block.Block
 
 	} else {
 		if proposal.Block.EmptyBlock == nil {
@@ -326,9 +328,13 @@ func (self *Server) constructEndorseMsg(proposal *blockProposalMsg, forEmpty boo
 		}
 
 		proposerSig = proposal.EmptyBlockProposerSig
-		blkHash = proposal.Block.EmptyBlock.Hash()
+		blk = proposal.Block.EmptyBlock
 	}
-	endorserSig, err = signature.Sign(self.account, blkHash[:])
+	blkHash := blk.Hash()
+	if err := self.guardSigning(gover.FaultyEndorse, forEmpty, proposal.GetBlockNum(), blkHash); err != nil {
+		return nil, err
+	}
+	endorserSig, err = types.SignHeader(self.account, blk.Header)
 	if err != nil {
 		return nil, fmt.Errorf("endorser failed to sign block. hash:%x, err: %s", blkHash, err)
 	}
@@ -339,12 +345,13 @@ func (self *Server) constructEndorseMsg(proposal *blockProposalMsg, forEmpty boo
 		BlockNum:          proposal.Block.getBlockNum(),
 		EndorsedBlockHash: blkHash,
 		EndorseForEmpty:   forEmpty,
+		FaultyProposals:   self.faulty.reports(proposal.GetBlockNum(), gover.FaultyProposal),
 		ProposerSig:       proposerSig,
 		EndorserSig:       endorserSig,
 	}
 	if proposal.Block.CrossChainMsg != nil {
 		hash := proposal.Block.CrossChainMsg.Hash()
-		sig, err := signature.Sign(self.account, hash[:])
+		sig, err := types.SignCrossChainMsg(self.account, proposal.Block.CrossChainMsg)
 		if err != nil {
 			return nil, fmt.Errorf("sign cross chain msg root failed,msg hash:%s,err:%s", hash.ToHexString(), err)
 		}
@@ -356,15 +363,13 @@ func (self *Server) constructEndorseMsg(proposal *blockProposalMsg, forEmpty boo
 
 func (self *Server) constructCommitMsg(proposal *blockProposalMsg, endorses []*blockEndorseMsg, forEmpty bool) (*blockCommitMsg, error) {
 
-	// TODO, support faultyMsg reporting
-
 	var proposerSig, committerSig []byte
-	var blkHash common.Uint256
+	var blk *types.Block
 	var err error
 
 	if !forEmpty {
 		proposerSig = proposal.BlockProposerSig
-		blkHash = proposal.Block.Block.Hash()
+		blk = proposal.Block.Block
 	} else {
 		if proposal.Block.EmptyBlock == nil {
 			return nil, fmt.Errorf("blk %d proposal from %d has no empty proposal",
@@ -372,9 +377,13 @@ func (self *Server) constructCommitMsg(proposal *blockProposalMsg, endorses []*b
 		}
 
 		proposerSig = proposal.EmptyBlockProposerSig
-		blkHash = proposal.Block.EmptyBlock.Hash()
+		blk = proposal.Block.EmptyBlock
+	}
+	blkHash := blk.Hash()
+	if err := self.guardSigning(gover.FaultyCommit, forEmpty, proposal.GetBlockNum(), blkHash); err != nil {
+		return nil, err
 	}
-	committerSig, err = signature.Sign(self.account, blkHash[:])
+	committerSig, err = types.SignHeader(self.account, blk.Header)
 	if err != nil {
 		return nil, fmt.Errorf("endorser failed to sign block. hash:%x, caused by: %s", blkHash, err)
 	}
@@ -403,6 +412,7 @@ func (self *Server) constructCommitMsg(proposal *blockProposalMsg, endorses []*b
 		BlockNum:                  proposal.Block.getBlockNum(),
 		CommitBlockHash:           blkHash,
 		CommitForEmpty:            forEmpty,
+		FaultyVerifies:            self.faulty.reports(proposal.GetBlockNum(), gover.FaultyEndorse, gover.FaultyCommit),
 		ProposerSig:               proposerSig,
 		EndorsersSig:              endorsersSig,
 		CommitterSig:              committerSig,
@@ -412,7 +422,7 @@ func (self *Server) constructCommitMsg(proposal *blockProposalMsg, endorses []*b
 	}
 
 	if proposal.Block.CrossChainMsg != nil && commitCrossChain {
-		sig, err := signature.Sign(self.account, hash[:])
+		sig, err := types.SignCrossChainMsg(self.account, proposal.Block.CrossChainMsg)
 		if err != nil {
 			return nil, fmt.Errorf("sign cross chain msg root failed,msg hash:%s,err:%s", hash.ToHexString(), err)
 		}
@@ -455,7 +465,7 @@ func (self *Server) constructProposalFetchMsg(blkNum uint32, proposer uint32) *p
 }
 
 func (self *Server) constructBlockSubmitMsg(blkNum uint32, stateRoot common.Uint256) (*blockSubmitMsg, error) {
-	submitSig, err := signature.Sign(self.account, stateRoot[:])
+	submitSig, err := signature.SignPayload(self.account, signature.PAYLOAD_STATE_ROOT, stateRoot[:])
 	if err != nil {
 		return nil, fmt.Errorf("submit failed to sign stateroot hash:%x, err: %s", stateRoot, err)
 	}
@@ -429,12 +429,12 @@ func (self *Server) heartbeat() {
 	}
 }
 
-func (self *Server) receiveFromPeer(peerIdx uint32) (uint32, []byte, error) {
+func (self *Server) receiveFromPeer(peerIdx uint32) (uint32, *p2pmsg.ConsensusPayload, error) {
 	if C := self.GetPeerMsgChan(peerIdx); C != nil {
 		select {
 		case payload := <-C:
 			if payload != nil {
-				return payload.fromPeer, payload.payload.Data, nil
+				return payload.fromPeer, payload.payload, nil
 			}
 
 		case <-self.quitC:
@@ -457,7 +457,7 @@ func (self *Server) sendToPeer(peerIdx uint32, data []byte) error {
 
 	sink := common.NewZeroCopySink(nil)
 	msg.SerializationUnsigned(sink)
-	msg.Signature, _ = signature.Sign(self.account, sink.Bytes())
+	msg.Signature, _ = signature.SignPayload(self.account, signature.PAYLOAD_CONSENSUS, sink.Bytes())
 
 	cons := msgpack.NewConsensus(msg)
 	p2pid, present := self.peerPool.getP2pId(peerIdx)
@@ -484,7 +484,7 @@ func (self *Server) broadcastToAll(data []byte) {
 
 	sink := common.NewZeroCopySink(nil)
 	payload.SerializationUnsigned(sink)
-	payload.Signature, _ = signature.Sign(self.account, sink.Bytes())
+	payload.Signature, _ = signature.SignPayload(self.account, signature.PAYLOAD_CONSENSUS, sink.Bytes())
 
 	msg := msgpack.NewConsensus(payload)
 	go self.p2p.Broadcast(msg)
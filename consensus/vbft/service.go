@@ -31,6 +31,7 @@ import (
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	actorTypes "github.com/ontio/ontology/consensus/actor"
 	vconfig "github.com/ontio/ontology/consensus/vbft/config"
@@ -128,6 +129,10 @@ type Server struct {
 	quitC      chan struct{}
 	quit       bool
 	quitWg     sync.WaitGroup
+
+	roundMetrics roundMetrics
+	faulty       faultyDetector
+	signGuard    *signGuard
 }
 
 func NewVbftServer(account *account.Account, txpool *actor.PID, p2p p2p.P2P) (*Server, error) {
@@ -140,6 +145,11 @@ func NewVbftServer(account *account.Account, txpool *actor.PID, p2p p2p.P2P) (*S
 		incrValidator:      increment.NewIncrementValidator(20),
 	}
 	server.stateMgr = newStateMgr(server)
+	guard, err := loadSignGuard(signGuardPath(config.DefConfig.Common.DataDir, config.DefConfig.P2PNode.NetworkName))
+	if err != nil {
+		return nil, fmt.Errorf("vbft server load signed msgs failed: %s", err)
+	}
+	server.signGuard = guard
 
 	props := actor.FromProducer(func() actor.Actor {
 		return server
@@ -174,6 +184,8 @@ func (self *Server) Receive(context actor.Context) {
 		log.Info("vbft actor start consensus")
 	case *actorTypes.StopConsensus:
 		self.stop()
+	case *actorTypes.GetConsensusStatus:
+		context.Respond(&actorTypes.ConsensusStatusRsp{Status: self.GetConsensusStatus()})
 	case *message.SaveBlockCompleteMsg:
 		log.Infof("vbft actor SaveBlockCompleteMsg receives block complete event. block height=%d, numtx=%d",
 			msg.Block.Header.Height, len(msg.Block.Transactions))
@@ -213,6 +225,7 @@ func (self *Server) handleBlockPersistCompleted(block *types.Block) {
 	}
 	self.SetCompletedBlockNum(block.Header.Height)
 	self.incrValidator.AddBlock(block)
+	self.faulty.prune(block.Header.Height)
 	if self.nonConsensusNode() {
 		self.blockPool.ReloadFromLedger()
 		if self.GetCommittedBlockNo() >= self.GetCurrentBlockNo() {
@@ -512,7 +525,7 @@ func (self *Server) initialize() error {
 
 func (self *Server) start() error {
 	// check if server pubkey support VRF
-	if !vrf.ValidatePrivateKey(self.account.PrivateKey) || !vrf.ValidatePublicKey(self.account.PublicKey) {
+	if (!self.account.IsRemote() && !vrf.ValidatePrivateKey(self.account.PrivateKey)) || !vrf.ValidatePublicKey(self.account.PublicKey) {
 		return fmt.Errorf("server %d consensus start failed: invalid account key for VRF", self.Index)
 	}
 
@@ -585,11 +598,12 @@ func (self *Server) run(peerPubKey keypair.PublicKey) error {
 	errC := make(chan error)
 	go func() {
 		for {
-			fromPeer, msgData, err := self.receiveFromPeer(peerIdx)
+			fromPeer, payload, err := self.receiveFromPeer(peerIdx)
 			if err != nil {
 				errC <- err
 				return
 			}
+			msgData := payload.Data
 			msg, err := DeserializeVbftMsg(msgData)
 
 			if err != nil {
@@ -619,7 +633,7 @@ func (self *Server) run(peerPubKey keypair.PublicKey) error {
 						self.Index, msg.GetBlockNum(), msg.Type(), fromPeer)
 				}
 
-				self.onConsensusMsg(fromPeer, msg, hashData(msgData))
+				self.onConsensusMsg(fromPeer, msg, hashData(msgData), payload)
 			}
 		}
 	}()
@@ -657,6 +671,7 @@ func (self *Server) updateParticipantConfig() error {
 
 func (self *Server) startNewRound() error {
 	blkNum := self.GetCurrentBlockNo()
+	self.roundMetrics.startRound(blkNum, self.GetChainConfig().View)
 
 	if err := self.updateParticipantConfig(); err != nil {
 		log.Errorf("startNewRound error:%s", err)
@@ -749,7 +764,7 @@ func (self *Server) startNewProposal(blkNum uint32) {
 }
 
 // verify consensus messsage, then send msg to processMsgEvent
-func (self *Server) onConsensusMsg(peerIdx uint32, msg ConsensusMsg, msgHash common.Uint256) {
+func (self *Server) onConsensusMsg(peerIdx uint32, msg ConsensusMsg, msgHash common.Uint256, payload *p2pmsg.ConsensusPayload) {
 
 	if self.msgPool.HasMsg(msg, msgHash) && msg.Type() != BlockCommitMessage {
 		// dup msg checking
@@ -764,6 +779,7 @@ func (self *Server) onConsensusMsg(peerIdx uint32, msg ConsensusMsg, msgHash com
 			log.Error("invalid msg with proposal msg type")
 			return
 		}
+		self.detectFaulty(peerIdx, pMsg, payload)
 
 		msgBlkNum := pMsg.GetBlockNum()
 		if msgBlkNum > self.GetCurrentBlockNo() {
@@ -819,6 +835,7 @@ func (self *Server) onConsensusMsg(peerIdx uint32, msg ConsensusMsg, msgHash com
 			log.Error("invalid msg with endorse msg type")
 			return
 		}
+		self.detectFaulty(peerIdx, pMsg, payload)
 
 		// TODO: verify msg
 
@@ -876,6 +893,7 @@ func (self *Server) onConsensusMsg(peerIdx uint32, msg ConsensusMsg, msgHash com
 			log.Error("invalid msg with commit msg type")
 			return
 		}
+		self.detectFaulty(peerIdx, pMsg, payload)
 
 		// TODO: verify msg
 
@@ -1083,6 +1101,7 @@ func (self *Server) verifyCrossChainMsg(msg *blockProposalMsg) bool {
 
 func (self *Server) processProposalMsg(msg *blockProposalMsg) {
 	msgBlkNum := msg.GetBlockNum()
+	self.roundMetrics.onProposal(msgBlkNum)
 	blk, prevBlkHash := self.blockPool.getSealedBlock(msg.GetBlockNum() - 1)
 	if blk == nil {
 		log.Errorf("BlockProposal failed to GetPreBlock:%d", msg.GetBlockNum()-1)
@@ -1654,6 +1673,7 @@ func (self *Server) timerLoop() {
 }
 
 func (self *Server) processTimerEvent(evt *TimerEvent) error {
+	onTimerEventMetrics(evt.evtType)
 	switch evt.evtType {
 	case EventProposalBackoff:
 		// 1. if endorsed, return
@@ -2069,6 +2089,8 @@ func (self *Server) sealBlock(block *Block, empty bool, sigdata bool) error {
 	self.msgPool.onBlockSealed(sealedBlkNum)
 	self.blockPool.onBlockSealed(sealedBlkNum)
 
+	self.roundMetrics.onSealed(sealedBlkNum, block.getProposer(), empty)
+
 	_, h := self.blockPool.getSealedBlock(sealedBlkNum)
 	prevBlkHash := block.getPrevBlockHash()
 	log.Infof("server %d, sealed block %d, proposer %d, prevhash: %s, hash: %s", self.Index,
@@ -2229,6 +2251,7 @@ func (self *Server) makeProposal(blkNum uint32, forEmpty bool) error {
 	self.msgPool.AddMsg(proposal, h)
 	self.processProposalMsg(proposal)
 	self.broadcast(proposal)
+	onProposalMadeMetrics(forEmpty)
 	return nil
 }
 
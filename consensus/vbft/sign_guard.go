@@ -0,0 +1,153 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ontio/ontology/common"
+)
+
+// file in the chain data dir keeping the last consensus msgs signed by the server
+const signGuardFile = "vbft_signed.json"
+
+//signedRecord is the last consensus msg of one kind signed by the server
+type signedRecord struct {
+	Height uint32         `json:"height"`
+	View   uint32         `json:"view"`
+	Hash   common.Uint256 `json:"hash"`
+}
+
+//signGuard persists the last signed proposal, endorse and commit msgs of the server, and refuses to sign
+//a conflicting msg, so that the server would not be reported as faulty after it crashed and restarted.
+type signGuard struct {
+	lock    sync.Mutex
+	path    string
+	records map[string]*signedRecord
+}
+
+func signGuardPath(dataDir, networkName string) string {
+	return filepath.Join(dataDir, networkName, signGuardFile)
+}
+
+func loadSignGuard(path string) (*signGuard, error) {
+	guard := &signGuard{
+		path:    path,
+		records: make(map[string]*signedRecord),
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return guard, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read signed msgs: %s", err)
+	}
+	if err := json.Unmarshal(data, &guard.records); err != nil {
+		return nil, fmt.Errorf("unmarshal signed msgs %s: %s", path, err)
+	}
+	return guard, nil
+}
+
+func signedKind(faultyType uint32, forEmpty bool) string {
+	if forEmpty {
+		return faultyTypeNames[faultyType] + "_empty"
+	}
+	return faultyTypeNames[faultyType]
+}
+
+//sign checks the msg to sign against the last signed one of the same kind, and persists it before the
+//msg is signed. Signing a msg of lower height, or of the same height with different hash is refused.
+func (self *signGuard) sign(faultyType uint32, forEmpty bool, height, view uint32, hash common.Uint256) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	kind := signedKind(faultyType, forEmpty)
+	if last, present := self.records[kind]; present {
+		if height < last.Height {
+			return fmt.Errorf("refuse to sign %s of blk %d, already signed blk %d", kind, height, last.Height)
+		}
+		if height == last.Height {
+			if hash == last.Hash {
+				return nil
+			}
+			return fmt.Errorf("refuse to sign %s of blk %d, conflicting with signed %s", kind, height,
+				last.Hash.ToHexString())
+		}
+	}
+	self.records[kind] = &signedRecord{Height: height, View: view, Hash: hash}
+	if err := self.persist(); err != nil {
+		delete(self.records, kind)
+		return err
+	}
+	return nil
+}
+
+//persist writes the records to a temp file and renames it, the file is never left partially written
+func (self *signGuard) persist() error {
+	data, err := json.Marshal(self.records)
+	if err != nil {
+		return fmt.Errorf("marshal signed msgs: %s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(self.path), 0700); err != nil {
+		return fmt.Errorf("create signed msgs dir: %s", err)
+	}
+	tmp := self.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("open signed msgs: %s", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("write signed msgs: %s", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("sync signed msgs: %s", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close signed msgs: %s", err)
+	}
+	if err := os.Rename(tmp, self.path); err != nil {
+		return fmt.Errorf("rename signed msgs: %s", err)
+	}
+	return nil
+}
+
+//guardSigning records the consensus msg to sign by the server, the server without sign guard signs anything
+func (self *Server) guardSigning(faultyType uint32, forEmpty bool, height uint32, hash common.Uint256) error {
+	if self.signGuard == nil {
+		return nil
+	}
+	return self.signGuard.sign(faultyType, forEmpty, height, self.GetChainConfig().View, hash)
+}
+
+//proposalHash identifies the proposal of a round, the block and empty block of one proposal share the
+//previous block hash, timestamp and consensus payload
+func proposalHash(prevBlkHash common.Uint256, timestamp uint32, consensusPayload []byte) common.Uint256 {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteHash(prevBlkHash)
+	sink.WriteUint32(timestamp)
+	sink.WriteVarBytes(consensusPayload)
+	return common.Uint256(sha256.Sum256(sink.Bytes()))
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	gover "github.com/ontio/ontology/smartcontract/service/native/governance"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignGuard(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vbft-sign-guard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := signGuardPath(dir, "testnet")
+
+	guard, err := loadSignGuard(path)
+	assert.Nil(t, err)
+	assert.Nil(t, guard.sign(gover.FaultyEndorse, false, 10, 1, common.Uint256{1}))
+	// signing the same msg again is allowed
+	assert.Nil(t, guard.sign(gover.FaultyEndorse, false, 10, 1, common.Uint256{1}))
+	// the empty block is endorsed separately
+	assert.Nil(t, guard.sign(gover.FaultyEndorse, true, 10, 1, common.Uint256{2}))
+	assert.Nil(t, guard.sign(gover.FaultyCommit, false, 10, 1, common.Uint256{1}))
+	assert.NotNil(t, guard.sign(gover.FaultyEndorse, false, 10, 1, common.Uint256{3}))
+	_, err = os.Stat(filepath.Join(dir, "testnet", signGuardFile))
+	assert.Nil(t, err)
+
+	// the signed msgs survive restarting
+	guard, err = loadSignGuard(path)
+	assert.Nil(t, err)
+	assert.NotNil(t, guard.sign(gover.FaultyEndorse, false, 10, 1, common.Uint256{3}))
+	assert.NotNil(t, guard.sign(gover.FaultyCommit, false, 9, 1, common.Uint256{3}))
+	assert.Nil(t, guard.sign(gover.FaultyEndorse, false, 11, 1, common.Uint256{3}))
+
+	hash := proposalHash(common.Uint256{1}, 100, []byte("payload"))
+	assert.Nil(t, guard.sign(gover.FaultyProposal, false, 11, 1, hash))
+	assert.NotNil(t, guard.sign(gover.FaultyProposal, false, 11, 1, proposalHash(common.Uint256{1}, 101, []byte("payload"))))
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("{"), 0600))
+	_, err = loadSignGuard(path)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"sort"
+
+	vconfig "github.com/ontio/ontology/consensus/vbft/config"
+)
+
+var serverStateNames = map[ServerState]string{
+	Init:             "Init",
+	LocalConfigured:  "LocalConfigured",
+	Configured:       "Configured",
+	Syncing:          "Syncing",
+	WaitNetworkReady: "WaitNetworkReady",
+	SyncReady:        "SyncReady",
+	Synced:           "Synced",
+	SyncingCheck:     "SyncingCheck",
+}
+
+//names of the timer events other than the consensus timeouts in timeoutEventNames
+var otherTimerEventNames = map[TimerEventType]string{
+	EventProposalBackoff: "proposal_backoff",
+	EventRandomBackoff:   "random_backoff",
+	EventPeerHeartbeat:   "peer_heartbeat",
+	EventTxPool:          "tx_pool",
+	EventTxBlockTimeout:  "tx_block",
+}
+
+func (evtType TimerEventType) String() string {
+	if name, ok := timeoutEventNames[evtType]; ok {
+		return name
+	}
+	if name, ok := otherTimerEventNames[evtType]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func (state ServerState) String() string {
+	if name, ok := serverStateNames[state]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+//PeerStatus is the latest known state of a consensus peer
+type PeerStatus struct {
+	Index                uint32 `json:"index"`
+	ID                   string `json:"id"`
+	Connected            bool   `json:"connected"`
+	CommittedBlockNumber uint32 `json:"committed_block_number"`
+	ChainConfigView      uint32 `json:"chain_config_view"`
+	LastHeartbeat        int64  `json:"last_heartbeat"`
+}
+
+//RoundMsgStatus counts the consensus msgs pending for one block
+type RoundMsgStatus struct {
+	BlockNum     uint32 `json:"block_num"`
+	Proposals    int    `json:"proposals"`
+	Endorsements int    `json:"endorsements"`
+	Commits      int    `json:"commits"`
+	Sealed       bool   `json:"sealed"`
+}
+
+//TimerStatus lists the blocks with a running timer of one event type
+type TimerStatus struct {
+	Event  string   `json:"event"`
+	Blocks []uint32 `json:"blocks"`
+}
+
+//ConsensusStatus is the snapshot of vbft internal state for diagnosis
+type ConsensusStatus struct {
+	Index              uint32               `json:"index"`
+	State              string               `json:"state"`
+	CurrentBlockNum    uint32               `json:"current_block_num"`
+	CommittedBlockNum  uint32               `json:"committed_block_num"`
+	LastConfigBlockNum uint32               `json:"last_config_block_num"`
+	ChainConfig        *vconfig.ChainConfig `json:"chain_config"`
+	Peers              []*PeerStatus        `json:"peers"`
+	Rounds             []*RoundMsgStatus    `json:"rounds"`
+	Timers             []*TimerStatus       `json:"timers"`
+	RecentRounds       []RoundOutcome       `json:"recent_rounds"`
+}
+
+//GetConsensusStatus take a snapshot of server state, each component is read under its own lock
+func (self *Server) GetConsensusStatus() *ConsensusStatus {
+	cfg := self.GetChainConfig()
+	self.metaLock.RLock()
+	lastConfigBlkNum := self.LastConfigBlockNum
+	self.metaLock.RUnlock()
+
+	status := &ConsensusStatus{
+		Index:              self.Index,
+		State:              self.getState().String(),
+		CurrentBlockNum:    self.GetCurrentBlockNo(),
+		CommittedBlockNum:  self.GetCommittedBlockNo(),
+		LastConfigBlockNum: lastConfigBlkNum,
+		ChainConfig:        &cfg,
+		RecentRounds:       self.roundMetrics.recentRounds(),
+	}
+	if self.peerPool != nil {
+		status.Peers = self.peerPool.getPeerStatus()
+	}
+	if self.msgPool != nil && self.blockPool != nil {
+		status.Rounds = self.blockPool.getRoundStatus(self.msgPool.getRoundStatus())
+	}
+	if self.timer != nil {
+		status.Timers = self.timer.getTimerStatus()
+	}
+	return status
+}
+
+func (pool *PeerPool) getPeerStatus() []*PeerStatus {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	peers := make([]*PeerStatus, 0, len(pool.configs))
+	for idx, cfg := range pool.configs {
+		p := &PeerStatus{
+			Index: idx,
+			ID:    cfg.ID,
+		}
+		if peer, present := pool.peers[idx]; present {
+			p.Connected = peer.connected
+			if peer.LatestInfo != nil {
+				p.CommittedBlockNumber = peer.LatestInfo.CommittedBlockNumber
+				p.ChainConfigView = peer.LatestInfo.ChainConfigView
+			}
+			if !peer.LastUpdateTime.IsZero() {
+				p.LastHeartbeat = peer.LastUpdateTime.Unix()
+			}
+		}
+		peers = append(peers, p)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Index < peers[j].Index })
+	return peers
+}
+
+func (pool *MsgPool) getRoundStatus() map[uint32]*RoundMsgStatus {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	rounds := make(map[uint32]*RoundMsgStatus, len(pool.rounds))
+	for blkNum, round := range pool.rounds {
+		rounds[blkNum] = &RoundMsgStatus{
+			BlockNum:     blkNum,
+			Proposals:    len(round.msgs[BlockProposalMessage]),
+			Endorsements: len(round.msgs[BlockEndorseMessage]),
+			Commits:      len(round.msgs[BlockCommitMessage]),
+		}
+	}
+	return rounds
+}
+
+//getRoundStatus merge the sealed flag of candidate blocks into msg pool counts
+func (pool *BlockPool) getRoundStatus(rounds map[uint32]*RoundMsgStatus) []*RoundMsgStatus {
+	pool.lock.RLock()
+	for blkNum, candidate := range pool.candidateBlocks {
+		r, present := rounds[blkNum]
+		if !present {
+			r = &RoundMsgStatus{BlockNum: blkNum}
+			rounds[blkNum] = r
+		}
+		r.Sealed = candidate.SealedBlock != nil
+	}
+	pool.lock.RUnlock()
+
+	result := make([]*RoundMsgStatus, 0, len(rounds))
+	for _, r := range rounds {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].BlockNum < result[j].BlockNum })
+	return result
+}
+
+func (self *EventTimer) getTimerStatus() []*TimerStatus {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	timers := make([]*TimerStatus, 0)
+	for i := 0; i < int(EventMax); i++ {
+		evtType := TimerEventType(i)
+		blocks := make([]uint32, 0, len(self.eventTimers[evtType]))
+		for blkNum := range self.eventTimers[evtType] {
+			blocks = append(blocks, blkNum)
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+		sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+		timers = append(timers, &TimerStatus{
+			Event:  evtType.String(),
+			Blocks: blocks,
+		})
+	}
+	return timers
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"testing"
+	"time"
+
+	vconfig "github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecentRounds(t *testing.T) {
+	metrics := &roundMetrics{}
+	assert.Empty(t, metrics.recentRounds())
+
+	metrics.startRound(1, 0)
+	metrics.onSealed(1, 2, false)
+	rounds := metrics.recentRounds()
+	assert.Equal(t, 1, len(rounds))
+	assert.Equal(t, uint32(1), rounds[0].BlockNum)
+	assert.Equal(t, uint32(2), rounds[0].Proposer)
+
+	for i := uint32(2); i < roundHistoryLen+10; i++ {
+		metrics.onSealed(i, i%3, i%2 == 0)
+	}
+	rounds = metrics.recentRounds()
+	assert.Equal(t, roundHistoryLen, len(rounds))
+	assert.Equal(t, uint32(10), rounds[0].BlockNum)
+	assert.Equal(t, uint32(roundHistoryLen+9), rounds[roundHistoryLen-1].BlockNum)
+	assert.True(t, rounds[roundHistoryLen-1].Empty == ((roundHistoryLen+9)%2 == 0))
+}
+
+func TestGetConsensusStatus(t *testing.T) {
+	server := constructServer()
+	server.SetCurrentBlockNo(3)
+	server.peerPool = NewPeerPool(0, server)
+	server.peerPool.configs[1] = &vconfig.PeerConfig{Index: 1, ID: "peer1"}
+	server.peerPool.configs[2] = &vconfig.PeerConfig{Index: 2, ID: "peer2"}
+	server.peerPool.peers[2] = &Peer{
+		Index:          2,
+		LatestInfo:     &peerHeartbeatMsg{CommittedBlockNumber: 2, ChainConfigView: 12},
+		LastUpdateTime: time.Unix(1000, 0),
+		connected:      true,
+	}
+	server.msgPool = newMsgPool(server, 10)
+	server.blockPool = &BlockPool{server: server, candidateBlocks: make(map[uint32]*CandidateInfo)}
+	server.timer = NewEventTimer(server)
+	server.timer.StartProposalTimer(3)
+	defer server.timer.CancelProposalTimer(3)
+
+	block, err := constructBlock()
+	assert.Nil(t, err)
+	proposal := &blockProposalMsg{Block: block}
+	h, _ := HashMsg(proposal)
+	assert.Nil(t, server.msgPool.AddMsg(proposal, h))
+	server.blockPool.candidateBlocks[block.getBlockNum()] = &CandidateInfo{SealedBlock: block}
+
+	status := server.GetConsensusStatus()
+	assert.Equal(t, "Syncing", status.State)
+	assert.Equal(t, uint32(3), status.CurrentBlockNum)
+	assert.Equal(t, uint32(12), status.ChainConfig.View)
+
+	assert.Equal(t, 2, len(status.Peers))
+	assert.Equal(t, uint32(1), status.Peers[0].Index)
+	assert.False(t, status.Peers[0].Connected)
+	assert.True(t, status.Peers[1].Connected)
+	assert.Equal(t, uint32(2), status.Peers[1].CommittedBlockNumber)
+	assert.Equal(t, int64(1000), status.Peers[1].LastHeartbeat)
+
+	assert.Equal(t, 1, len(status.Rounds))
+	assert.Equal(t, block.getBlockNum(), status.Rounds[0].BlockNum)
+	assert.Equal(t, 1, status.Rounds[0].Proposals)
+	assert.True(t, status.Rounds[0].Sealed)
+
+	assert.Equal(t, 1, len(status.Timers))
+	assert.Equal(t, "propose_block", status.Timers[0].Event)
+	assert.Equal(t, []uint32{3}, status.Timers[0].Blocks)
+}
@@ -47,7 +47,7 @@ func SignMsg(account *account.Account, msg ConsensusMsg) ([]byte, error) {
 		return nil, fmt.Errorf("failed to marshal msg when signing: %s", err)
 	}
 
-	return signature.Sign(account, data)
+	return signature.SignPayload(account, signature.PAYLOAD_CONSENSUS, data)
 }
 
 func hashData(data []byte) common.Uint256 {
@@ -95,7 +95,7 @@ type vrfData struct {
 	PrevVrf  []byte `json:"prev_vrf"`
 }
 
-func computeVrf(sk keypair.PrivateKey, blkNum uint32, prevVrf []byte) ([]byte, []byte, error) {
+func computeVrf(signer *account.Account, blkNum uint32, prevVrf []byte) ([]byte, []byte, error) {
 	data, err := json.Marshal(&vrfData{
 		BlockNum: blkNum,
 		PrevVrf:  prevVrf,
@@ -104,7 +104,7 @@ func computeVrf(sk keypair.PrivateKey, blkNum uint32, prevVrf []byte) ([]byte, [
 		return nil, nil, fmt.Errorf("computeVrf failed to marshal vrfData: %s", err)
 	}
 
-	return vrf.Vrf(sk, data)
+	return signer.Vrf(data)
 }
 
 func verifyVrf(pk keypair.PublicKey, blkNum uint32, prevVrf, newVrf, proof []byte) error {
@@ -31,10 +31,22 @@ var DefLedger *Ledger
 
 type Ledger struct {
 	store.LedgerStore
+
+	dataDir           string
+	stateHashHeight   uint32
+	defaultBookkeeper []keypair.PublicKey
+	genesisBlock      *types.Block
+	stateHistory      bool
+	addressIndex      bool
+	snapshots         []string
+	locked            *lockedStore
 }
 
+//GetStore returns the underlying ledger store, which is not guarded against Snapshot and Revert
 func (self *Ledger) GetStore() store.LedgerStore {
-	return self.LedgerStore
+	self.locked.lock.RLock()
+	defer self.locked.lock.RUnlock()
+	return self.locked.store
 }
 
 func InitLedger(dataDir string, stateHashHeight uint32, defaultBookkeeper []keypair.PublicKey,
@@ -49,7 +61,29 @@ func InitLedger(dataDir string, stateHashHeight uint32, defaultBookkeeper []keyp
 		return nil, err
 	}
 
+	locked := &lockedStore{store: ldgStore}
 	return &Ledger{
-		LedgerStore: ldgStore,
+		LedgerStore:       locked,
+		locked:            locked,
+		dataDir:           dataDir,
+		stateHashHeight:   stateHashHeight,
+		defaultBookkeeper: defaultBookkeeper,
+		genesisBlock:      genesisBlock,
 	}, nil
 }
+
+func (self *Ledger) EnableStateHistory() error {
+	err := self.LedgerStore.EnableStateHistory()
+	if err == nil {
+		self.stateHistory = true
+	}
+	return err
+}
+
+func (self *Ledger) EnableAddressIndex() error {
+	err := self.LedgerStore.EnableAddressIndex()
+	if err == nil {
+		self.addressIndex = true
+	}
+	return err
+}
@@ -0,0 +1,371 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledger
+
+import (
+	"sync"
+
+	common2 "github.com/ethereum/go-ethereum/common"
+	types2 "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/core/store"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/context"
+	"github.com/ontio/ontology/smartcontract/event"
+	types3 "github.com/ontio/ontology/smartcontract/service/evm/types"
+	cstates "github.com/ontio/ontology/smartcontract/states"
+	"github.com/ontio/ontology/smartcontract/storage"
+	"github.com/ontio/ontology/vm/evm"
+)
+
+//lockedStore guards the calls to the ledger store, so that the store is not closed or swapped by Snapshot and
+//Revert of the ledger while it is in use
+type lockedStore struct {
+	lock  sync.RWMutex
+	store store.LedgerStore
+}
+
+func (self *lockedStore) InitLedgerStoreWithGenesisBlock(genesisblock *types.Block, defaultBookkeeper []keypair.PublicKey) error {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.InitLedgerStoreWithGenesisBlock(genesisblock, defaultBookkeeper)
+}
+
+func (self *lockedStore) Close() error {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.Close()
+}
+
+func (self *lockedStore) AddHeaders(headers []*types.Header) error {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.AddHeaders(headers)
+}
+
+func (self *lockedStore) AddBlock(block *types.Block, ccMsg *types.CrossChainMsg, stateMerkleRoot common.Uint256) error {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.AddBlock(block, ccMsg, stateMerkleRoot)
+}
+
+func (self *lockedStore) ExecuteBlock(b *types.Block) (store.ExecuteResult, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.ExecuteBlock(b)
+}
+
+func (self *lockedStore) SubmitBlock(b *types.Block, crossChainMsg *types.CrossChainMsg, exec store.ExecuteResult) error {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.SubmitBlock(b, crossChainMsg, exec)
+}
+
+func (self *lockedStore) GetStateMerkleRoot(height uint32) (result common.Uint256, err error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetStateMerkleRoot(height)
+}
+
+func (self *lockedStore) GetCurrentBlockHash() common.Uint256 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetCurrentBlockHash()
+}
+
+func (self *lockedStore) GetCurrentBlockHeight() uint32 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetCurrentBlockHeight()
+}
+
+func (self *lockedStore) GetCurrentHeaderHeight() uint32 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetCurrentHeaderHeight()
+}
+
+func (self *lockedStore) GetFilterStart() uint32 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetFilterStart()
+}
+
+func (self *lockedStore) GetIndexStore() *leveldbstore.LevelDBStore {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetIndexStore()
+}
+
+func (self *lockedStore) GetCurrentHeaderHash() common.Uint256 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetCurrentHeaderHash()
+}
+
+func (self *lockedStore) GetBlockHash(height uint32) common.Uint256 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetBlockHash(height)
+}
+
+func (self *lockedStore) GetHeaderByHash(blockHash common.Uint256) (*types.Header, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetHeaderByHash(blockHash)
+}
+
+func (self *lockedStore) GetRawHeaderByHash(blockHash common.Uint256) (*types.RawHeader, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetRawHeaderByHash(blockHash)
+}
+
+func (self *lockedStore) GetHeaderByHeight(height uint32) (*types.Header, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetHeaderByHeight(height)
+}
+
+func (self *lockedStore) GetBlockByHash(blockHash common.Uint256) (*types.Block, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetBlockByHash(blockHash)
+}
+
+func (self *lockedStore) GetBlockByHeight(height uint32) (*types.Block, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetBlockByHeight(height)
+}
+
+func (self *lockedStore) GetTransaction(txHash common.Uint256) (*types.Transaction, uint32, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetTransaction(txHash)
+}
+
+func (self *lockedStore) GetBloomData(height uint32) (types2.Bloom, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetBloomData(height)
+}
+
+func (self *lockedStore) BloomStatus() (uint32, uint32) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.BloomStatus()
+}
+
+func (self *lockedStore) IsContainBlock(blockHash common.Uint256) (bool, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.IsContainBlock(blockHash)
+}
+
+func (self *lockedStore) IsContainTransaction(txHash common.Uint256) (bool, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.IsContainTransaction(txHash)
+}
+
+func (self *lockedStore) GetBlockRootWithNewTxRoots(startHeight uint32, txRoots []common.Uint256) common.Uint256 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetBlockRootWithNewTxRoots(startHeight, txRoots)
+}
+
+func (self *lockedStore) GetMerkleProof(m, n uint32) ([]common.Uint256, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetMerkleProof(m, n)
+}
+
+func (self *lockedStore) GetContractState(contractHash common.Address) (*payload.DeployCode, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetContractState(contractHash)
+}
+
+func (self *lockedStore) GetBookkeeperState() (*states.BookkeeperState, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetBookkeeperState()
+}
+
+func (self *lockedStore) GetStorageItem(codeHash common.Address, key []byte) ([]byte, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetStorageItem(codeHash, key)
+}
+
+func (self *lockedStore) PreExecuteContract(tx *types.Transaction) (*cstates.PreExecResult, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.PreExecuteContract(tx)
+}
+
+func (self *lockedStore) PreExecuteContractBatch(txes []*types.Transaction, atomic bool) ([]*cstates.PreExecResult, uint32, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.PreExecuteContractBatch(txes, atomic)
+}
+
+func (self *lockedStore) PreExecuteContractWithTracer(tx *types.Transaction, tracer context.Tracer) (*cstates.PreExecResult, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.PreExecuteContractWithTracer(tx, tracer)
+}
+
+func (self *lockedStore) PreExecuteEip155Tx(msg types2.Message) (*types3.ExecutionResult, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.PreExecuteEip155Tx(msg)
+}
+
+func (self *lockedStore) TraceEip155Tx(msg types2.Message, tracer evm.Tracer) (*types3.ExecutionResult, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.TraceEip155Tx(msg, tracer)
+}
+
+func (self *lockedStore) TraceBlock(block *types.Block, txIndex int, newTracer func(txIndex int) evm.Tracer) ([]*types3.ExecutionResult, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.TraceBlock(block, txIndex, newTracer)
+}
+
+func (self *lockedStore) GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetEventNotifyByTx(tx)
+}
+
+func (self *lockedStore) GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetEventNotifyByBlock(height)
+}
+
+func (self *lockedStore) GetEthCode(hash common2.Hash) ([]byte, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetEthCode(hash)
+}
+
+func (self *lockedStore) GetEthState(address common2.Address, key common2.Hash) ([]byte, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetEthState(address, key)
+}
+
+func (self *lockedStore) GetEthAccount(address common2.Address) (*storage.EthAccount, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetEthAccount(address)
+}
+
+func (self *lockedStore) GetCrossStatesRoot(height uint32) (common.Uint256, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetCrossStatesRoot(height)
+}
+
+func (self *lockedStore) GetCrossStates(height uint32) ([]common.Uint256, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetCrossStates(height)
+}
+
+func (self *lockedStore) GetCrossChainMsg(height uint32) (*types.CrossChainMsg, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetCrossChainMsg(height)
+}
+
+func (self *lockedStore) GetCrossStatesProof(height uint32, key []byte) ([]byte, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetCrossStatesProof(height, key)
+}
+
+func (self *lockedStore) EnableBlockPrune(numBeforeCurr uint32) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	self.store.EnableBlockPrune(numBeforeCurr)
+}
+
+func (self *lockedStore) GetCacheDB() *storage.CacheDB {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetCacheDB()
+}
+
+func (self *lockedStore) EnableStateHistory() error {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.EnableStateHistory()
+}
+
+func (self *lockedStore) GetCacheDBAt(height uint32) (*storage.CacheDB, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetCacheDBAt(height)
+}
+
+func (self *lockedStore) PreExecuteEip155TxAt(msg types2.Message, height uint32) (*types3.ExecutionResult, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.PreExecuteEip155TxAt(msg, height)
+}
+
+func (self *lockedStore) ExecuteEip155TxAt(msg types2.Message, height uint32, override func(cache *storage.CacheDB) error,
+	tracer evm.Tracer) (*types3.ExecutionResult, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.ExecuteEip155TxAt(msg, height, override, tracer)
+}
+
+func (self *lockedStore) EnableAddressIndex() error {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.EnableAddressIndex()
+}
+
+func (self *lockedStore) GetAddressIndexStart() (uint32, bool) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetAddressIndexStart()
+}
+
+func (self *lockedStore) GetAddressTxs(address common.Address, offset, limit uint32) ([]*store.AddressTx, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetAddressTxs(address, offset, limit)
+}
+
+func (self *lockedStore) GetAddressTransfers(address common.Address, offset, limit uint32) ([]*store.AddressTransfer, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.store.GetAddressTransfers(address, offset, limit)
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ontio/ontology/core/store/ledgerstore"
+)
+
+//Snapshot copies the whole ledger data aside and returns the id of the snapshot, so that the ledger can be rolled
+//back to current state by Revert. The ledger store is closed and reopened, it's meant for the dev chain of solo consensus
+func (self *Ledger) Snapshot() (uint32, error) {
+	if self.dataDir == "" {
+		return 0, fmt.Errorf("ledger snapshot is not supported")
+	}
+	self.locked.lock.Lock()
+	defer self.locked.lock.Unlock()
+	if err := self.locked.store.Close(); err != nil {
+		return 0, fmt.Errorf("close ledger store error %s", err)
+	}
+	id := uint32(len(self.snapshots)) + 1
+	dir := self.snapshotDir(id)
+	err := os.RemoveAll(dir)
+	if err == nil {
+		err = copyDir(self.dataDir, dir)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		if e := self.reopen(); e != nil {
+			return 0, e
+		}
+		return 0, fmt.Errorf("copy ledger data error %s", err)
+	}
+	self.snapshots = append(self.snapshots, dir)
+	return id, self.reopen()
+}
+
+//Revert rolls the ledger back to the state saved by the snapshot id. The snapshot and the ones taken after it are
+//dropped. The snapshot is copied aside before the ledger data is replaced, the ledger data is restored if it fails
+func (self *Ledger) Revert(id uint32) error {
+	self.locked.lock.Lock()
+	defer self.locked.lock.Unlock()
+	if id == 0 || int(id) > len(self.snapshots) {
+		return fmt.Errorf("snapshot %d not found", id)
+	}
+	dataDir := filepath.Clean(self.dataDir)
+	staging := dataDir + "_reverting"
+	err := os.RemoveAll(staging)
+	if err == nil {
+		err = copyDir(self.snapshots[id-1], staging)
+	}
+	if err != nil {
+		os.RemoveAll(staging)
+		return fmt.Errorf("copy snapshot data error %s", err)
+	}
+
+	if err := self.locked.store.Close(); err != nil {
+		os.RemoveAll(staging)
+		return fmt.Errorf("close ledger store error %s", err)
+	}
+	backup := dataDir + "_reverted"
+	err = os.RemoveAll(backup)
+	if err == nil {
+		err = os.Rename(dataDir, backup)
+	}
+	if err != nil {
+		os.RemoveAll(staging)
+		if e := self.reopen(); e != nil {
+			return e
+		}
+		return fmt.Errorf("move ledger data error %s", err)
+	}
+	if err := os.Rename(staging, dataDir); err != nil {
+		os.RemoveAll(staging)
+		if e := os.Rename(backup, dataDir); e != nil {
+			return fmt.Errorf("restore ledger data error %s", e)
+		}
+		if e := self.reopen(); e != nil {
+			return e
+		}
+		return fmt.Errorf("move snapshot data error %s", err)
+	}
+	os.RemoveAll(backup)
+
+	for _, dir := range self.snapshots[id-1:] {
+		os.RemoveAll(dir)
+	}
+	self.snapshots = self.snapshots[:id-1]
+	return self.reopen()
+}
+
+func (self *Ledger) snapshotDir(id uint32) string {
+	return fmt.Sprintf("%s_snapshot%d", filepath.Clean(self.dataDir), id)
+}
+
+//reopen opens the ledger store of the data dir, it's called with the store lock held
+func (self *Ledger) reopen() error {
+	ldgStore, err := ledgerstore.NewLedgerStore(self.dataDir, self.stateHashHeight)
+	if err != nil {
+		return fmt.Errorf("NewLedgerStore error %s", err)
+	}
+	err = ldgStore.InitLedgerStoreWithGenesisBlock(self.genesisBlock, self.defaultBookkeeper)
+	if err != nil {
+		return err
+	}
+	if self.stateHistory {
+		if err = ldgStore.EnableStateHistory(); err != nil {
+			return fmt.Errorf("EnableStateHistory error %s", err)
+		}
+	}
+	if self.addressIndex {
+		if err = ldgStore.EnableAddressIndex(); err != nil {
+			return fmt.Errorf("EnableAddressIndex error %s", err)
+		}
+	}
+	self.locked.store = ldgStore
+	return nil
+}
+
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode())
+		}
+		// leveldb lock file is recreated when the db is opened
+		if info.Name() == "LOCK" {
+			return nil
+		}
+		return copyFile(path, target, info.Mode())
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
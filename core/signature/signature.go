@@ -27,7 +27,20 @@ import (
 
 // Sign returns the signature of data using privKey
 func Sign(signer Signer, data []byte) ([]byte, error) {
-	signature, err := s.Sign(signer.Scheme(), signer.PrivKey(), data, nil)
+	return SignWithPrivKey(signer.Scheme(), signer.PrivKey(), data)
+}
+
+// SignPayload returns the signature of the message derived from the typed payload, see PayloadMessage
+func SignPayload(signer Signer, payloadType PayloadType, payload []byte) ([]byte, error) {
+	if ps, ok := signer.(PayloadSigner); ok {
+		return ps.SignPayload(payloadType, payload)
+	}
+	return SignWithPrivKey(signer.Scheme(), signer.PrivKey(), PayloadMessage(payloadType, payload))
+}
+
+// SignWithPrivKey returns the signature of data using privKey directly
+func SignWithPrivKey(scheme s.SignatureScheme, privKey keypair.PrivateKey, data []byte) ([]byte, error) {
+	signature, err := s.Sign(scheme, privKey, data, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +62,11 @@ func Verify(pubKey keypair.PublicKey, data, signature []byte) error {
 	return nil
 }
 
+// VerifyPayload check the signature of the typed payload using pubKey
+func VerifyPayload(pubKey keypair.PublicKey, payloadType PayloadType, payload, signature []byte) error {
+	return Verify(pubKey, PayloadMessage(payloadType, payload), signature)
+}
+
 // VerifyMultiSignature check whether more than m sigs are signed by the keys
 func VerifyMultiSignature(data []byte, keys []keypair.PublicKey, m int, sigs [][]byte) error {
 	n := len(keys)
@@ -19,6 +19,8 @@
 package signature
 
 import (
+	"crypto/sha256"
+
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-crypto/signature"
 )
@@ -33,3 +35,48 @@ type Signer interface {
 
 	Scheme() signature.SignatureScheme
 }
+
+//PayloadType is the kind of payload to be signed, which the remote signer checks with its allow-list. The payload
+//is the data which the signed message is derived from, so that the remote signer can check its structure
+type PayloadType string
+
+const (
+	PAYLOAD_BLOCK       PayloadType = "block"       //unsigned block header, signed by its hash
+	PAYLOAD_CROSS_CHAIN PayloadType = "crosschain"  //unsigned cross chain msg, signed by its hash
+	PAYLOAD_TRANSACTION PayloadType = "transaction" //unsigned transaction, signed by its hash
+	PAYLOAD_CONSENSUS   PayloadType = "consensus"   //unsigned consensus payload
+	PAYLOAD_STATE_ROOT  PayloadType = "stateroot"   //state root of block submit msg, never signed by remote signer
+	PAYLOAD_VRF         PayloadType = "vrf"         //vrf input of block proposal
+	PAYLOAD_DATA        PayloadType = "data"        //arbitrary data, signed with DATA_PAYLOAD_PREFIX
+	PAYLOAD_SUBNET      PayloadType = "subnet"      //subnet members request of consensus node
+	PAYLOAD_WITNESS     PayloadType = "witness"     //unsigned offline witness proposal or vote, signed by its sha256
+)
+
+//DATA_PAYLOAD_PREFIX is prepended to the data payload, so that its signature can not be taken as another type
+const DATA_PAYLOAD_PREFIX = "ontology data payload:"
+
+//PayloadMessage return the message actually signed for the payload, which is what the other nodes verify
+func PayloadMessage(payloadType PayloadType, payload []byte) []byte {
+	switch payloadType {
+	case PAYLOAD_BLOCK, PAYLOAD_CROSS_CHAIN, PAYLOAD_TRANSACTION:
+		temp := sha256.Sum256(payload)
+		hash := sha256.Sum256(temp[:])
+		return hash[:]
+	case PAYLOAD_WITNESS:
+		hash := sha256.Sum256(payload)
+		return hash[:]
+	case PAYLOAD_DATA:
+		buf := make([]byte, 0, len(DATA_PAYLOAD_PREFIX)+len(payload))
+		buf = append(buf, DATA_PAYLOAD_PREFIX...)
+		return append(buf, payload...)
+	}
+	return payload
+}
+
+// PayloadSigner is the signer which signs the payload itself, such as the account whose private key is kept
+// by an external signing process.
+type PayloadSigner interface {
+	Signer
+
+	SignPayload(payloadType PayloadType, payload []byte) ([]byte, error)
+}
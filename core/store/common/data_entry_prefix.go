@@ -28,6 +28,9 @@ const (
 	DATA_TRANSACTION                       = 0x02 //Transction hash => transaction key prefix
 	DATA_STATE_MERKLE_ROOT                 = 0x21 // block height => write set hash + state merkle root
 	DATA_BLOOM                             = 0x23 // block height => block bloom data
+	DATA_STATE_HISTORY                     = 0x24 // state key + reversed block height => state value after the block
+	DATA_ADDRESS_TX                        = 0x29 // address + reversed block height + reversed tx index => tx hash
+	DATA_ADDRESS_TRANSFER                  = 0x2a // address + reversed block height + reversed tx and event index => transfer
 
 	// Transaction
 	ST_BOOKKEEPER DataEntryPrefix = 0x03 //BookKeeper state key prefix
@@ -49,6 +52,8 @@ const (
 	SYS_BLOCK_MERKLE_TREE    DataEntryPrefix = 0x13 // Block merkle tree root key prefix
 	SYS_STATE_MERKLE_TREE    DataEntryPrefix = 0x20 // state merkle tree root key prefix
 	SYS_CROSS_CHAIN_MSG      DataEntryPrefix = 0x22 // state merkle tree root key prefix
+	SYS_STATE_HISTORY        DataEntryPrefix = 0x25 // state history start height + last recorded height
+	SYS_ADDRESS_INDEX        DataEntryPrefix = 0x2b // address index start height + last indexed height
 
 	EVENT_NOTIFY DataEntryPrefix = 0x14 //Event notify key prefix
 
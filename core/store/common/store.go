@@ -26,6 +26,11 @@ import (
 )
 
 var ErrNotFound = errors.New("not found")
+var ErrStatePruned = errors.New("state pruned")
+
+//ErrInvalidBlock marks the errors of verifying the block and header data, such as bad signatures, as opposed to
+//the errors of local store
+var ErrInvalidBlock = errors.New("invalid block")
 
 //Store iterator for iterate store
 type StoreIterator interface {
@@ -42,16 +47,17 @@ type StoreIterator interface {
 
 //PersistStore of ledger
 type PersistStore interface {
-	Put(key []byte, value []byte) error      //Put the key-value pair to store
-	Get(key []byte) ([]byte, error)          //Get the value if key in store
-	Has(key []byte) (bool, error)            //Whether the key is exist in store
-	Delete(key []byte) error                 //Delete the key in store
-	NewBatch()                               //Start commit batch
-	BatchPut(key []byte, value []byte)       //Put a key-value pair to batch
-	BatchDelete(key []byte)                  //Delete the key in batch
-	BatchCommit() error                      //Commit batch to store
-	Close() error                            //Close store
-	NewIterator(prefix []byte) StoreIterator //Return the iterator of store
+	Put(key []byte, value []byte) error                 //Put the key-value pair to store
+	Get(key []byte) ([]byte, error)                     //Get the value if key in store
+	Has(key []byte) (bool, error)                       //Whether the key is exist in store
+	Delete(key []byte) error                            //Delete the key in store
+	NewBatch()                                          //Start commit batch
+	BatchPut(key []byte, value []byte)                  //Put a key-value pair to batch
+	BatchDelete(key []byte)                             //Delete the key in batch
+	BatchCommit() error                                 //Commit batch to store
+	Close() error                                       //Close store
+	NewIterator(prefix []byte) StoreIterator            //Return the iterator of store
+	NewRangeIterator(start, limit []byte) StoreIterator //Return the iterator of store in key range [start, limit)
 }
 
 //EventStore save event notify
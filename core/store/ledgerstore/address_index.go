@@ -0,0 +1,343 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+
+	common2 "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/store"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+// The address index records the transactions and token transfers touching an address. The entries
+// of an address are stored under prefix + address + reversed big endian heights and indexes, so that
+// they are iterated from the newest to the oldest.
+//
+// The index starts at the block after the one current when it was enabled.
+
+var (
+	erc20TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	oep4TransferName   = hex.EncodeToString([]byte("transfer"))
+)
+
+//EnableAddressIndex start to index the blocks after currHeight by address
+func (this *BlockStore) EnableAddressIndex(currHeight uint32) error {
+	start, last, err := this.getAddressIndexRange()
+	if err != nil && err != scom.ErrNotFound {
+		return err
+	}
+	if err == scom.ErrNotFound || last != currHeight {
+		if err == nil {
+			log.Warnf("address index recorded to height %d but current height is %d, restart indexing", last, currHeight)
+		}
+		start = currHeight + 1
+		err = this.store.Put(genAddressIndexRangeKey(), encodeAddressIndexRange(start, currHeight))
+		if err != nil {
+			return err
+		}
+	}
+	this.addressIndexEnabled = true
+	this.addressIndexStart = start
+	log.Infof("address index enabled from height %d", start)
+	return nil
+}
+
+//GetAddressIndexStart return the lowest height indexed by address
+func (this *BlockStore) GetAddressIndexStart() (uint32, bool) {
+	return this.addressIndexStart, this.addressIndexEnabled
+}
+
+//SaveAddressIndex add the transactions and transfers of block to address index batch. The notifies are
+//matched to transactions by TxIndex, since not every transaction has a notify
+func (this *BlockStore) SaveAddressIndex(block *types.Block, notifies []*event.ExecuteNotify) {
+	if !this.addressIndexEnabled {
+		return
+	}
+	txNotifies := make(map[uint32]*event.ExecuteNotify, len(notifies))
+	for _, notify := range notifies {
+		txNotifies[notify.TxIndex] = notify
+	}
+	height := block.Header.Height
+	for i, tx := range block.Transactions {
+		txIndex := uint32(i)
+		txHash := tx.Hash()
+		addrs := map[common.Address]bool{tx.Payer: true}
+		if notify := txNotifies[txIndex]; notify != nil && notify.State == event.CONTRACT_STATE_SUCCESS {
+			for j, info := range notify.Notify {
+				transfer, ok := parseTransfer(info)
+				if !ok {
+					continue
+				}
+				transfer.TxHash = txHash
+				value := encodeAddressTransfer(transfer)
+				this.store.BatchPut(genAddressTransferKey(transfer.From, height, txIndex, uint32(j)), value)
+				if transfer.To != transfer.From {
+					this.store.BatchPut(genAddressTransferKey(transfer.To, height, txIndex, uint32(j)), value)
+				}
+				addrs[transfer.From] = true
+				addrs[transfer.To] = true
+			}
+		}
+		for addr := range addrs {
+			this.store.BatchPut(genAddressTxKey(addr, height, txIndex), txHash.ToArray())
+		}
+	}
+	this.store.BatchPut(genAddressIndexRangeKey(), encodeAddressIndexRange(this.addressIndexStart, height))
+}
+
+//GetAddressTxs return the transactions touching address from the newest, skipping offset ones
+func (this *BlockStore) GetAddressTxs(address common.Address, offset, limit uint32) ([]*store.AddressTx, error) {
+	var txs []*store.AddressTx
+	err := this.iterateAddressIndex(genAddressIndexPrefix(scom.DATA_ADDRESS_TX, address), offset, limit,
+		func(key, value []byte) error {
+			txHash, err := common.Uint256ParseFromBytes(value)
+			if err != nil {
+				return err
+			}
+			height, txIndex := decodeAddressIndexKey(key[1+common.ADDR_LEN:])
+			txs = append(txs, &store.AddressTx{TxHash: txHash, Height: height, TxIndex: txIndex})
+			return nil
+		})
+	return txs, err
+}
+
+//GetAddressTransfers return the token transfers from or to address from the newest, skipping offset ones
+func (this *BlockStore) GetAddressTransfers(address common.Address, offset, limit uint32) ([]*store.AddressTransfer, error) {
+	var transfers []*store.AddressTransfer
+	err := this.iterateAddressIndex(genAddressIndexPrefix(scom.DATA_ADDRESS_TRANSFER, address), offset, limit,
+		func(key, value []byte) error {
+			transfer, err := decodeAddressTransfer(value)
+			if err != nil {
+				return err
+			}
+			transfer.Height, transfer.TxIndex = decodeAddressIndexKey(key[1+common.ADDR_LEN:])
+			transfer.EventIndex = math.MaxUint32 - binary.BigEndian.Uint32(key[len(key)-4:])
+			transfers = append(transfers, transfer)
+			return nil
+		})
+	return transfers, err
+}
+
+func (this *BlockStore) iterateAddressIndex(prefix []byte, offset, limit uint32, handle func(key, value []byte) error) error {
+	iter := this.store.NewIterator(prefix)
+	defer iter.Release()
+	for ok := iter.First(); ok && limit > 0; ok = iter.Next() {
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if err := handle(iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+		limit--
+	}
+	return iter.Error()
+}
+
+func (this *BlockStore) getAddressIndexRange() (start uint32, last uint32, err error) {
+	data, err := this.store.Get(genAddressIndexRangeKey())
+	if err != nil {
+		return 0, 0, err
+	}
+	source := common.NewZeroCopySource(data)
+	start, eof := source.NextUint32()
+	if eof {
+		return 0, 0, fmt.Errorf("read address index start: %w", common.ErrIrregularData)
+	}
+	last, eof = source.NextUint32()
+	if eof {
+		return 0, 0, fmt.Errorf("read address index last height: %w", common.ErrIrregularData)
+	}
+	return start, last, nil
+}
+
+//parseTransfer parse the transfer event of native ONT/ONG, OEP-4 and ERC-20 contracts
+func parseTransfer(info *event.NotifyEventInfo) (*store.AddressTransfer, bool) {
+	if info.IsEvm {
+		return parseErc20Transfer(info)
+	}
+	states, ok := info.States.([]interface{})
+	if !ok || len(states) < 4 {
+		return nil, false
+	}
+	if info.ContractAddress == utils.OntContractAddress || info.ContractAddress == utils.OngContractAddress {
+		return parseNativeTransfer(info.ContractAddress, states)
+	}
+	return parseOep4Transfer(info.ContractAddress, states)
+}
+
+func parseNativeTransfer(contract common.Address, states []interface{}) (*store.AddressTransfer, bool) {
+	if name, _ := states[0].(string); name != "transfer" {
+		return nil, false
+	}
+	from, fromOk := states[1].(string)
+	to, toOk := states[2].(string)
+	amount, amountOk := states[3].(uint64)
+	if !fromOk || !toOk || !amountOk {
+		return nil, false
+	}
+	transfer := &store.AddressTransfer{Contract: contract, Amount: fmt.Sprint(amount)}
+	var err error
+	if transfer.From, err = common.AddressFromBase58(from); err != nil {
+		return nil, false
+	}
+	if transfer.To, err = common.AddressFromBase58(to); err != nil {
+		return nil, false
+	}
+	if len(states) > 4 {
+		// the decimal part of ong transfer with 18 decimals
+		if floatPart, ok := states[4].(uint64); ok {
+			transfer.Amount = fmt.Sprintf("%d.%09d", amount, floatPart)
+		}
+	}
+	return transfer, true
+}
+
+func parseOep4Transfer(contract common.Address, states []interface{}) (*store.AddressTransfer, bool) {
+	if name, _ := states[0].(string); name != oep4TransferName {
+		return nil, false
+	}
+	var raw [3][]byte
+	for i := range raw {
+		str, ok := states[i+1].(string)
+		if !ok {
+			return nil, false
+		}
+		data, err := hex.DecodeString(str)
+		if err != nil {
+			return nil, false
+		}
+		raw[i] = data
+	}
+	from, err := common.AddressParseFromBytes(raw[0])
+	if err != nil {
+		return nil, false
+	}
+	to, err := common.AddressParseFromBytes(raw[1])
+	if err != nil {
+		return nil, false
+	}
+	amount := common.BigIntFromNeoBytes(raw[2])
+	return &store.AddressTransfer{Contract: contract, From: from, To: to, Amount: amount.String()}, true
+}
+
+func parseErc20Transfer(info *event.NotifyEventInfo) (*store.AddressTransfer, bool) {
+	evmLog, err := event.NotifyEventInfoToEvmLog(info)
+	if err != nil {
+		return nil, false
+	}
+	// erc-721 transfer has the token id as the third indexed topic
+	if len(evmLog.Topics) != 3 || evmLog.Topics[0] != erc20TransferTopic || len(evmLog.Data) != 32 {
+		return nil, false
+	}
+	return &store.AddressTransfer{
+		Contract: info.ContractAddress,
+		From:     common.Address(common2.BytesToAddress(evmLog.Topics[1].Bytes())),
+		To:       common.Address(common2.BytesToAddress(evmLog.Topics[2].Bytes())),
+		Amount:   new(big.Int).SetBytes(evmLog.Data).String(),
+	}, true
+}
+
+func encodeAddressTransfer(transfer *store.AddressTransfer) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteHash(transfer.TxHash)
+	sink.WriteAddress(transfer.Contract)
+	sink.WriteAddress(transfer.From)
+	sink.WriteAddress(transfer.To)
+	sink.WriteString(transfer.Amount)
+	return sink.Bytes()
+}
+
+func decodeAddressTransfer(data []byte) (*store.AddressTransfer, error) {
+	source := common.NewZeroCopySource(data)
+	transfer := &store.AddressTransfer{}
+	var eof bool
+	transfer.TxHash, eof = source.NextHash()
+	if !eof {
+		transfer.Contract, eof = source.NextAddress()
+	}
+	if !eof {
+		transfer.From, eof = source.NextAddress()
+	}
+	if !eof {
+		transfer.To, eof = source.NextAddress()
+	}
+	if eof {
+		return nil, fmt.Errorf("read address transfer: %w", common.ErrIrregularData)
+	}
+	amount, _, irregular, eof := source.NextString()
+	if irregular || eof {
+		return nil, fmt.Errorf("read address transfer amount: %w", common.ErrIrregularData)
+	}
+	transfer.Amount = amount
+	return transfer, nil
+}
+
+func encodeAddressIndexRange(start, last uint32) []byte {
+	sink := common.NewZeroCopySink(make([]byte, 0, 8))
+	sink.WriteUint32(start)
+	sink.WriteUint32(last)
+	return sink.Bytes()
+}
+
+func genAddressIndexRangeKey() []byte {
+	return []byte{byte(scom.SYS_ADDRESS_INDEX)}
+}
+
+func genAddressIndexPrefix(prefix scom.DataEntryPrefix, address common.Address) []byte {
+	key := make([]byte, 0, 1+common.ADDR_LEN+12)
+	key = append(key, byte(prefix))
+	return append(key, address[:]...)
+}
+
+func genAddressTxKey(address common.Address, height, txIndex uint32) []byte {
+	key := genAddressIndexPrefix(scom.DATA_ADDRESS_TX, address)
+	return appendReversedUint32(key, height, txIndex)
+}
+
+func genAddressTransferKey(address common.Address, height, txIndex, eventIndex uint32) []byte {
+	key := genAddressIndexPrefix(scom.DATA_ADDRESS_TRANSFER, address)
+	return appendReversedUint32(key, height, txIndex, eventIndex)
+}
+
+func appendReversedUint32(key []byte, vals ...uint32) []byte {
+	var buf [4]byte
+	for _, val := range vals {
+		binary.BigEndian.PutUint32(buf[:], math.MaxUint32-val)
+		key = append(key, buf[:]...)
+	}
+	return key
+}
+
+func decodeAddressIndexKey(key []byte) (height, txIndex uint32) {
+	height = math.MaxUint32 - binary.BigEndian.Uint32(key[0:4])
+	txIndex = math.MaxUint32 - binary.BigEndian.Uint32(key[4:8])
+	return height, txIndex
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	common2 "github.com/ethereum/go-ethereum/common"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressIndex(t *testing.T) {
+	blockStore := &BlockStore{store: leveldbstore.NewMemLevelDBStore()}
+	assert.Nil(t, blockStore.EnableAddressIndex(9))
+	start, enabled := blockStore.GetAddressIndexStart()
+	assert.True(t, enabled)
+	assert.Equal(t, uint32(10), start)
+
+	payer := common.AddressFromVmCode([]byte("payer"))
+	to := common.AddressFromVmCode([]byte("to"))
+	oep4 := common.AddressFromVmCode([]byte("oep4"))
+	erc20 := common.AddressFromVmCode([]byte("erc20"))
+	amount := big.NewInt(1000)
+	erc20Log := &types.StorageLog{
+		Address: common2.Address(erc20),
+		Topics:  []common2.Hash{erc20TransferTopic, common2.BytesToHash(payer[:]), common2.BytesToHash(to[:])},
+		Data:    common2.BigToHash(amount).Bytes(),
+	}
+
+	newTx := func(nonce uint32) *types.Transaction {
+		tx := &types.MutableTransaction{TxType: types.InvokeNeo, Nonce: nonce, Payer: payer, Payload: &payload.InvokeCode{}}
+		imm, err := tx.IntoImmutable()
+		assert.Nil(t, err)
+		return imm
+	}
+	saveBlock := func(height uint32, txs []*types.Transaction, notifies []*event.ExecuteNotify) {
+		blockStore.NewBatch()
+		blockStore.SaveAddressIndex(&types.Block{Header: &types.Header{Height: height}, Transactions: txs}, notifies)
+		assert.Nil(t, blockStore.CommitTo())
+	}
+
+	tx1, tx2 := newTx(1), newTx(2)
+	saveBlock(10, []*types.Transaction{tx1}, []*event.ExecuteNotify{{
+		State: event.CONTRACT_STATE_SUCCESS,
+		Notify: []*event.NotifyEventInfo{
+			{ContractAddress: utils.OntContractAddress, States: []interface{}{"transfer", payer.ToBase58(), to.ToBase58(), uint64(5)}},
+			{ContractAddress: oep4, States: []interface{}{"approve", hex.EncodeToString(payer[:]), hex.EncodeToString(to[:]), "01"}},
+			{ContractAddress: oep4, States: []interface{}{oep4TransferName, hex.EncodeToString(payer[:]), hex.EncodeToString(to[:]),
+				hex.EncodeToString(common.BigIntToNeoBytes(amount))}},
+		},
+	}})
+	saveBlock(11, []*types.Transaction{tx2}, []*event.ExecuteNotify{{
+		State:  event.CONTRACT_STATE_SUCCESS,
+		Notify: []*event.NotifyEventInfo{event.NotifyEventInfoFromEvmLog(erc20Log)},
+	}})
+
+	txs, err := blockStore.GetAddressTxs(to, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(txs))
+	assert.Equal(t, tx2.Hash(), txs[0].TxHash)
+	assert.Equal(t, uint32(11), txs[0].Height)
+	assert.Equal(t, tx1.Hash(), txs[1].TxHash)
+
+	transfers, err := blockStore.GetAddressTransfers(payer, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(transfers))
+	assert.Equal(t, erc20, transfers[0].Contract)
+	assert.Equal(t, "1000", transfers[0].Amount)
+	assert.Equal(t, oep4, transfers[1].Contract)
+	assert.Equal(t, uint32(2), transfers[1].EventIndex)
+	assert.Equal(t, "1000", transfers[1].Amount)
+	assert.Equal(t, utils.OntContractAddress, transfers[2].Contract)
+	assert.Equal(t, to, transfers[2].To)
+	assert.Equal(t, "5", transfers[2].Amount)
+
+	transfers, err = blockStore.GetAddressTransfers(to, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(transfers))
+	assert.Equal(t, oep4, transfers[0].Contract)
+
+	txs, err = blockStore.GetAddressTxs(oep4, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(txs))
+
+	// the notify of the second transaction is indexed to it even if the first one has no notify
+	other := common.Address{3}
+	tx3, tx4 := newTx(3), newTx(4)
+	saveBlock(12, []*types.Transaction{tx3, tx4}, []*event.ExecuteNotify{{
+		TxIndex: 1,
+		State:   event.CONTRACT_STATE_SUCCESS,
+		Notify: []*event.NotifyEventInfo{
+			{ContractAddress: utils.OntContractAddress, States: []interface{}{"transfer", payer.ToBase58(), other.ToBase58(), uint64(1)}},
+		},
+	}})
+	txs, err = blockStore.GetAddressTxs(other, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, tx4.Hash(), txs[0].TxHash)
+	assert.Equal(t, uint32(1), txs[0].TxIndex)
+}
@@ -35,12 +35,14 @@ import (
 
 //Block store save the data of block & transaction
 type BlockStore struct {
-	enableCache bool                       //Is enable lru cache
-	dbDir       string                     //The path of store file
-	cache       *BlockCache                //The cache of block, if have.
-	bloomCache  map[uint32]*types2.Bloom   //bloomCache for bloom index, delete cached bloom after calculating bloom index
-	filterStart uint32                     // Start block that filter supported
-	store       *leveldbstore.LevelDBStore //block store handler
+	enableCache         bool                       //Is enable lru cache
+	dbDir               string                     //The path of store file
+	cache               *BlockCache                //The cache of block, if have.
+	bloomCache          map[uint32]*types2.Bloom   //bloomCache for bloom index, delete cached bloom after calculating bloom index
+	filterStart         uint32                     // Start block that filter supported
+	store               *leveldbstore.LevelDBStore //block store handler
+	addressIndexEnabled bool                       //Is address index enabled
+	addressIndexStart   uint32                     //Start block that address index supported
 }
 
 //NewBlockStore return the block store instance
@@ -38,6 +38,7 @@ import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/common/metrics"
 	vconfig "github.com/ontio/ontology/consensus/vbft/config"
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/signature"
@@ -52,6 +53,7 @@ import (
 	"github.com/ontio/ontology/events/message"
 	"github.com/ontio/ontology/merkle"
 	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/context"
 	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/ontio/ontology/smartcontract/service/evm"
 	types5 "github.com/ontio/ontology/smartcontract/service/evm/types"
@@ -84,6 +86,7 @@ type PrexecuteParam struct {
 	JitMode    bool
 	WasmFactor uint64
 	MinGas     bool
+	Tracer     context.Tracer
 }
 
 //LedgerStoreImp is main store struct fo ledger
@@ -467,17 +470,17 @@ func (this *LedgerStoreImp) verifyHeader(header *types.Header) error {
 	}
 
 	if prevHeader.Height+1 != header.Height {
-		return fmt.Errorf("block height is incorrect")
+		return fmt.Errorf("%w: block height is incorrect", scom.ErrInvalidBlock)
 	}
 
 	if prevHeader.Timestamp >= header.Timestamp {
-		return fmt.Errorf("block timestamp is incorrect")
+		return fmt.Errorf("%w: block timestamp is incorrect", scom.ErrInvalidBlock)
 	}
 	consensusType := strings.ToLower(config.DefConfig.Genesis.ConsensusType)
 	if consensusType == "vbft" {
 		blkInfo, err := vconfig.VbftBlock(header)
 		if err != nil {
-			return err
+			return fmt.Errorf("%w: %s", scom.ErrInvalidBlock, err)
 		}
 		var chainConfigHeight uint32
 		if blkInfo.NewChainConfig != nil {
@@ -517,7 +520,8 @@ func (this *LedgerStoreImp) verifyHeader(header *types.Header) error {
 		this.lock.RUnlock()
 		m := len(vbftPeerInfo) - (len(vbftPeerInfo)*6)/7
 		if len(header.Bookkeepers) < m {
-			return fmt.Errorf("header Bookkeepers %d more than 6/7 len vbftPeerInfo%d", len(header.Bookkeepers), len(vbftPeerInfo))
+			return fmt.Errorf("%w: header Bookkeepers %d more than 6/7 len vbftPeerInfo%d", scom.ErrInvalidBlock,
+				len(header.Bookkeepers), len(vbftPeerInfo))
 		}
 		usedPubKey := make(map[string]bool)
 		for _, bookkeeper := range header.Bookkeepers {
@@ -527,20 +531,20 @@ func (this *LedgerStoreImp) verifyHeader(header *types.Header) error {
 				val, _ := json.Marshal(vbftPeerInfo)
 				log.Errorf("verify header error: invalid pubkey :%v, height:%d, current vbftPeerInfo :%s",
 					pubkey, header.Height, string(val))
-				return fmt.Errorf("verify header error: invalid pubkey : %v", pubkey)
+				return fmt.Errorf("%w: verify header error: invalid pubkey : %v", scom.ErrInvalidBlock, pubkey)
 			}
 			usedPubKey[pubkey] = true
 		}
 		if uint32(len(usedPubKey)) < c+1 {
 			log.Errorf("verify header error:  height:%d,pubkey len:%d,c:%d",
 				header.Height, len(usedPubKey), c)
-			return fmt.Errorf("verify header error height:%d", header.Height)
+			return fmt.Errorf("%w: verify header error height:%d", scom.ErrInvalidBlock, header.Height)
 		}
 		hash := header.Hash()
 		err = signature.VerifyMultiSignature(hash[:], header.Bookkeepers, m, header.SigData)
 		if err != nil {
 			log.Errorf("VerifyMultiSignature:%s,Bookkeepers:%d,pubkey:%d,heigh:%d", err, len(header.Bookkeepers), len(vbftPeerInfo), header.Height)
-			return err
+			return fmt.Errorf("%w: %s", scom.ErrInvalidBlock, err)
 		}
 		if blkInfo.NewChainConfig != nil {
 			peerInfo := make(map[string]uint32)
@@ -554,17 +558,17 @@ func (this *LedgerStoreImp) verifyHeader(header *types.Header) error {
 	} else {
 		address, err := types.AddressFromBookkeepers(header.Bookkeepers)
 		if err != nil {
-			return err
+			return fmt.Errorf("%w: %s", scom.ErrInvalidBlock, err)
 		}
 		if prevHeader.NextBookkeeper != address {
-			return fmt.Errorf("bookkeeper address error")
+			return fmt.Errorf("%w: bookkeeper address error", scom.ErrInvalidBlock)
 		}
 
 		m := len(header.Bookkeepers) - (len(header.Bookkeepers)-1)/3
 		hash := header.Hash()
 		err = signature.VerifyMultiSignature(hash[:], header.Bookkeepers, m, header.SigData)
 		if err != nil {
-			return err
+			return fmt.Errorf("%w: %s", scom.ErrInvalidBlock, err)
 		}
 	}
 	return nil
@@ -599,7 +603,7 @@ func (this *LedgerStoreImp) AddHeader(header *types.Header) error {
 	err := this.verifyHeader(header)
 	//this.vbftPeerInfoheader, err = this.verifyHeader(header, this.vbftPeerInfoheader)
 	if err != nil {
-		return fmt.Errorf("verifyHeader error %s", err)
+		return fmt.Errorf("verifyHeader error %w", err)
 	}
 	this.addHeaderCache(header)
 	this.setHeaderIndex(header.Height, header.Hash())
@@ -705,14 +709,16 @@ func (this *LedgerStoreImp) AddBlock(block *types.Block, ccMsg *types.CrossChain
 	}
 	err := this.verifyHeader(block.Header)
 	if err != nil {
-		return fmt.Errorf("verifyHeader error %s", err)
+		return fmt.Errorf("verifyHeader error %w", err)
 	}
 	if ccMsg != nil {
 		if ccMsg.Height != currBlockHeight {
-			return fmt.Errorf("cross chain msg height %d not equal next block height %d", blockHeight, ccMsg.Height)
+			return fmt.Errorf("%w: cross chain msg height %d not equal next block height %d", scom.ErrInvalidBlock,
+				blockHeight, ccMsg.Height)
 		}
 		if ccMsg.Version != types.CURR_CROSS_STATES_VERSION {
-			return fmt.Errorf("error cross chain msg version excepted:%d actual:%d", types.CURR_CROSS_STATES_VERSION, ccMsg.Version)
+			return fmt.Errorf("%w: error cross chain msg version excepted:%d actual:%d", scom.ErrInvalidBlock,
+				types.CURR_CROSS_STATES_VERSION, ccMsg.Version)
 		}
 		root, err := this.stateStore.GetCrossStatesRoot(ccMsg.Height)
 		if err != nil {
@@ -722,7 +728,7 @@ func (this *LedgerStoreImp) AddBlock(block *types.Block, ccMsg *types.CrossChain
 			return fmt.Errorf("cross state root compare fail, expected:%x actual:%x", ccMsg.StatesRoot, root)
 		}
 		if err := this.verifyCrossChainMsg(ccMsg, block.Header.Bookkeepers); err != nil {
-			return fmt.Errorf("verifyCrossChainMsg error: %s", err)
+			return fmt.Errorf("%w: verifyCrossChainMsg error: %s", scom.ErrInvalidBlock, err)
 		}
 	}
 	err = this.saveBlock(block, ccMsg, stateMerkleRoot)
@@ -752,6 +758,7 @@ func (this *LedgerStoreImp) saveBlockToBlockStore(block *types.Block, bloom type
 }
 
 func (this *LedgerStoreImp) executeBlock(block *types.Block) (result store.ExecuteResult, err error) {
+	defer metrics.ObserveSince(metrics.LedgerBlockExecuteDuration, time.Now())
 	overlay := this.stateStore.NewOverlayDB()
 	var evmWitness common2.Address
 	if block.Header.Height != 0 {
@@ -897,6 +904,11 @@ func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block, result sto
 
 	log.Debugf("the state transition hash of block %d is:%s", blockHeight, result.Hash.ToHexString())
 
+	err = this.stateStore.SaveStateHistory(blockHeight, result.WriteSet)
+	if err != nil {
+		return fmt.Errorf("SaveStateHistory error %s", err)
+	}
+
 	result.WriteSet.ForEach(func(key, val []byte) {
 		if len(val) == 0 {
 			this.stateStore.BatchDeleteRawKey(key)
@@ -976,8 +988,35 @@ func (this *LedgerStoreImp) BloomStatus() (uint32, uint32) {
 	return BloomBitsBlocks, this.currBlockHeight / BloomBitsBlocks
 }
 
+//updateVmMetrics count the transactions and gas used of the saved block by vm
+func updateVmMetrics(block *types.Block, notifies []*event.ExecuteNotify) {
+	for _, notify := range notifies {
+		if int(notify.TxIndex) >= len(block.Transactions) {
+			continue
+		}
+		vm := "unknown"
+		switch block.Transactions[notify.TxIndex].TxType {
+		case types.Deploy:
+			vm = "deploy"
+		case types.InvokeNeo:
+			vm = "neovm"
+		case types.InvokeWasm:
+			vm = "wasmvm"
+		case types.EIP155:
+			vm = "evm"
+		}
+		state := "success"
+		if notify.State != event.CONTRACT_STATE_SUCCESS {
+			state = "fail"
+		}
+		metrics.VmTxs.WithLabelValues(vm, state).Inc()
+		metrics.VmGasUsed.WithLabelValues(vm).Add(float64(notify.GasStepUsed))
+	}
+}
+
 //saveBlock do the job of execution samrt contract and commit block to store.
 func (this *LedgerStoreImp) submitBlock(block *types.Block, crossChainMsg *types.CrossChainMsg, result store.ExecuteResult) error {
+	start := time.Now()
 	blockHash := block.Hash()
 	blockHeight := block.Header.Height
 	blockRoot := this.GetBlockRootWithNewTxRoots(block.Header.Height, []common.Uint256{block.Header.TransactionsRoot})
@@ -994,6 +1033,7 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, crossChainMsg *types
 	if err != nil {
 		return fmt.Errorf("save to block store height:%d error:%s", blockHeight, err)
 	}
+	this.blockStore.SaveAddressIndex(block, result.Notify)
 	this.tryPruneBlock(block.Header)
 	err = this.crossChainStore.SaveMsgToCrossChainStore(crossChainMsg)
 	if err != nil {
@@ -1018,6 +1058,9 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, crossChainMsg *types
 		return fmt.Errorf("stateStore.CommitTo height:%d error %s", blockHeight, err)
 	}
 	this.setCurrentBlock(blockHeight, blockHash)
+	metrics.ObserveSince(metrics.LedgerBlockCommitDuration, start)
+	metrics.LedgerHeight.Set(float64(blockHeight))
+	updateVmMetrics(block, result.Notify)
 
 	if events.DefActorPublisher != nil {
 		events.DefActorPublisher.Publish(
@@ -1096,7 +1139,7 @@ func (this *LedgerStoreImp) handleTransaction(overlay *overlaydb.OverlayDB, cach
 			Height:    block.Header.Height,
 			Timestamp: block.Header.Timestamp,
 		}
-		_, receipt, err = this.stateStore.HandleEIP155Transaction(this, cache, eiptx, ctx, notify, true)
+		_, receipt, err = this.stateStore.HandleEIP155Transaction(this, cache, eiptx, ctx, notify, true, evm2.Config{})
 		if overlay.Error() != nil {
 			return nil, nil, nil, fmt.Errorf("HandleInvokeTransaction tx %s error %s", txHash.ToHexString(), overlay.Error())
 		}
@@ -1295,7 +1338,7 @@ func (this *LedgerStoreImp) PreExecuteEIP155(tx *types3.Transaction, ctx Eip155C
 	cache := storage.NewCacheDB(overlay)
 
 	notify := &event.ExecuteNotify{State: event.CONTRACT_STATE_FAIL, TxIndex: ctx.TxIndex}
-	result, _, err := this.stateStore.HandleEIP155Transaction(this, cache, tx, ctx, notify, false)
+	result, _, err := this.stateStore.HandleEIP155Transaction(this, cache, tx, ctx, notify, false, evm2.Config{})
 	return result, notify, err
 }
 
@@ -1380,6 +1423,7 @@ func (this *LedgerStoreImp) PreExecuteContractWithParam(tx *types.Transaction, p
 			WasmExecStep: config.DEFAULT_WASM_MAX_STEPCOUNT,
 			JitMode:      preParam.JitMode,
 			PreExec:      true,
+			Tracer:       preParam.Tracer,
 		}
 		//start the smart contract executive function
 		engine, _ := sc.NewExecuteEngine(invoke.Code, tx.TxType)
@@ -1448,16 +1492,142 @@ func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*sstate.P
 	return this.PreExecuteContractWithParam(tx, param)
 }
 
+//PreExecuteContractWithTracer pre-execute the neovm or wasmvm invoke transaction, and report the contract calls and steps to tracer
+func (this *LedgerStoreImp) PreExecuteContractWithTracer(tx *types.Transaction, tracer context.Tracer) (*sstate.PreExecResult, error) {
+	param := PrexecuteParam{
+		JitMode:    false,
+		WasmFactor: 0,
+		MinGas:     true,
+		Tracer:     tracer,
+	}
+
+	return this.PreExecuteContractWithParam(tx, param)
+}
+
 func (this *LedgerStoreImp) TraceEip155Tx(msg types3.Message, tracer evm2.Tracer) (*types5.ExecutionResult, error) {
-	return this.executeEip155Tx(msg, evm2.Config{Debug: true, Tracer: tracer})
+	return this.ExecuteEip155TxAt(msg, this.GetCurrentBlockHeight(), nil, tracer)
+}
+
+//TraceBlock re-execute the transactions of the committed block on the state before the block was executed, and stop
+//after the tx at txIndex if txIndex is not negative. the eip155 tx is traced by the tracer returned by newTracer, and
+//is executed as other txs if the tracer is nil. the execution results of the traced txs are returned by tx index
+func (this *LedgerStoreImp) TraceBlock(block *types.Block, txIndex int,
+	newTracer func(txIndex int) evm2.Tracer) ([]*types5.ExecutionResult, error) {
+	height := block.Header.Height
+	if height == 0 {
+		return nil, fmt.Errorf("genesis block is not traceable")
+	}
+	if height > this.GetCurrentBlockHeight() {
+		return nil, fmt.Errorf("block %d is not committed", height)
+	}
+	num := len(block.Transactions)
+	if txIndex >= num {
+		return nil, fmt.Errorf("tx index %d out of range, block %d has %d txs", txIndex, height, num)
+	} else if txIndex >= 0 {
+		num = txIndex + 1
+	}
+	overlay, err := this.newOverlayDBAt(height - 1)
+	if err != nil {
+		return nil, err
+	}
+	// rebuild the gas table and evm witness with the global params before the block as executeBlock does, the
+	// shared gas table is not refreshed since it is used by the blocks being executed
+	paramOverlay, err := this.newOverlayDBAt(height - 1)
+	if err != nil {
+		return nil, err
+	}
+	config := &smartcontract.Config{
+		Time:   block.Header.Timestamp,
+		Height: height,
+		Tx:     &types.Transaction{},
+	}
+	gasParams, err := getGasParams(config, storage.NewCacheDB(paramOverlay), this)
+	if err != nil {
+		return nil, err
+	}
+	evmWitness := getEvmSystemWitnessAddress(config, storage.NewCacheDB(paramOverlay), this)
+	gasTable := make(map[string]uint64)
+	neovm.GAS_TABLE.Range(func(k, value interface{}) bool {
+		gasTable[k.(string)] = value.(uint64)
+		return true
+	})
+	for key, value := range gasParams {
+		gasTable[key] = value
+	}
+	cache := storage.NewCacheDB(overlay)
+	results := make([]*types5.ExecutionResult, num)
+	for i, tx := range block.Transactions[:num] {
+		cache.Reset()
+		var tracer evm2.Tracer
+		if tx.TxType == types.EIP155 {
+			tracer = newTracer(i)
+		}
+		if tracer == nil {
+			_, _, _, err := this.handleTransaction(overlay, cache, gasTable, block, tx, uint32(i), evmWitness)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		txHash := tx.Hash()
+		eiptx, err := tx.GetEIP155Tx()
+		if err != nil {
+			return nil, fmt.Errorf("trace tx %s error: %s", txHash.ToHexString(), err)
+		}
+		ctx := Eip155Context{
+			BlockHash: block.Hash(),
+			TxIndex:   uint32(i),
+			Height:    height,
+			Timestamp: block.Header.Timestamp,
+		}
+		notify := &event.ExecuteNotify{TxHash: txHash, State: event.CONTRACT_STATE_FAIL, TxIndex: uint32(i)}
+		result, _, err := this.stateStore.HandleEIP155Transaction(this, cache, eiptx, ctx, notify, true,
+			evm2.Config{Debug: true, Tracer: tracer})
+		if overlay.Error() != nil {
+			return nil, fmt.Errorf("trace tx %s error: %s", txHash.ToHexString(), overlay.Error())
+		}
+		if err != nil {
+			log.Debugf("trace tx %s error %s", txHash.ToHexString(), err)
+		}
+		results[i] = result
+	}
+	return results, nil
 }
 
 func (this *LedgerStoreImp) PreExecuteEip155Tx(msg types3.Message) (*types5.ExecutionResult, error) {
-	return this.executeEip155Tx(msg, evm2.Config{})
+	return this.executeEip155Tx(this.GetCacheDB(), this.GetCurrentBlockHeight(), msg, evm2.Config{})
 }
 
-func (this *LedgerStoreImp) executeEip155Tx(msg types3.Message, conf evm2.Config) (*types5.ExecutionResult, error) {
-	height := this.GetCurrentBlockHeight()
+//PreExecuteEip155TxAt execute the eip155 message on the state after the block at height was executed
+func (this *LedgerStoreImp) PreExecuteEip155TxAt(msg types3.Message, height uint32) (*types5.ExecutionResult, error) {
+	return this.ExecuteEip155TxAt(msg, height, nil, nil)
+}
+
+//ExecuteEip155TxAt execute the eip155 message on the state after the block at height was executed. the state is
+//changed by override before execution if override is not nil, and the execution is traced if tracer is not nil
+func (this *LedgerStoreImp) ExecuteEip155TxAt(msg types3.Message, height uint32, override func(cache *storage.CacheDB) error,
+	tracer evm2.Tracer) (*types5.ExecutionResult, error) {
+	cache, err := this.GetCacheDBAt(height)
+	if err != nil {
+		return nil, err
+	}
+	if curr := this.GetCurrentBlockHeight(); height > curr {
+		height = curr
+	}
+	if override != nil {
+		if err := override(cache); err != nil {
+			return nil, err
+		}
+	}
+	conf := evm2.Config{}
+	if tracer != nil {
+		conf = evm2.Config{Debug: true, Tracer: tracer}
+	}
+	return this.executeEip155Tx(cache, height, msg, conf)
+}
+
+func (this *LedgerStoreImp) executeEip155Tx(cache *storage.CacheDB, height uint32, msg types3.Message,
+	conf evm2.Config) (*types5.ExecutionResult, error) {
 	// use previous block time to make it predictable for easy test
 	blockTime := uint32(time.Now().Unix())
 	if header, err := this.GetHeaderByHeight(height); err == nil {
@@ -1473,7 +1643,6 @@ func (this *LedgerStoreImp) executeEip155Tx(msg types3.Message, conf evm2.Config
 	config := params.GetChainConfig(config.DefConfig.P2PNode.EVMChainId)
 	txContext := evm.NewEVMTxContext(msg)
 	blockContext := evm.NewEVMBlockContext(height, blockTime, this)
-	cache := this.GetCacheDB()
 	statedb := storage.NewStateDB(cache, common2.Hash{}, common2.Hash(ctx.BlockHash), ong.OngBalanceHandle{})
 	vmenv := evm2.NewEVM(blockContext, txContext, statedb, config, conf)
 	res, err := evm.ApplyMessage(vmenv, msg, common2.Address(utils.GovernanceContractAddress))
@@ -1543,3 +1712,51 @@ func (this *LedgerStoreImp) GetCacheDB() *storage.CacheDB {
 	return storage.NewCacheDB(overlay)
 
 }
+
+//EnableStateHistory record the state of every block from now on, so that state can be queried at past heights
+func (this *LedgerStoreImp) EnableStateHistory() error {
+	this.getSavingBlockLock()
+	defer this.releaseSavingBlockLock()
+
+	return this.stateStore.EnableStateHistory(this.GetCurrentBlockHeight())
+}
+
+//GetCacheDBAt return the cache db of the state after the block at height was executed.
+//return ErrStatePruned if the state at height is not kept
+func (this *LedgerStoreImp) GetCacheDBAt(height uint32) (*storage.CacheDB, error) {
+	overlay, err := this.newOverlayDBAt(height)
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewCacheDB(overlay), nil
+}
+
+func (this *LedgerStoreImp) newOverlayDBAt(height uint32) (*overlaydb.OverlayDB, error) {
+	if height >= this.GetCurrentBlockHeight() {
+		return this.stateStore.NewOverlayDB(), nil
+	}
+	return this.stateStore.NewOverlayDBAt(height)
+}
+
+//EnableAddressIndex index the transactions and transfers of every block from now on by address
+func (this *LedgerStoreImp) EnableAddressIndex() error {
+	this.getSavingBlockLock()
+	defer this.releaseSavingBlockLock()
+
+	return this.blockStore.EnableAddressIndex(this.GetCurrentBlockHeight())
+}
+
+//GetAddressIndexStart return the lowest height indexed by address, and whether the index is enabled
+func (this *LedgerStoreImp) GetAddressIndexStart() (uint32, bool) {
+	return this.blockStore.GetAddressIndexStart()
+}
+
+//GetAddressTxs return the transactions touching address from the newest, skipping offset ones
+func (this *LedgerStoreImp) GetAddressTxs(address common.Address, offset, limit uint32) ([]*store.AddressTx, error) {
+	return this.blockStore.GetAddressTxs(address, offset, limit)
+}
+
+//GetAddressTransfers return the token transfers from or to address from the newest, skipping offset ones
+func (this *LedgerStoreImp) GetAddressTransfers(address common.Address, offset, limit uint32) ([]*store.AddressTransfer, error) {
+	return this.blockStore.GetAddressTransfers(address, offset, limit)
+}
@@ -0,0 +1,256 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// The state history records, for every state key written by a block, the value of the key after
+// that block. Versions of one key are stored under DATA_STATE_HISTORY + varbytes(key) + reversed
+// big endian height, so that the newest version not above a height is the first key found by seek.
+//
+// The history starts at the block after the one current when it was enabled. The value of a key
+// before its first recorded change is kept as version historyStart-1, so keys never changed since
+// then are read from the current state.
+
+var errReadOnlyHistory = errors.New("state history is read only")
+
+//EnableStateHistory start to record state history from the block after currHeight
+func (self *StateStore) EnableStateHistory(currHeight uint32) error {
+	start, last, err := self.getStateHistoryRange()
+	if err != nil && err != scom.ErrNotFound {
+		return err
+	}
+	if err == scom.ErrNotFound || last != currHeight {
+		if err == nil {
+			log.Warnf("state history recorded to height %d but current height is %d, restart recording", last, currHeight)
+		}
+		start = currHeight + 1
+		err = self.saveStateHistoryRange(start, currHeight)
+		if err != nil {
+			return err
+		}
+	}
+	self.historyEnabled = true
+	self.historyStart = start
+	log.Infof("state history enabled from height %d", start)
+	return nil
+}
+
+//GetStateHistoryStart return the lowest height at which state can be queried
+func (self *StateStore) GetStateHistoryStart() (uint32, bool) {
+	if !self.historyEnabled {
+		return 0, false
+	}
+	return self.historyStart - 1, true
+}
+
+//SaveStateHistory add the write set of the block at height to state history batch
+func (self *StateStore) SaveStateHistory(height uint32, writeSet *overlaydb.MemDB) error {
+	if !self.historyEnabled {
+		return nil
+	}
+	var err error
+	writeSet.ForEach(func(key, val []byte) {
+		if err != nil {
+			return
+		}
+		err = self.savePreviousStateVersion(key)
+		if err != nil {
+			return
+		}
+		self.store.BatchPut(genStateHistoryKey(key, height), val)
+	})
+	if err != nil {
+		return err
+	}
+	sink := common.NewZeroCopySink(make([]byte, 0, 8))
+	sink.WriteUint32(self.historyStart)
+	sink.WriteUint32(height)
+	self.store.BatchPut(genStateHistoryRangeKey(), sink.Bytes())
+	return nil
+}
+
+// savePreviousStateVersion keep the current value of key as version historyStart-1 if the key
+// has not been recorded since the history started
+func (self *StateStore) savePreviousStateVersion(key []byte) error {
+	iter := self.store.NewIterator(genStateHistoryPrefix(key))
+	recorded := iter.First() && decodeStateHistoryHeight(iter.Key())+1 >= self.historyStart
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if recorded {
+		return nil
+	}
+	value, err := self.store.Get(key)
+	if err != nil && err != scom.ErrNotFound {
+		return err
+	}
+	self.store.BatchPut(genStateHistoryKey(key, self.historyStart-1), value)
+	return nil
+}
+
+//GetStateAt return the value of state key after the block at height was executed
+func (self *StateStore) GetStateAt(key []byte, height uint32) ([]byte, error) {
+	prefix := genStateHistoryPrefix(key)
+	iter := self.store.NewRangeIterator(genStateHistoryKey(key, height), util.BytesPrefix(prefix).Limit)
+	defer iter.Release()
+	if iter.First() {
+		if decodeStateHistoryHeight(iter.Key())+1 >= self.historyStart {
+			value := iter.Value()
+			if len(value) == 0 {
+				return nil, scom.ErrNotFound
+			}
+			return append([]byte(nil), value...), nil
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return self.store.Get(key)
+}
+
+//NewOverlayDBAt return a read only overlay db of the state after the block at height was executed
+func (self *StateStore) NewOverlayDBAt(height uint32) (*overlaydb.OverlayDB, error) {
+	lowest, enabled := self.GetStateHistoryStart()
+	if !enabled {
+		return nil, fmt.Errorf("%w: state history is not enabled", scom.ErrStatePruned)
+	}
+	if height < lowest {
+		return nil, fmt.Errorf("%w: state history starts at height %d", scom.ErrStatePruned, lowest)
+	}
+	return overlaydb.NewOverlayDB(&stateHistoryStore{stateStore: self, height: height}), nil
+}
+
+func (self *StateStore) getStateHistoryRange() (start uint32, last uint32, err error) {
+	data, err := self.store.Get(genStateHistoryRangeKey())
+	if err != nil {
+		return 0, 0, err
+	}
+	source := common.NewZeroCopySource(data)
+	start, eof := source.NextUint32()
+	if eof {
+		return 0, 0, fmt.Errorf("read state history start: %w", common.ErrIrregularData)
+	}
+	last, eof = source.NextUint32()
+	if eof {
+		return 0, 0, fmt.Errorf("read state history last height: %w", common.ErrIrregularData)
+	}
+	return start, last, nil
+}
+
+func (self *StateStore) saveStateHistoryRange(start, last uint32) error {
+	sink := common.NewZeroCopySink(make([]byte, 0, 8))
+	sink.WriteUint32(start)
+	sink.WriteUint32(last)
+	return self.store.Put(genStateHistoryRangeKey(), sink.Bytes())
+}
+
+func genStateHistoryRangeKey() []byte {
+	return []byte{byte(scom.SYS_STATE_HISTORY)}
+}
+
+func genStateHistoryPrefix(key []byte) []byte {
+	sink := common.NewZeroCopySink(make([]byte, 0, 1+9+len(key)+4))
+	sink.WriteByte(byte(scom.DATA_STATE_HISTORY))
+	sink.WriteVarBytes(key)
+	return sink.Bytes()
+}
+
+func genStateHistoryKey(key []byte, height uint32) []byte {
+	prefix := genStateHistoryPrefix(key)
+	var version [4]byte
+	binary.BigEndian.PutUint32(version[:], math.MaxUint32-height)
+	return append(prefix, version[:]...)
+}
+
+func decodeStateHistoryHeight(historyKey []byte) uint32 {
+	return math.MaxUint32 - binary.BigEndian.Uint32(historyKey[len(historyKey)-4:])
+}
+
+// stateHistoryStore is a read only view of state store at a past height
+type stateHistoryStore struct {
+	stateStore *StateStore
+	height     uint32
+}
+
+func (self *stateHistoryStore) Put(key []byte, value []byte) error {
+	return errReadOnlyHistory
+}
+
+func (self *stateHistoryStore) Get(key []byte) ([]byte, error) {
+	return self.stateStore.GetStateAt(key, self.height)
+}
+
+func (self *stateHistoryStore) Has(key []byte) (bool, error) {
+	_, err := self.Get(key)
+	if err == scom.ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (self *stateHistoryStore) Delete(key []byte) error {
+	return errReadOnlyHistory
+}
+
+func (self *stateHistoryStore) NewBatch() {}
+
+func (self *stateHistoryStore) BatchPut(key []byte, value []byte) {}
+
+func (self *stateHistoryStore) BatchDelete(key []byte) {}
+
+func (self *stateHistoryStore) BatchCommit() error {
+	return errReadOnlyHistory
+}
+
+func (self *stateHistoryStore) Close() error {
+	return nil
+}
+
+// iterating the state history needs merging every version of every key, which is not supported
+func (self *stateHistoryStore) NewIterator(prefix []byte) scom.StoreIterator {
+	return errIterator{err: fmt.Errorf("iterate state at height %d is not supported", self.height)}
+}
+
+func (self *stateHistoryStore) NewRangeIterator(start, limit []byte) scom.StoreIterator {
+	return errIterator{err: fmt.Errorf("iterate state at height %d is not supported", self.height)}
+}
+
+type errIterator struct {
+	err error
+}
+
+func (self errIterator) Next() bool    { return false }
+func (self errIterator) First() bool   { return false }
+func (self errIterator) Key() []byte   { return nil }
+func (self errIterator) Value() []byte { return nil }
+func (self errIterator) Release()      {}
+func (self errIterator) Error() error  { return self.err }
@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"errors"
+	"testing"
+
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateHistory(t *testing.T) {
+	db := NewMemStateStore(0)
+	key := []byte{byte(scom.ST_STORAGE), 1, 2, 3}
+	untouched := []byte{byte(scom.ST_STORAGE), 1, 2}
+
+	db.NewBatch()
+	db.BatchPutRawKeyVal(key, []byte("v10"))
+	db.BatchPutRawKeyVal(untouched, []byte("u"))
+	assert.Nil(t, db.CommitTo())
+	assert.Nil(t, db.EnableStateHistory(10))
+
+	saveBlock := func(height uint32, val []byte) {
+		writeSet := overlaydb.NewMemDB(0, 0)
+		if len(val) == 0 {
+			writeSet.Delete(key)
+		} else {
+			writeSet.Put(key, val)
+		}
+		db.NewBatch()
+		assert.Nil(t, db.SaveStateHistory(height, writeSet))
+		writeSet.ForEach(func(key, val []byte) {
+			if len(val) == 0 {
+				db.BatchDeleteRawKey(key)
+			} else {
+				db.BatchPutRawKeyVal(key, val)
+			}
+		})
+		assert.Nil(t, db.CommitTo())
+	}
+	saveBlock(11, []byte("v11"))
+	saveBlock(12, nil)
+	saveBlock(14, []byte("v14"))
+
+	expected := map[uint32]string{10: "v10", 11: "v11", 12: "", 13: "", 14: "v14", 15: "v14"}
+	for height, val := range expected {
+		overlay, err := db.NewOverlayDBAt(height)
+		assert.Nil(t, err)
+		value, err := overlay.Get(key)
+		assert.Nil(t, err)
+		assert.Equal(t, val, string(value), "height %d", height)
+		value, err = overlay.Get(untouched)
+		assert.Nil(t, err)
+		assert.Equal(t, "u", string(value))
+	}
+
+	_, err := db.NewOverlayDBAt(9)
+	assert.True(t, errors.Is(err, scom.ErrStatePruned))
+
+	// restart without recording the blocks in between
+	db.historyEnabled = false
+	assert.Nil(t, db.EnableStateHistory(20))
+	lowest, enabled := db.GetStateHistoryStart()
+	assert.True(t, enabled)
+	assert.Equal(t, uint32(20), lowest)
+}
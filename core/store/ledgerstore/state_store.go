@@ -53,6 +53,8 @@ type StateStore struct {
 	deltaMerkleTree      *merkle.CompactMerkleTree //Merkle tree of delta state root
 	merkleHashStore      merkle.HashStore
 	stateHashCheckHeight uint32
+	historyEnabled       bool   //Whether state history is recorded
+	historyStart         uint32 //Height of the first block recorded in state history
 }
 
 //NewStateStore return state store instance
@@ -299,7 +299,7 @@ func SaveNotify(eventStore scommon.EventStore, txHash common.Uint256, notify *ev
 	if err := eventStore.SaveEventNotifyByTx(txHash, notify); err != nil {
 		return fmt.Errorf("SaveEventNotifyByTx error %s", err)
 	}
-	event.PushSmartCodeEvent(txHash, 0, event.EVENT_NOTIFY, notify)
+	event.PushSmartCodeEvent(txHash, blk.Header.Height, 0, event.EVENT_NOTIFY, notify)
 	event.PushEthSmartCodeEvent(notify, blk)
 	return nil
 }
@@ -373,6 +373,18 @@ func getEvmSystemWitnessAddress(config *smartcontract.Config, cache *storage.Cac
 }
 
 func refreshGlobalParam(config *smartcontract.Config, cache *storage.CacheDB, store store.LedgerStore) error {
+	gasParams, err := getGasParams(config, cache, store)
+	if err != nil {
+		return err
+	}
+	for key, value := range gasParams {
+		neovm.GAS_TABLE.Store(key, value)
+	}
+	return nil
+}
+
+//getGasParams return the gas prices set by the global params of cache
+func getGasParams(config *smartcontract.Config, cache *storage.CacheDB, store store.LedgerStore) (map[string]uint64, error) {
 	sink := common.NewZeroCopySink(nil)
 	utils.EncodeVarUint(sink, uint64(len(neovm.GAS_TABLE_KEYS)))
 	for _, value := range neovm.GAS_TABLE_KEYS {
@@ -389,12 +401,13 @@ func refreshGlobalParam(config *smartcontract.Config, cache *storage.CacheDB, st
 	service, _ := sc.NewNativeService()
 	result, err := service.NativeCall(utils.ParamContractAddress, "getGlobalParam", sink.Bytes())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	params := new(global_params.Params)
 	if err := params.Deserialization(common.NewZeroCopySource(result)); err != nil {
-		return fmt.Errorf("deserialize global params error:%s", err)
+		return nil, fmt.Errorf("deserialize global params error:%s", err)
 	}
+	gasParams := make(map[string]uint64)
 	neovm.GAS_TABLE.Range(func(key, value interface{}) bool {
 		n, ps := params.GetParam(key.(string))
 		if n != -1 && ps.Value != "" {
@@ -402,12 +415,12 @@ func refreshGlobalParam(config *smartcontract.Config, cache *storage.CacheDB, st
 			if err != nil {
 				log.Errorf("[refreshGlobalParam] failed to parse uint %v\n", ps.Value)
 			} else {
-				neovm.GAS_TABLE.Store(key, pu)
+				gasParams[key.(string)] = pu
 			}
 		}
 		return true
 	})
-	return nil
+	return gasParams, nil
 }
 
 func getBalanceFromNative(config *smartcontract.Config, cache *storage.CacheDB, store store.LedgerStore, address common.Address) (uint64, error) {
@@ -453,12 +466,12 @@ type Eip155Context struct {
 }
 
 func (self *StateStore) HandleEIP155Transaction(store store.LedgerStore, cache *storage.CacheDB,
-	tx *types2.Transaction, ctx Eip155Context, notify *event.ExecuteNotify, checkNonce bool) (*types3.ExecutionResult, *types.Receipt, error) {
+	tx *types2.Transaction, ctx Eip155Context, notify *event.ExecuteNotify, checkNonce bool, vmConfig evm.Config) (*types3.ExecutionResult, *types.Receipt, error) {
 	usedGas := uint64(0)
 	config := params.GetChainConfig(sysconfig.DefConfig.P2PNode.EVMChainId)
 	statedb := storage.NewStateDB(cache, tx.Hash(), common2.Hash(ctx.BlockHash), ong.OngBalanceHandle{})
 	result, receipt, err := evm2.ApplyTransaction(config, store, statedb, ctx.Height, ctx.Timestamp, tx, &usedGas,
-		utils.GovernanceContractAddress, evm.Config{}, checkNonce)
+		utils.GovernanceContractAddress, vmConfig, checkNonce)
 
 	if err != nil {
 		cache.SetDbErr(err)
@@ -28,6 +28,7 @@ import (
 	"github.com/ontio/ontology/core/store/leveldbstore"
 	"github.com/ontio/ontology/core/store/overlaydb"
 	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/context"
 	"github.com/ontio/ontology/smartcontract/event"
 	types3 "github.com/ontio/ontology/smartcontract/service/evm/types"
 	cstates "github.com/ontio/ontology/smartcontract/states"
@@ -45,6 +46,25 @@ type ExecuteResult struct {
 	Bloom           types2.Bloom
 }
 
+//AddressTx is a transaction touching an address, as payer or party of a transfer
+type AddressTx struct {
+	TxHash  common.Uint256
+	Height  uint32
+	TxIndex uint32
+}
+
+//AddressTransfer is a token transfer of ONT, ONG, OEP-4 or ERC-20 contract
+type AddressTransfer struct {
+	TxHash     common.Uint256
+	Height     uint32
+	TxIndex    uint32
+	EventIndex uint32
+	Contract   common.Address
+	From       common.Address
+	To         common.Address
+	Amount     string
+}
+
 // LedgerStore provides func with store package.
 type LedgerStore interface {
 	InitLedgerStoreWithGenesisBlock(genesisblock *types.Block, defaultBookkeeper []keypair.PublicKey) error
@@ -78,8 +98,10 @@ type LedgerStore interface {
 	GetStorageItem(codeHash common.Address, key []byte) ([]byte, error)
 	PreExecuteContract(tx *types.Transaction) (*cstates.PreExecResult, error)
 	PreExecuteContractBatch(txes []*types.Transaction, atomic bool) ([]*cstates.PreExecResult, uint32, error)
+	PreExecuteContractWithTracer(tx *types.Transaction, tracer context.Tracer) (*cstates.PreExecResult, error)
 	PreExecuteEip155Tx(msg types2.Message) (*types3.ExecutionResult, error)
 	TraceEip155Tx(msg types2.Message, tracer evm.Tracer) (*types3.ExecutionResult, error)
+	TraceBlock(block *types.Block, txIndex int, newTracer func(txIndex int) evm.Tracer) ([]*types3.ExecutionResult, error)
 	GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error)
 	GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error)
 	GetEthCode(hash common2.Hash) ([]byte, error)
@@ -93,4 +115,15 @@ type LedgerStore interface {
 	EnableBlockPrune(numBeforeCurr uint32)
 	//expose the cache db
 	GetCacheDB() *storage.CacheDB
+	//historical state
+	EnableStateHistory() error
+	GetCacheDBAt(height uint32) (*storage.CacheDB, error)
+	PreExecuteEip155TxAt(msg types2.Message, height uint32) (*types3.ExecutionResult, error)
+	ExecuteEip155TxAt(msg types2.Message, height uint32, override func(cache *storage.CacheDB) error,
+		tracer evm.Tracer) (*types3.ExecutionResult, error)
+	//address index
+	EnableAddressIndex() error
+	GetAddressIndexStart() (uint32, bool)
+	GetAddressTxs(address common.Address, offset, limit uint32) ([]*AddressTx, error)
+	GetAddressTransfers(address common.Address, offset, limit uint32) ([]*AddressTransfer, error)
 }
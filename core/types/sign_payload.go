@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/signature"
+)
+
+//SignHeader return the signature of header hash. The unsigned header is passed to signer as the payload, so that
+//the remote signer can check it is a block header before signing its hash
+func SignHeader(signer signature.Signer, header *Header) ([]byte, error) {
+	sink := common.NewZeroCopySink(nil)
+	header.serializationUnsigned(sink)
+	return signature.SignPayload(signer, signature.PAYLOAD_BLOCK, sink.Bytes())
+}
+
+//SignCrossChainMsg return the signature of cross chain msg hash
+func SignCrossChainMsg(signer signature.Signer, msg *CrossChainMsg) ([]byte, error) {
+	sink := common.NewZeroCopySink(nil)
+	msg.serializationUnsigned(sink)
+	return signature.SignPayload(signer, signature.PAYLOAD_CROSS_CHAIN, sink.Bytes())
+}
+
+//SignTransaction return the signature of transaction hash
+func SignTransaction(signer signature.Signer, tx *MutableTransaction) ([]byte, error) {
+	sink := common.NewZeroCopySink(nil)
+	err := tx.serializeUnsigned(sink)
+	if err != nil {
+		return nil, err
+	}
+	return signature.SignPayload(signer, signature.PAYLOAD_TRANSACTION, sink.Bytes())
+}
+
+//CheckSignPayload check the payload of block, cross chain msg or transaction has exactly the structure of its type.
+//They are all signed by the hash of payload, the payload which can be decoded as more than one type is refused,
+//so that the signature of one type can never be taken as another
+func CheckSignPayload(payloadType signature.PayloadType, payload []byte) error {
+	kinds := make([]signature.PayloadType, 0, 1)
+	if isUnsignedHeader(payload) {
+		kinds = append(kinds, signature.PAYLOAD_BLOCK)
+	}
+	if isUnsignedCrossChainMsg(payload) {
+		kinds = append(kinds, signature.PAYLOAD_CROSS_CHAIN)
+	}
+	if isUnsignedTransaction(payload) {
+		kinds = append(kinds, signature.PAYLOAD_TRANSACTION)
+	}
+	if len(kinds) != 1 || kinds[0] != payloadType {
+		return fmt.Errorf("payload is not an unsigned %s", payloadType)
+	}
+	return nil
+}
+
+func isUnsignedHeader(payload []byte) bool {
+	source := common.NewZeroCopySource(payload)
+	err := new(Header).deserializationUnsigned(source)
+	return err == nil && source.Len() == 0
+}
+
+func isUnsignedCrossChainMsg(payload []byte) bool {
+	source := common.NewZeroCopySource(payload)
+	_, eof := source.NextByte()
+	if eof {
+		return false
+	}
+	_, eof = source.NextUint32()
+	if eof {
+		return false
+	}
+	_, eof = source.NextHash()
+	return !eof && source.Len() == 0
+}
+
+func isUnsignedTransaction(payload []byte) bool {
+	source := common.NewZeroCopySource(payload)
+	err := new(Transaction).deserializeOntUnsigned(source)
+	return err == nil && source.Len() == 0
+}
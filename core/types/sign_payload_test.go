@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package types
+import (
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSigner struct {
+	priv keypair.PrivateKey
+	pub  keypair.PublicKey
+}
+
+func (self *testSigner) PrivKey() keypair.PrivateKey { return self.priv }
+func (self *testSigner) PubKey() keypair.PublicKey   { return self.pub }
+func (self *testSigner) Scheme() s.SignatureScheme   { return s.SHA256withECDSA }
+
+func TestCheckSignPayload(t *testing.T) {
+	header := &Header{Height: 1, ConsensusPayload: []byte{1, 2, 3}}
+	sink := common.NewZeroCopySink(nil)
+	header.serializationUnsigned(sink)
+	unsignedHeader := sink.Bytes()
+
+	tx := &MutableTransaction{TxType: InvokeNeo, Payload: &payload.InvokeCode{Code: []byte{1, 2, 3}}}
+	sink = common.NewZeroCopySink(nil)
+	assert.Nil(t, tx.serializeUnsigned(sink))
+	unsignedTx := sink.Bytes()
+
+	msg := &CrossChainMsg{Version: CURR_CROSS_STATES_VERSION, Height: 1}
+	sink = common.NewZeroCopySink(nil)
+	msg.serializationUnsigned(sink)
+	unsignedMsg := sink.Bytes()
+
+	assert.Nil(t, CheckSignPayload(signature.PAYLOAD_BLOCK, unsignedHeader))
+	assert.Nil(t, CheckSignPayload(signature.PAYLOAD_TRANSACTION, unsignedTx))
+	assert.Nil(t, CheckSignPayload(signature.PAYLOAD_CROSS_CHAIN, unsignedMsg))
+
+	txHash := tx.Hash()
+	assert.NotNil(t, CheckSignPayload(signature.PAYLOAD_BLOCK, txHash[:]))
+	assert.NotNil(t, CheckSignPayload(signature.PAYLOAD_BLOCK, unsignedTx))
+	assert.NotNil(t, CheckSignPayload(signature.PAYLOAD_BLOCK, append(unsignedHeader, 0)))
+	assert.NotNil(t, CheckSignPayload(signature.PAYLOAD_TRANSACTION, unsignedHeader))
+	assert.NotNil(t, CheckSignPayload(signature.PAYLOAD_CROSS_CHAIN, txHash[:]))
+
+	//the signature of payload verifies against the hash of header and transaction
+	priv, pub, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	acc := &testSigner{priv: priv, pub: pub}
+	sig, err := SignHeader(acc, header)
+	assert.Nil(t, err)
+	hash := header.Hash()
+	assert.Nil(t, signature.Verify(acc.pub, hash[:], sig))
+	sig, err = SignTransaction(acc, tx)
+	assert.Nil(t, err)
+	assert.Nil(t, signature.Verify(acc.pub, txHash[:], sig))
+}
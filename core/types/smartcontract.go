@@ -22,6 +22,7 @@ import "github.com/ontio/ontology/common"
 
 type SmartCodeEvent struct {
 	TxHash common.Uint256
+	Height uint32
 	Action string
 	Result interface{}
 	Error  int64
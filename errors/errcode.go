@@ -60,6 +60,9 @@ const (
 	ErrETHTxGaslimitExceed  ErrCode = 45023
 	ErrSameNonceExist       ErrCode = 45024
 	ErrETHTxNonceToobig     ErrCode = 45025
+	ErrTxPoolUnderpriced    ErrCode = 45026
+	ErrTxPoolAccountLimit   ErrCode = 45027
+	ErrTxPoolQueueFull      ErrCode = 45028
 )
 
 func (err ErrCode) Error() string {
@@ -116,6 +119,12 @@ func (err ErrCode) Error() string {
 		return "eth transaction with same nonce existed"
 	case ErrETHTxNonceToobig:
 		return "eth transaction nonce is much greater than tx pool"
+	case ErrTxPoolUnderpriced:
+		return "tx pool full, transaction gas price is not higher than the lowest one"
+	case ErrTxPoolAccountLimit:
+		return "too many transactions of the payer in tx pool"
+	case ErrTxPoolQueueFull:
+		return "too many eth transactions waiting for missing nonce in tx pool"
 	}
 
 	return fmt.Sprintf("Unknown error? Error code = %d", err)
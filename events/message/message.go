@@ -30,12 +30,17 @@ const (
 	TOPIC_PENDING_TX_EVENT    = "pendingtx"
 	TOPIC_CHAIN_EVENT         = "chainevt"
 	TOPIC_ETH_SC_EVENT        = "ethscevt"
+	TOPIC_TX_POOL_ADDED       = "txpooladd"
 )
 
 type SaveBlockCompleteMsg struct {
 	Block *types.Block
 }
 
+type TxPoolAddedMsg struct {
+	Tx *types.Transaction
+}
+
 type SmartCodeEventMsg struct {
 	Event *types.SmartCodeEvent
 }
@@ -19,10 +19,18 @@
 package actor
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology/common/config"
 	cactor "github.com/ontio/ontology/consensus/actor"
 )
 
+const DEV_REQ_TIMEOUT = 60
+const CONSENSUS_STATUS_TIMEOUT = 10
+
 var consensusSrvPid *actor.PID
 
 func SetConsensusPid(actr *actor.PID) {
@@ -44,3 +52,35 @@ func ConsensusSrvHalt() error {
 	}
 	return nil
 }
+
+//send dev chain control to solo consensus actor, and wait for the result
+func DevChainRequest(req interface{}) (uint64, error) {
+	if consensusSrvPid == nil || !config.DefConfig.Genesis.IsDevChain() {
+		return 0, errors.New("dev chain methods are only supported in test mode")
+	}
+	ret, err := consensusSrvPid.RequestFuture(req, DEV_REQ_TIMEOUT*time.Second).Result()
+	if err != nil {
+		return 0, fmt.Errorf(ERR_ACTOR_COMM, err)
+	}
+	rsp, ok := ret.(*cactor.DevChainRsp)
+	if !ok {
+		return 0, fmt.Errorf("unexpected response %T", ret)
+	}
+	return rsp.Result, rsp.Err
+}
+
+//get the internal state snapshot of vbft consensus
+func GetConsensusStatus() (interface{}, error) {
+	if consensusSrvPid == nil || config.DefConfig.Genesis.ConsensusType != config.CONSENSUS_TYPE_VBFT {
+		return nil, errors.New("consensus status is only supported by vbft consensus")
+	}
+	ret, err := consensusSrvPid.RequestFuture(&cactor.GetConsensusStatus{}, CONSENSUS_STATUS_TIMEOUT*time.Second).Result()
+	if err != nil {
+		return nil, fmt.Errorf(ERR_ACTOR_COMM, err)
+	}
+	rsp, ok := ret.(*cactor.ConsensusStatusRsp)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response %T", ret)
+	}
+	return rsp.Status, nil
+}
@@ -19,15 +19,20 @@
 package actor
 
 import (
+	"math/big"
+
 	common2 "github.com/ethereum/go-ethereum/common"
 	types2 "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/store"
 	"github.com/ontio/ontology/core/store/leveldbstore"
 	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/context"
 	"github.com/ontio/ontology/smartcontract/event"
 	types3 "github.com/ontio/ontology/smartcontract/service/evm/types"
+	"github.com/ontio/ontology/smartcontract/service/native/ong"
 	cstate "github.com/ontio/ontology/smartcontract/states"
 	"github.com/ontio/ontology/smartcontract/storage"
 )
@@ -42,6 +47,11 @@ func GetHeaderByHeight(height uint32) (*types.Header, error) {
 	return ledger.DefLedger.GetHeaderByHeight(height)
 }
 
+//GetHeaderByHash from ledger
+func GetHeaderByHash(hash common.Uint256) (*types.Header, error) {
+	return ledger.DefLedger.GetHeaderByHash(hash)
+}
+
 //GetBlockByHeight from ledger
 func GetBlockByHeight(height uint32) (*types.Block, error) {
 	return ledger.DefLedger.GetBlockByHeight(height)
@@ -95,6 +105,11 @@ func PreExecuteContract(tx *types.Transaction) (*cstate.PreExecResult, error) {
 	return ledger.DefLedger.PreExecuteContract(tx)
 }
 
+//PreExecuteContractWithTracer from ledger
+func PreExecuteContractWithTracer(tx *types.Transaction, tracer context.Tracer) (*cstate.PreExecResult, error) {
+	return ledger.DefLedger.PreExecuteContractWithTracer(tx, tracer)
+}
+
 func PreExecuteContractBatch(tx []*types.Transaction, atomic bool) ([]*cstate.PreExecResult, uint32, error) {
 	return ledger.DefLedger.PreExecuteContractBatch(tx, atomic)
 }
@@ -109,6 +124,21 @@ func GetEventNotifyByHeight(height uint32) ([]*event.ExecuteNotify, error) {
 	return ledger.DefLedger.GetEventNotifyByBlock(height)
 }
 
+//GetAddressIndexStart return the lowest height indexed by address, and whether the index is enabled
+func GetAddressIndexStart() (uint32, bool) {
+	return ledger.DefLedger.GetAddressIndexStart()
+}
+
+//GetAddressTxs from ledger
+func GetAddressTxs(address common.Address, offset, limit uint32) ([]*store.AddressTx, error) {
+	return ledger.DefLedger.GetAddressTxs(address, offset, limit)
+}
+
+//GetAddressTransfers from ledger
+func GetAddressTransfers(address common.Address, offset, limit uint32) ([]*store.AddressTransfer, error) {
+	return ledger.DefLedger.GetAddressTransfers(address, offset, limit)
+}
+
 //GetMerkleProof from ledger
 func GetMerkleProof(proofHeight uint32, rootHeight uint32) ([]common.Uint256, error) {
 	return ledger.DefLedger.GetMerkleProof(proofHeight, rootHeight)
@@ -143,6 +173,48 @@ func PreExecuteEip155Tx(msg types2.Message) (*types3.ExecutionResult, error) {
 	return res, err
 }
 
+//GetEthAccountAt return the eth account after the block at height was executed
+func GetEthAccountAt(address common2.Address, height uint32) (*storage.EthAccount, error) {
+	cache, err := ledger.DefLedger.GetCacheDBAt(height)
+	if err != nil {
+		return nil, err
+	}
+	account, err := cache.GetEthAccount(address)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+//GetEthStorageAt return the eth storage value after the block at height was executed
+func GetEthStorageAt(addr common2.Address, key common2.Hash, height uint32) ([]byte, error) {
+	cache, err := ledger.DefLedger.GetCacheDBAt(height)
+	if err != nil {
+		return nil, err
+	}
+	return cache.Get(append(addr.Bytes(), key.Bytes()...))
+}
+
+//GetOngBalanceAt return the ong balance after the block at height was executed
+func GetOngBalanceAt(addr common.Address, height uint32) (*big.Int, error) {
+	cache, err := ledger.DefLedger.GetCacheDBAt(height)
+	if err != nil {
+		return nil, err
+	}
+	return ong.OngBalanceHandle{}.GetBalance(cache, addr)
+}
+
+func PreExecuteEip155TxAt(msg types2.Message, height uint32) (*types3.ExecutionResult, error) {
+	return ledger.DefLedger.PreExecuteEip155TxAt(msg, height)
+}
+
+//PreExecuteEip155TxWithOverride execute the eip155 message on the state after the block at height was executed,
+//which is changed by override first
+func PreExecuteEip155TxWithOverride(msg types2.Message, height uint32,
+	override func(cache *storage.CacheDB) error) (*types3.ExecutionResult, error) {
+	return ledger.DefLedger.ExecuteEip155TxAt(msg, height, override, nil)
+}
+
 func BloomStatus() (uint32, uint32) {
 	return ledger.DefLedger.BloomStatus()
 }
@@ -67,6 +67,15 @@ func GetTxFromPool(hash common.Uint256) (tcomn.TXEntry, error) {
 	return tcomn.TXEntry{Tx: txn, Attrs: status.Attrs}, nil
 }
 
+//GetReplacedTx returns the hash of the transaction which replaced the given one in txpool
+func GetReplacedTx(hash common.Uint256) (common.Uint256, bool) {
+	status := txPoolService.GetTransactionStatus(hash)
+	if status == nil || status.ReplacedBy == nil {
+		return common.UINT256_EMPTY, false
+	}
+	return *status.ReplacedBy, true
+}
+
 //GetTxnCount from txpool actor
 func GetTxnCount() []uint32 {
 	return txPoolService.GetTxAmount()
@@ -77,6 +86,11 @@ func GetTxnHashList() []common.Uint256 {
 	return txPoolService.GetTxList()
 }
 
+//GetTxsFromPool returns the txs in txpool sorted by payer and nonce, only the ones of payer if it is not nil
+func GetTxsFromPool(payer *common.Address) []*tcomn.PoolTxInfo {
+	return txPoolService.GetPoolTxs(payer)
+}
+
 func GetGasPrice() uint64 {
 	return txPoolService.GetGasPrice()
 }
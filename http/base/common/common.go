@@ -22,6 +22,8 @@ package common
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -50,6 +52,25 @@ import (
 const MAX_SEARCH_HEIGHT uint32 = 100
 const MAX_REQUEST_BODY_SIZE = 1 << 20
 
+//default and max number of records in a page of address history
+const (
+	DEFAULT_ADDRESS_HISTORY_LIMIT uint32 = 20
+	MAX_ADDRESS_HISTORY_LIMIT     uint32 = 100
+)
+
+var ErrAddressIndexDisabled = errors.New("address index is not enabled")
+
+//addressHistoryLimit return the page size of address history for the requested limit
+func addressHistoryLimit(limit uint32) uint32 {
+	if limit == 0 {
+		return DEFAULT_ADDRESS_HISTORY_LIMIT
+	}
+	if limit > MAX_ADDRESS_HISTORY_LIMIT {
+		return MAX_ADDRESS_HISTORY_LIMIT
+	}
+	return limit
+}
+
 type BalanceOfRsp struct {
 	Ont    string `json:"ont"`
 	Ong    string `json:"ong"`
@@ -109,6 +130,12 @@ type PreExecuteResult struct {
 	Notify []NotifyEventInfo
 }
 
+type TraceTransactionResult struct {
+	PreExecuteResult
+	Error string `json:",omitempty"`
+	Trace json.RawMessage
+}
+
 type NotifyEventInfo struct {
 	ContractAddress string
 	States          interface{}
@@ -207,8 +234,96 @@ type TXNAttrInfo struct {
 }
 
 type TXNEntryInfo struct {
-	State []TXNAttrInfo // the result from each validator
-	Raw   string
+	State  []TXNAttrInfo // the result from each validator
+	Raw    string
+	Status string `json:",omitempty"` // "replaced by <hash>" if the transaction is replaced
+}
+
+//GetReplacedTxEntryInfo returns the entry info of the transaction replaced in txpool
+func GetReplacedTxEntryInfo(hash common.Uint256) (TXNEntryInfo, bool) {
+	by, ok := bactor.GetReplacedTx(hash)
+	if !ok {
+		return TXNEntryInfo{}, false
+	}
+	return TXNEntryInfo{Status: "replaced by " + by.ToHexString()}, true
+}
+
+type MemPoolPayerTxs struct {
+	Payer string
+	Txs   []MemPoolTxInfo // sorted by nonce
+}
+
+type MemPoolTxInfo struct {
+	TxHash   string
+	TxType   types.TransactionType
+	Nonce    uint32
+	GasPrice uint64
+	GasLimit uint64
+	Sender   string // "net", "http" or "journal", "nil" if unknown
+	State    string // "pending", "queued" for eip tx with nonce gap, or "verifying"
+	Age      uint64 // seconds since the transaction is received
+}
+
+//GetMemPoolTxs returns the transactions in txpool grouped by payer, only the ones of payer if it is not nil
+func GetMemPoolTxs(payer *common.Address) []MemPoolPayerTxs {
+	now := time.Now()
+	ret := make([]MemPoolPayerTxs, 0)
+	for _, info := range bactor.GetTxsFromPool(payer) {
+		tx := info.Tx
+		payerBase58 := tx.Payer.ToBase58()
+		if len(ret) == 0 || ret[len(ret)-1].Payer != payerBase58 {
+			ret = append(ret, MemPoolPayerTxs{Payer: payerBase58})
+		}
+		state := "pending"
+		if info.Verifying {
+			state = "verifying"
+		} else if info.Queued {
+			state = "queued"
+		}
+		var age uint64
+		if !info.Received.IsZero() && now.After(info.Received) {
+			age = uint64(now.Sub(info.Received) / time.Second)
+		}
+		last := &ret[len(ret)-1]
+		last.Txs = append(last.Txs, MemPoolTxInfo{
+			TxHash:   tx.Hash().ToHexString(),
+			TxType:   tx.TxType,
+			Nonce:    tx.Nonce,
+			GasPrice: tx.GasPrice,
+			GasLimit: tx.GasLimit,
+			Sender:   info.Sender.String(),
+			State:    state,
+			Age:      age,
+		})
+	}
+	return ret
+}
+
+type AddressTxsRsp struct {
+	IndexStart uint32 // the history below this height is not indexed
+	Txs        []AddressTxInfo
+}
+
+type AddressTxInfo struct {
+	TxHash  string
+	Height  uint32
+	TxIndex uint32
+}
+
+type AddressTransfersRsp struct {
+	IndexStart uint32 // the history below this height is not indexed
+	Transfers  []AddressTransferInfo
+}
+
+type AddressTransferInfo struct {
+	TxHash     string
+	Height     uint32
+	TxIndex    uint32
+	EventIndex uint32
+	Contract   string
+	From       string
+	To         string
+	Amount     string
 }
 
 func GetLogEvent(obj *event.LogEventArgs) (map[string]bool, LogEventArgs) {
@@ -334,6 +449,49 @@ func GetBlockInfo(block *types.Block) BlockInfo {
 	return b
 }
 
+//GetAddressTxs return a page of transactions touching address, from the newest. limit 0 means the default page size
+func GetAddressTxs(address common.Address, offset, limit uint32) (*AddressTxsRsp, error) {
+	start, enabled := bactor.GetAddressIndexStart()
+	if !enabled {
+		return nil, ErrAddressIndexDisabled
+	}
+	txs, err := bactor.GetAddressTxs(address, offset, addressHistoryLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	rsp := &AddressTxsRsp{IndexStart: start, Txs: make([]AddressTxInfo, 0, len(txs))}
+	for _, tx := range txs {
+		rsp.Txs = append(rsp.Txs, AddressTxInfo{TxHash: tx.TxHash.ToHexString(), Height: tx.Height, TxIndex: tx.TxIndex})
+	}
+	return rsp, nil
+}
+
+//GetAddressTransfers return a page of token transfers from or to address, from the newest. limit 0 means the default page size
+func GetAddressTransfers(address common.Address, offset, limit uint32) (*AddressTransfersRsp, error) {
+	start, enabled := bactor.GetAddressIndexStart()
+	if !enabled {
+		return nil, ErrAddressIndexDisabled
+	}
+	transfers, err := bactor.GetAddressTransfers(address, offset, addressHistoryLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	rsp := &AddressTransfersRsp{IndexStart: start, Transfers: make([]AddressTransferInfo, 0, len(transfers))}
+	for _, t := range transfers {
+		rsp.Transfers = append(rsp.Transfers, AddressTransferInfo{
+			TxHash:     t.TxHash.ToHexString(),
+			Height:     t.Height,
+			TxIndex:    t.TxIndex,
+			EventIndex: t.EventIndex,
+			Contract:   t.Contract.ToHexString(),
+			From:       t.From.ToBase58(),
+			To:         t.To.ToBase58(),
+			Amount:     t.Amount,
+		})
+	}
+	return rsp, nil
+}
+
 func GetBalance(address common.Address) (*BalanceOfRsp, error) {
 	balances, height, err := GetNativeTokenBalance(0, []common.Address{utils.OntContractAddress, utils.OngContractAddress}, address, true)
 	if err != nil {
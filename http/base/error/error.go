@@ -45,6 +45,15 @@ const (
 	PRE_EXEC_ERROR  int64 = 47002
 )
 
+//error codes defined by json rpc 2.0
+const (
+	RPC_PARSE_ERROR      int64 = -32700
+	RPC_INVALID_REQUEST  int64 = -32600
+	RPC_METHOD_NOT_FOUND int64 = -32601
+	RPC_INVALID_PARAMS   int64 = -32602
+	RPC_INTERNAL_ERROR   int64 = -32603
+)
+
 var ErrMap = map[int64]string{
 	SUCCESS:            "SUCCESS",
 	SESSION_EXPIRED:    "SESSION EXPIRED",
@@ -82,4 +91,43 @@ var ErrMap = map[int64]string{
 	int64(ontErrors.ErrSummaryAsset):         "INTERNAL ERROR, ErrSummaryAsset",
 	int64(ontErrors.ErrXmitFail):             "INTERNAL ERROR, ErrXmitFail",
 	int64(ontErrors.ErrNoAccount):            "INTERNAL ERROR, ErrNoAccount",
+
+	RPC_PARSE_ERROR:      "Parse error",
+	RPC_INVALID_REQUEST:  "Invalid Request",
+	RPC_METHOD_NOT_FOUND: "Method not found",
+	RPC_INVALID_PARAMS:   "Invalid params",
+	RPC_INTERNAL_ERROR:   "Internal error",
+}
+
+//JsonRpcErrorCode map the error code to json rpc 2.0 error code. The codes of json rpc 2.0 are used
+//if defined, otherwise the code is kept as application defined error
+func JsonRpcErrorCode(errcode int64) int64 {
+	switch errcode {
+	case ILLEGAL_DATAFORMAT:
+		return RPC_PARSE_ERROR
+	case INVALID_METHOD:
+		return RPC_METHOD_NOT_FOUND
+	case INVALID_PARAMS:
+		return RPC_INVALID_PARAMS
+	case INTERNAL_ERROR:
+		return RPC_INTERNAL_ERROR
+	default:
+		return errcode
+	}
+}
+
+//LegacyErrorCode map the json rpc 2.0 error code back to the ontology error code
+func LegacyErrorCode(errcode int64) int64 {
+	switch errcode {
+	case RPC_PARSE_ERROR:
+		return ILLEGAL_DATAFORMAT
+	case RPC_METHOD_NOT_FOUND:
+		return INVALID_METHOD
+	case RPC_INVALID_REQUEST, RPC_INVALID_PARAMS:
+		return INVALID_PARAMS
+	case RPC_INTERNAL_ERROR:
+		return INTERNAL_ERROR
+	default:
+		return errcode
+	}
 }
@@ -73,6 +73,18 @@ func GetNodeSyncStatus(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+// get the internal state of vbft consensus
+func GetConsensusStatus(cmd map[string]interface{}) map[string]interface{} {
+	status, err := bactor.GetConsensusStatus()
+	if err != nil {
+		log.Errorf("GetConsensusStatus error: %s", err)
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp := ResponsePack(berr.SUCCESS)
+	resp["Result"] = status
+	return resp
+}
+
 // get block height
 func GetBlockHeight(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
@@ -429,6 +441,66 @@ func GetBalance(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+//get transactions touching address
+func GetAddressTxs(cmd map[string]interface{}) map[string]interface{} {
+	address, offset, limit, ok := parseAddressHistoryParams(cmd)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	rsp, err := bcomn.GetAddressTxs(address, offset, limit)
+	if err == bcomn.ErrAddressIndexDisabled {
+		return ResponsePack(berr.INVALID_METHOD)
+	}
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp := ResponsePack(berr.SUCCESS)
+	resp["Result"] = rsp
+	return resp
+}
+
+//get token transfers from or to address
+func GetAddressTransfers(cmd map[string]interface{}) map[string]interface{} {
+	address, offset, limit, ok := parseAddressHistoryParams(cmd)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	rsp, err := bcomn.GetAddressTransfers(address, offset, limit)
+	if err == bcomn.ErrAddressIndexDisabled {
+		return ResponsePack(berr.INVALID_METHOD)
+	}
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp := ResponsePack(berr.SUCCESS)
+	resp["Result"] = rsp
+	return resp
+}
+
+func parseAddressHistoryParams(cmd map[string]interface{}) (address common.Address, offset, limit uint32, ok bool) {
+	addrBase58, ok := cmd["Addr"].(string)
+	if !ok {
+		return
+	}
+	address, err := common.AddressFromBase58(addrBase58)
+	if err != nil {
+		return address, 0, 0, false
+	}
+	var page [2]uint32
+	for i, name := range []string{"Offset", "Limit"} {
+		param, _ := cmd[name].(string)
+		if len(param) == 0 {
+			continue
+		}
+		val, err := strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return address, 0, 0, false
+		}
+		page[i] = uint32(val)
+	}
+	return address, page[0], page[1], true
+}
+
 // get balance of address
 func GetBalanceV2(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
@@ -618,6 +690,21 @@ func GetMemPoolTxHashList(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+// get memory pool transactions grouped by payer, filtered by payer if it is given
+func GetMemPoolTxList(cmd map[string]interface{}) map[string]interface{} {
+	var payer *common.Address
+	if addrBase58, ok := cmd["Payer"].(string); ok && addrBase58 != "" {
+		address, err := common.AddressFromBase58(addrBase58)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+		payer = &address
+	}
+	resp := ResponsePack(berr.SUCCESS)
+	resp["Result"] = bcomn.GetMemPoolTxs(payer)
+	return resp
+}
+
 // get memory poll transaction state
 func GetMemPoolTxState(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
@@ -631,6 +718,10 @@ func GetMemPoolTxState(cmd map[string]interface{}) map[string]interface{} {
 	}
 	txEntry, err := bactor.GetTxFromPool(hash)
 	if err != nil {
+		if info, ok := bcomn.GetReplacedTxEntryInfo(hash); ok {
+			resp["Result"] = info
+			return resp
+		}
 		return ResponsePack(berr.UNKNOWN_TRANSACTION)
 	}
 	var attrs []bcomn.TXNAttrInfo
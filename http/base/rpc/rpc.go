@@ -20,11 +20,15 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"sync"
 
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/http/base/common"
 	berr "github.com/ontio/ontology/http/base/error"
@@ -91,58 +95,158 @@ func (mainMux *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		mainMux.RUnlock()
 		return
 	}
-	var request JReq
 	defer r.Body.Close()
-	decoder := json.NewDecoder(io.LimitReader(r.Body, common.MAX_REQUEST_BODY_SIZE))
-	err := decoder.Decode(&request)
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, common.MAX_REQUEST_BODY_SIZE))
 	if err != nil {
-		log.Error("HTTP JSON RPC Handle - json.Unmarshal: ", err)
+		log.Error("HTTP JSON RPC Handle - read body: ", err)
+		return
+	}
+	var response interface{}
+	body = bytes.TrimSpace(body)
+	if len(body) != 0 && body[0] == '[' {
+		if responses := mainMux.handleBatch(body); responses != nil {
+			response = responses
+		}
+	} else if resp := mainMux.handleMessage(body); resp != nil {
+		response = resp
+	}
+	w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("content-type", "application/json;charset=utf-8")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if response == nil {
+		//no response to notifications
+		return
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
 		return
 	}
+	w.Write(data)
+}
+
+//handleBatch handle the batch of requests, the responses are in the same order as requests.
+//Return nil if the batch only contains notifications
+func (self *ServeMux) handleBatch(body []byte) interface{} {
+	var messages []json.RawMessage
+	if err := json.Unmarshal(body, &messages); err != nil {
+		log.Error("HTTP JSON RPC Handle - json.Unmarshal: ", err)
+		return errorResponse(nil, berr.RPC_PARSE_ERROR, err.Error())
+	}
+	if len(messages) == 0 {
+		return errorResponse(nil, berr.RPC_INVALID_REQUEST, "empty batch")
+	}
+	if limit := config.DefConfig.Rpc.MaxBatchSize; limit != 0 && uint(len(messages)) > limit {
+		return errorResponse(nil, berr.RPC_INVALID_REQUEST, fmt.Sprintf("batch size exceeds limit %d", limit))
+	}
+	responses := make([]map[string]interface{}, 0, len(messages))
+	for _, msg := range messages {
+		if resp := self.handleMessage(msg); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
+//isNotification check whether the request has no id member, which needs no response
+func isNotification(msg []byte) bool {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &fields); err != nil {
+		return false
+	}
+	_, ok := fields["id"]
+	return !ok
+}
+
+//handleMessage handle the request, return nil if the request is a notification
+func (self *ServeMux) handleMessage(msg []byte) map[string]interface{} {
+	var request JReq
+	if err := json.Unmarshal(msg, &request); err != nil {
+		log.Error("HTTP JSON RPC Handle - json.Unmarshal: ", err)
+		if _, ok := err.(*json.SyntaxError); ok {
+			return errorResponse(nil, berr.RPC_PARSE_ERROR, err.Error())
+		}
+		return errorResponse(nil, berr.RPC_INVALID_REQUEST, err.Error())
+	}
 	if request.Method == "" {
 		log.Error("HTTP JSON RPC Handle - method is not string: ")
-		return
+		return errorResponse(request.ID, berr.RPC_INVALID_REQUEST, "method is not string")
 	}
 	//get the corresponding function
-	mainMux.RLock()
-	function, ok := mainMux.m[request.Method]
-	mainMux.RUnlock()
-	if ok {
-		response := function(request.Params)
-		data, err := json.Marshal(map[string]interface{}{
-			"jsonrpc": "2.0",
-			"error":   response["error"],
-			"desc":    response["desc"],
-			"result":  response["result"],
-			"id":      request.ID,
-		})
-		if err != nil {
-			log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
-			return
-		}
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("content-type", "application/json;charset=utf-8")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Write(data)
-	} else {
+	self.RLock()
+	function, ok := self.m[request.Method]
+	self.RUnlock()
+	jsonrpc2 := config.DefConfig.Rpc.JsonRpc2
+	notification := jsonrpc2 && isNotification(msg)
+	if !ok {
 		//if the function does not exist
 		log.Warn("HTTP JSON RPC Handle - No function to call for ", request.Method)
-		data, err := json.Marshal(map[string]interface{}{
+		if notification {
+			return nil
+		}
+		if jsonrpc2 {
+			return errorResponse(request.ID, berr.RPC_METHOD_NOT_FOUND, "The called method was not found on the server")
+		}
+		return map[string]interface{}{
 			"error": berr.INVALID_METHOD,
 			"result": map[string]interface{}{
-				"code":    -32601,
+				"code":    berr.RPC_METHOD_NOT_FOUND,
 				"message": "Method not found",
 				"data":    "The called method was not found on the server",
 			},
 			"id": request.ID,
-		})
-		if err != nil {
-			log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
-			return
 		}
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("content-type", "application/json;charset=utf-8")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Write(data)
+	}
+	response := function(request.Params)
+	if notification {
+		return nil
+	}
+	if jsonrpc2 {
+		errcode, _ := response["error"].(int64)
+		if errcode != berr.SUCCESS {
+			return errorResponse(request.ID, errcode, response["result"])
+		}
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"result":  response["result"],
+			"id":      request.ID,
+		}
+	}
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"error":   response["error"],
+		"desc":    response["desc"],
+		"result":  response["result"],
+		"id":      request.ID,
+	}
+}
+
+//errorResponse return the response with json rpc 2.0 error object if enabled, otherwise
+//with the ontology error code
+func errorResponse(id interface{}, errcode int64, data interface{}) map[string]interface{} {
+	if !config.DefConfig.Rpc.JsonRpc2 {
+		errcode = berr.LegacyErrorCode(errcode)
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"error":   errcode,
+			"desc":    berr.ErrMap[errcode],
+			"result":  data,
+			"id":      id,
+		}
+	}
+	rpcErr := map[string]interface{}{
+		"code":    berr.JsonRpcErrorCode(errcode),
+		"message": berr.ErrMap[errcode],
+	}
+	if data != nil && data != "" {
+		rpcErr["data"] = data
+	}
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"error":   rpcErr,
+		"id":      id,
 	}
 }
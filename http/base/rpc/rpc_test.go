@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ontio/ontology/common/config"
+	berr "github.com/ontio/ontology/http/base/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveRaw(mux *ServeMux, body string) []byte {
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	return w.Body.Bytes()
+}
+
+func serve(t *testing.T, mux *ServeMux, body string) interface{} {
+	var resp interface{}
+	assert.Nil(t, json.Unmarshal(serveRaw(mux, body), &resp))
+	return resp
+}
+
+func errorCode(resp interface{}) float64 {
+	return resp.(map[string]interface{})["error"].(map[string]interface{})["code"].(float64)
+}
+
+func newEchoMux() *ServeMux {
+	mux := NewServeMux()
+	mux.HandleFunc("echo", func(params []interface{}) map[string]interface{} {
+		if len(params) == 0 {
+			return ResponsePack(berr.INVALID_PARAMS, "")
+		}
+		return ResponseSuccess(params[0])
+	})
+	return mux
+}
+
+func enableJsonRpc2() func() {
+	config.DefConfig.Rpc.JsonRpc2 = true
+	return func() { config.DefConfig.Rpc.JsonRpc2 = false }
+}
+
+func TestServeBatch(t *testing.T) {
+	mux := newEchoMux()
+	defer enableJsonRpc2()()
+
+	resp := serve(t, mux, `{"jsonrpc":"2.0","method":"echo","params":["a"],"id":1}`).(map[string]interface{})
+	assert.Equal(t, "a", resp["result"])
+	_, ok := resp["error"]
+	assert.False(t, ok)
+
+	batch := serve(t, mux, `[{"jsonrpc":"2.0","method":"echo","params":["a"],"id":1},
+		{"jsonrpc":"2.0","method":"echo","params":[],"id":2}, 1]`).([]interface{})
+	assert.Equal(t, 3, len(batch))
+	assert.Equal(t, "a", batch[0].(map[string]interface{})["result"])
+	assert.Equal(t, float64(berr.RPC_INVALID_PARAMS), errorCode(batch[1]))
+	assert.Equal(t, float64(2), batch[1].(map[string]interface{})["id"])
+	assert.Equal(t, float64(berr.RPC_INVALID_REQUEST), errorCode(batch[2]))
+
+	assert.Equal(t, float64(berr.RPC_PARSE_ERROR), errorCode(serve(t, mux, `{"method":`)))
+	assert.Equal(t, float64(berr.RPC_INVALID_REQUEST), errorCode(serve(t, mux, `[]`)))
+
+	limit := config.DefConfig.Rpc.MaxBatchSize
+	defer func() { config.DefConfig.Rpc.MaxBatchSize = limit }()
+	config.DefConfig.Rpc.MaxBatchSize = 1
+	resp = serve(t, mux, `[{"method":"echo","params":["a"],"id":1},{"method":"echo","params":["a"],"id":2}]`).(map[string]interface{})
+	assert.Equal(t, float64(berr.RPC_INVALID_REQUEST), errorCode(resp))
+}
+
+func TestNotification(t *testing.T) {
+	mux := newEchoMux()
+	defer enableJsonRpc2()()
+
+	assert.Equal(t, 0, len(serveRaw(mux, `{"jsonrpc":"2.0","method":"echo","params":["a"]}`)))
+	assert.Equal(t, 0, len(serveRaw(mux, `{"jsonrpc":"2.0","method":"unknown","params":[]}`)))
+	assert.Equal(t, 0, len(serveRaw(mux, `[{"jsonrpc":"2.0","method":"echo","params":["a"]}]`)))
+
+	batch := serve(t, mux, `[{"jsonrpc":"2.0","method":"echo","params":["a"]},
+		{"jsonrpc":"2.0","method":"echo","params":["b"],"id":null}]`).([]interface{})
+	assert.Equal(t, 1, len(batch))
+	assert.Equal(t, "b", batch[0].(map[string]interface{})["result"])
+
+	// invalid requests are always responded
+	assert.Equal(t, float64(berr.RPC_INVALID_REQUEST), errorCode(serve(t, mux, `{"jsonrpc":"2.0","params":[]}`)))
+}
+
+func TestLegacyResponse(t *testing.T) {
+	mux := newEchoMux()
+
+	resp := serve(t, mux, `{"jsonrpc":"2.0","method":"echo","params":[],"id":1}`).(map[string]interface{})
+	assert.Equal(t, float64(berr.INVALID_PARAMS), resp["error"])
+	assert.Equal(t, float64(1), resp["id"])
+	resp = serve(t, mux, `{"jsonrpc":"2.0","method":"unknown","params":[],"id":1}`).(map[string]interface{})
+	assert.Equal(t, float64(berr.INVALID_METHOD), resp["error"])
+	resp = serve(t, mux, `{"jsonrpc":"2.0","method":"echo","params":["a"]}`).(map[string]interface{})
+	assert.Equal(t, "a", resp["result"])
+	assert.Equal(t, float64(berr.SUCCESS), resp["error"])
+
+	batch := serve(t, mux, `[{"jsonrpc":"2.0","method":"echo","params":["a"],"id":1}, 1]`).([]interface{})
+	assert.Equal(t, 2, len(batch))
+	assert.Equal(t, "a", batch[0].(map[string]interface{})["result"])
+	assert.Equal(t, float64(berr.INVALID_PARAMS), batch[1].(map[string]interface{})["error"])
+	resp = serve(t, mux, `{"method":`).(map[string]interface{})
+	assert.Equal(t, float64(berr.ILLEGAL_DATAFORMAT), resp["error"])
+}
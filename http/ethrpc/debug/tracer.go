@@ -21,11 +21,16 @@ package debug
 import (
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
+	oComm "github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/ledger"
+	sCom "github.com/ontio/ontology/core/store/common"
+	otypes "github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/http/ethrpc/eth"
 	types2 "github.com/ontio/ontology/http/ethrpc/types"
+	evmtypes "github.com/ontio/ontology/smartcontract/service/evm/types"
 	"github.com/ontio/ontology/vm/evm"
 	"github.com/ontio/ontology/vm/evm/tracers"
 )
@@ -53,8 +58,8 @@ type TraceCallConfig struct {
 	*evm.LogConfig
 	Tracer  *string
 	Timeout *string
-	Reexec  *uint64
-	//StateOverrides *ethapi.StateOverride
+	Reexec         *uint64
+	StateOverrides *eth.StateOverride
 }
 
 // TraceCall lets you trace a given eth_call. It collects the structured logs
@@ -66,7 +71,9 @@ func (api *DebugAPI) TraceCall(args types2.CallArgs, blockNrOrHash rpc.BlockNumb
 	msg := args.AsMessage(eth.RPCGasCap)
 
 	var traceConfig *TraceConfig
+	var overrides *eth.StateOverride
 	if config != nil {
+		overrides = config.StateOverrides
 		traceConfig = &TraceConfig{
 			LogConfig: config.LogConfig,
 			Tracer:    config.Tracer,
@@ -74,40 +81,172 @@ func (api *DebugAPI) TraceCall(args types2.CallArgs, blockNrOrHash rpc.BlockNumb
 			Reexec:    config.Reexec,
 		}
 	}
-	return api.traceTx(msg, traceConfig)
+	return api.traceTx(msg, overrides, traceConfig)
+}
+
+// TraceTransaction returns the structured logs created during the execution of EVM
+// and returns them as a JSON object. The transactions before it in the same block
+// are re-executed on the state before the block to rebuild the state of the transaction.
+func (api *DebugAPI) TraceTransaction(hash common.Hash, config *TraceConfig) (interface{}, error) {
+	txHash := oComm.Uint256(hash)
+	tx, height, err := ledger.DefLedger.GetTransaction(txHash)
+	if err != nil {
+		if err == sCom.ErrNotFound {
+			return nil, fmt.Errorf("transaction %s not found", hash.Hex())
+		}
+		return nil, err
+	}
+	if tx.TxType != otypes.EIP155 {
+		return nil, fmt.Errorf("transaction %s is not an evm transaction", hash.Hex())
+	}
+	block, err := ledger.DefLedger.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	index := -1
+	for i, t := range block.Transactions {
+		if t.Hash() == txHash {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("transaction %s not found in block %d", hash.Hex(), height)
+	}
+	tracer, err := newTracer(config)
+	if err != nil {
+		return nil, err
+	}
+	results, err := ledger.DefLedger.TraceBlock(block, index, func(i int) evm.Tracer {
+		if i == index {
+			return tracer
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tracing failed: %w", err)
+	}
+	return formatResult(tracer, results[index])
+}
+
+// TraceBlockByNumber returns the structured logs created during the execution of
+// EVM and returns them as a JSON object.
+func (api *DebugAPI) TraceBlockByNumber(number types2.BlockNumber, config *TraceConfig) ([]*TxTraceResult, error) {
+	height := uint32(number)
+	if number.IsLatest() || number.IsPending() {
+		height = ledger.DefLedger.GetCurrentBlockHeight()
+	}
+	block, err := ledger.DefLedger.GetBlockByHeight(height)
+	if err != nil {
+		if err == sCom.ErrNotFound {
+			return nil, fmt.Errorf("block #%d not found", height)
+		}
+		return nil, err
+	}
+	return api.traceBlock(block, config)
+}
+
+// TraceBlockByHash returns the structured logs created during the execution of
+// EVM and returns them as a JSON object.
+func (api *DebugAPI) TraceBlockByHash(hash common.Hash, config *TraceConfig) ([]*TxTraceResult, error) {
+	block, err := ledger.DefLedger.GetBlockByHash(oComm.Uint256(hash))
+	if err != nil {
+		if err == sCom.ErrNotFound {
+			return nil, fmt.Errorf("block %s not found", hash.Hex())
+		}
+		return nil, err
+	}
+	return api.traceBlock(block, config)
+}
+
+// traceBlock re-executes all the transactions of the block on the state before
+// the block, and traces the evm transactions with a new tracer each.
+func (api *DebugAPI) traceBlock(block *otypes.Block, config *TraceConfig) ([]*TxTraceResult, error) {
+	txTracers := make([]evm.Tracer, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		if tx.TxType != otypes.EIP155 {
+			continue
+		}
+		tracer, err := newTracer(config)
+		if err != nil {
+			return nil, err
+		}
+		txTracers[i] = tracer
+	}
+	results, err := ledger.DefLedger.TraceBlock(block, -1, func(i int) evm.Tracer {
+		return txTracers[i]
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tracing failed: %w", err)
+	}
+	traces := make([]*TxTraceResult, 0, len(txTracers))
+	for i, tracer := range txTracers {
+		if tracer == nil {
+			continue
+		}
+		trace := &TxTraceResult{TxHash: common.Hash(block.Transactions[i].Hash())}
+		res, err := formatResult(tracer, results[i])
+		if err != nil {
+			trace.Error = err.Error()
+		} else {
+			trace.Result = res
+		}
+		traces = append(traces, trace)
+	}
+	return traces, nil
 }
 
 // traceTx configures a new tracer according to the provided configuration, and
-// executes the given message in the provided environment. The return value will
-// be tracer dependent.
-func (api *DebugAPI) traceTx(message types.Message, config *TraceConfig) (interface{}, error) {
-	// Assemble the structured logger or the JavaScript tracer
-	var (
-		tracer evm.Tracer
-		err    error
-	)
+// executes the given message in the provided environment, which is changed by the
+// state overrides first. The return value will be tracer dependent.
+func (api *DebugAPI) traceTx(message types.Message, overrides *eth.StateOverride, config *TraceConfig) (interface{}, error) {
+	tracer, err := newTracer(config)
+	if err != nil {
+		return nil, err
+	}
+	var result *evmtypes.ExecutionResult
+	if overrides != nil {
+		height := ledger.DefLedger.GetCurrentBlockHeight()
+		result, err = ledger.DefLedger.ExecuteEip155TxAt(message, height, overrides.Apply, tracer)
+	} else {
+		result, err = ledger.DefLedger.TraceEip155Tx(message, tracer)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tracing failed: %w", err)
+	}
+	return formatResult(tracer, result)
+}
+
+// newTracer assembles the structured logger or the named tracer according to the
+// provided configuration.
+func newTracer(config *TraceConfig) (evm.Tracer, error) {
 	switch {
 	case config == nil:
-		tracer = evm.NewStructLogger(nil)
+		return evm.NewStructLogger(nil), nil
 	case config.Tracer != nil:
 		switch *config.Tracer {
 		case "callTracer":
-			tracer = tracers.NewCallTracer()
+			return tracers.NewCallTracer(), nil
+		case "prestateTracer":
+			return tracers.NewPrestateTracer(), nil
+		case "4byteTracer":
+			return tracers.NewFourByteTracer(), nil
 		default:
 			return nil, fmt.Errorf("unkown tracer type: %s", *config.Tracer)
 		}
 	default:
-		tracer = evm.NewStructLogger(config.LogConfig)
-	}
-
-	result, err := ledger.DefLedger.TraceEip155Tx(message, nil)
-	if err != nil {
-		return nil, fmt.Errorf("tracing failed: %w", err)
+		return evm.NewStructLogger(config.LogConfig), nil
 	}
+}
 
-	// Depending on the tracer type, format and return the output.
+// formatResult formats and returns the output depending on the tracer type.
+func formatResult(tracer evm.Tracer, result *evmtypes.ExecutionResult) (interface{}, error) {
 	switch tracer := tracer.(type) {
 	case *evm.StructLogger:
+		// The transaction failed to apply, which is logged by the ledger.
+		if result == nil {
+			return nil, fmt.Errorf("transaction execution failed")
+		}
 		// If the result contains a revert reason, return it.
 		returnVal := fmt.Sprintf("%x", result.Return())
 		if len(result.Revert()) > 0 {
@@ -123,6 +262,12 @@ func (api *DebugAPI) traceTx(message types.Message, config *TraceConfig) (interf
 	case *tracers.CallTracer:
 		return tracer.GetResult()
 
+	case *tracers.PrestateTracer:
+		return tracer.GetResult()
+
+	case *tracers.FourByteTracer:
+		return tracer.GetResult()
+
 	default:
 		panic(fmt.Sprintf("bad tracer type %T", tracer))
 	}
@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -46,6 +47,7 @@ import (
 	"github.com/ontio/ontology/smartcontract/service/evm"
 	types3 "github.com/ontio/ontology/smartcontract/service/evm/types"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/smartcontract/storage"
 	errors2 "github.com/ontio/ontology/vm/evm/errors"
 	"github.com/ontio/ontology/vm/evm/params"
 )
@@ -82,8 +84,19 @@ func (api *EthereumAPI) BlockNumber() (hexutil.Uint64, error) {
 	return hexutil.Uint64(height), nil
 }
 
-func (api *EthereumAPI) GetBalance(address common.Address, _ rpc.BlockNumberOrHash) (*hexutil.Big, error) {
-	log.Debugf("eth_getBalance address %v", address.Hex())
+func (api *EthereumAPI) GetBalance(address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	log.Debugf("eth_getBalance address %v, block %v", address.Hex(), blockNrOrHash)
+	height, latest, err := stateHeightByNumberOrHash(blockNrOrHash)
+	if err != nil {
+		return (*hexutil.Big)(big.NewInt(0)), err
+	}
+	if !latest {
+		balance, err := bactor.GetOngBalanceAt(oComm.Address(address), height)
+		if err != nil {
+			return (*hexutil.Big)(big.NewInt(0)), err
+		}
+		return (*hexutil.Big)(balance), nil
+	}
 	balance, err := getOngBalance(address)
 	if err != nil {
 		return (*hexutil.Big)(big.NewInt(0)), err
@@ -91,6 +104,43 @@ func (api *EthereumAPI) GetBalance(address common.Address, _ rpc.BlockNumberOrHa
 	return (*hexutil.Big)(balance.ToBigInt()), err
 }
 
+// stateHeight return the height of the state the block number refers to, latest is true if
+// the current state should be used
+func stateHeight(blockNum types2.BlockNumber) (height uint32, latest bool, err error) {
+	if blockNum.IsLatest() || blockNum.IsPending() {
+		return 0, true, nil
+	}
+	return checkStateHeight(blockNum.Int64())
+}
+
+//checkStateHeight rejects the block number beyond the current height instead of wrapping it to a wrong height
+func checkStateHeight(number int64) (height uint32, latest bool, err error) {
+	current := bactor.GetCurrentBlockHeight()
+	if number < 0 || number > math.MaxUint32 || uint32(number) > current {
+		return 0, false, fmt.Errorf("block number %d is beyond the current height %d", number, current)
+	}
+	height = uint32(number)
+	return height, height == current, nil
+}
+
+func stateHeightByNumberOrHash(blockNrOrHash rpc.BlockNumberOrHash) (height uint32, latest bool, err error) {
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		header, err := bactor.GetHeaderByHash(oComm.Uint256(hash))
+		if err != nil || header == nil {
+			return 0, false, fmt.Errorf("block %v not found", hash.Hex())
+		}
+		return header.Height, header.Height >= bactor.GetCurrentBlockHeight(), nil
+	}
+	number, _ := blockNrOrHash.Number()
+	switch number {
+	case rpc.LatestBlockNumber, rpc.PendingBlockNumber:
+		return 0, true, nil
+	case rpc.EarliestBlockNumber:
+		return 0, false, nil
+	}
+	return checkStateHeight(number.Int64())
+}
+
 func getOngBalance(address common.Address) (states.NativeTokenBalance, error) {
 	balances, _, err := hComm.GetNativeTokenBalance(0, []oComm.Address{utils.OngContractAddress}, oComm.Address(address), true)
 	if err != nil {
@@ -144,6 +194,13 @@ func (api *EthereumAPI) Accounts() ([]common.Address, error) {
 
 func (api *EthereumAPI) GetStorageAt(address common.Address, key string, blockNum types2.BlockNumber) (hexutil.Bytes, error) {
 	log.Debugf("eth_getStorageAt address %v, key %s, blockNum %v", address.Hex(), key, blockNum)
+	height, latest, err := stateHeight(blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if !latest {
+		return bactor.GetEthStorageAt(address, common.HexToHash(key), height)
+	}
 	return bactor.GetEthStorage(address, common.HexToHash(key))
 }
 
@@ -154,7 +211,16 @@ func (api *EthereumAPI) GetTransactionCount(address common.Address, blockNum typ
 		n := hexutil.Uint64(nonce)
 		return &n, nil
 	}
-	account, err := bactor.GetEthAccount(address)
+	height, latest, err := stateHeight(blockNum)
+	if err != nil {
+		return nil, err
+	}
+	var account *storage.EthAccount
+	if latest {
+		account, err = bactor.GetEthAccount(address)
+	} else {
+		account, err = bactor.GetEthAccountAt(address, height)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -198,7 +264,16 @@ func (api *EthereumAPI) GetUncleCountByBlockNumber(number int64) hexutil.Uint {
 
 func (api *EthereumAPI) GetCode(address common.Address, blockNumber types2.BlockNumber) (hexutil.Bytes, error) {
 	log.Debugf("eth_getCode address %s, blockNumber %v", address.Hex(), blockNumber)
-	account, err := bactor.GetEthAccount(address)
+	height, latest, err := stateHeight(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	var account *storage.EthAccount
+	if latest {
+		account, err = bactor.GetEthAccount(address)
+	} else {
+		account, err = bactor.GetEthAccountAt(address, height)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -284,10 +359,25 @@ func (api *EthereumAPI) SendRawTransaction(data hexutil.Bytes) (common.Hash, err
 	return common.Hash(txhash), nil
 }
 
-func (api *EthereumAPI) Call(args types2.CallArgs, blockNumber types2.BlockNumber, _ *map[common.Address]types2.Account) (hexutil.Bytes, error) {
+func (api *EthereumAPI) Call(args types2.CallArgs, blockNumber types2.BlockNumber, overrides *StateOverride) (hexutil.Bytes, error) {
 	log.Debugf("eth_call block number %v ", blockNumber)
 	msg := args.AsMessage(RPCGasCap)
-	res, err := bactor.PreExecuteEip155Tx(msg)
+	height, latest, err := stateHeight(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	var res *types3.ExecutionResult
+	switch {
+	case overrides != nil:
+		if latest {
+			height = bactor.GetCurrentBlockHeight()
+		}
+		res, err = bactor.PreExecuteEip155TxWithOverride(msg, height, overrides.Apply)
+	case latest:
+		res, err = bactor.PreExecuteEip155Tx(msg)
+	default:
+		res, err = bactor.PreExecuteEip155TxAt(msg, height)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -314,7 +404,7 @@ type revertError struct {
 	reason string // revert reason hex encoded
 }
 
-func (api *EthereumAPI) EstimateGas(args types2.CallArgs, _ *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+func (api *EthereumAPI) EstimateGas(args types2.CallArgs, _ *rpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Uint64, error) {
 	var (
 		lo  uint64 = params.TxGas
 		hi  uint64
@@ -333,6 +423,11 @@ func (api *EthereumAPI) EstimateGas(args types2.CallArgs, _ *rpc.BlockNumberOrHa
 	if args.GasPrice != nil && args.GasPrice.ToInt().BitLen() != 0 {
 		balance, _ := getOngBalance(*args.From)
 		available := balance.ToBigInt()
+		if overrides != nil {
+			if account, ok := (*overrides)[*args.From]; ok && account.Balance != nil {
+				available = new(big.Int).Set((*account.Balance).ToInt())
+			}
+		}
 		if args.Value != nil {
 			if args.Value.ToInt().Cmp(available) >= 0 {
 				return 0, errors.New("insufficient funds for transfer")
@@ -354,7 +449,13 @@ func (api *EthereumAPI) EstimateGas(args types2.CallArgs, _ *rpc.BlockNumberOrHa
 	executable := func(gas uint64) (bool, *types3.ExecutionResult, error) {
 		args.Gas = (*hexutil.Uint64)(&gas)
 		tx := args.AsMessage(RPCGasCap)
-		result, err := bactor.PreExecuteEip155Tx(tx)
+		var result *types3.ExecutionResult
+		var err error
+		if overrides != nil {
+			result, err = bactor.PreExecuteEip155TxWithOverride(tx, bactor.GetCurrentBlockHeight(), overrides.Apply)
+		} else {
+			result, err = bactor.PreExecuteEip155Tx(tx)
+		}
 		if err != nil {
 			if errors.Is(err, evm.ErrIntrinsicGas) {
 				return true, nil, nil
@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	oComm "github.com/ontio/ontology/common"
+	types2 "github.com/ontio/ontology/http/ethrpc/types"
+	"github.com/ontio/ontology/smartcontract/service/native/ong"
+	"github.com/ontio/ontology/smartcontract/storage"
+)
+
+// StateOverride is the collection of overridden accounts.
+type StateOverride map[common.Address]types2.Account
+
+// Apply overrides the fields of specified accounts into the given cache db.
+func (diff *StateOverride) Apply(cache *storage.CacheDB) error {
+	if diff == nil {
+		return nil
+	}
+	state := storage.NewStateDB(cache, common.Hash{}, common.Hash{}, ong.OngBalanceHandle{})
+	for addr, account := range *diff {
+		// Override account nonce.
+		if account.Nonce != nil {
+			state.SetNonce(addr, uint64(*account.Nonce))
+		}
+		// Override account(contract) code.
+		if account.Code != nil {
+			state.SetCode(addr, *account.Code)
+		}
+		// Override account balance.
+		if account.Balance != nil {
+			err := ong.OngBalanceHandle{}.SetBalance(cache, oComm.Address(addr), (*account.Balance).ToInt())
+			if err != nil {
+				return err
+			}
+		}
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
+		}
+		// Replace entire state if caller requires.
+		if account.State != nil {
+			if err := cache.CleanContractStorageData(oComm.Address(addr)); err != nil {
+				return err
+			}
+			for key, value := range *account.State {
+				state.SetState(addr, key, value)
+			}
+		}
+		// Apply state diff into specified accounts.
+		if account.StateDiff != nil {
+			for key, value := range *account.StateDiff {
+				state.SetState(addr, key, value)
+			}
+		}
+	}
+	return state.DbErr()
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package eth
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/smartcontract/service/native/ong"
+	"github.com/ontio/ontology/smartcontract/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateOverride(t *testing.T) {
+	addr := common.HexToAddress("0x0a")
+	backend := overlaydb.NewOverlayDB(leveldbstore.NewMemLevelDBStore())
+	state := storage.NewStateDB(storage.NewCacheDB(backend), common.Hash{}, common.Hash{}, ong.OngBalanceHandle{})
+	state.SetState(addr, common.HexToHash("0x01"), common.HexToHash("0x11"))
+	state.SetState(addr, common.HexToHash("0x02"), common.HexToHash("0x22"))
+	assert.Nil(t, state.Commit())
+
+	var overrides StateOverride
+	assert.Nil(t, json.Unmarshal([]byte(`{"0x000000000000000000000000000000000000000a": {
+		"nonce": "0x5", "code": "0x6000", "balance": "0x64",
+		"stateDiff": {"0x0000000000000000000000000000000000000000000000000000000000000002": "0x0000000000000000000000000000000000000000000000000000000000000033"}
+	}}`), &overrides))
+	cache := storage.NewCacheDB(backend)
+	assert.Nil(t, overrides.Apply(cache))
+	state = storage.NewStateDB(cache, common.Hash{}, common.Hash{}, ong.OngBalanceHandle{})
+	assert.Equal(t, uint64(5), state.GetNonce(addr))
+	assert.Equal(t, []byte{0x60, 0x00}, state.GetCode(addr))
+	assert.Equal(t, big.NewInt(100), state.GetBalance(addr))
+	assert.Equal(t, common.HexToHash("0x11"), state.GetState(addr, common.HexToHash("0x01")))
+	assert.Equal(t, common.HexToHash("0x33"), state.GetState(addr, common.HexToHash("0x02")))
+
+	// the full state replaces all the storage of the account
+	overrides = StateOverride{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"0x000000000000000000000000000000000000000a": {
+		"state": {"0x0000000000000000000000000000000000000000000000000000000000000002": "0x0000000000000000000000000000000000000000000000000000000000000044"}
+	}}`), &overrides))
+	cache = storage.NewCacheDB(backend)
+	assert.Nil(t, overrides.Apply(cache))
+	state = storage.NewStateDB(cache, common.Hash{}, common.Hash{}, ong.OngBalanceHandle{})
+	assert.Equal(t, common.Hash{}, state.GetState(addr, common.HexToHash("0x01")))
+	assert.Equal(t, common.HexToHash("0x44"), state.GetState(addr, common.HexToHash("0x02")))
+
+	// the overrides are not written to the backend
+	state = storage.NewStateDB(storage.NewCacheDB(backend), common.Hash{}, common.Hash{}, ong.OngBalanceHandle{})
+	assert.Equal(t, common.HexToHash("0x22"), state.GetState(addr, common.HexToHash("0x02")))
+
+	diff := map[common.Hash]common.Hash{}
+	overrides = StateOverride{addr: {State: &diff, StateDiff: &diff}}
+	assert.NotNil(t, overrides.Apply(storage.NewCacheDB(backend)))
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package evm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ontio/ontology/common/log"
+	cactor "github.com/ontio/ontology/consensus/actor"
+	bactor "github.com/ontio/ontology/http/base/actor"
+)
+
+// DevChainAPI offers the evm_ namespace of hardhat and ganache to control the dev chain of solo consensus
+type DevChainAPI struct {
+	request func(req interface{}) (uint64, error)
+}
+
+func NewDevChainAPI() *DevChainAPI {
+	return &DevChainAPI{request: bactor.DevChainRequest}
+}
+
+// Quantity accepts both json number and hex string
+type Quantity uint64
+
+func (q *Quantity) UnmarshalJSON(input []byte) error {
+	if len(input) > 0 && input[0] == '"' {
+		var v hexutil.Uint64
+		if err := json.Unmarshal(input, &v); err != nil {
+			return err
+		}
+		*q = Quantity(v)
+		return nil
+	}
+	v, err := strconv.ParseUint(string(input), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid quantity %s", input)
+	}
+	*q = Quantity(v)
+	return nil
+}
+
+// MineOptions is the parameter of evm_mine, either the timestamp of the block or {"blocks": n, "timestamp": t}
+type MineOptions struct {
+	Blocks    Quantity `json:"blocks"`
+	Timestamp Quantity `json:"timestamp"`
+}
+
+func (opts *MineOptions) UnmarshalJSON(input []byte) error {
+	if len(input) > 0 && input[0] == '{' {
+		type options MineOptions
+		return json.Unmarshal(input, (*options)(opts))
+	}
+	return json.Unmarshal(input, &opts.Timestamp)
+}
+
+// Mine mines blocks immediately, with the timestamp of the first block if given
+func (api *DevChainAPI) Mine(opts *MineOptions) (string, error) {
+	log.Debugf("evm_mine %v", opts)
+	blocks := uint32(1)
+	if opts != nil {
+		if opts.Blocks > 1 {
+			blocks = uint32(opts.Blocks)
+		}
+		if opts.Timestamp != 0 {
+			if err := api.SetNextBlockTimestamp(opts.Timestamp); err != nil {
+				return "", err
+			}
+		}
+	}
+	if _, err := api.request(&cactor.DevMine{Blocks: blocks}); err != nil {
+		return "", err
+	}
+	return "0x0", nil
+}
+
+// Snapshot saves the state of the chain and returns the id of the snapshot
+func (api *DevChainAPI) Snapshot() (hexutil.Uint64, error) {
+	log.Debug("evm_snapshot")
+	id, err := api.request(&cactor.DevSnapshot{})
+	return hexutil.Uint64(id), err
+}
+
+// Revert reverts the chain to the snapshot, the snapshot and the ones taken after it can not be used again
+func (api *DevChainAPI) Revert(id Quantity) (bool, error) {
+	log.Debugf("evm_revert %d", id)
+	if _, err := api.request(&cactor.DevRevert{Id: uint32(id)}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// IncreaseTime adds seconds to the timestamp of following blocks, and returns the total time increased
+func (api *DevChainAPI) IncreaseTime(seconds Quantity) (uint64, error) {
+	log.Debugf("evm_increaseTime %d", seconds)
+	return api.request(&cactor.DevIncreaseTime{Seconds: uint64(seconds)})
+}
+
+// SetNextBlockTimestamp sets the timestamp of next block, following blocks keep increasing from it
+func (api *DevChainAPI) SetNextBlockTimestamp(timestamp Quantity) error {
+	log.Debugf("evm_setNextBlockTimestamp %d", timestamp)
+	_, err := api.request(&cactor.DevSetNextBlockTimestamp{Timestamp: uint32(timestamp)})
+	return err
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package evm
+
+import (
+	"encoding/json"
+	"testing"
+
+	cactor "github.com/ontio/ontology/consensus/actor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMineOptions(t *testing.T) {
+	var opts MineOptions
+	assert.Nil(t, json.Unmarshal([]byte(`1700000000`), &opts))
+	assert.Equal(t, MineOptions{Timestamp: 1700000000}, opts)
+
+	opts = MineOptions{}
+	assert.Nil(t, json.Unmarshal([]byte(`"0x10"`), &opts))
+	assert.Equal(t, MineOptions{Timestamp: 16}, opts)
+
+	opts = MineOptions{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"blocks": "0x5", "timestamp": 1700000000}`), &opts))
+	assert.Equal(t, MineOptions{Blocks: 5, Timestamp: 1700000000}, opts)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`-1`), &opts))
+}
+
+func TestMine(t *testing.T) {
+	var reqs []interface{}
+	api := &DevChainAPI{request: func(req interface{}) (uint64, error) {
+		reqs = append(reqs, req)
+		return 0, nil
+	}}
+	_, err := api.Mine(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{&cactor.DevMine{Blocks: 1}}, reqs)
+
+	reqs = nil
+	_, err = api.Mine(&MineOptions{Blocks: 3, Timestamp: 1700000000})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{&cactor.DevSetNextBlockTimestamp{Timestamp: 1700000000}, &cactor.DevMine{Blocks: 3}}, reqs)
+}
@@ -51,6 +51,9 @@ type PublicFilterAPI struct {
 	filters   map[rpc.ID]*filter
 	events    *EventSystem
 	timeout   time.Duration
+
+	// SubscriptionEnded is called with the id of subscription ended by the server instead of the client
+	SubscriptionEnded func(id rpc.ID)
 }
 
 func NewPublicFilterAPI(backend Backend) *PublicFilterAPI {
@@ -132,6 +135,15 @@ func (api *PublicFilterAPI) NewPendingTransactionFilter() rpc.ID {
 	return pendingTxSub.ID
 }
 
+// endSubscription stops pushing to the subscription when the event system uninstalled it or the
+// notification failed, the client is not told and needs to subscribe again.
+func (api *PublicFilterAPI) endSubscription(id rpc.ID, sub *Subscription) {
+	sub.Unsubscribe()
+	if api.SubscriptionEnded != nil {
+		api.SubscriptionEnded(id)
+	}
+}
+
 // NewPendingTransactions creates a subscription that is triggered each time a transaction
 // enters the transaction pool and was signed from one of the transactions this nodes manages.
 func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context) (*rpc.Subscription, error) {
@@ -149,8 +161,14 @@ func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context) (*rpc.Su
 				// To keep the original behaviour, send a single tx hash in one notification.
 				// TODO(rjl493456442) Send a batch of tx hashes in one notification
 				for _, h := range hashes {
-					notifier.Notify(rpcSub.ID, h)
+					if err := notifier.Notify(rpcSub.ID, h); err != nil {
+						api.endSubscription(rpcSub.ID, pendingTxSub)
+						return
+					}
 				}
+			case <-pendingTxSub.Err():
+				api.endSubscription(rpcSub.ID, pendingTxSub)
+				return
 			case <-rpcSub.Err():
 				pendingTxSub.Unsubscribe()
 				return
@@ -215,7 +233,13 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 		for {
 			select {
 			case h := <-headers:
-				notifier.Notify(rpcSub.ID, h)
+				if err := notifier.Notify(rpcSub.ID, h); err != nil {
+					api.endSubscription(rpcSub.ID, headersSub)
+					return
+				}
+			case <-headersSub.Err():
+				api.endSubscription(rpcSub.ID, headersSub)
+				return
 			case <-rpcSub.Err():
 				headersSub.Unsubscribe()
 				return
@@ -251,9 +275,17 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit filters.FilterCriteri
 		for {
 			select {
 			case logs := <-matchedLogs:
+				// logs are pushed after the block is committed, which is final in ontology, so the
+				// removed field is never set
 				for _, log := range logs {
-					notifier.Notify(rpcSub.ID, &log)
+					if err := notifier.Notify(rpcSub.ID, log); err != nil {
+						api.endSubscription(rpcSub.ID, logsSub)
+						return
+					}
 				}
+			case <-logsSub.Err(): // uninstalled by the event system
+				api.endSubscription(rpcSub.ID, logsSub)
+				return
 			case <-rpcSub.Err(): // client send an unsubscribe request
 				logsSub.Unsubscribe()
 				return
@@ -30,8 +30,10 @@ import (
 	backend2 "github.com/ontio/ontology/http/ethrpc/backend"
 	"github.com/ontio/ontology/http/ethrpc/debug"
 	"github.com/ontio/ontology/http/ethrpc/eth"
+	"github.com/ontio/ontology/http/ethrpc/evm"
 	filters2 "github.com/ontio/ontology/http/ethrpc/filters"
 	"github.com/ontio/ontology/http/ethrpc/net"
+	txpool2 "github.com/ontio/ontology/http/ethrpc/txpool"
 	"github.com/ontio/ontology/http/ethrpc/utils"
 	"github.com/ontio/ontology/http/ethrpc/web3"
 	tp "github.com/ontio/ontology/txnpool/proc"
@@ -60,7 +62,9 @@ func StartEthServer(txpool *tp.TXPoolServer) error {
 		return err
 	}
 
-	if err := server.RegisterName("eth", filters2.NewPublicFilterAPI(backend)); err != nil {
+	filterAPI := filters2.NewPublicFilterAPI(backend)
+	filterAPI.SubscriptionEnded = releaseWsSubscription
+	if err := server.RegisterName("eth", filterAPI); err != nil {
 		return err
 	}
 	if err := server.RegisterName("net", net.NewPublicNetAPI()); err != nil {
@@ -72,6 +76,22 @@ func StartEthServer(txpool *tp.TXPoolServer) error {
 	if err := server.RegisterName("debug", debug.NewDebugAPI()); err != nil {
 		return err
 	}
+	if err := server.RegisterName("txpool", txpool2.NewPublicTxPoolAPI(txpool)); err != nil {
+		return err
+	}
+	if cfg.DefConfig.Genesis.IsDevChain() {
+		if err := server.RegisterName("evm", evm.NewDevChainAPI()); err != nil {
+			return err
+		}
+	}
+
+	if cfg.DefConfig.Rpc.EnableEthWs {
+		go func() {
+			if err := startWsServer(server); err != nil {
+				log.Error("eth websocket server stopped", "err", err)
+			}
+		}()
+	}
 
 	// add cors wrapper
 	wrappedCORSHandler := node.NewHTTPHandlerStack(server, cors, vhosts)
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package txpool
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	oComm "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	types2 "github.com/ontio/ontology/http/ethrpc/types"
+	utils2 "github.com/ontio/ontology/http/ethrpc/utils"
+	tc "github.com/ontio/ontology/txnpool/common"
+)
+
+type TxPoolService interface {
+	GetPoolTxs(payer *oComm.Address) []*tc.PoolTxInfo
+}
+
+// PublicTxPoolAPI offers the txpool_ namespace, only the eip155 transactions are returned.
+// The transactions still being verified are regarded as pending.
+type PublicTxPoolAPI struct {
+	txpool TxPoolService
+}
+
+func NewPublicTxPoolAPI(txpool TxPoolService) *PublicTxPoolAPI {
+	return &PublicTxPoolAPI{txpool: txpool}
+}
+
+// Content returns the transactions contained within the transaction pool.
+func (api *PublicTxPoolAPI) Content() map[string]map[string]map[string]*types2.Transaction {
+	log.Debug("txpool_content")
+	content := map[string]map[string]map[string]*types2.Transaction{
+		"pending": make(map[string]map[string]*types2.Transaction),
+		"queued":  make(map[string]map[string]*types2.Transaction),
+	}
+	for _, info := range api.txpool.GetPoolTxs(nil) {
+		ethTx, err := info.Tx.GetEIP155Tx()
+		if err != nil {
+			continue
+		}
+		rpcTx, err := utils2.NewTransaction(ethTx, ethTx.Hash(), common.Hash{}, 0, 0)
+		if err != nil {
+			continue
+		}
+		group := content[poolState(info)]
+		from := common.Address(info.Tx.Payer).Hex()
+		if group[from] == nil {
+			group[from] = make(map[string]*types2.Transaction)
+		}
+		group[from][fmt.Sprintf("%d", ethTx.Nonce())] = rpcTx
+	}
+	return content
+}
+
+// Inspect returns a textual summary of the transactions contained within the transaction pool.
+func (api *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
+	log.Debug("txpool_inspect")
+	content := map[string]map[string]map[string]string{
+		"pending": make(map[string]map[string]string),
+		"queued":  make(map[string]map[string]string),
+	}
+	for _, info := range api.txpool.GetPoolTxs(nil) {
+		ethTx, err := info.Tx.GetEIP155Tx()
+		if err != nil {
+			continue
+		}
+		summary := fmt.Sprintf("contract creation: %v wei + %v gas × %v wei", ethTx.Value(), ethTx.Gas(), ethTx.GasPrice())
+		if to := ethTx.To(); to != nil {
+			summary = fmt.Sprintf("%s: %v wei + %v gas × %v wei", to.Hex(), ethTx.Value(), ethTx.Gas(), ethTx.GasPrice())
+		}
+		group := content[poolState(info)]
+		from := common.Address(info.Tx.Payer).Hex()
+		if group[from] == nil {
+			group[from] = make(map[string]string)
+		}
+		group[from][fmt.Sprintf("%d", ethTx.Nonce())] = summary
+	}
+	return content
+}
+
+// Status returns the number of pending and queued transactions in the pool.
+func (api *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
+	log.Debug("txpool_status")
+	var pending, queued uint
+	for _, info := range api.txpool.GetPoolTxs(nil) {
+		if !info.Tx.IsEipTx() {
+			continue
+		}
+		if info.Queued {
+			queued++
+		} else {
+			pending++
+		}
+	}
+	return map[string]hexutil.Uint{
+		"pending": hexutil.Uint(pending),
+		"queued":  hexutil.Uint(queued),
+	}
+}
+
+func poolState(info *tc.PoolTxInfo) string {
+	if info.Queued {
+		return "queued"
+	}
+	return "pending"
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	oComm "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/constants"
+	"github.com/ontio/ontology/core/types"
+	tc "github.com/ontio/ontology/txnpool/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTxPool []*tc.PoolTxInfo
+
+func (self testTxPool) GetPoolTxs(payer *oComm.Address) []*tc.PoolTxInfo {
+	return self
+}
+
+func genEipTx(t *testing.T, nonce uint64) *types.Transaction {
+	privateKey, _ := crypto.HexToECDSA("fad9c8855b740a0b7ed4c221dbad0f33a83a49cad6b3fe8d5817ac83d38b6a19")
+	tx := ethtypes.NewTransaction(nonce, common.Address{1}, big.NewInt(1), 21000, big.NewInt(2500*constants.GWei), nil)
+	signedTx, err := ethtypes.SignTx(tx, ethtypes.NewEIP155Signer(big.NewInt(12345)), privateKey)
+	assert.Nil(t, err)
+	otx, err := types.TransactionFromEIP155(signedTx)
+	assert.Nil(t, err)
+	return otx
+}
+
+func TestTxPoolAPI(t *testing.T) {
+	config.DefConfig.P2PNode.EVMChainId = 12345
+	tx0, tx2 := genEipTx(t, 0), genEipTx(t, 2)
+	api := NewPublicTxPoolAPI(testTxPool{
+		{Tx: tx0},
+		{Tx: tx2, Queued: true},
+	})
+	from := common.Address(tx0.Payer).Hex()
+
+	status := api.Status()
+	assert.Equal(t, hexutil.Uint(1), status["pending"])
+	assert.Equal(t, hexutil.Uint(1), status["queued"])
+
+	content := api.Content()
+	assert.Equal(t, common.Hash(tx0.Hash()), content["pending"][from]["0"].Hash)
+	assert.Equal(t, common.Hash(tx2.Hash()), content["queued"][from]["2"].Hash)
+
+	inspect := api.Inspect()
+	assert.Equal(t, common.Address{1}.Hex()+": 1 wei + 21000 gas × 2500000000000 wei", inspect["queued"][from]["2"])
+}
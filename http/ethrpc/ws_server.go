@@ -0,0 +1,326 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package ethrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+	cfg "github.com/ontio/ontology/common/config"
+	hComm "github.com/ontio/ontology/http/base/common"
+	"golang.org/x/net/netutil"
+)
+
+const (
+	wsReadLimit    = 5 * 1024 * 1024
+	wsPingInterval = 30 * time.Second
+	wsPongTimeout  = 30 * time.Second
+	wsWriteTimeout = 10 * time.Second
+
+	errCodeLimitExceeded = -32005
+)
+
+//wsSubscriptions map the active subscription ids to their connections, so that the subscriptions
+//ended by the server are released from the count of connections
+var wsSubscriptions sync.Map
+
+//releaseWsSubscription release the subscription ended by the server from its connection
+func releaseWsSubscription(id rpc.ID) {
+	if c, ok := wsSubscriptions.Load(string(id)); ok {
+		c.(*wsConn).release(string(id))
+	}
+}
+
+//startWsServer serve the eth json rpc over websocket, so that eth_subscribe can push events to clients
+func startWsServer(server *rpc.Server) error {
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(int(cfg.DefConfig.Rpc.EthWsPort)))
+	if err != nil {
+		return err
+	}
+	if cfg.DefConfig.Rpc.EthWsMaxConnections > 0 {
+		listener = netutil.LimitListener(listener, int(cfg.DefConfig.Rpc.EthWsMaxConnections))
+	}
+	handler := newWsHandler(server, cfg.DefConfig.Rpc.EthWsOrigins, cfg.DefConfig.Rpc.EthWsMaxSubscriptions)
+	return http.Serve(listener, handler)
+}
+
+type wsHandler struct {
+	server   *rpc.Server
+	upgrader websocket.Upgrader
+	maxSubs  uint
+}
+
+func newWsHandler(server *rpc.Server, origins []string, maxSubs uint) *wsHandler {
+	return &wsHandler{
+		server: server,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     hComm.OriginChecker(origins),
+		},
+		maxSubs: maxSubs,
+	}
+}
+
+func (self *wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := self.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Debug("eth websocket upgrade failed", "err", err)
+		return
+	}
+	c := newWsConn(conn, self.maxSubs)
+	go c.pingLoop()
+	self.server.ServeCodec(rpc.NewFuncCodec(c, c.encode, c.decode), 0)
+	c.stop()
+}
+
+//jsonMessage is the part of json rpc message used to track subscriptions
+type jsonMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+//wsConn track the subscriptions of a websocket connection, to limit the number of them. The
+//subscriptions are counted when the subscribe requests are read, and confirmed or released by
+//the responses written, or released when the server ends them.
+type wsConn struct {
+	conn    *websocket.Conn
+	maxSubs uint
+	quit    chan struct{}
+
+	writeLock sync.Mutex
+	lock      sync.Mutex
+	pending   map[string]bool   //request id of subscribe calls waiting for response
+	unsubs    map[string]string //request id of unsubscribe calls => subscription id
+	subs      map[string]bool   //active subscription ids
+}
+
+func newWsConn(conn *websocket.Conn, maxSubs uint) *wsConn {
+	conn.SetReadLimit(wsReadLimit)
+	return &wsConn{
+		conn:    conn,
+		maxSubs: maxSubs,
+		quit:    make(chan struct{}),
+		pending: make(map[string]bool),
+		unsubs:  make(map[string]string),
+		subs:    make(map[string]bool),
+	}
+}
+
+//decode read the next message and reject the subscribe calls over limit
+func (self *wsConn) decode(v interface{}) error {
+	for {
+		_, data, err := self.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		data, err = self.trackRequests(data)
+		if err != nil {
+			return err
+		}
+		if data != nil {
+			return json.Unmarshal(data, v)
+		}
+	}
+}
+
+//trackRequests count the subscribe and unsubscribe calls of message, returns the message without
+//the rejected calls, or nil if all the calls are rejected
+func (self *wsConn) trackRequests(data []byte) ([]byte, error) {
+	raws, msgs := parseJsonMessages(data)
+	if len(msgs) == 0 {
+		return data, nil
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	var accepted []json.RawMessage
+	var rejected []*jsonMessage
+	for i, msg := range msgs {
+		if msg == nil || len(msg.ID) == 0 {
+			accepted = append(accepted, raws[i])
+			continue
+		}
+		switch {
+		case strings.HasSuffix(msg.Method, "_subscribe"):
+			if self.maxSubs > 0 && uint(len(self.subs)+len(self.pending)) >= self.maxSubs {
+				rejected = append(rejected, msg)
+				continue
+			}
+			self.pending[string(msg.ID)] = true
+		case strings.HasSuffix(msg.Method, "_unsubscribe"):
+			var params []string
+			if json.Unmarshal(msg.Params, &params) == nil && len(params) > 0 {
+				self.unsubs[string(msg.ID)] = params[0]
+			}
+		}
+		accepted = append(accepted, raws[i])
+	}
+	for _, msg := range rejected {
+		err := self.writeJSON(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      msg.ID,
+			"error": map[string]interface{}{
+				"code":    errCodeLimitExceeded,
+				"message": "too many subscriptions, the max is " + strconv.Itoa(int(self.maxSubs)),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(rejected) == 0 {
+		return data, nil
+	}
+	if len(accepted) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(accepted)
+}
+
+//encode write the message and update the subscriptions by the responses
+func (self *wsConn) encode(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	self.trackResponses(data)
+	self.writeLock.Lock()
+	defer self.writeLock.Unlock()
+	return self.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (self *wsConn) trackResponses(data []byte) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if len(self.pending) == 0 && len(self.unsubs) == 0 {
+		return
+	}
+	_, msgs := parseJsonMessages(data)
+	for _, msg := range msgs {
+		if msg == nil || len(msg.ID) == 0 {
+			continue
+		}
+		id := string(msg.ID)
+		if self.pending[id] {
+			delete(self.pending, id)
+			var subId string
+			if json.Unmarshal(msg.Result, &subId) == nil {
+				self.subs[subId] = true
+				wsSubscriptions.Store(subId, self)
+			}
+		} else if subId, ok := self.unsubs[id]; ok {
+			// the subscription is not active after any response of unsubscribe, either removed
+			// or not found
+			delete(self.unsubs, id)
+			delete(self.subs, subId)
+			wsSubscriptions.Delete(subId)
+		}
+	}
+}
+
+//release remove the subscription ended by the server
+func (self *wsConn) release(subId string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	delete(self.subs, subId)
+	wsSubscriptions.Delete(subId)
+}
+
+func (self *wsConn) writeJSON(v interface{}) error {
+	self.writeLock.Lock()
+	defer self.writeLock.Unlock()
+	self.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return self.conn.WriteJSON(v)
+}
+
+//SetWriteDeadline set the deadline of the writes of json rpc server
+func (self *wsConn) SetWriteDeadline(t time.Time) error {
+	self.writeLock.Lock()
+	defer self.writeLock.Unlock()
+	return self.conn.SetWriteDeadline(t)
+}
+
+func (self *wsConn) RemoteAddr() string {
+	return self.conn.RemoteAddr().String()
+}
+
+func (self *wsConn) Close() error {
+	return self.conn.Close()
+}
+
+//pingLoop keep the idle connection alive and close the dead one
+func (self *wsConn) pingLoop() {
+	self.conn.SetPongHandler(func(string) error {
+		return self.conn.SetReadDeadline(time.Time{})
+	})
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			self.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+			err := self.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+			if err != nil {
+				return
+			}
+		case <-self.quit:
+			return
+		}
+	}
+}
+
+func (self *wsConn) stop() {
+	close(self.quit)
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	for subId := range self.subs {
+		wsSubscriptions.Delete(subId)
+	}
+	self.subs = make(map[string]bool)
+}
+
+//parseJsonMessages parse the single or batch json rpc messages, the invalid ones are nil. The raw
+//messages of batch are returned to rebuild the batch
+func parseJsonMessages(data []byte) ([]json.RawMessage, []*jsonMessage) {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	raws := []json.RawMessage{data}
+	if len(data) > 0 && data[0] == '[' {
+		if json.Unmarshal(data, &raws) != nil {
+			return nil, nil
+		}
+	}
+	msgs := make([]*jsonMessage, len(raws))
+	for i, raw := range raws {
+		msg := &jsonMessage{}
+		if json.Unmarshal(raw, msg) == nil {
+			msgs[i] = msg
+		}
+	}
+	return raws, msgs
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ethrpc
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSubService struct{}
+
+func (self *testSubService) Ticks(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	return notifier.CreateSubscription(), nil
+}
+
+type testRsp struct {
+	ID     int         `json:"id"`
+	Result interface{} `json:"result"`
+	Error  *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+func TestWsSubscriptionLimit(t *testing.T) {
+	server := rpc.NewServer()
+	assert.Nil(t, server.RegisterName("test", &testSubService{}))
+	httpServer := httptest.NewServer(newWsHandler(server, []string{"*"}, 1))
+	defer httpServer.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	call := func(req string) *testRsp {
+		assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(req)))
+		rsp := &testRsp{}
+		assert.Nil(t, conn.ReadJSON(rsp))
+		return rsp
+	}
+	rsp := call(`{"jsonrpc":"2.0","id":1,"method":"test_subscribe","params":["ticks"]}`)
+	assert.Nil(t, rsp.Error)
+	subId := rsp.Result.(string)
+
+	rsp = call(`{"jsonrpc":"2.0","id":2,"method":"test_subscribe","params":["ticks"]}`)
+	assert.Equal(t, 2, rsp.ID)
+	assert.Equal(t, errCodeLimitExceeded, rsp.Error.Code)
+
+	rsp = call(`{"jsonrpc":"2.0","id":3,"method":"test_unsubscribe","params":["` + subId + `"]}`)
+	assert.Equal(t, true, rsp.Result)
+
+	rsp = call(`{"jsonrpc":"2.0","id":4,"method":"test_subscribe","params":["ticks"]}`)
+	assert.Nil(t, rsp.Error)
+	// the subscription ended by the server is released
+	releaseWsSubscription(rpc.ID(rsp.Result.(string)))
+	rsp = call(`{"jsonrpc":"2.0","id":5,"method":"test_subscribe","params":["ticks"]}`)
+	assert.Nil(t, rsp.Error)
+	subId = rsp.Result.(string)
+
+	// unsubscribe of the subscription already removed by the server
+	rsp = call(`{"jsonrpc":"2.0","id":6,"method":"test_unsubscribe","params":["0x01"]}`)
+	assert.NotNil(t, rsp.Error)
+	rsp = call(`{"jsonrpc":"2.0","id":7,"method":"test_subscribe","params":["ticks"]}`)
+	assert.Equal(t, errCodeLimitExceeded, rsp.Error.Code)
+
+	conn.Close()
+	assert.Eventually(t, func() bool {
+		_, ok := wsSubscriptions.Load(subId)
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
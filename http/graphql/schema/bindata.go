@@ -96,7 +96,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _schemaGraphql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x55\x4d\x6f\xdb\x30\x0c\xbd\xfb\x57\xb0\xc8\xa5\xbb\xf4\xd0\xb5\xc5\xe0\x5b\xb3\x16\x48\xd1\xaf\x6c\xc9\x36\x0c\x45\x31\x30\x32\x6b\x0b\xb1\x25\x4f\x94\xd3\x18\x45\xff\xfb\x20\xcb\x76\xe5\xc4\x1d\xd0\x53\x22\xea\xf1\xe9\x3d\x92\x92\x27\xb0\x42\xa6\xd3\x2f\xa0\x0d\x64\xb4\x05\xb6\x46\xaa\x14\x30\x49\x0c\x31\x47\x2c\x30\x47\x03\xe7\xed\x72\x12\x62\xf4\x13\x64\xc8\xd9\xf1\xe9\x59\x07\x9b\xb9\xff\xbb\x98\xb2\x5a\xe5\x52\xc0\x9a\xea\x0e\x36\xaf\x56\xd7\x54\x47\xdd\xf2\x87\x54\xf6\xf3\x71\x34\x81\x4a\x2a\x7b\x76\x02\xa4\x84\x4e\x28\x01\xe4\x96\x25\x04\x9e\x9d\x44\x11\xa9\xaa\x80\xe5\x76\x59\x97\x04\x2f\x11\x00\xc0\xd5\xdd\xcf\xfb\xeb\xcb\x3f\x77\x97\xf7\xe1\xf2\xd7\xf9\xe2\xb6\x59\x5f\x5c\xce\x6f\xee\x7f\xf7\xdb\xed\xb2\xd9\x7e\x8d\x22\xa9\x2c\x99\x27\x14\x04\x73\xac\x73\x8d\x49\x4b\xea\x54\x40\x0c\x8b\x46\xc3\x81\x43\x5a\x77\xe2\x95\xda\xe8\x35\x7d\x75\x9b\xb2\x28\x73\x2a\x48\x59\x1e\x49\xdd\xcf\xbc\xa0\x32\xd7\xf5\x47\x32\x5d\x64\x53\x38\xa3\xc3\x98\xc2\x62\x17\x45\x86\xa5\x56\xc3\x20\x56\x36\xd3\x66\x18\xa3\x02\x65\x3e\x0c\x25\xc4\x62\xa0\x76\x02\xd6\xa0\x62\x14\x56\x6a\xe5\x9a\x50\x09\x5b\x19\x72\xdd\xd4\xca\xea\x5c\xa7\xb5\x77\xb4\x0c\x60\x2f\x43\x1d\xbe\xab\xfe\x00\x37\x26\x71\x33\x1d\xad\x7c\xad\x04\x0d\x21\x76\xeb\x5d\xfa\xb6\xfa\x58\x8a\x3c\x37\x72\x17\x99\x22\xdf\xc8\x42\xda\x61\xb4\xc4\x9a\x4c\xdc\x0d\x6a\x1f\x73\x95\x8d\xbb\x12\xfb\x28\xcb\x94\x63\x78\x58\xc8\xf4\xe0\xf1\x20\xf2\xfa\x48\xa6\x59\x40\xd8\x35\x6c\x21\xd3\xd6\x16\xcb\xf4\x02\x2d\xba\x3c\x5f\xa6\xc7\xf6\x88\x66\x94\x1d\x9f\x1f\xea\x2e\x7e\x3b\x20\x9b\xc0\x34\xd7\x62\xfd\xbf\x4a\x7a\x80\x3f\x6c\x02\xcb\x8c\x20\x23\x4c\xc8\x38\xa4\xcd\x24\xc3\xca\x01\x8e\x5a\xb9\x6e\x27\x86\x59\xf3\xdb\x7a\xf0\x49\x41\xdf\x38\xc8\x03\xa9\x44\x5e\x25\x94\x78\x82\x10\x15\xc3\x43\xd0\x45\xa7\xbf\x11\xec\xb9\x41\x3a\x96\x50\x0b\x7a\x42\x2f\xba\x05\x85\xaa\xdb\xfe\xf7\xb2\x7d\xe6\xd1\xf8\x6c\x84\x6e\x91\xb3\xd1\xa4\x70\x76\x02\x7c\x69\x68\x23\x75\xd5\xf9\x73\x28\x8f\x77\x1b\xb3\xf1\x1c\x51\x19\x43\xca\x76\x29\x4d\xd3\x8f\x46\x07\x20\xac\xa8\x2c\x88\x2d\x16\x65\x58\xce\x94\x14\x19\xb4\x7d\x3d\x3b\xcc\x28\x43\x41\x66\x9d\xbb\xd6\x10\x81\xd1\xda\xc2\xb3\xb4\x19\xe4\x84\x1b\x62\x78\x32\xba\x68\xe8\xb8\x27\xb7\x7a\xaf\xe3\xcd\xdf\xef\x5a\xdb\x11\x57\x83\x96\x37\xfc\x23\x23\x63\xb7\xfc\x4e\xba\xd0\x8a\x49\x71\xc5\x90\xa0\xc5\xb1\xdc\x1e\xe1\x6f\x80\x7f\x85\x87\x0e\xab\xdc\xca\xee\x9b\xe1\x28\x84\x56\xba\x65\x55\x3a\x21\x86\xe7\x4c\x83\x40\xd5\x17\x0e\x14\x6d\x6d\x78\x88\x5b\x4f\xb5\x5e\xaf\x89\xca\xc1\x45\x1e\x4a\xdd\x67\xed\x19\xf7\x6a\xd6\xb3\x0d\xaf\x67\x40\xc8\x32\x55\xd8\xdd\xc7\x8f\xb1\x8f\x3d\x08\xdd\xb3\x31\xc5\x1c\x95\xe8\xbe\x4d\x5a\xd9\xb7\xa2\xf9\x40\x3a\x0c\xbc\xf7\xfc\x7c\xab\xc8\xd4\x2d\x4b\x4a\xb6\x79\x22\xa6\xf5\xac\x41\x1f\xee\x24\x7d\x8a\xfd\x13\xb2\x0b\x46\xce\x0e\x83\x2b\x34\x0a\xf3\xa0\x3d\xbe\xb7\xf7\x3a\x25\xbb\xdc\xee\xd0\x04\x8f\x46\x4f\xe6\x7d\x1f\xba\x41\x78\xeb\xa0\x3b\xd2\x6f\x34\xce\x58\x64\x54\x60\xeb\xea\xaf\x73\x18\x7b\xa3\xd1\x6b\xf4\x2f\x00\x00\xff\xff\x28\x4d\x93\xb3\x8d\x08\x00\x00")
+var _schemaGraphql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x95\x58\x6d\x6f\x1b\x37\x0c\xfe\xee\x5f\xa1\xc0\x5f\x32\xc0\x30\x92\xae\x29\x06\x7f\x4b\x52\x63\x09\xba\xbc\x6c\x71\x37\x0c\x41\x31\xc8\x77\xb2\xad\xe5\x4e\xba\x49\x3a\xbf\xa0\xd8\x7f\x1f\x25\x4a\x3a\xe9\x7c\x49\xd7\x7e\x69\x4e\x22\x29\xea\x21\xf9\x90\xf2\x98\x2c\xa9\x66\x17\x3f\x11\xa9\xc8\x86\xed\x89\x36\x8a\x8b\x35\xa1\x65\xa9\x98\xd6\x23\x5d\xd0\x8a\x2a\x72\xe9\x3f\xc7\xa9\x8c\x5c\x91\x0d\xd5\x9b\x77\x17\x1f\x82\xd8\x8d\xfd\xbb\x2f\xd3\xb4\xcb\x8a\x17\xe4\x85\x1d\x82\xd8\x63\xbb\xfc\x04\x5f\xe1\xf3\x33\x17\xe6\xc7\x77\xa0\xd7\xc2\x1f\x1f\xde\x13\x26\x0a\x59\xb2\x92\x50\xed\xad\xa4\x82\x1f\xde\x8f\x46\x4c\xb4\x35\x59\xec\x17\x87\x86\x91\xaf\x23\x02\xff\x6e\xef\x7f\x7f\xf8\x34\xff\xeb\x7e\xfe\x90\x7e\xfe\x71\xf9\x74\xe7\xbe\x3f\xce\x1f\x7f\x79\xf8\x33\x6e\xfb\xcf\xb8\x3d\xbf\x7d\x3c\xbf\xb8\x18\xfd\x3b\x1a\xc1\x01\x4c\xad\x68\xc1\xc8\x23\x3d\x54\x92\x96\xde\xbe\x75\x88\xcc\xc8\x93\x73\xe7\xc4\x4a\x1a\x7b\xf8\xad\xd8\xca\x17\x76\x6d\x37\x79\xdd\x54\xac\x66\xc2\xe8\x01\xd5\x63\xcd\x8f\xac\xa9\xe4\xe1\x7b\x34\xed\xca\xb6\xb6\x77\xce\xd7\x04\xad\xfb\x52\x4c\x69\x2e\x45\xbe\x48\x5b\xb3\x91\x2a\x5f\x63\x35\xe5\x55\xbe\x54\x32\x5d\x64\xde\x8e\x09\x33\x1b\xa6\x18\x20\x6e\x14\x15\x9a\x16\x06\x6c\x93\x9d\xa2\x4d\x03\x31\xe2\x82\x50\xe1\x01\x4c\x05\xf0\x96\xb8\xfe\xf6\x2d\xc7\x44\x55\x4d\x8c\xf9\xd0\x61\xaf\x80\xd1\xcf\x33\xd0\xb4\xea\x21\x75\x27\x44\xb4\x55\x45\x56\x90\xd7\x85\x14\x60\xae\x30\xa4\x50\x8c\x46\x8b\x46\x06\x7b\xde\xdc\x96\x56\x2d\xb3\x37\xda\x31\x8e\x40\xda\x85\xfc\x50\x2e\x9a\xd6\xf4\x02\x20\x45\x01\x52\x98\x9c\xb8\x54\x6c\x28\x17\xb7\x65\xb7\xe8\x80\x4c\xf1\x03\xb7\xdb\xc2\xb4\x8a\x59\xcf\xc1\x3d\x59\xc9\xf5\x01\x41\x5b\x24\x62\x5f\xf3\x80\x62\xa5\xe0\x19\xb6\xf4\x66\xae\xe2\x8e\xdc\x08\x22\x66\x8f\xe9\x82\xa5\x82\x6b\x6b\xaa\x1f\x15\xef\x4b\xc2\xea\x2f\xbc\xe6\x26\x5f\x6d\xe8\x81\x41\xca\xf8\xe2\x8f\x6b\x36\x78\xb3\x10\x45\x5c\xd5\x7c\xad\x67\xe4\xf9\x89\xaf\x4f\xbe\x9c\x8c\xd0\x3f\xc6\xd7\x9b\xc4\xa0\x07\x79\x61\xa3\xb4\x67\x45\x6b\x18\x01\xab\x6d\x65\x20\x81\x20\xee\x5b\x97\x1a\x2e\x8c\x5c\xa7\x50\xf9\x38\xf2\x15\xe1\x86\xc0\x96\x90\x26\x18\x28\xa7\x98\xc6\x4e\x77\x46\xe6\xb8\x7a\x2f\x0d\x5f\x1d\x62\xa5\x81\x4f\x1e\x46\x70\xf2\x23\x35\xd4\xfa\x89\xe1\xfb\xe2\xaf\xe4\xe8\xc8\xfa\x8f\xc4\x14\xd6\xef\x3a\xe7\x5d\xfc\xae\x2a\x59\xbc\xbc\x15\x39\x14\xf8\x9a\xdc\x74\xc3\x68\xc9\x54\xbc\xd6\xd2\x0a\x4c\x3d\x3c\x76\x07\x02\xe8\xfe\xcf\xe0\x49\x2e\xaf\x13\x3d\xc8\xbd\xa2\x6a\xcb\x70\xeb\x54\x0a\x5c\x4f\xb2\x26\x86\xe0\xff\xa0\x9d\x9e\x05\xa9\xdf\x77\x33\x60\xfb\x9c\x81\xdb\x3b\xa1\x50\x52\x6b\x4c\x7a\x52\x43\xa6\xd0\x35\x1b\xba\xb2\x13\xbb\xb6\x52\x77\x7a\x3d\x23\xd7\xe9\x27\x22\x8c\x60\xd8\x28\x9b\x0c\x3c\x8a\x66\x10\x65\x2f\x94\xc2\xec\x0b\x24\x1e\x8a\x9a\xd3\xe1\xe2\x49\xc3\x03\x35\x34\xa8\x94\x16\x57\x22\xdf\x28\xb6\xe5\xb2\x0d\x01\xb1\x52\x28\x6f\x37\x6e\x86\x75\x8a\x56\x29\xc0\x30\xa8\xb8\xaa\x98\x7e\xab\x42\x0c\x07\x1c\x0d\xad\x9b\x34\xfe\x6b\x26\x98\xa2\x31\xed\xa3\xcc\xa0\x85\x9a\xa9\x97\xca\xe6\x12\x83\xc8\x4b\xa8\x99\x1d\x07\x6e\xac\x18\xdd\x32\x4d\x56\x4a\xd6\xce\x9c\x8e\xc6\x8d\x3c\x8a\x97\xfb\xf3\x37\xd0\x1d\xb8\x55\x96\x37\xce\xfe\x40\xc0\xcd\x5e\xbf\xa2\x0e\x8c\xac\x99\xd0\x80\x64\x09\x15\x39\x98\x2c\x41\x02\x4b\x36\x10\x69\x7a\x43\xc8\x66\x1e\xd8\xde\x9a\x00\x15\xe9\xad\x0a\xe8\x16\x9a\xec\x36\x92\x14\xd0\x9e\x02\x70\x44\xb0\xbd\x49\x0f\xb1\xdf\x57\x52\xbe\xbc\x30\xd6\x64\x4c\x97\xbb\x7a\x6c\x35\x5a\x3c\xc2\x2c\x5a\xcb\xf9\x24\x31\x08\x34\x24\x68\x20\x90\xef\xb3\x3e\xc4\x60\x81\xe7\xae\x60\x44\x82\x0e\xe0\xeb\x02\x88\x29\x6f\x49\x52\xac\xf3\x85\x7e\xf6\x61\xaf\xcf\xb9\xc2\x95\xde\x71\x57\x4f\xa9\xc0\x9f\x67\xf6\x37\xbd\x7e\x34\x26\xe7\x90\x84\xa0\x4a\x74\x5b\x14\xae\x21\x9f\xf9\x85\x15\x8c\x1d\xac\xc4\x1b\x19\xb8\xe7\x51\x2f\xba\x86\xe0\xb7\x35\x2b\x73\x8f\x61\xe3\xc9\xb0\xe6\xb3\xee\x6f\x98\xfd\xad\x28\xd9\x3e\x37\x13\xa3\x97\xb4\x7e\x18\x30\x96\x87\x37\x3a\x8c\x90\x9d\x02\xc8\x78\x9d\xc0\x5e\xee\xe3\xda\xef\xc7\x64\xf1\xcd\xd7\x62\x01\x71\x41\x50\xe6\x96\x35\xd3\xe0\x24\xcb\x71\xb8\x43\x3b\xde\x4a\xaf\xcb\x8e\xc9\xdf\x1a\xf8\x2c\x4c\x45\x0e\xa5\xae\x3b\x3a\x52\xee\xe0\xd3\xbd\x11\x45\xcf\xb7\xf5\x8c\x40\x5e\x43\xb5\x0b\x1f\xd8\x78\xad\xd2\x0d\x9e\x08\x44\x32\x84\xc2\x94\x04\x49\xc9\xb7\x1d\x62\xe8\x78\xb8\xae\xf7\x9a\xbe\xe2\xad\x85\x1a\x2d\xe3\xa8\x1c\x3c\x0d\xc6\x92\x61\xac\x77\xcc\xd4\x8f\x9d\xc1\x93\x59\xe2\xd5\xab\x63\x9e\x36\x52\xd9\x26\x83\x13\x9b\x7d\x87\x74\x22\xf0\xce\x48\x03\x6a\xe0\xd0\x56\x84\x64\x73\x6a\xa7\x20\x12\x21\xfb\x21\x0e\x81\xf9\xb0\x4b\x8b\x02\xf4\x3c\x0a\x73\xb3\xb9\xc4\xef\xd8\x76\xde\x9c\x3d\x73\xac\xbe\x35\x2c\xc2\x4d\xb1\x78\x52\xc1\xa1\x79\xf7\xe1\xfe\x67\x78\xad\x61\x99\x27\x63\xaa\x5f\xfa\xf6\x74\x7c\x04\x9b\xae\xa4\xc9\x90\xb1\x0b\xc7\xd0\x74\x79\x9c\xf5\x6c\x8f\x45\x7f\x10\x1d\x9e\x56\x7b\x6c\xd3\x25\x6f\xda\x21\xde\xe0\xb8\xb1\xb5\x6b\xf9\xe6\x15\x6a\x22\x6e\xf6\xa8\x1b\xc8\x7a\x3f\x46\xef\x2f\x8d\x09\x63\xc2\xd0\xf1\xe3\xc8\x47\xce\x91\xca\x51\xd4\x79\xba\xb4\x6a\x2b\xe4\x17\x37\x45\xa7\xba\x4c\x29\x4c\xd6\x84\x3d\xdd\xa9\x77\xe8\xc2\x62\x1f\x79\x71\x96\x0e\xf4\x27\x29\xe9\x3d\xa3\x8b\x29\x4f\x78\xf5\x6c\xb8\x81\xc7\xee\x12\xc7\x20\x87\x00\xb7\x2f\x63\x98\xe1\x10\x0e\x1b\xdd\xb4\x17\x87\xe6\xd9\x5a\xfe\x7f\xf6\xde\x7d\x39\x49\x38\xda\xd2\xc5\xb3\x83\x3b\x2e\x9f\x26\x21\x84\xa0\xc7\x3b\x44\xb7\x7e\x6d\x99\x0a\x4c\xbf\x66\xc6\xcd\xb9\x57\x87\x1b\x07\xea\x69\x0f\x5b\x30\xe0\xf6\xfb\xc2\x70\x44\xef\x9c\x63\x31\x14\x3a\xb2\x97\x36\x15\xd7\x0d\xfd\x04\x83\x92\x16\x4e\x05\x0c\x43\x0d\xa9\xa5\x36\xa4\xb2\x0f\x99\x20\x47\x95\x6d\x66\xd0\x6d\x05\x2b\x27\x7e\x0b\x78\xe9\xfc\xcc\xf2\x5f\xc9\x56\x34\x8c\xc4\xe7\x67\x67\xc1\x42\xe6\x92\x3e\x75\xe6\xa3\x3b\xde\x48\xf8\x06\xef\x9e\x9d\x5c\x40\x13\xf4\xae\x71\xe2\xbb\x19\xc8\x39\xd8\x5d\xf4\xe1\x5e\xf4\x5e\xba\xf6\x64\xac\xe8\x53\x4b\x21\x1d\xd7\x5a\xc8\x70\x23\x80\x41\xb5\x66\xee\x3e\x40\xa6\x96\xc2\xa5\x7f\xcb\x82\x89\xcb\xaa\x92\x3b\x34\x62\x85\x62\x29\x4f\x1c\x74\x9d\xd1\x89\x7b\x07\x27\x47\x64\x1d\x97\x19\xd7\xb3\x74\xcf\xe5\xfc\xa5\x95\x49\xbe\x91\x16\xc7\x6f\x88\x00\x98\xef\x05\xc7\xf7\x0d\x3b\x41\xf2\xc9\xf3\x54\x2e\x38\x21\xc3\x8c\x1e\x1c\x8b\xec\xed\xf5\x3a\xc1\x6e\xab\x73\x26\xa5\xb8\x81\x5b\xe4\xcf\x16\xaf\xe4\x4b\x26\xd6\x4e\x18\xa7\x5c\xc3\x77\xc3\x01\xc7\x96\x4b\x93\x01\x43\xe4\xef\x9a\x70\xd7\x74\x4a\x38\x1e\xab\x86\x98\xcc\x1d\x33\x4b\x87\x8c\x8e\x51\x9e\xda\xa5\x2e\x14\x6f\x92\x9f\x13\x62\x11\x69\x78\x0c\x94\x76\xf2\x87\x69\x6c\x0d\x14\xe3\x8a\x4a\xc8\x1d\xf1\x99\x28\xd8\xce\xe5\xb6\x2f\xd5\x90\x75\xe9\x5b\x9d\x25\x2d\x7e\xc5\x2b\x03\xcd\xd3\xcf\x58\x2c\xdc\x9e\xd6\xcc\xb6\xe2\x35\x74\x7d\x31\xc5\x17\x69\xb7\x51\x53\x53\x00\x25\x59\x79\x6f\x7d\xc5\x95\x36\x48\x91\x48\xf2\x82\xc9\x6d\x6d\xb3\x7b\x47\x75\x8d\xba\xd3\x6c\x84\x72\x37\x3e\x2d\xfa\x83\xd9\x04\x65\xef\x93\xdf\xc7\x92\x8c\xf2\x30\xe1\x99\x26\x7f\xd1\x10\xe9\x3a\xac\xab\xac\x1e\x46\x13\x2b\x2b\xb0\x9b\xa6\xc8\x16\xd2\xfe\xbe\x05\xed\xcc\x87\x0d\x8e\x81\x8b\xc0\x08\xfb\x7a\xb1\xbb\x18\x69\xb8\x7d\x4d\x7d\x60\xfe\xb1\x34\x3b\x43\xb6\xc5\x46\x91\x1c\x31\xcb\x42\x09\xba\xff\x01\x41\x46\x48\x40\xba\x15\x00\x00")
 
 func schemaGraphqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -111,7 +111,7 @@ func schemaGraphql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "schema.graphql", size: 2189, mode: os.FileMode(438), modTime: time.Unix(1598341445, 0)}
+	info := bindataFileInfo{name: "schema.graphql", size: 5562, mode: os.FileMode(438), modTime: time.Unix(1792278109, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
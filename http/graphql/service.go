@@ -19,20 +19,29 @@
 package graphql
 
 import (
+	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 
+	common2 "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/payload"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/events/message"
 	"github.com/ontio/ontology/http/base/actor"
 	comm "github.com/ontio/ontology/http/base/common"
 	"github.com/ontio/ontology/http/graphql/schema"
+	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 	"golang.org/x/net/netutil"
 )
@@ -83,6 +92,15 @@ type deployCodePayload struct {
 	Desc    string
 }
 
+type eip155CodePayload struct {
+	Code    string
+	To      *string
+	Value   string
+	Input   string
+	Nonce   Uint64
+	ChainId Uint64
+}
+
 type TxPayload struct {
 	pl interface{}
 }
@@ -97,12 +115,19 @@ func (self *TxPayload) ToDeployCode() (*deployCodePayload, bool) {
 	return pl, ok
 }
 
+func (self *TxPayload) ToEIP155Code() (*eip155CodePayload, bool) {
+	pl, ok := self.pl.(*eip155CodePayload)
+	return pl, ok
+}
+
 func (self *TxPayload) Code() string {
 	switch pd := self.pl.(type) {
 	case *invokeCodePayload:
 		return pd.Code
 	case *deployCodePayload:
 		return pd.Code
+	case *eip155CodePayload:
+		return pd.Code
 	default:
 		panic("unreachable")
 	}
@@ -113,25 +138,46 @@ func NewTxPayload(pl types.Payload) *TxPayload {
 	case *payload.InvokeCode:
 		return &TxPayload{pl: &invokeCodePayload{Code: common.ToHexString(val.Code)}}
 	case *payload.DeployCode:
-		vmty := "Neo"
-		if val.VmType() == payload.WASMVM_TYPE {
-			vmty = "Wasm"
-		}
-		dp := &deployCodePayload{
-			Code:    common.ToHexString(val.GetRawCode()),
-			VmType:  vmty,
-			Name:    val.Name,
-			Version: val.Version,
-			Author:  val.Author,
-			Email:   val.Email,
-			Desc:    val.Description,
-		}
-		return &TxPayload{pl: dp}
+		return &TxPayload{pl: NewDeployCode(val)}
+	case *payload.EIP155Code:
+		return &TxPayload{pl: NewEIP155Code(val)}
 	default:
 		panic("unreachable")
 	}
 }
 
+func NewDeployCode(val *payload.DeployCode) *deployCodePayload {
+	vmty := "Neo"
+	if val.VmType() == payload.WASMVM_TYPE {
+		vmty = "Wasm"
+	}
+	return &deployCodePayload{
+		Code:    common.ToHexString(val.GetRawCode()),
+		VmType:  vmty,
+		Name:    val.Name,
+		Version: val.Version,
+		Author:  val.Author,
+		Email:   val.Email,
+		Desc:    val.Description,
+	}
+}
+
+func NewEIP155Code(val *payload.EIP155Code) *eip155CodePayload {
+	code, _ := rlp.EncodeToBytes(val.EIPTx)
+	pl := &eip155CodePayload{
+		Code:    common.ToHexString(code),
+		Value:   val.EIPTx.Value().String(),
+		Input:   hexutil.Encode(val.EIPTx.Data()),
+		Nonce:   Uint64(val.EIPTx.Nonce()),
+		ChainId: Uint64(val.EIPTx.ChainId().Uint64()),
+	}
+	if to := val.EIPTx.To(); to != nil {
+		addr := to.Hex()
+		pl.To = &addr
+	}
+	return pl
+}
+
 func NewTransaction(tx *types.Transaction, height uint32) *transaction {
 	ty := convTxType(tx)
 	var sigs []*Sig
@@ -229,6 +275,46 @@ func (self *resolver) GetBlockHash(args struct{ Height Uint32 }) H256 {
 	return H256(actor.GetBlockHashFromStore(uint32(args.Height)))
 }
 
+const (
+	defaultBlockPageSize = 10
+	maxBlockPageSize     = 100
+)
+
+func (self *resolver) GetBlocks(args struct {
+	Start Uint32
+	Limit *Uint32
+}) ([]*block, error) {
+	limit := uint32(defaultBlockPageSize)
+	if args.Limit != nil {
+		limit = uint32(*args.Limit)
+	}
+	if limit > maxBlockPageSize {
+		limit = maxBlockPageSize
+	}
+	var blocks []*block
+	curr := actor.GetCurrentBlockHeight()
+	for height := uint32(args.Start); height <= curr && uint32(len(blocks)) < limit; height++ {
+		b, err := actor.GetBlockByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, NewBlock(b))
+	}
+	return blocks, nil
+}
+
+func (self *resolver) GetCurrentHeight() Uint32 {
+	return Uint32(actor.GetCurrentBlockHeight())
+}
+
+func (self *block) Events() ([]*executeNotify, error) {
+	return getEventsByHeight(uint32(self.Header.Height))
+}
+
+func (self *block) CrossChainMsg() (*crossChainMsg, error) {
+	return getCrossChainMsg(uint32(self.Header.Height))
+}
+
 type balance struct {
 	Ont    Uint64
 	Ong    Uint64
@@ -241,9 +327,12 @@ const INVOKE_NEO TxType = "INVOKE_NEO"
 const INVOKE_WASM TxType = "INVOKE_WASM"
 const DEPLOY_NEO TxType = "DEPLOY_NEO"
 const DEPLOY_WASM TxType = "DEPLOY_WASM"
+const EIP155 TxType = "EIP155"
 
 func convTxType(tx *types.Transaction) TxType {
 	switch pl := tx.Payload.(type) {
+	case *payload.EIP155Code:
+		return EIP155
 	case *payload.InvokeCode:
 		if tx.TxType == types.InvokeNeo {
 			return INVOKE_NEO
@@ -292,6 +381,10 @@ func (self *resolver) GetTx(args struct{ Hash H256 }) (*transaction, error) {
 	return NewTransaction(tx, height), nil
 }
 
+func (self *transaction) Events() (*executeNotify, error) {
+	return getEvents(common.Uint256(self.Hash))
+}
+
 func (self *resolver) GetBalance(args struct{ Addr Addr }) (*balance, error) {
 	balances, height, err := comm.GetNativeTokenBalance(0,
 		[]common.Address{utils.OntContractAddress, utils.OngContractAddress}, args.Addr.Address, true)
@@ -306,6 +399,280 @@ func (self *resolver) GetBalance(args struct{ Addr Addr }) (*balance, error) {
 	}, nil
 }
 
+func (self *resolver) GetAllowance(args struct {
+	Asset string
+	From  Addr
+	To    Addr
+}) (Uint64, error) {
+	var contract common.Address
+	switch strings.ToLower(args.Asset) {
+	case "ont":
+		contract = utils.OntContractAddress
+	case "ong":
+		contract = utils.OngContractAddress
+	default:
+		return 0, fmt.Errorf("unsupported asset: %s", args.Asset)
+	}
+	allowance, err := comm.GetContractAllowance(0, contract, args.From.Address, args.To.Address)
+	if err != nil {
+		return 0, err
+	}
+	return Uint64(allowance), nil
+}
+
+type executeNotify struct {
+	TxHash          H256
+	State           Uint32
+	GasConsumed     Uint64
+	GasStepUsed     Uint64
+	TxIndex         Uint32
+	CreatedContract *Addr
+	Notify          []*notifyEvent
+}
+
+type notifyEvent struct {
+	ContractAddress Addr
+	States          string
+	IsEvm           bool
+
+	rawStates interface{}
+}
+
+func NewExecuteNotify(notify *event.ExecuteNotify) *executeNotify {
+	var events []*notifyEvent
+	for _, n := range notify.Notify {
+		states, _ := json.Marshal(n.States)
+		events = append(events, &notifyEvent{
+			ContractAddress: Addr{n.ContractAddress},
+			States:          string(states),
+			IsEvm:           n.IsEvm,
+			rawStates:       n.States,
+		})
+	}
+	en := &executeNotify{
+		TxHash:      H256(notify.TxHash),
+		State:       Uint32(notify.State),
+		GasConsumed: Uint64(notify.GasConsumed),
+		GasStepUsed: Uint64(notify.GasStepUsed),
+		TxIndex:     Uint32(notify.TxIndex),
+		Notify:      events,
+	}
+	if notify.CreatedContract != common.ADDRESS_EMPTY {
+		en.CreatedContract = &Addr{notify.CreatedContract}
+	}
+	return en
+}
+
+func getEvents(hash common.Uint256) (*executeNotify, error) {
+	notify, err := actor.GetEventNotifyByTxHash(hash)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if notify == nil {
+		return nil, nil
+	}
+	return NewExecuteNotify(notify), nil
+}
+
+func getEventsByHeight(height uint32) ([]*executeNotify, error) {
+	notifies, err := actor.GetEventNotifyByHeight(height)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var events []*executeNotify
+	for _, notify := range notifies {
+		events = append(events, NewExecuteNotify(notify))
+	}
+	return events, nil
+}
+
+func (self *resolver) GetEvents(args struct{ Hash H256 }) (*executeNotify, error) {
+	return getEvents(common.Uint256(args.Hash))
+}
+
+func (self *resolver) GetEventsByHeight(args struct{ Height Uint32 }) ([]*executeNotify, error) {
+	return getEventsByHeight(uint32(args.Height))
+}
+
+type contract struct {
+	Address    Addr
+	DeployCode *deployCodePayload
+}
+
+func (self *contract) Storage(args struct{ Key string }) (*string, error) {
+	return getStorage(self.Address.Address, args.Key)
+}
+
+func getStorage(addr common.Address, key string) (*string, error) {
+	k, err := common.HexToBytes(strings.TrimPrefix(key, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	value, err := actor.GetStorageItem(addr, k)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	v := common.ToHexString(value)
+	return &v, nil
+}
+
+func (self *resolver) GetContract(args struct{ Addr Addr }) (*contract, error) {
+	dc, err := actor.GetContractStateFromStore(args.Addr.Address)
+	if err != nil && err != scom.ErrNotFound {
+		return nil, err
+	}
+	c := &contract{Address: args.Addr}
+	if dc != nil {
+		c.DeployCode = NewDeployCode(dc)
+	}
+	return c, nil
+}
+
+func (self *resolver) GetStorage(args struct {
+	Addr Addr
+	Key  string
+}) (*string, error) {
+	return getStorage(args.Addr.Address, args.Key)
+}
+
+type ethAccount struct {
+	addr     common2.Address
+	Address  string
+	Nonce    Uint64
+	CodeHash string
+	Code     string
+	Balance  string
+}
+
+func (self *ethAccount) Storage(args struct{ Slot string }) (string, error) {
+	value, err := actor.GetEthStorage(self.addr, common2.HexToHash(args.Slot))
+	if err != nil && err != scom.ErrNotFound {
+		return "", err
+	}
+	return common2.BytesToHash(value).Hex(), nil
+}
+
+func (self *resolver) GetEthAccount(args struct{ Addr string }) (*ethAccount, error) {
+	if !common2.IsHexAddress(args.Addr) {
+		return nil, fmt.Errorf("invalid eth address: %s", args.Addr)
+	}
+	addr := common2.HexToAddress(args.Addr)
+	account, err := actor.GetEthAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	code, err := actor.GetEthCode(account.CodeHash)
+	if err != nil && err != scom.ErrNotFound {
+		return nil, err
+	}
+	balance, err := actor.GetOngBalanceAt(common.Address(addr), actor.GetCurrentBlockHeight())
+	if err != nil {
+		return nil, err
+	}
+	return &ethAccount{
+		addr:     addr,
+		Address:  addr.Hex(),
+		Nonce:    Uint64(account.Nonce),
+		CodeHash: account.CodeHash.Hex(),
+		Code:     hexutil.Encode(code),
+		Balance:  balance.String(),
+	}, nil
+}
+
+type crossChainMsg struct {
+	Hash       H256
+	Version    Uint32
+	Height     Uint32
+	StatesRoot H256
+	SigData    []string
+}
+
+func getCrossChainMsg(height uint32) (*crossChainMsg, error) {
+	msg, err := actor.GetCrossChainMsg(height)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if msg == nil {
+		return nil, nil
+	}
+	var sigData []string
+	for _, sig := range msg.SigData {
+		sigData = append(sigData, common.ToHexString(sig))
+	}
+	return &crossChainMsg{
+		Hash:       H256(msg.Hash()),
+		Version:    Uint32(msg.Version),
+		Height:     Uint32(msg.Height),
+		StatesRoot: H256(msg.StatesRoot),
+		SigData:    sigData,
+	}, nil
+}
+
+func (self *resolver) GetCrossChainMsg(args struct{ Height Uint32 }) (*crossChainMsg, error) {
+	return getCrossChainMsg(uint32(args.Height))
+}
+
+type txAttr struct {
+	Height  Uint32
+	Type    Uint32
+	ErrCode Uint32
+}
+
+type mempoolTx struct {
+	Tx    *transaction
+	State []*txAttr
+}
+
+type mempool struct{}
+
+func (self *mempool) Count() []Uint32 {
+	var count []Uint32
+	for _, c := range actor.GetTxnCount() {
+		count = append(count, Uint32(c))
+	}
+	return count
+}
+
+func (self *mempool) TxHashes() []H256 {
+	var hashes []H256
+	for _, hash := range actor.GetTxnHashList() {
+		hashes = append(hashes, H256(hash))
+	}
+	return hashes
+}
+
+func (self *mempool) Tx(args struct{ Hash H256 }) *mempoolTx {
+	entry, err := actor.GetTxFromPool(common.Uint256(args.Hash))
+	if err != nil {
+		return nil
+	}
+	var attrs []*txAttr
+	for _, attr := range entry.Attrs {
+		attrs = append(attrs, &txAttr{
+			Height:  Uint32(attr.Height),
+			Type:    Uint32(attr.Type),
+			ErrCode: Uint32(attr.ErrCode),
+		})
+	}
+	return &mempoolTx{Tx: NewTransaction(entry.Tx, 0), State: attrs}
+}
+
+func (self *resolver) GetMempool() *mempool {
+	return &mempool{}
+}
+
 func StartServer(cfg *config.GraphQLConfig) {
 	if !cfg.EnableGraphQL || cfg.GraphQLPort == 0 {
 		return
@@ -317,6 +684,8 @@ func StartServer(cfg *config.GraphQLConfig) {
 	}))
 
 	serverMut.Handle("/query", &relay.Handler{Schema: ontSchema})
+	serverMut.Handle("/subscriptions", newWsHandler(ontSchema, config.DefConfig.Rpc.EthWsOrigins))
+	actor.SubscribeEvent(message.TOPIC_SAVE_BLOCK_COMPLETE, hub.onBlock)
 
 	server := &http.Server{Handler: serverMut}
 	listener, err := net.Listen("tcp", ":"+strconv.Itoa(int(cfg.GraphQLPort)))
@@ -0,0 +1,178 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/http/base/actor"
+)
+
+// the number of blocks buffered for a subscription, a subscription is closed if its buffer is full
+const subscriberBufferSize = 64
+
+// blockHub dispatches the blocks saved to ledger to subscriptions
+type blockHub struct {
+	lock        sync.Mutex
+	subscribers map[chan *types.Block]struct{}
+}
+
+var hub = &blockHub{subscribers: make(map[chan *types.Block]struct{})}
+
+func (self *blockHub) onBlock(v interface{}) {
+	block, ok := v.(types.Block)
+	if !ok {
+		return
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	for ch := range self.subscribers {
+		select {
+		case ch <- &block:
+		default:
+			log.Warnf("graphql subscriber is too slow at block %d, close it", block.Header.Height)
+			delete(self.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// subscribe return the channel of new blocks, which is closed when ctx is done
+func (self *blockHub) subscribe(ctx context.Context) <-chan *types.Block {
+	ch := make(chan *types.Block, subscriberBufferSize)
+	self.lock.Lock()
+	self.subscribers[ch] = struct{}{}
+	self.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		self.lock.Lock()
+		defer self.lock.Unlock()
+		if _, ok := self.subscribers[ch]; ok {
+			delete(self.subscribers, ch)
+			close(ch)
+		}
+	}()
+	return ch
+}
+
+type contractEvent struct {
+	TxHash H256
+	Height Uint32
+	Event  *notifyEvent
+}
+
+func (self *resolver) NewBlock(ctx context.Context) <-chan *block {
+	blocks := hub.subscribe(ctx)
+	out := make(chan *block)
+	go func() {
+		defer close(out)
+		for b := range blocks {
+			select {
+			case out <- NewBlock(b):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func (self *resolver) ContractEvent(ctx context.Context, args struct {
+	Contract  *Addr
+	EventName *string
+}) <-chan *contractEvent {
+	blocks := hub.subscribe(ctx)
+	out := make(chan *contractEvent)
+	go func() {
+		defer close(out)
+		for b := range blocks {
+			notifies, err := actor.GetEventNotifyByHeight(b.Header.Height)
+			if err != nil {
+				log.Errorf("graphql subscription get events of block %d: %s", b.Header.Height, err)
+				continue
+			}
+			for _, notify := range notifies {
+				for _, n := range NewExecuteNotify(notify).Notify {
+					if args.Contract != nil && n.ContractAddress.Address != args.Contract.Address {
+						continue
+					}
+					if args.EventName != nil && !matchEventName(n.rawStates, *args.EventName) {
+						continue
+					}
+					evt := &contractEvent{TxHash: H256(notify.TxHash), Height: Uint32(b.Header.Height), Event: n}
+					select {
+					case out <- evt:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// matchEventName check whether the first state of a neovm or wasm event is the event name,
+// which may be encoded as a hex string
+func matchEventName(states interface{}, name string) bool {
+	list, ok := states.([]interface{})
+	if !ok || len(list) == 0 {
+		return false
+	}
+	first, ok := list[0].(string)
+	if !ok {
+		return false
+	}
+	return first == name || first == hex.EncodeToString([]byte(name))
+}
+
+func (self *resolver) TxConfirmed(ctx context.Context, args struct{ Hash H256 }) <-chan *transaction {
+	hash := common.Uint256(args.Hash)
+	subCtx, cancel := context.WithCancel(ctx)
+	// subscribe before checking the ledger, so the block saved in between is not missed
+	blocks := hub.subscribe(subCtx)
+	out := make(chan *transaction, 1)
+	go func() {
+		defer close(out)
+		defer cancel()
+		if height, tx, err := actor.GetTxnWithHeightByTxHash(hash); err == nil && tx != nil {
+			out <- NewTransaction(tx, height)
+			return
+		}
+		for b := range blocks {
+			for _, tx := range b.Transactions {
+				if tx.Hash() != hash {
+					continue
+				}
+				select {
+				case out <- NewTransaction(tx, b.Header.Height):
+				case <-ctx.Done():
+				}
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,257 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/ontio/ontology/common/log"
+	comm "github.com/ontio/ontology/http/base/common"
+)
+
+// message types of the graphql-ws protocol, see
+// https://github.com/apollographql/subscriptions-transport-ws/blob/master/PROTOCOL.md
+const (
+	gqlConnectionInit      = "connection_init"
+	gqlConnectionAck       = "connection_ack"
+	gqlConnectionKeepAlive = "ka"
+	gqlConnectionTerminate = "connection_terminate"
+	gqlStart               = "start"
+	gqlStop                = "stop"
+	gqlData                = "data"
+	gqlError               = "error"
+	gqlComplete            = "complete"
+)
+
+const (
+	wsReadLimit          = 1024 * 1024
+	wsKeepAliveInterval  = 10 * time.Second
+	wsWriteTimeout       = 10 * time.Second
+	maxOperationsPerConn = 100
+)
+
+type operationMessage struct {
+	Id      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type startPayload struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type errorPayload struct {
+	Message string `json:"message"`
+}
+
+// wsHandler serves graphql queries and subscriptions over websocket with the graphql-ws protocol
+type wsHandler struct {
+	schema   *graphql.Schema
+	upgrader websocket.Upgrader
+}
+
+// newWsHandler accepts the connections from the origins allowed as the eth websocket server does
+func newWsHandler(schema *graphql.Schema, origins []string) *wsHandler {
+	return &wsHandler{
+		schema: schema,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{"graphql-ws"},
+			CheckOrigin:  comm.OriginChecker(origins),
+		},
+	}
+}
+
+func (self *wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := self.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Errorf("graphql websocket upgrade: %s", err)
+		return
+	}
+	conn.SetReadLimit(wsReadLimit)
+	wsConn := &wsConnection{
+		conn:       conn,
+		schema:     self.schema,
+		operations: make(map[string]*wsOperation),
+	}
+	wsConn.serve()
+}
+
+type wsConnection struct {
+	conn      *websocket.Conn
+	schema    *graphql.Schema
+	writeLock sync.Mutex
+
+	lock       sync.Mutex
+	operations map[string]*wsOperation
+}
+
+// wsOperation is a running operation, the id of a stopped operation may be reused by a new one
+type wsOperation struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (self *wsConnection) serve() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		self.conn.Close()
+	}()
+
+	initialized := false
+	for {
+		var msg operationMessage
+		if err := self.conn.ReadJSON(&msg); err != nil {
+			log.Debugf("graphql websocket read: %s", err)
+			return
+		}
+		switch msg.Type {
+		case gqlConnectionInit:
+			if initialized {
+				continue
+			}
+			initialized = true
+			self.write(&operationMessage{Type: gqlConnectionAck})
+			go self.keepAlive(ctx)
+		case gqlStart:
+			if !initialized {
+				self.writeError(msg.Id, "connection is not initialized")
+				continue
+			}
+			self.start(ctx, msg)
+		case gqlStop:
+			self.stop(msg.Id)
+		case gqlConnectionTerminate:
+			return
+		default:
+			self.writeError(msg.Id, fmt.Sprintf("unknown message type %s", msg.Type))
+		}
+	}
+}
+
+func (self *wsConnection) start(ctx context.Context, msg operationMessage) {
+	var payload startPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		self.writeError(msg.Id, fmt.Sprintf("invalid start payload: %s", err))
+		return
+	}
+
+	op, errMsg := self.addOperation(ctx, msg.Id)
+	if op == nil {
+		self.writeError(msg.Id, errMsg)
+		return
+	}
+
+	responses, err := self.schema.Subscribe(op.ctx, payload.Query, payload.OperationName, payload.Variables)
+	if err != nil {
+		self.finish(msg.Id, op)
+		self.writeError(msg.Id, err.Error())
+		return
+	}
+	go func() {
+		// drain the responses until closed, so the resolvers are not blocked after the operation stops
+		for resp := range responses {
+			if op.ctx.Err() != nil {
+				continue
+			}
+			data, err := json.Marshal(resp)
+			if err != nil {
+				log.Errorf("graphql websocket marshal response: %s", err)
+				continue
+			}
+			self.write(&operationMessage{Id: msg.Id, Type: gqlData, Payload: data})
+		}
+		if op.ctx.Err() == nil {
+			self.write(&operationMessage{Id: msg.Id, Type: gqlComplete})
+		}
+		self.finish(msg.Id, op)
+	}()
+}
+
+// addOperation registers a new operation of id, returns nil and the reason if it is rejected
+func (self *wsConnection) addOperation(ctx context.Context, id string) (*wsOperation, string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if _, ok := self.operations[id]; ok {
+		return nil, "duplicated operation id"
+	}
+	if len(self.operations) >= maxOperationsPerConn {
+		return nil, "too many operations"
+	}
+	opCtx, cancel := context.WithCancel(ctx)
+	op := &wsOperation{ctx: opCtx, cancel: cancel}
+	self.operations[id] = op
+	return op, ""
+}
+
+// finish cancels op and unregisters it, unless id has been taken by a newer operation
+func (self *wsConnection) finish(id string, op *wsOperation) {
+	op.cancel()
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if self.operations[id] == op {
+		delete(self.operations, id)
+	}
+}
+
+// stop cancels the running operation of id requested by client
+func (self *wsConnection) stop(id string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if op, ok := self.operations[id]; ok {
+		op.cancel()
+		delete(self.operations, id)
+	}
+}
+
+func (self *wsConnection) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(wsKeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			self.write(&operationMessage{Type: gqlConnectionKeepAlive})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (self *wsConnection) writeError(id string, message string) {
+	payload, _ := json.Marshal(&errorPayload{Message: message})
+	self.write(&operationMessage{Id: id, Type: gqlError, Payload: payload})
+}
+
+func (self *wsConnection) write(msg *operationMessage) {
+	self.writeLock.Lock()
+	defer self.writeLock.Unlock()
+	self.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if err := self.conn.WriteJSON(msg); err != nil {
+		log.Debugf("graphql websocket write: %s", err)
+	}
+}
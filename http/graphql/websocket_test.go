@@ -0,0 +1,191 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSchema = `
+schema {
+    query: Query
+    subscription: Subscription
+}
+type Query {
+    hello: String!
+}
+type Subscription {
+    ticks(count: Int!): Int!
+    wait: Int!
+}
+`
+
+type testResolver struct{}
+
+func (*testResolver) Hello() string {
+	return "world"
+}
+
+func (*testResolver) Ticks(ctx context.Context, args struct{ Count int32 }) <-chan int32 {
+	ch := make(chan int32)
+	go func() {
+		defer close(ch)
+		for i := int32(0); i < args.Count; i++ {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (*testResolver) Wait(ctx context.Context) <-chan int32 {
+	ch := make(chan int32)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch
+}
+
+func dialTestServer(t *testing.T, origins []string, header http.Header) (*websocket.Conn, *http.Response, func()) {
+	schema := graphql.MustParseSchema(testSchema, &testResolver{})
+	server := httptest.NewServer(newWsHandler(schema, origins))
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-ws"}}
+	conn, rsp, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), header)
+	if err != nil {
+		server.Close()
+		return nil, rsp, nil
+	}
+	return conn, rsp, func() {
+		conn.Close()
+		server.Close()
+	}
+}
+
+func sendMsg(t *testing.T, conn *websocket.Conn, id, ty, query string) {
+	msg := operationMessage{Id: id, Type: ty}
+	if query != "" {
+		msg.Payload, _ = json.Marshal(&startPayload{Query: query})
+	}
+	assert.Nil(t, conn.WriteJSON(&msg))
+}
+
+// readMsg returns the next message except keep alive
+func readMsg(t *testing.T, conn *websocket.Conn) *operationMessage {
+	for {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		msg := &operationMessage{}
+		if !assert.Nil(t, conn.ReadJSON(msg)) {
+			return msg
+		}
+		if msg.Type != gqlConnectionKeepAlive {
+			return msg
+		}
+	}
+}
+
+func TestWsProtocol(t *testing.T) {
+	conn, _, closer := dialTestServer(t, []string{"*"}, nil)
+	if !assert.NotNil(t, conn) {
+		return
+	}
+	defer closer()
+
+	sendMsg(t, conn, "1", gqlStart, "subscription { ticks(count: 2) }")
+	msg := readMsg(t, conn)
+	assert.Equal(t, gqlError, msg.Type)
+	assert.Equal(t, "1", msg.Id)
+
+	sendMsg(t, conn, "", gqlConnectionInit, "")
+	assert.Equal(t, gqlConnectionAck, readMsg(t, conn).Type)
+
+	sendMsg(t, conn, "1", gqlStart, "subscription { ticks(count: 2) }")
+	for i := 0; i < 2; i++ {
+		msg = readMsg(t, conn)
+		assert.Equal(t, gqlData, msg.Type)
+		assert.Equal(t, "1", msg.Id)
+		assert.Contains(t, string(msg.Payload), `"ticks":`)
+	}
+	msg = readMsg(t, conn)
+	assert.Equal(t, gqlComplete, msg.Type)
+	assert.Equal(t, "1", msg.Id)
+
+	// the id of a stopped operation can be reused
+	sendMsg(t, conn, "2", gqlStart, "subscription { wait }")
+	sendMsg(t, conn, "2", gqlStart, "subscription { wait }")
+	msg = readMsg(t, conn)
+	assert.Equal(t, gqlError, msg.Type)
+	assert.Contains(t, string(msg.Payload), "duplicated operation id")
+	sendMsg(t, conn, "2", gqlStop, "")
+	sendMsg(t, conn, "2", gqlStart, "subscription { ticks(count: 1) }")
+	msg = readMsg(t, conn)
+	assert.Equal(t, gqlData, msg.Type)
+	assert.Equal(t, "2", msg.Id)
+	assert.Equal(t, gqlComplete, readMsg(t, conn).Type)
+
+	sendMsg(t, conn, "3", "unknown", "")
+	msg = readMsg(t, conn)
+	assert.Equal(t, gqlError, msg.Type)
+	assert.Equal(t, "3", msg.Id)
+}
+
+func TestWsOperationIdReuse(t *testing.T) {
+	conn := &wsConnection{operations: make(map[string]*wsOperation)}
+	old, _ := conn.addOperation(context.Background(), "1")
+	assert.NotNil(t, old)
+	conn.stop("1")
+	assert.NotNil(t, old.ctx.Err())
+
+	op, _ := conn.addOperation(context.Background(), "1")
+	assert.NotNil(t, op)
+	// the stopped operation finishes after its id is reused
+	conn.finish("1", old)
+	assert.Nil(t, op.ctx.Err())
+	assert.Equal(t, op, conn.operations["1"])
+
+	conn.finish("1", op)
+	assert.NotNil(t, op.ctx.Err())
+	assert.Equal(t, 0, len(conn.operations))
+}
+
+func TestWsOrigin(t *testing.T) {
+	header := http.Header{}
+	header.Set("Origin", "http://other.com")
+	conn, rsp, _ := dialTestServer(t, []string{"http://example.com"}, header)
+	assert.Nil(t, conn)
+	assert.Equal(t, http.StatusForbidden, rsp.StatusCode)
+
+	header.Set("Origin", "http://example.com")
+	conn, _, closer := dialTestServer(t, []string{"http://example.com"}, header)
+	assert.NotNil(t, conn)
+	closer()
+}
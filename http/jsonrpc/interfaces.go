@@ -20,10 +20,12 @@ package jsonrpc
 
 import (
 	"encoding/hex"
+	"encoding/json"
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
+	cactor "github.com/ontio/ontology/consensus/actor"
 	"github.com/ontio/ontology/core/payload"
 	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/types"
@@ -33,6 +35,7 @@ import (
 	berr "github.com/ontio/ontology/http/base/error"
 	"github.com/ontio/ontology/http/base/rpc"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/smartcontract/tracers"
 )
 
 // get best block hash
@@ -144,6 +147,23 @@ func GetMemPoolTxHashList(params []interface{}) map[string]interface{} {
 	return rpc.ResponseSuccess(txHashList)
 }
 
+// get memory pool transactions grouped by payer, params: payer(optional)
+func GetMemPoolTxList(params []interface{}) map[string]interface{} {
+	var payer *common.Address
+	if len(params) > 0 {
+		addrBase58, ok := params[0].(string)
+		if !ok {
+			return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+		}
+		address, err := common.AddressFromBase58(addrBase58)
+		if err != nil {
+			return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+		}
+		payer = &address
+	}
+	return rpc.ResponseSuccess(bcomn.GetMemPoolTxs(payer))
+}
+
 // get memory pool transaction state
 func GetMemPoolTxState(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
@@ -158,6 +178,9 @@ func GetMemPoolTxState(params []interface{}) map[string]interface{} {
 		}
 		txEntry, err := bactor.GetTxFromPool(hash)
 		if err != nil {
+			if info, ok := bcomn.GetReplacedTxEntryInfo(hash); ok {
+				return rpc.ResponseSuccess(info)
+			}
 			return rpc.ResponsePack(berr.UNKNOWN_TRANSACTION, "unknown transaction")
 		}
 		var attrs []bcomn.TXNAttrInfo
@@ -304,6 +327,60 @@ func SendRawTransaction(params []interface{}) map[string]interface{} {
 	return rpc.ResponseSuccess(hash.ToHexString())
 }
 
+// trace the pre-execution of neovm or wasmvm invoke transaction
+// Input JSON string examples for tracerawtransaction method as following:
+//
+//	{"jsonrpc": "2.0", "method": "tracerawtransaction", "params": ["00d1..."], "id": 0}
+//	{"jsonrpc": "2.0", "method": "tracerawtransaction", "params": ["00d1...", {"enableStack": true, "limit": 1000}], "id": 0}
+func TraceRawTransaction(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return rpc.ResponsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+	}
+	raw, err := common.HexToBytes(str)
+	if err != nil {
+		return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+	}
+	txn, err := types.TransactionFromRawBytes(raw)
+	if err != nil {
+		return rpc.ResponsePack(berr.INVALID_TRANSACTION, "")
+	}
+	if txn.TxType != types.InvokeNeo && txn.TxType != types.InvokeWasm {
+		return rpc.ResponsePack(berr.INVALID_TRANSACTION, "only neovm and wasmvm invoke transaction can be traced")
+	}
+	cfg := &tracers.LogConfig{}
+	if len(params) > 1 && params[1] != nil {
+		if _, ok := params[1].(map[string]interface{}); !ok {
+			return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+		}
+		buf, err := json.Marshal(params[1])
+		if err != nil {
+			return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+		}
+		if err := json.Unmarshal(buf, cfg); err != nil {
+			return rpc.ResponsePack(berr.INVALID_PARAMS, err.Error())
+		}
+	}
+
+	tracer := tracers.NewCallTracer(cfg)
+	result, err := bactor.PreExecuteContractWithTracer(txn, tracer)
+	if result == nil {
+		return rpc.ResponsePack(berr.SMARTCODE_ERROR, err.Error())
+	}
+	res := bcomn.TraceTransactionResult{PreExecuteResult: bcomn.ConvertPreExecuteResult(result)}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	res.Trace, err = tracer.GetResult()
+	if err != nil {
+		return rpc.ResponsePack(berr.SMARTCODE_ERROR, err.Error())
+	}
+	return rpc.ResponseSuccess(res)
+}
+
 // get node version
 func GetNodeVersion(params []interface{}) map[string]interface{} {
 	return rpc.ResponseSuccess(config.Version)
@@ -508,6 +585,61 @@ func GetBalancesV2(params []interface{}) map[string]interface{} {
 	return rpc.ResponseSuccess(rsp)
 }
 
+// get transactions touching address, params: address, offset, limit
+func GetAddressTxs(params []interface{}) map[string]interface{} {
+	address, offset, limit, ok := parseAddressHistoryParams(params)
+	if !ok {
+		return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetAddressTxs(address, offset, limit)
+	if err == bcomn.ErrAddressIndexDisabled {
+		return rpc.ResponsePack(berr.INVALID_METHOD, err.Error())
+	}
+	if err != nil {
+		return rpc.ResponsePack(berr.INTERNAL_ERROR, "")
+	}
+	return rpc.ResponseSuccess(rsp)
+}
+
+// get token transfers from or to address, params: address, offset, limit
+func GetAddressTransfers(params []interface{}) map[string]interface{} {
+	address, offset, limit, ok := parseAddressHistoryParams(params)
+	if !ok {
+		return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetAddressTransfers(address, offset, limit)
+	if err == bcomn.ErrAddressIndexDisabled {
+		return rpc.ResponsePack(berr.INVALID_METHOD, err.Error())
+	}
+	if err != nil {
+		return rpc.ResponsePack(berr.INTERNAL_ERROR, "")
+	}
+	return rpc.ResponseSuccess(rsp)
+}
+
+func parseAddressHistoryParams(params []interface{}) (address common.Address, offset, limit uint32, ok bool) {
+	if len(params) < 1 {
+		return
+	}
+	addrBase58, ok := params[0].(string)
+	if !ok {
+		return
+	}
+	address, err := common.AddressFromBase58(addrBase58)
+	if err != nil {
+		return address, 0, 0, false
+	}
+	var page [2]uint32
+	for i := 0; i < len(page) && i+1 < len(params); i++ {
+		val, ok := params[i+1].(float64)
+		if !ok || val < 0 {
+			return address, 0, 0, false
+		}
+		page[i] = uint32(val)
+	}
+	return address, page[0], page[1], true
+}
+
 func parseAddressParam(params []interface{}) ([]common.Address, error) {
 	res := make([]common.Address, len(params))
 	var err error
@@ -762,3 +894,65 @@ func GetCrossStatesProof(params []interface{}) map[string]interface{} {
 	}
 	return rpc.ResponseSuccess(bcomn.CrossStatesProof{"CrossStatesProof", hex.EncodeToString(proof)})
 }
+
+// mine blocks on demand in dev chain, return the new block height
+func DevMine(params []interface{}) map[string]interface{} {
+	blocks := float64(1)
+	if len(params) > 0 {
+		var ok bool
+		if blocks, ok = params[0].(float64); !ok || blocks < 1 {
+			return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	return devChainResponse(&cactor.DevMine{Blocks: uint32(blocks)})
+}
+
+// snapshot the ledger of dev chain, return the snapshot id
+func DevSnapshot(params []interface{}) map[string]interface{} {
+	return devChainResponse(&cactor.DevSnapshot{})
+}
+
+// revert the ledger of dev chain to the snapshot
+func DevRevert(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return rpc.ResponsePack(berr.INVALID_PARAMS, nil)
+	}
+	id, ok := params[0].(float64)
+	if !ok {
+		return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+	}
+	return devChainResponse(&cactor.DevRevert{Id: uint32(id)})
+}
+
+// increase the timestamp of following blocks of dev chain, return the total time increased
+func DevIncreaseTime(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return rpc.ResponsePack(berr.INVALID_PARAMS, nil)
+	}
+	seconds, ok := params[0].(float64)
+	if !ok || seconds < 0 {
+		return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+	}
+	return devChainResponse(&cactor.DevIncreaseTime{Seconds: uint64(seconds)})
+}
+
+// set the timestamp of next block of dev chain
+func DevSetNextBlockTimestamp(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return rpc.ResponsePack(berr.INVALID_PARAMS, nil)
+	}
+	timestamp, ok := params[0].(float64)
+	if !ok || timestamp < 0 {
+		return rpc.ResponsePack(berr.INVALID_PARAMS, "")
+	}
+	return devChainResponse(&cactor.DevSetNextBlockTimestamp{Timestamp: uint32(timestamp)})
+}
+
+func devChainResponse(req interface{}) map[string]interface{} {
+	result, err := bactor.DevChainRequest(req)
+	if err != nil {
+		log.Errorf("dev chain request %T error: %s", req, err)
+		return rpc.ResponsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return rpc.ResponseSuccess(result)
+}
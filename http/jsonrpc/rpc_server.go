@@ -41,6 +41,7 @@ func NewRPCHandler() *rpc.ServeMux {
 
 	mux.HandleFunc("getrawtransaction", GetRawTransaction)
 	mux.HandleFunc("sendrawtransaction", SendRawTransaction)
+	mux.HandleFunc("tracerawtransaction", TraceRawTransaction)
 	mux.HandleFunc("getstorage", GetStorage)
 	mux.HandleFunc("getversion", GetNodeVersion)
 	mux.HandleFunc("getnetworkid", GetNetworkId)
@@ -49,6 +50,7 @@ func NewRPCHandler() *rpc.ServeMux {
 	mux.HandleFunc("getmempooltxcount", GetMemPoolTxCount)
 	mux.HandleFunc("getmempooltxstate", GetMemPoolTxState)
 	mux.HandleFunc("getmempooltxhashlist", GetMemPoolTxHashList)
+	mux.HandleFunc("getmempooltxlist", GetMemPoolTxList)
 	mux.HandleFunc("getsmartcodeevent", GetSmartCodeEvent)
 	mux.HandleFunc("getblockheightbytxhash", GetBlockHeightByTxHash)
 
@@ -63,11 +65,21 @@ func NewRPCHandler() *rpc.ServeMux {
 	mux.HandleFunc("getgasprice", GetGasPrice)
 	mux.HandleFunc("getunboundong", GetUnboundOng)
 	mux.HandleFunc("getgrantong", GetGrantOng)
+	mux.HandleFunc("getaddresstxs", GetAddressTxs)
+	mux.HandleFunc("getaddresstransfers", GetAddressTransfers)
 
 	mux.HandleFunc("getcrosschainmsg", GetCrossChainMsg)
 	mux.HandleFunc("getcrossstatesproof", GetCrossStatesProof)
 	mux.HandleFunc("getcrossstatesleafhashes", GetCrossStatesLeafHashes)
 
+	if cfg.DefConfig.Genesis.IsDevChain() {
+		mux.HandleFunc("devmine", DevMine)
+		mux.HandleFunc("devsnapshot", DevSnapshot)
+		mux.HandleFunc("devrevert", DevRevert)
+		mux.HandleFunc("devincreasetime", DevIncreaseTime)
+		mux.HandleFunc("devsetnextblocktimestamp", DevSetNextBlockTimestamp)
+	}
+
 	return mux
 }
 
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ontio/ontology/common/config"
+	berr "github.com/ontio/ontology/http/base/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDevMethodsInTestMode(t *testing.T) {
+	serve := func() map[string]interface{} {
+		w := httptest.NewRecorder()
+		body := `{"jsonrpc":"2.0","method":"devsnapshot","params":[],"id":1}`
+		NewRPCHandler().ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		resp := make(map[string]interface{})
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	genesis := config.DefConfig.Genesis
+	defer func() { config.DefConfig.Genesis = genesis }()
+	config.DefConfig.Genesis = config.NewGenesisConfig()
+	config.DefConfig.Genesis.ConsensusType = config.CONSENSUS_TYPE_SOLO
+	assert.Equal(t, float64(berr.INVALID_METHOD), serve()["error"])
+
+	// the method is registered but there is no solo consensus to serve it
+	config.DefConfig.Genesis.SOLO.DevChain = true
+	assert.Equal(t, float64(berr.INTERNAL_ERROR), serve()["error"])
+}
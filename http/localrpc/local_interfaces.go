@@ -84,3 +84,12 @@ func SetDebugInfo(params []interface{}) map[string]interface{} {
 	}
 	return rpc.ResponsePack(berr.SUCCESS, true)
 }
+
+func GetConsensusStatus(params []interface{}) map[string]interface{} {
+	status, err := bactor.GetConsensusStatus()
+	if err != nil {
+		log.Errorf("GetConsensusStatus error: %s", err)
+		return rpc.ResponsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return rpc.ResponseSuccess(status)
+}
@@ -44,6 +44,7 @@ func StartLocalServer() error {
 	rpcMux.HandleFunc("startconsensus", StartConsensus)
 	rpcMux.HandleFunc("stopconsensus", StopConsensus)
 	rpcMux.HandleFunc("setdebuginfo", SetDebugInfo)
+	rpcMux.HandleFunc("getconsensusstatus", GetConsensusStatus)
 
 	mux := http.NewServeMux()
 	mux.Handle("/", rpcMux)
@@ -0,0 +1,38 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package metrics provides the prometheus metrics server of node
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ontio/ontology/common/log"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+//StartServer serve the metrics registered to the default registry on /metrics
+func StartServer(port uint) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	err := http.ListenAndServe(":"+strconv.Itoa(int(port)), mux)
+	if err != nil {
+		log.Errorf("metrics server ListenAndServe error: %s", err)
+	}
+}
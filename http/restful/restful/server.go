@@ -57,6 +57,7 @@ const (
 	GET_ALL_API           = "/api/v1"
 	GET_CONN_COUNT        = "/api/v1/node/connectioncount"
 	GET_SYNC_STATUS       = "/api/v1/node/syncstatus"
+	GET_CONSENSUS_STATUS  = "/api/v1/node/consensusstatus"
 	GET_BLK_TXS_BY_HEIGHT = "/api/v1/block/transactions/height/:height"
 	GET_BLK_BY_HEIGHT     = "/api/v1/block/details/height/:height"
 	GET_BLK_BY_HASH       = "/api/v1/block/details/hash/:hash"
@@ -79,8 +80,11 @@ const (
 	GET_MEMPOOL_TXCOUNT   = "/api/v1/mempool/txcount"
 	GET_MEMPOOL_TXSTATE   = "/api/v1/mempool/txstate/:hash"
 	GET_MEMPOOL_TXHASHS   = "/api/v1/mempool/txhashlist"
+	GET_MEMPOOL_TXLIST    = "/api/v1/mempool/txlist"
 	GET_VERSION           = "/api/v1/version"
 	GET_NETWORKID         = "/api/v1/networkid"
+	GET_ADDRESS_TXS       = "/api/v1/address/txs/:addr"
+	GET_ADDRESS_TRANSFERS = "/api/v1/address/transfers/:addr"
 
 	POST_RAW_TX = "/api/v1/transaction"
 )
@@ -154,6 +158,7 @@ func (this *restServer) registryMethod() {
 		}},
 		GET_CONN_COUNT:        {name: "getconnectioncount", handler: rest.GetConnectionCount},
 		GET_SYNC_STATUS:       {name: "getsyncstatus", handler: rest.GetNodeSyncStatus},
+		GET_CONSENSUS_STATUS:  {name: "getconsensusstatus", handler: rest.GetConsensusStatus},
 		GET_BLK_TXS_BY_HEIGHT: {name: "getblocktxsbyheight", handler: rest.GetBlockTxsByHeight},
 		GET_BLK_BY_HEIGHT:     {name: "getblockbyheight", handler: rest.GetBlockByHeight},
 		GET_BLK_BY_HASH:       {name: "getblockbyhash", handler: rest.GetBlockByHash},
@@ -176,8 +181,11 @@ func (this *restServer) registryMethod() {
 		GET_MEMPOOL_TXCOUNT:   {name: "getmempooltxcount", handler: rest.GetMemPoolTxCount},
 		GET_MEMPOOL_TXSTATE:   {name: "getmempooltxstate", handler: rest.GetMemPoolTxState},
 		GET_MEMPOOL_TXHASHS:   {name: "getmempooltxhashlist", handler: rest.GetMemPoolTxHashList},
+		GET_MEMPOOL_TXLIST:    {name: "getmempooltxlist", handler: rest.GetMemPoolTxList},
 		GET_VERSION:           {name: "getversion", handler: rest.GetNodeVersion},
 		GET_NETWORKID:         {name: "getnetworkid", handler: rest.GetNetworkId},
+		GET_ADDRESS_TXS:       {name: "getaddresstxs", handler: rest.GetAddressTxs},
+		GET_ADDRESS_TRANSFERS: {name: "getaddresstransfers", handler: rest.GetAddressTransfers},
 	}
 
 	postMethodMap := map[string]Action{
@@ -225,6 +233,10 @@ func (this *restServer) getPath(url string) string {
 		return GET_GRANTONG
 	} else if strings.Contains(url, strings.TrimRight(GET_MEMPOOL_TXSTATE, ":hash")) {
 		return GET_MEMPOOL_TXSTATE
+	} else if strings.Contains(url, strings.TrimRight(GET_ADDRESS_TXS, ":addr")) {
+		return GET_ADDRESS_TXS
+	} else if strings.Contains(url, strings.TrimRight(GET_ADDRESS_TRANSFERS, ":addr")) {
+		return GET_ADDRESS_TRANSFERS
 	}
 	return url
 }
@@ -269,6 +281,11 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 		req["Addr"] = getParam(r, "addr")
 	case GET_MEMPOOL_TXSTATE:
 		req["Hash"] = getParam(r, "hash")
+	case GET_MEMPOOL_TXLIST:
+		req["Payer"] = r.FormValue("payer")
+	case GET_ADDRESS_TXS, GET_ADDRESS_TRANSFERS:
+		req["Addr"] = getParam(r, "addr")
+		req["Offset"], req["Limit"] = r.FormValue("offset"), r.FormValue("limit")
 	default:
 	}
 	return req
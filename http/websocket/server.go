@@ -20,15 +20,12 @@
 package websocket
 
 import (
-	"github.com/ontio/ontology/common"
 	cfg "github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/events/message"
 	bactor "github.com/ontio/ontology/http/base/actor"
 	bcomn "github.com/ontio/ontology/http/base/common"
-	Err "github.com/ontio/ontology/http/base/error"
-	"github.com/ontio/ontology/http/base/rest"
 	"github.com/ontio/ontology/http/websocket/websocket"
 	"github.com/ontio/ontology/smartcontract/event"
 )
@@ -45,10 +42,7 @@ func StartServer() {
 }
 func sendBlock2WSclient(v interface{}) {
 	if cfg.DefConfig.Ws.HttpWsPort != 0 {
-		go func() {
-			pushBlock(v)
-			pushBlockTransactions(v)
-		}()
+		go pushBlock(v)
 	}
 }
 func Stop() {
@@ -79,24 +73,18 @@ func pushSmartCodeEvent(v interface{}) {
 		switch object := rs.Result.(type) {
 		case *event.LogEventArgs:
 			contractAddrs, evts := bcomn.GetLogEvent(object)
-			pushEvent(contractAddrs, rs.TxHash.ToHexString(), rs.Error, rs.Action, evts)
+			pushEvent(contractAddrs, rs.TxHash.ToHexString(), rs.Height, rs.Error, rs.Action, evts)
 		case *event.ExecuteNotify:
 			contractAddrs, notify := bcomn.GetExecuteNotify(object)
-			pushEvent(contractAddrs, rs.TxHash.ToHexString(), rs.Error, rs.Action, notify)
+			pushEvent(contractAddrs, rs.TxHash.ToHexString(), rs.Height, rs.Error, rs.Action, notify)
 		default:
 		}
 	}()
 }
 
-func pushEvent(contractAddrs map[string]bool, txHash string, errcode int64, action string, result interface{}) {
+func pushEvent(contractAddrs map[string]bool, txHash string, height uint32, errcode int64, action string, result interface{}) {
 	if ws != nil {
-		resp := rest.ResponsePack(Err.SUCCESS)
-		resp["Result"] = result
-		resp["Error"] = errcode
-		resp["Action"] = action
-		resp["Desc"] = Err.ErrMap[resp["Error"].(int64)]
-		ws.PushTxResult(contractAddrs, txHash, resp)
-		ws.BroadcastToSubscribers(contractAddrs, websocket.WSTOPIC_EVENT, resp)
+		ws.PushEvent(contractAddrs, txHash, height, errcode, action, result)
 	}
 }
 
@@ -104,25 +92,7 @@ func pushBlock(v interface{}) {
 	if ws == nil {
 		return
 	}
-	resp := rest.ResponsePack(Err.SUCCESS)
 	if block, ok := v.(types.Block); ok {
-		resp["Action"] = "sendrawblock"
-		resp["Result"] = common.ToHexString(block.ToArray())
-		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_RAW_BLOCK, resp)
-
-		resp["Action"] = "sendjsonblock"
-		resp["Result"] = bcomn.GetBlockInfo(&block)
-		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_JSON_BLOCK, resp)
-	}
-}
-func pushBlockTransactions(v interface{}) {
-	if ws == nil {
-		return
-	}
-	resp := rest.ResponsePack(Err.SUCCESS)
-	if block, ok := v.(types.Block); ok {
-		resp["Result"] = bcomn.GetBlockTransactions(&block)
-		resp["Action"] = "sendblocktxhashs"
-		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_TXHASHS, resp)
+		ws.PushBlock(&block)
 	}
 }
@@ -0,0 +1,166 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ontio/ontology/common"
+	bcomn "github.com/ontio/ontology/http/base/common"
+	"github.com/ontio/ontology/smartcontract/event"
+)
+
+//max filters subscribed in one session
+const MAX_FILTER_PER_SESSION = 32
+
+//EventFilter select the smart contract events pushed to a subscription
+type EventFilter struct {
+	SubscriptionId string
+	Contract       string    `json:",omitempty"` //contract address in hex
+	EventName      string    `json:",omitempty"` //the first state of notify, in hex or raw string
+	States         []*string `json:",omitempty"` //states to match by position, nil matches anything
+	MinHeight      uint32    `json:",omitempty"`
+}
+
+//parseEventFilter parse the filter from subscribe request
+func parseEventFilter(cmd map[string]interface{}) (*EventFilter, error) {
+	filter := &EventFilter{}
+	if contract, ok := cmd["Contract"].(string); ok && contract != "" {
+		addr, err := common.AddressFromHexString(contract)
+		if err != nil {
+			addr, err = common.AddressFromBase58(contract)
+			if err != nil {
+				return nil, fmt.Errorf("invalid contract address %s", contract)
+			}
+		}
+		filter.Contract = addr.ToHexString()
+	}
+	if name, ok := cmd["EventName"].(string); ok {
+		filter.EventName = name
+	}
+	if states, ok := cmd["States"].([]interface{}); ok {
+		for _, v := range states {
+			switch val := v.(type) {
+			case nil:
+				filter.States = append(filter.States, nil)
+			case string:
+				filter.States = append(filter.States, &val)
+			default:
+				return nil, fmt.Errorf("invalid state filter %v", v)
+			}
+		}
+	}
+	if height, ok := cmd["MinHeight"].(float64); ok {
+		if height < 0 {
+			return nil, fmt.Errorf("invalid min height %v", height)
+		}
+		filter.MinHeight = uint32(height)
+	}
+	return filter, nil
+}
+
+//filterEvent return the part of event result matched by filter
+func (self *EventFilter) filterEvent(height uint32, result interface{}) (interface{}, bool) {
+	if height < self.MinHeight {
+		return nil, false
+	}
+	switch val := result.(type) {
+	case bcomn.ExecuteNotify:
+		var notifies []bcomn.NotifyEventInfo
+		for _, n := range val.Notify {
+			if self.matchNotify(&n) {
+				notifies = append(notifies, n)
+			}
+		}
+		if len(notifies) == 0 {
+			return nil, false
+		}
+		val.Notify = notifies
+		return val, true
+	case bcomn.LogEventArgs:
+		if self.EventName != "" || len(self.States) != 0 {
+			return nil, false
+		}
+		return val, self.Contract == "" || self.Contract == val.ContractAddress
+	default:
+		return nil, false
+	}
+}
+
+func (self *EventFilter) matchNotify(notify *bcomn.NotifyEventInfo) bool {
+	if self.Contract != "" && self.Contract != notify.ContractAddress {
+		return false
+	}
+	if self.EventName == "" && len(self.States) == 0 {
+		return true
+	}
+	states := notifyStates(notify)
+	if self.EventName != "" && (len(states) == 0 || !matchState(states[0], self.EventName)) {
+		return false
+	}
+	if len(self.States) > len(states) {
+		return false
+	}
+	for i, expected := range self.States {
+		if expected != nil && !matchState(states[i], *expected) {
+			return false
+		}
+	}
+	return true
+}
+
+//notifyStates return the positional states of notify, the topics are used for evm logs
+func notifyStates(notify *bcomn.NotifyEventInfo) []interface{} {
+	if notify.IsEvm {
+		evmLog, err := event.NotifyEventInfoToEvmLog(&event.NotifyEventInfo{States: notify.States, IsEvm: true})
+		if err != nil {
+			return nil
+		}
+		states := make([]interface{}, 0, len(evmLog.Topics))
+		for _, topic := range evmLog.Topics {
+			states = append(states, hexutil.Encode(topic[:]))
+		}
+		return states
+	}
+	states, _ := notify.States.([]interface{})
+	return states
+}
+
+//matchState compare state with expected value, which may be the raw string, hex string
+//or base58 address of the state
+func matchState(state interface{}, expected string) bool {
+	str, ok := state.(string)
+	if !ok {
+		return fmt.Sprint(state) == expected
+	}
+	str = strings.TrimPrefix(str, "0x")
+	if str == expected || strings.EqualFold(str, strings.TrimPrefix(expected, "0x")) {
+		return true
+	}
+	if str == hex.EncodeToString([]byte(expected)) {
+		return true
+	}
+	if addr, err := common.AddressFromBase58(expected); err == nil {
+		return strings.EqualFold(str, hex.EncodeToString(addr[:]))
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	bcomn "github.com/ontio/ontology/http/base/common"
+	Err "github.com/ontio/ontology/http/base/error"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventFilter(t *testing.T) {
+	to := common.AddressFromVmCode([]byte("to"))
+	oep4 := common.AddressFromVmCode([]byte("oep4"))
+	notify := bcomn.ExecuteNotify{
+		Notify: []bcomn.NotifyEventInfo{
+			{
+				ContractAddress: utils.OngContractAddress.ToHexString(),
+				States:          []interface{}{"transfer", utils.OntContractAddress.ToBase58(), to.ToBase58(), int64(10)},
+			},
+			{
+				ContractAddress: oep4.ToHexString(),
+				States:          []interface{}{hex.EncodeToString([]byte("transfer")), hex.EncodeToString(oep4[:]), hex.EncodeToString(to[:])},
+			},
+		},
+	}
+
+	check := func(cmd map[string]interface{}, height uint32, expected int) {
+		filter, err := parseEventFilter(cmd)
+		assert.Nil(t, err)
+		result, ok := filter.filterEvent(height, notify)
+		assert.Equal(t, expected != 0, ok, "%v", cmd)
+		if ok {
+			assert.Equal(t, expected, len(result.(bcomn.ExecuteNotify).Notify), "%v", cmd)
+		}
+	}
+	check(map[string]interface{}{}, 1, 2)
+	check(map[string]interface{}{"Contract": oep4.ToBase58()}, 1, 1)
+	check(map[string]interface{}{"Contract": oep4.ToHexString(), "EventName": "transfer"}, 1, 1)
+	check(map[string]interface{}{"EventName": "transfer", "States": []interface{}{nil, nil, to.ToBase58()}}, 1, 2)
+	check(map[string]interface{}{"States": []interface{}{nil, nil, nil, "10"}}, 1, 1)
+	check(map[string]interface{}{"EventName": "approve"}, 1, 0)
+	check(map[string]interface{}{"MinHeight": float64(2)}, 1, 0)
+
+	_, err := parseEventFilter(map[string]interface{}{"Contract": "invalid"})
+	assert.NotNil(t, err)
+
+	filter, _ := parseEventFilter(map[string]interface{}{"Contract": oep4.ToHexString()})
+	_, ok := filter.filterEvent(1, bcomn.LogEventArgs{ContractAddress: oep4.ToHexString()})
+	assert.True(t, ok)
+	filter.EventName = "transfer"
+	_, ok = filter.filterEvent(1, bcomn.LogEventArgs{ContractAddress: oep4.ToHexString()})
+	assert.False(t, ok)
+}
+
+func TestSubscribeFilterSession(t *testing.T) {
+	server := InitWsServer()
+	server.registryMethod()
+	subscribeFilter := server.ActionMap["subscribefilter"].handler
+
+	resp := subscribeFilter(map[string]interface{}{"SessionId": "unknown", "EventName": "transfer"})
+	assert.Equal(t, Err.SESSION_EXPIRED, resp["Error"])
+	assert.Equal(t, 0, len(server.SubscribeMap))
+
+	s, err := server.SessionList.NewSession(nil)
+	assert.Nil(t, err)
+	resp = subscribeFilter(map[string]interface{}{"SessionId": s.GetSessionId(), "EventName": "transfer"})
+	assert.Equal(t, Err.SUCCESS, resp["Error"])
+	assert.Equal(t, 1, len(server.SubscribeMap[s.GetSessionId()].Filters))
+}
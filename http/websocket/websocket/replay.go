@@ -0,0 +1,210 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	"github.com/ontio/ontology/common/log"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/types"
+	bactor "github.com/ontio/ontology/http/base/actor"
+	bcomn "github.com/ontio/ontology/http/base/common"
+	Err "github.com/ontio/ontology/http/base/error"
+	"github.com/ontio/ontology/http/base/rest"
+	"github.com/ontio/ontology/http/websocket/session"
+	"github.com/ontio/ontology/smartcontract/event"
+)
+
+//max live messages buffered for a session during replay, the session is closed if exceeded
+const MAX_REPLAY_PENDING = 10000
+
+type pendingMsg struct {
+	height uint32
+	data   []byte
+}
+
+//replayState track the history blocks and events replayed to a session. The live messages are
+//buffered until the replay reaches the tip of ledger, and then the ones not replayed are sent.
+//The state is removed once a live message higher than the replayed blocks is sent.
+type replayState struct {
+	from     uint32
+	started  bool
+	done     bool
+	overflow bool
+	liveFrom uint32 //the live messages lower than this height have been replayed
+	pending  []pendingMsg
+}
+
+//startReplay prepare replaying from height to the session, need hold the lock. The live messages are
+//buffered from now on, and the replay is started by runReplay after the subscribe response is sent.
+//Return false if the session is replaying
+func (self *WsServer) startReplay(sessionId string, from uint32) bool {
+	if state, ok := self.replayMap[sessionId]; ok && !state.done {
+		return false
+	}
+	self.replayMap[sessionId] = &replayState{from: from}
+	return true
+}
+
+//runReplay start the replay prepared for the session
+func (self *WsServer) runReplay(sessionId string) {
+	self.Lock()
+	defer self.Unlock()
+	state := self.replayMap[sessionId]
+	if state == nil || state.started {
+		return
+	}
+	state.started = true
+	go self.replay(sessionId, state.from)
+}
+
+//sendToSession send live message of block height to session, need hold the lock
+func (self *WsServer) sendToSession(s *session.Session, height uint32, data []byte) {
+	state := self.replayMap[s.GetSessionId()]
+	if state == nil {
+		s.Send(data)
+		return
+	}
+	if state.done {
+		if height >= state.liveFrom {
+			// the live messages are pushed in the order of height, so no replayed one follows
+			delete(self.replayMap, s.GetSessionId())
+			s.Send(data)
+		}
+		return
+	}
+	if len(state.pending) >= MAX_REPLAY_PENDING {
+		state.overflow = true
+		return
+	}
+	state.pending = append(state.pending, pendingMsg{height: height, data: data})
+}
+
+func (self *WsServer) replay(sessionId string, from uint32) {
+	height := from
+	for ; height <= bactor.GetCurrentBlockHeight(); height++ {
+		block, err := bactor.GetBlockByHeight(height)
+		if err != nil {
+			log.Errorf("websocket replay GetBlockByHeight %d: %s", height, err)
+			self.abortReplay(sessionId, Err.INTERNAL_ERROR)
+			return
+		}
+		notifies, err := bactor.GetEventNotifyByHeight(height)
+		if err != nil && err != scom.ErrNotFound {
+			log.Errorf("websocket replay GetEventNotifyByHeight %d: %s", height, err)
+			self.abortReplay(sessionId, Err.INTERNAL_ERROR)
+			return
+		}
+		if !self.replayBlock(sessionId, block, notifies) {
+			return
+		}
+	}
+	self.finishReplay(sessionId, height)
+}
+
+//replayBlock send the events and block to the session in the same order as live messages,
+//return false if the replay is stopped. The messages are sent without holding the lock, the live
+//messages are buffered until the replay is done
+func (self *WsServer) replayBlock(sessionId string, block *types.Block, notifies []*event.ExecuteNotify) bool {
+	s, msgs := self.replayMessages(sessionId, block, notifies)
+	if s == nil {
+		return false
+	}
+	for _, data := range msgs {
+		s.Send(data)
+	}
+	return true
+}
+
+//replayMessages return the session and the messages of block to replay, nil if the replay is stopped
+func (self *WsServer) replayMessages(sessionId string, block *types.Block, notifies []*event.ExecuteNotify) (*session.Session, [][]byte) {
+	self.Lock()
+	defer self.Unlock()
+	state := self.replayMap[sessionId]
+	s := self.SessionList.GetSessionById(sessionId)
+	if state == nil || s == nil {
+		return nil, nil
+	}
+	if state.overflow {
+		self.closeOverflow(s)
+		return nil, nil
+	}
+	var msgs [][]byte
+	sub := self.SubscribeMap[sessionId]
+	height := block.Header.Height
+	for _, notify := range notifies {
+		contractAddrs, result := bcomn.GetExecuteNotify(notify)
+		resp := eventResponse(0, event.EVENT_NOTIFY, result)
+		if sub.matchTopic(WSTOPIC_EVENT, contractAddrs) {
+			msgs = append(msgs, marshalResp(resp))
+		}
+		msgs = append(msgs, sub.filterEvent(height, resp)...)
+	}
+	for _, topic := range []int{WSTOPIC_RAW_BLOCK, WSTOPIC_JSON_BLOCK, WSTOPIC_TXHASHS} {
+		if sub.matchTopic(topic, nil) {
+			msgs = append(msgs, marshalResp(blockResponse(topic, block)))
+		}
+	}
+	return s, msgs
+}
+
+//finishReplay switch the session to live messages from height
+func (self *WsServer) finishReplay(sessionId string, height uint32) {
+	self.Lock()
+	defer self.Unlock()
+	state := self.replayMap[sessionId]
+	s := self.SessionList.GetSessionById(sessionId)
+	if state == nil || s == nil {
+		return
+	}
+	if state.overflow {
+		self.closeOverflow(s)
+		return
+	}
+	state.done = true
+	state.liveFrom = height
+	pending := state.pending
+	state.pending = nil
+	for _, msg := range pending {
+		if msg.height >= height {
+			// later live messages are all higher than the replayed blocks
+			delete(self.replayMap, sessionId)
+			s.Send(msg.data)
+		}
+	}
+}
+
+//closeOverflow close the session buffered too many live messages during replay, need hold the lock
+func (self *WsServer) closeOverflow(s *session.Session) {
+	resp := rest.ResponsePack(Err.SERVICE_CEILING)
+	resp["Action"] = "subscribe"
+	s.Send(marshalResp(resp))
+	delete(self.replayMap, s.GetSessionId())
+	go self.SessionList.CloseSession(s)
+}
+
+func (self *WsServer) abortReplay(sessionId string, errCode int64) {
+	self.Lock()
+	defer self.Unlock()
+	delete(self.replayMap, sessionId)
+	if s := self.SessionList.GetSessionById(sessionId); s != nil {
+		resp := rest.ResponsePack(errCode)
+		resp["Action"] = "subscribe"
+		s.Send(marshalResp(resp))
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayState(t *testing.T) {
+	server := InitWsServer()
+	s, err := server.SessionList.NewSession(nil)
+	assert.Nil(t, err)
+	sessionId := s.GetSessionId()
+
+	server.Lock()
+	assert.True(t, server.startReplay(sessionId, 1))
+	assert.False(t, server.startReplay(sessionId, 1))
+	// live messages are buffered before the replay is started
+	server.sendToSession(s, 3, []byte("live"))
+	server.Unlock()
+	state := server.replayMap[sessionId]
+	assert.False(t, state.started)
+	assert.Equal(t, 1, len(state.pending))
+
+	server.finishReplay(sessionId, 4)
+	assert.True(t, state.done)
+	assert.Nil(t, state.pending)
+	assert.NotNil(t, server.replayMap[sessionId])
+
+	server.Lock()
+	server.sendToSession(s, 3, []byte("replayed"))
+	assert.NotNil(t, server.replayMap[sessionId])
+	server.sendToSession(s, 4, []byte("live"))
+	assert.Nil(t, server.replayMap[sessionId])
+	server.Unlock()
+
+	server.Lock()
+	assert.True(t, server.startReplay(sessionId, 1))
+	server.Unlock()
+	server.deleteSubscribe(sessionId)
+	assert.Nil(t, server.replayMap[sessionId])
+}
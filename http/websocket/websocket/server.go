@@ -33,9 +33,13 @@ import (
 	"github.com/ontio/ontology/common"
 	cfg "github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/types"
+	bactor "github.com/ontio/ontology/http/base/actor"
+	bcomn "github.com/ontio/ontology/http/base/common"
 	Err "github.com/ontio/ontology/http/base/error"
 	"github.com/ontio/ontology/http/base/rest"
 	"github.com/ontio/ontology/http/websocket/session"
+	"github.com/pborman/uuid"
 )
 
 const (
@@ -49,6 +53,8 @@ type handler func(map[string]interface{}) map[string]interface{}
 type Handler struct {
 	handler  handler
 	pushFlag bool
+	//called after the successful response is sent to the session
+	afterSend func(sessionId string)
 }
 
 //subscribe event for client
@@ -58,16 +64,19 @@ type subscribe struct {
 	SubscribeJsonBlock    bool     `json:"SubscribeJsonBlock"`
 	SubscribeRawBlock     bool     `json:"SubscribeRawBlock"`
 	SubscribeBlockTxHashs bool     `json:"SubscribeBlockTxHashs"`
+
+	Filters []*EventFilter `json:"Filters,omitempty"`
 }
 type WsServer struct {
 	sync.RWMutex
 	Upgrader     websocket.Upgrader
 	listener     net.Listener
 	server       *http.Server
-	SessionList  *session.SessionList // websocket sesseionlist
-	ActionMap    map[string]Handler   //handler functions
-	TxHashMap    map[string]string    //key: txHash   value:sessionid
-	SubscribeMap map[string]subscribe //key: sessionId   value:subscribeInfo
+	SessionList  *session.SessionList    // websocket sesseionlist
+	ActionMap    map[string]Handler      //handler functions
+	TxHashMap    map[string]string       //key: txHash   value:sessionid
+	SubscribeMap map[string]subscribe    //key: sessionId   value:subscribeInfo
+	replayMap    map[string]*replayState //key: sessionId   value:replay of history blocks and events
 }
 
 //init websocket server
@@ -77,6 +86,7 @@ func InitWsServer() *WsServer {
 		SessionList:  session.NewSessionList(),
 		TxHashMap:    make(map[string]string),
 		SubscribeMap: make(map[string]subscribe),
+		replayMap:    make(map[string]*replayState),
 	}
 	return ws
 }
@@ -165,12 +175,66 @@ func (self *WsServer) registryMethod() {
 				}
 			}
 		}
+		if height, ok := cmd["FromHeight"].(float64); ok {
+			if height < 0 || uint32(height) > bactor.GetCurrentBlockHeight()+1 {
+				return rest.ResponsePack(Err.INVALID_PARAMS)
+			}
+			if !self.startReplay(sessionId, uint32(height)) {
+				return rest.ResponsePack(Err.SERVICE_CEILING)
+			}
+		}
 		self.SubscribeMap[sessionId] = sub
 
 		resp["Action"] = "subscribe"
 		resp["Result"] = sub
 		return resp
 	}
+	subscribeFilter := func(cmd map[string]interface{}) map[string]interface{} {
+		filter, err := parseEventFilter(cmd)
+		if err != nil {
+			resp := rest.ResponsePack(Err.INVALID_PARAMS)
+			resp["Result"] = err.Error()
+			return resp
+		}
+		self.Lock()
+		defer self.Unlock()
+
+		sessionId, _ := cmd["SessionId"].(string)
+		if self.SessionList.GetSessionById(sessionId) == nil {
+			return rest.ResponsePack(Err.SESSION_EXPIRED)
+		}
+		sub := self.SubscribeMap[sessionId]
+		if len(sub.Filters) >= MAX_FILTER_PER_SESSION {
+			resp := rest.ResponsePack(Err.INVALID_PARAMS)
+			resp["Result"] = "too many filters"
+			return resp
+		}
+		filter.SubscriptionId = uuid.NewUUID().String()
+		sub.Filters = append(sub.Filters, filter)
+		self.SubscribeMap[sessionId] = sub
+
+		resp := rest.ResponsePack(Err.SUCCESS)
+		resp["Result"] = filter
+		return resp
+	}
+	unsubscribeFilter := func(cmd map[string]interface{}) map[string]interface{} {
+		self.Lock()
+		defer self.Unlock()
+
+		sessionId, _ := cmd["SessionId"].(string)
+		subId, _ := cmd["SubscriptionId"].(string)
+		sub := self.SubscribeMap[sessionId]
+		for i, filter := range sub.Filters {
+			if filter.SubscriptionId == subId {
+				sub.Filters = append(sub.Filters[:i:i], sub.Filters[i+1:]...)
+				self.SubscribeMap[sessionId] = sub
+				resp := rest.ResponsePack(Err.SUCCESS)
+				resp["Result"] = subId
+				return resp
+			}
+		}
+		return rest.ResponsePack(Err.INVALID_PARAMS)
+	}
 	getsessioncount := func(cmd map[string]interface{}) map[string]interface{} {
 		resp := rest.ResponsePack(Err.SUCCESS)
 		resp["Action"] = "getsessioncount"
@@ -192,7 +256,9 @@ func (self *WsServer) registryMethod() {
 		"gettransaction":            {handler: rest.GetTransactionByHash},
 		"sendrawtransaction":        {handler: rest.SendRawTransaction, pushFlag: true},
 		"heartbeat":                 {handler: heartbeat},
-		"subscribe":                 {handler: subscribe},
+		"subscribe":                 {handler: subscribe, afterSend: self.runReplay},
+		"subscribefilter":           {handler: subscribeFilter},
+		"unsubscribefilter":         {handler: unsubscribeFilter},
 		"getstorage":                {handler: rest.GetStorage},
 		"getallowance":              {handler: rest.GetAllowance},
 		"getallowancev2":            {handler: rest.GetAllowanceV2},
@@ -204,6 +270,7 @@ func (self *WsServer) registryMethod() {
 		"getmempooltxcount":         {handler: rest.GetMemPoolTxCount},
 		"getmempooltxstate":         {handler: rest.GetMemPoolTxState},
 		"getmempooltxhashlist":      {handler: rest.GetMemPoolTxHashList},
+		"getmempooltxlist":          {handler: rest.GetMemPoolTxList},
 		"getversion":                {handler: rest.GetNodeVersion},
 		"getnetworkid":              {handler: rest.GetNetworkId},
 
@@ -357,6 +424,11 @@ func (self *WsServer) OnDataHandle(curSession *session.Session, bysMsg []byte, r
 		}
 	}
 	curSession.Send(marshalResp(resp))
+	if action.afterSend != nil {
+		if errCode, _ := resp["Error"].(int64); errCode == 0 {
+			action.afterSend(curSession.GetSessionId())
+		}
+	}
 
 	return true
 }
@@ -378,6 +450,7 @@ func (self *WsServer) deleteSubscribe(sessionId string) {
 	self.Lock()
 	defer self.Unlock()
 	delete(self.SubscribeMap, sessionId)
+	delete(self.replayMap, sessionId)
 }
 
 func marshalResp(resp map[string]interface{}) []byte {
@@ -391,23 +464,31 @@ func marshalResp(resp map[string]interface{}) []byte {
 	return data
 }
 
+//PushBlock push the saved block to the subscribers of block topics
+func (self *WsServer) PushBlock(block *types.Block) {
+	for _, topic := range []int{WSTOPIC_RAW_BLOCK, WSTOPIC_JSON_BLOCK, WSTOPIC_TXHASHS} {
+		self.BroadcastToSubscribers(nil, topic, block.Header.Height, blockResponse(topic, block))
+	}
+}
+
+//PushEvent push the smart contract event to the session sent the transaction and the subscribers
+func (self *WsServer) PushEvent(contractAddrs map[string]bool, txHash string, height uint32, errcode int64,
+	action string, result interface{}) {
+	resp := eventResponse(errcode, action, result)
+	self.PushTxResult(contractAddrs, txHash, resp)
+	self.BroadcastToSubscribers(contractAddrs, WSTOPIC_EVENT, height, resp)
+	self.BroadcastToFilters(height, resp)
+}
+
 func (self *WsServer) PushTxResult(contractAddrs map[string]bool, txHashStr string, resp map[string]interface{}) {
 	self.Lock()
 	sessionId := self.TxHashMap[txHashStr]
 	delete(self.TxHashMap, txHashStr)
 	//avoid twice, will send in BroadcastToSubscribers
 	sub := self.SubscribeMap[sessionId]
-	if sub.SubscribeEvent {
-		if len(sub.ContractsFilter) == 0 {
-			self.Unlock()
-			return
-		}
-		for _, addr := range sub.ContractsFilter {
-			if contractAddrs[addr] {
-				self.Unlock()
-				return
-			}
-		}
+	if sub.matchTopic(WSTOPIC_EVENT, contractAddrs) {
+		self.Unlock()
+		return
 	}
 	self.Unlock()
 
@@ -416,7 +497,10 @@ func (self *WsServer) PushTxResult(contractAddrs map[string]bool, txHashStr stri
 		s.Send(marshalResp(resp))
 	}
 }
-func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, sub int, resp map[string]interface{}) {
+
+//BroadcastToSubscribers push the message of block height to the subscribers of topic
+func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, topic int, height uint32,
+	resp map[string]interface{}) {
 	// broadcast SubscribeMap
 	self.Lock()
 	defer self.Unlock()
@@ -426,25 +510,97 @@ func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, sub
 		if s == nil {
 			continue
 		}
-		if sub == WSTOPIC_JSON_BLOCK && v.SubscribeJsonBlock {
-			s.Send(data)
-		} else if sub == WSTOPIC_RAW_BLOCK && v.SubscribeRawBlock {
-			s.Send(data)
-		} else if sub == WSTOPIC_TXHASHS && v.SubscribeBlockTxHashs {
-			s.Send(data)
-		} else if sub == WSTOPIC_EVENT && v.SubscribeEvent {
-			if len(v.ContractsFilter) == 0 {
-				s.Send(data)
-				continue
-			}
-			for _, addr := range v.ContractsFilter {
-				if contractAddrs[addr] {
-					s.Send(data)
-					break
-				}
+		if v.matchTopic(topic, contractAddrs) {
+			self.sendToSession(s, height, data)
+		}
+	}
+}
+
+//BroadcastToFilters push the smart contract event of block height to the matched filters,
+//with the subscription id in response
+func (self *WsServer) BroadcastToFilters(height uint32, resp map[string]interface{}) {
+	self.Lock()
+	defer self.Unlock()
+	for sid, v := range self.SubscribeMap {
+		if len(v.Filters) == 0 {
+			continue
+		}
+		s := self.SessionList.GetSessionById(sid)
+		if s == nil {
+			continue
+		}
+		for _, data := range v.filterEvent(height, resp) {
+			self.sendToSession(s, height, data)
+		}
+	}
+}
+
+func (self subscribe) matchTopic(topic int, contractAddrs map[string]bool) bool {
+	switch topic {
+	case WSTOPIC_JSON_BLOCK:
+		return self.SubscribeJsonBlock
+	case WSTOPIC_RAW_BLOCK:
+		return self.SubscribeRawBlock
+	case WSTOPIC_TXHASHS:
+		return self.SubscribeBlockTxHashs
+	case WSTOPIC_EVENT:
+		if !self.SubscribeEvent {
+			return false
+		}
+		if len(self.ContractsFilter) == 0 {
+			return true
+		}
+		for _, addr := range self.ContractsFilter {
+			if contractAddrs[addr] {
+				return true
 			}
 		}
 	}
+	return false
+}
+
+//filterEvent return the messages of event matched by the filters
+func (self subscribe) filterEvent(height uint32, resp map[string]interface{}) [][]byte {
+	var msgs [][]byte
+	for _, filter := range self.Filters {
+		result, ok := filter.filterEvent(height, resp["Result"])
+		if !ok {
+			continue
+		}
+		filtered := make(map[string]interface{}, len(resp)+1)
+		for key, val := range resp {
+			filtered[key] = val
+		}
+		filtered["Result"] = result
+		filtered["SubscriptionId"] = filter.SubscriptionId
+		msgs = append(msgs, marshalResp(filtered))
+	}
+	return msgs
+}
+
+func blockResponse(topic int, block *types.Block) map[string]interface{} {
+	resp := rest.ResponsePack(Err.SUCCESS)
+	switch topic {
+	case WSTOPIC_RAW_BLOCK:
+		resp["Action"] = "sendrawblock"
+		resp["Result"] = common.ToHexString(block.ToArray())
+	case WSTOPIC_JSON_BLOCK:
+		resp["Action"] = "sendjsonblock"
+		resp["Result"] = bcomn.GetBlockInfo(block)
+	case WSTOPIC_TXHASHS:
+		resp["Action"] = "sendblocktxhashs"
+		resp["Result"] = bcomn.GetBlockTransactions(block)
+	}
+	return resp
+}
+
+func eventResponse(errcode int64, action string, result interface{}) map[string]interface{} {
+	resp := rest.ResponsePack(Err.SUCCESS)
+	resp["Result"] = result
+	resp["Error"] = errcode
+	resp["Action"] = action
+	resp["Desc"] = Err.ErrMap[resp["Error"].(int64)]
+	return resp
 }
 
 func (self *WsServer) initTlsListen() (net.Listener, error) {
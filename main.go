@@ -49,6 +49,7 @@ import (
 	"github.com/ontio/ontology/http/graphql"
 	"github.com/ontio/ontology/http/jsonrpc"
 	"github.com/ontio/ontology/http/localrpc"
+	"github.com/ontio/ontology/http/metrics"
 	"github.com/ontio/ontology/http/nodeinfo"
 	"github.com/ontio/ontology/http/restful"
 	"github.com/ontio/ontology/http/websocket"
@@ -56,6 +57,7 @@ import (
 	netreqactor "github.com/ontio/ontology/p2pserver/actor/req"
 	p2p "github.com/ontio/ontology/p2pserver/net/protocol"
 	"github.com/ontio/ontology/txnpool"
+	tc "github.com/ontio/ontology/txnpool/common"
 	"github.com/ontio/ontology/txnpool/proc"
 	"github.com/urfave/cli"
 )
@@ -90,10 +92,14 @@ func setupAPP() *cli.App {
 		utils.DataDirFlag,
 		utils.ETHTxGasLimitFlag,
 		utils.WasmVerifyMethodFlag,
+		utils.EnableStateHistoryFlag,
+		utils.EnableAddressIndexFlag,
 		//account setting
 		utils.WalletFileFlag,
 		utils.AccountAddressFlag,
 		utils.AccountPassFlag,
+		utils.RemoteSignerFlag,
+		utils.RemoteSignerTokenFlag,
 		//consensus setting
 		utils.EnableConsensusFlag,
 		utils.MaxTxInBlockFlag,
@@ -104,6 +110,14 @@ func setupAPP() *cli.App {
 		utils.DisableSyncVerifyTxFlag,
 		utils.DisableBroadcastNetTxFlag,
 		utils.TraceTxPoolFlag,
+		utils.TxPoolGlobalSlotsFlag,
+		utils.TxPoolAccountSlotsFlag,
+		utils.TxPoolGlobalQueueFlag,
+		utils.TxPoolAccountQueueFlag,
+		utils.TxPoolPriceBumpFlag,
+		utils.TxPoolNativeReplaceFlag,
+		utils.TxPoolJournalFlag,
+		utils.TxPoolJournalMaxAgeFlag,
 		//p2p setting
 		utils.ReservedPeersOnlyFlag,
 		utils.ReservedPeersFileFlag,
@@ -113,13 +127,23 @@ func setupAPP() *cli.App {
 		utils.MaxConnInBoundFlag,
 		utils.MaxConnOutBoundFlag,
 		utils.MaxConnInBoundForSingleIPFlag,
+		utils.SecureTransportFlag,
 		//test mode setting
 		utils.EnableTestModeFlag,
 		utils.TestModeGenBlockTimeFlag,
+		utils.TestModeAutoMineFlag,
+		utils.TestModeDevAccountsFlag,
 		//rpc setting
 		utils.RPCDisabledFlag,
 		utils.RPCPortFlag,
 		utils.ETHRPCPortFlag,
+		utils.RPCMaxBatchSizeFlag,
+		utils.RPCJsonRpc2Flag,
+		utils.ETHWsEnableFlag,
+		utils.ETHWsPortFlag,
+		utils.ETHWsOriginsFlag,
+		utils.ETHWsMaxConnsFlag,
+		utils.ETHWsMaxSubscriptionsFlag,
 		utils.RPCLocalEnableFlag,
 		utils.RPCLocalProtFlag,
 		//rest setting
@@ -133,6 +157,9 @@ func setupAPP() *cli.App {
 		//ws setting
 		utils.WsEnabledFlag,
 		utils.WsPortFlag,
+		//metrics setting
+		utils.MetricsEnableFlag,
+		utils.MetricsPortFlag,
 	}
 	app.Before = func(context *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
@@ -184,6 +211,11 @@ func startOntology(ctx *cli.Context) {
 		log.Errorf("initP2PNode error: %s", err)
 		return
 	}
+	err = initTxPoolJournal(txpool)
+	if err != nil {
+		log.Errorf("initTxPoolJournal error: %s", err)
+		return
+	}
 	_, err = initConsensus(ctx, p2p, txpool, acc)
 	if err != nil {
 		log.Errorf("initConsensus error: %s", err)
@@ -208,9 +240,10 @@ func startOntology(ctx *cli.Context) {
 	initRestful(ctx)
 	initWs(ctx)
 	initNodeInfo(ctx, p2pSvr)
+	initMetrics(ctx)
 
 	go logCurrBlockHeight()
-	waitToExit(ldg)
+	waitToExit(ldg, txpool)
 }
 
 func initLog(ctx *cli.Context) {
@@ -242,12 +275,14 @@ func initAccount(ctx *cli.Context) (*account.Account, error) {
 	if !config.DefConfig.Consensus.EnableConsensus {
 		return nil, nil
 	}
-	walletFile := ctx.GlobalString(utils.GetFlagName(utils.WalletFileFlag))
-	if walletFile == "" {
-		return nil, fmt.Errorf("please config wallet file using --wallet flag")
-	}
-	if !common.FileExisted(walletFile) {
-		return nil, fmt.Errorf("cannot find wallet file: %s. Please create a wallet first", walletFile)
+	if ctx.GlobalString(utils.GetFlagName(utils.RemoteSignerFlag)) == "" {
+		walletFile := ctx.GlobalString(utils.GetFlagName(utils.WalletFileFlag))
+		if walletFile == "" {
+			return nil, fmt.Errorf("please config wallet file using --wallet flag")
+		}
+		if !common.FileExisted(walletFile) {
+			return nil, fmt.Errorf("cannot find wallet file: %s. Please create a wallet first", walletFile)
+		}
 	}
 
 	acc, err := cmdcom.GetAccount(ctx)
@@ -283,6 +318,18 @@ func initLedger(ctx *cli.Context, stateHashHeight uint32) (*ledger.Ledger, error
 	if err != nil {
 		return nil, fmt.Errorf("NewLedger error: %s", err)
 	}
+	if config.DefConfig.Common.EnableStateHistory {
+		err = ledger.DefLedger.EnableStateHistory()
+		if err != nil {
+			return nil, fmt.Errorf("EnableStateHistory error: %s", err)
+		}
+	}
+	if config.DefConfig.Common.EnableAddressIndex {
+		err = ledger.DefLedger.EnableAddressIndex()
+		if err != nil {
+			return nil, fmt.Errorf("EnableAddressIndex error: %s", err)
+		}
+	}
 
 	log.Infof("Ledger init success")
 	return ledger.DefLedger, nil
@@ -304,6 +351,19 @@ func initTxPool(ctx *cli.Context) (*proc.TXPoolServer, error) {
 	return txPoolServer, nil
 }
 
+func initTxPoolJournal(txpoolSvr *proc.TXPoolServer) error {
+	cfg := config.DefConfig.TxPool
+	if !cfg.EnableJournal {
+		return nil
+	}
+	dir := utils.GetStoreDirPath(config.DefConfig.Common.DataDir, config.DefConfig.P2PNode.NetworkName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	maxAge := time.Duration(cfg.JournalMaxAge) * time.Hour
+	return txpoolSvr.StartJournal(filepath.Join(dir, tc.JOURNAL_FILE_NAME), maxAge)
+}
+
 func initP2PNode(ctx *cli.Context, txpoolSvr *proc.TXPoolServer, acct *account.Account) (*p2pserver.P2PServer, p2p.P2P, error) {
 	if config.DefConfig.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
 		return nil, nil, nil
@@ -455,6 +515,15 @@ func initNodeInfo(ctx *cli.Context, p2pSvr *p2pserver.P2PServer) {
 	log.Infof("Nodeinfo init success")
 }
 
+func initMetrics(ctx *cli.Context) {
+	if !config.DefConfig.Metrics.EnableMetrics {
+		return
+	}
+	go metrics.StartServer(config.DefConfig.Metrics.MetricsPort)
+
+	log.Infof("Metrics init success")
+}
+
 func logCurrBlockHeight() {
 	ticker := time.NewTicker(config.DEFAULT_GEN_BLOCK_TIME * time.Second)
 	defer ticker.Stop()
@@ -480,13 +549,14 @@ func setMaxOpenFiles() {
 	}
 }
 
-func waitToExit(db *ledger.Ledger) {
+func waitToExit(db *ledger.Ledger, txpool *proc.TXPoolServer) {
 	exit := make(chan bool, 0)
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 	go func() {
 		for sig := range sc {
 			log.Infof("Ontology received exit signal: %v.", sig.String())
+			txpool.StopJournal()
 			log.Infof("closing ledger...")
 			db.Close()
 			close(exit)
@@ -32,6 +32,7 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/signature"
 	"github.com/ontio/ontology/core/types"
 )
 
@@ -67,10 +68,20 @@ func (self *PeerId) ToHexString() string {
 	return self.val.ToHexString()
 }
 
+func PeerIdFromHexString(s string) (PeerId, error) {
+	val, err := common.AddressFromHexString(s)
+	if err != nil {
+		return PeerId{}, err
+	}
+	return PeerId{val: val}, nil
+}
+
 type PeerKeyId struct {
 	PublicKey keypair.PublicKey
 
 	Id PeerId
+
+	signer signature.Signer // only available for local key id
 }
 
 func (self PeerId) GenRandPeerId(prefix uint) PeerId {
@@ -179,9 +190,23 @@ func RandPeerKeyId() *PeerKeyId {
 	return &PeerKeyId{
 		PublicKey: acc.PublicKey,
 		Id:        kid,
+		signer:    acc,
 	}
 }
 
+//Sign data with the private key behind the key id, used to prove the ownership of PeerKeyId
+func (this *PeerKeyId) Sign(data []byte) ([]byte, error) {
+	if this.signer == nil {
+		return nil, errors.New("private key of peer key id is not available")
+	}
+	return signature.SignPayload(this.signer, signature.PAYLOAD_DATA, data)
+}
+
+//Verify the signature made by the owner of the key id
+func (this *PeerKeyId) Verify(data, sig []byte) error {
+	return signature.VerifyPayload(this.PublicKey, signature.PAYLOAD_DATA, data, sig)
+}
+
 func validatePublicKey(pubKey keypair.PublicKey) bool {
 	pub := keypair.SerializePublicKey(pubKey)
 	res := sha256.Sum256(pub)
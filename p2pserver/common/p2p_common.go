@@ -29,6 +29,7 @@ const (
 )
 
 const MIN_VERSION_FOR_DHT = "1.9.1-beta"
+const MIN_VERSION_FOR_SECURE_TRANSPORT = "2.0.0"
 
 //link and concurrent const
 const (
@@ -75,7 +76,10 @@ const (
 )
 
 //cap flag
-const HTTP_INFO_FLAG = 0 //peer`s http info bit in cap field
+const (
+	HTTP_INFO_FLAG        = 0 //peer`s http info bit in cap field
+	SECURE_TRANSPORT_FLAG = 1 //peer`s secure transport bit in cap field
+)
 
 //recent contact const
 const (
@@ -83,6 +87,18 @@ const (
 	RECENT_FILE_NAME = "peers.recent"
 )
 
+//peer score const
+const (
+	PEER_SCORE_MAX            = 100            //upper bound of peer score
+	PEER_SCORE_BAN_THRESHOLD  = -100           //peer is disconnected and banned when score drops to this value
+	PEER_SCORE_DECAY_INTERVAL = 60             //interval in second to decay peer scores toward zero
+	PEER_SCORE_DECAY_RATE     = 0.9            //score multiplier applied every decay interval
+	PEER_BAN_DURATION         = 24 * 3600      //ban time in second
+	PEER_TX_RATE_WINDOW       = 10             //window in second to count relayed transactions
+	PEER_TX_RATE_LIMIT        = 10000          //max transactions relayed by a peer in a window
+	BANNED_PEERS_FILE_NAME    = "peers.banned" //persisted ban list
+)
+
 //PeerAddr represent peer`s net information
 type PeerAddr struct {
 	Time     int64    //latest timestamp
@@ -114,6 +130,8 @@ const (
 	FINDNODE_TYPE      = "findnode"    // find node using dht
 	FINDNODE_RESP_TYPE = "findnodeack" // find node using dht
 	UPDATE_KADID_TYPE  = "updatekadid" //update node kadid
+	SECURE_HELLO_TYPE  = "securehello" //ephemeral key of secure transport handshake
+	PEER_KEY_AUTH_TYPE = "peerkeyauth" //prove the ownership of kad key id
 
 	GET_SUBNET_MEMBERS_TYPE = "getmembers" // request subnet members
 	SUBNET_MEMBERS_TYPE     = "members"    // response subnet members
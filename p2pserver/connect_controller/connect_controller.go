@@ -182,7 +182,7 @@ func (self *ConnectController) AcceptConnect(conn net.Conn) (*peer.PeerInfo, net
 		return nil, nil, err
 	}
 
-	peerInfo, err := handshake.HandshakeServer(self.peerInfo, self.selfId, conn)
+	peerInfo, conn, err := handshake.HandshakeServer(self.peerInfo, self.selfId, conn, self.SecureTransport)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -216,11 +216,12 @@ func (self *ConnectController) Connect(addr string) (*peer.PeerInfo, net.Conn, e
 		return nil, nil, err
 	}
 
-	peerInfo, err := handshake.HandshakeClient(self.peerInfo, self.selfId, conn)
+	peerInfo, secConn, err := handshake.HandshakeClient(self.peerInfo, self.selfId, conn, self.SecureTransport)
 	if err != nil {
 		_ = conn.Close()
 		return nil, nil, err
 	}
+	conn = secConn
 
 	err = self.afterHandshakeCheck(peerInfo, conn.RemoteAddr().String())
 	if err != nil {
@@ -248,6 +249,10 @@ func (self *ConnectController) beforeHandshakeCheck(addr string, index int) erro
 		return err
 	}
 
+	if self.BannedPeers.Contains(addr) {
+		return fmt.Errorf("peer address %s is banned", addr)
+	}
+
 	if self.hasBoundAddr(addr) {
 		return fmt.Errorf("peer %s already in connection records", addr)
 	}
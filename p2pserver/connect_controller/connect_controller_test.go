@@ -108,7 +108,7 @@ func TestConnectController_CanDetectSelfAddress(t *testing.T) {
 
 		c, s := trans.Pipe()
 		go func() {
-			_, _ = handshake.HandshakeClient(server.peerInfo, server.Key, c)
+			_, _, _ = handshake.HandshakeClient(server.peerInfo, server.Key, c, false)
 		}()
 
 		_, _, err := server.AcceptConnect(s)
@@ -132,7 +132,7 @@ func TestConnectController_AcceptConnect_MaxInBound(t *testing.T) {
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
-			_, err := handshake.HandshakeClient(client.peerInfo, client.Key, conn1)
+			_, _, err := handshake.HandshakeClient(client.peerInfo, client.Key, conn1, false)
 			if i < int(maxInboud) {
 				assert.Nil(t, err)
 			} else {
@@ -194,7 +194,7 @@ func TestConnCtrlOption_MaxInBoundPerIp(t *testing.T) {
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
-			_, err := handshake.HandshakeClient(client.peerInfo, client.Key, conn1)
+			_, _, err := handshake.HandshakeClient(client.peerInfo, client.Key, conn1, false)
 			if i < int(maxInBoundPerIp) {
 				assert.Nil(t, err)
 			} else {
@@ -27,6 +27,8 @@ type ConnCtrlOption struct {
 	MaxConnInBound      uint
 	MaxConnInBoundPerIP uint
 	ReservedPeers       p2p.AddressFilter // enabled if not empty
+	SecureTransport     bool              // encrypt and authenticate links with peers supporting it
+	BannedPeers         p2p.AddressFilter // banned by ip
 	dialer              Dialer
 }
 
@@ -36,6 +38,7 @@ func NewConnCtrlOption() ConnCtrlOption {
 		MaxConnOutBound:     config.DEFAULT_MAX_CONN_OUT_BOUND,
 		MaxConnInBoundPerIP: config.DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP,
 		ReservedPeers:       p2p.AllAddrFilter(),
+		BannedPeers:         p2p.NoneAddrFilter(),
 		dialer:              &noTlsDialer{},
 	}
 }
@@ -76,7 +79,19 @@ func ConnCtrlOptionFromConfig(config *config.P2PNodeConfig, reserveFilter p2p.Ad
 		MaxConnInBound:      config.MaxConnInBound,
 		MaxConnInBoundPerIP: config.MaxConnInBoundForSingleIP,
 		ReservedPeers:       reserveFilter,
+		SecureTransport:     config.SecureTransport,
+		BannedPeers:         p2p.NoneAddrFilter(),
 
 		dialer: dialer,
 	}, nil
 }
+
+func (self ConnCtrlOption) WithSecureTransport(enable bool) ConnCtrlOption {
+	self.SecureTransport = enable
+	return self
+}
+
+func (self ConnCtrlOption) WithBanFilter(filter p2p.AddressFilter) ConnCtrlOption {
+	self.BannedPeers = filter
+	return self
+}
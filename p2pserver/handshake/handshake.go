@@ -31,10 +31,12 @@ import (
 
 var HANDSHAKE_DURATION = 10 * time.Second // handshake time can not exceed this duration, or will treat as attack.
 
-func HandshakeClient(info *peer.PeerInfo, selfId *common.PeerKeyId, conn net.Conn) (*peer.PeerInfo, error) {
-	version := newVersion(info)
+//HandshakeClient return the remote peer info and the connection used after handshake, which is encrypted
+//when secure transport is negotiated
+func HandshakeClient(info *peer.PeerInfo, selfId *common.PeerKeyId, conn net.Conn, secure bool) (*peer.PeerInfo, net.Conn, error) {
+	version := newVersion(info, secure)
 	if err := conn.SetDeadline(time.Now().Add(HANDSHAKE_DURATION)); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer func() {
 		_ = conn.SetDeadline(time.Time{}) //reset back
@@ -43,34 +45,41 @@ func HandshakeClient(info *peer.PeerInfo, selfId *common.PeerKeyId, conn net.Con
 	// 1. sendMsg version
 	err := sendMsg(conn, version)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 2. read version
 	msg, _, err := types.ReadMessage(conn)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	receivedVersion, ok := msg.(*types.Version)
 	if !ok {
-		return nil, fmt.Errorf("expected version message, but got message type: %s", msg.CmdType())
+		return nil, nil, fmt.Errorf("expected version message, but got message type: %s", msg.CmdType())
 	}
 
 	// 3. update kadId
 	kid := common.PseudoPeerIdFromUint64(receivedVersion.P.Nonce)
-	if useDHT(receivedVersion.P.SoftVersion, info.SoftVersion) {
+	if useSecureTransport(version, receivedVersion) {
+		// 3-4. key exchange and authenticate kadId in secure channel
+		secConn, secKid, err := secureClient(conn, selfId, version, receivedVersion)
+		if err != nil {
+			return nil, nil, err
+		}
+		conn, kid = secConn, secKid
+	} else if useDHT(receivedVersion.P.SoftVersion, info.SoftVersion) {
 		err = sendMsg(conn, &types.UpdatePeerKeyId{KadKeyId: selfId})
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		// 4. read kadkeyid
 		msg, _, err = types.ReadMessage(conn)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		kadKeyId, ok := msg.(*types.UpdatePeerKeyId)
 		if !ok {
-			return nil, fmt.Errorf("handshake failed, expect kad id message, got %s", msg.CmdType())
+			return nil, nil, fmt.Errorf("handshake failed, expect kad id message, got %s", msg.CmdType())
 		}
 
 		kid = kadKeyId.KadKeyId.Id
@@ -79,26 +88,28 @@ func HandshakeClient(info *peer.PeerInfo, selfId *common.PeerKeyId, conn net.Con
 	// 5. sendMsg ack
 	err = sendMsg(conn, &types.VerACK{})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	msg, _, err = types.ReadMessage(conn)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 6. receive verack
 	if _, ok := msg.(*types.VerACK); !ok {
-		return nil, fmt.Errorf("handshake failed, expect verack message, got %s", msg.CmdType())
+		return nil, nil, fmt.Errorf("handshake failed, expect verack message, got %s", msg.CmdType())
 	}
 
-	return createPeerInfo(receivedVersion, kid, conn.RemoteAddr().String()), nil
+	return createPeerInfo(receivedVersion, kid, conn.RemoteAddr().String()), conn, nil
 }
 
-func HandshakeServer(info *peer.PeerInfo, selfId *common.PeerKeyId, conn net.Conn) (*peer.PeerInfo, error) {
-	ver := newVersion(info)
+//HandshakeServer return the remote peer info and the connection used after handshake, which is encrypted
+//when secure transport is negotiated
+func HandshakeServer(info *peer.PeerInfo, selfId *common.PeerKeyId, conn net.Conn, secure bool) (*peer.PeerInfo, net.Conn, error) {
+	ver := newVersion(info, secure)
 	if err := conn.SetDeadline(time.Now().Add(HANDSHAKE_DURATION)); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer func() {
 		_ = conn.SetDeadline(time.Time{}) //reset back
@@ -107,54 +118,61 @@ func HandshakeServer(info *peer.PeerInfo, selfId *common.PeerKeyId, conn net.Con
 	// 1. read version
 	msg, _, err := types.ReadMessage(conn)
 	if err != nil {
-		return nil, fmt.Errorf("[HandshakeServer] ReadMessage failed, error: %s", err)
+		return nil, nil, fmt.Errorf("[HandshakeServer] ReadMessage failed, error: %s", err)
 	}
 	if msg.CmdType() != common.VERSION_TYPE {
-		return nil, fmt.Errorf("[HandshakeServer] expected version message")
+		return nil, nil, fmt.Errorf("[HandshakeServer] expected version message")
 	}
 	version := msg.(*types.Version)
 
 	// 2. sendMsg version
 	err = sendMsg(conn, ver)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 3. read update kadkey id
 	kid := common.PseudoPeerIdFromUint64(version.P.Nonce)
-	if useDHT(version.P.SoftVersion, info.SoftVersion) {
+	if useSecureTransport(ver, version) {
+		// 3-4. key exchange and authenticate kadkey id in secure channel
+		secConn, secKid, err := secureServer(conn, selfId, ver, version)
+		if err != nil {
+			return nil, nil, fmt.Errorf("[HandshakeServer] secure handshake failed, error: %s", err)
+		}
+		conn, kid = secConn, secKid
+	} else if useDHT(version.P.SoftVersion, info.SoftVersion) {
 		msg, _, err := types.ReadMessage(conn)
 		if err != nil {
-			return nil, fmt.Errorf("[HandshakeServer] ReadMessage failed, error: %s", err)
+			return nil, nil, fmt.Errorf("[HandshakeServer] ReadMessage failed, error: %s", err)
 		}
 		kadkeyId, ok := msg.(*types.UpdatePeerKeyId)
 		if !ok {
-			return nil, fmt.Errorf("[HandshakeServer] expected update kadkeyid message")
+			return nil, nil, fmt.Errorf("[HandshakeServer] expected update kadkeyid message")
 		}
 		kid = kadkeyId.KadKeyId.Id
 		// 4. sendMsg update kadkey id
 		err = sendMsg(conn, &types.UpdatePeerKeyId{KadKeyId: selfId})
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	// 5. read version ack
 	msg, _, err = types.ReadMessage(conn)
 	if err != nil {
-		return nil, fmt.Errorf("[HandshakeServer] ReadMessage failed, error: %s", err)
+		return nil, nil, fmt.Errorf("[HandshakeServer] ReadMessage failed, error: %s", err)
 	}
 	if msg.CmdType() != common.VERACK_TYPE {
-		return nil, fmt.Errorf("[HandshakeServer] expected version ack message")
+		return nil, nil, fmt.Errorf("[HandshakeServer] expected version ack message")
 	}
 
 	// 6. sendMsg ack
 	err = sendMsg(conn, &types.VerACK{})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return createPeerInfo(version, kid, conn.RemoteAddr().String()), nil
+	return createPeerInfo(version, kid, conn.RemoteAddr().String()), conn, nil
 }
 
 func sendMsg(conn net.Conn, msg types.Message) error {
@@ -173,7 +191,7 @@ func createPeerInfo(version *types.Version, kid common.PeerId, addr string) *pee
 		version.P.SyncPort, version.P.StartHeight, version.P.SoftVersion, addr)
 }
 
-func newVersion(peerInfo *peer.PeerInfo) *types.Version {
+func newVersion(peerInfo *peer.PeerInfo, secure bool) *types.Version {
 	var version types.Version
 	version.P = types.VersionPayload{
 		Version:      peerInfo.Version,
@@ -197,6 +215,9 @@ func newVersion(peerInfo *peer.PeerInfo) *types.Version {
 	} else {
 		version.P.Cap[common.HTTP_INFO_FLAG] = 0x00
 	}
+	if secure {
+		version.P.Cap[common.SECURE_TRANSPORT_FLAG] = 0x01
+	}
 
 	return &version
 }
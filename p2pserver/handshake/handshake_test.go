@@ -18,12 +18,15 @@
 package handshake
 
 import (
+	"bytes"
+	"io"
 	"math/rand"
 	"net"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/blang/semver"
 	"github.com/ontio/ontology/p2pserver/common"
 	"github.com/ontio/ontology/p2pserver/message/types"
 	"github.com/ontio/ontology/p2pserver/peer"
@@ -76,13 +79,13 @@ func TestHandshakeNormal(t *testing.T) {
 			err  error
 		}, 2)
 		go func() {
-			info, err := HandshakeClient(client.Info, client.Id, client.Conn)
+			info, _, err := HandshakeClient(client.Info, client.Id, client.Conn, false)
 			result[0].err = err
 			result[0].info = [2]*peer.PeerInfo{info, server.Info}
 			wg.Done()
 		}()
 		go func() {
-			info, err := HandshakeServer(server.Info, server.Id, server.Conn)
+			info, _, err := HandshakeServer(server.Info, server.Id, server.Conn, false)
 			result[1].err = err
 			result[1].info = [2]*peer.PeerInfo{info, client.Info}
 			wg.Done()
@@ -99,7 +102,7 @@ func TestHandshakeNormal(t *testing.T) {
 func TestHandshakeTimeout(t *testing.T) {
 	client, _ := NewPair()
 
-	_, err := HandshakeClient(client.Info, client.Id, client.Conn)
+	_, _, err := HandshakeClient(client.Info, client.Id, client.Conn, false)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "i/o timeout") // golang 1.5 error msg changed
 }
@@ -111,7 +114,7 @@ func TestHandshakeWrongMsg(t *testing.T) {
 		assert.Nil(t, err)
 	}()
 
-	_, err := HandshakeServer(server.Info, server.Id, server.Conn)
+	_, _, err := HandshakeServer(server.Info, server.Id, server.Conn, false)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "expected version message")
 }
@@ -132,3 +135,106 @@ func TestVersion(t *testing.T) {
 	assert.False(t, supportDHT("1.8.0-beta-9-geeaeewwf"))
 	assert.False(t, supportDHT("1.8.0"))
 }
+
+func handshakePair(client, server Node, clientSecure, serverSecure bool) (conns [2]net.Conn, infos [2]*peer.PeerInfo, errs [2]error) {
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		infos[0], conns[0], errs[0] = HandshakeClient(client.Info, client.Id, client.Conn, clientSecure)
+		if errs[0] != nil {
+			_ = client.Conn.Close()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		infos[1], conns[1], errs[1] = HandshakeServer(server.Info, server.Id, server.Conn, serverSecure)
+		if errs[1] != nil {
+			_ = server.Conn.Close()
+		}
+	}()
+	wg.Wait()
+	return
+}
+
+func TestHandshakeSecure(t *testing.T) {
+	client, server := NewPair()
+	client.Info.SoftVersion = "v2.0.0"
+	server.Info.SoftVersion = "v2.0.0"
+
+	conns, infos, errs := handshakePair(client, server, true, true)
+	assert.Nil(t, errs[0])
+	assert.Nil(t, errs[1])
+	assert.Equal(t, server.Id.Id, infos[0].Id)
+	assert.Equal(t, client.Id.Id, infos[1].Id)
+	assert.IsType(t, &secureConn{}, conns[0])
+	assert.IsType(t, &secureConn{}, conns[1])
+
+	data := make([]byte, 3*maxSecureFramePayload+100)
+	rand.Read(data)
+	go func() {
+		_, err := conns[0].Write(data)
+		assert.Nil(t, err)
+	}()
+	received := make([]byte, len(data))
+	_, err := io.ReadFull(conns[1], received)
+	assert.Nil(t, err)
+	assert.True(t, bytes.Equal(data, received))
+}
+
+func TestHandshakeSecureFallback(t *testing.T) {
+	cases := []struct {
+		clientVersion, serverVersion string
+		clientSecure, serverSecure   bool
+	}{
+		{"v2.0.0", "v2.0.0", true, false},
+		{"v2.0.0", "v2.0.0", false, true},
+		{"v2.0.0", "v1.9.0", true, true},
+		{"v1.9.0", "v2.0.0", true, true},
+		{"v1.8.0", "v2.0.0", true, true},
+	}
+	for _, c := range cases {
+		client, server := NewPair()
+		client.Info.SoftVersion = c.clientVersion
+		server.Info.SoftVersion = c.serverVersion
+
+		conns, infos, errs := handshakePair(client, server, c.clientSecure, c.serverSecure)
+		assert.Nil(t, errs[0])
+		assert.Nil(t, errs[1])
+		assert.Equal(t, server.Id.Id.ToUint64(), infos[0].Id.ToUint64())
+		assert.Equal(t, client.Id.Id.ToUint64(), infos[1].Id.ToUint64())
+		assert.Equal(t, client.Conn, conns[0])
+		assert.Equal(t, server.Conn, conns[1])
+	}
+}
+
+func TestHandshakeSecureImpersonation(t *testing.T) {
+	client, server := NewPair()
+	client.Info.SoftVersion = "v2.0.0"
+	server.Info.SoftVersion = "v2.0.0"
+	// server claims the key id of another peer without owning its private key
+	victim := common.RandPeerKeyId()
+	forged := *server.Id
+	forged.PublicKey = victim.PublicKey
+	forged.Id = victim.Id
+	server.Id = &forged
+
+	_, _, errs := handshakePair(client, server, true, true)
+	assert.NotNil(t, errs[0])
+	assert.Contains(t, errs[0].Error(), "invalid peer key signature")
+}
+
+func TestSecureTransportVersion(t *testing.T) {
+	_, err := semver.ParseTolerant(common.MIN_VERSION_FOR_SECURE_TRANSPORT)
+	assert.Nil(t, err)
+
+	info := &peer.PeerInfo{SoftVersion: "v2.0.0"}
+	assert.True(t, supportSecureTransport(newVersion(info, true)))
+	assert.False(t, supportSecureTransport(newVersion(info, false)))
+	info.SoftVersion = "v2.1.0"
+	assert.True(t, supportSecureTransport(newVersion(info, true)))
+	info.SoftVersion = "v1.9.1"
+	assert.False(t, supportSecureTransport(newVersion(info, true)))
+	info.SoftVersion = ""
+	assert.False(t, supportSecureTransport(newVersion(info, true)))
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handshake
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/blang/semver"
+	common2 "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/p2pserver/common"
+	"github.com/ontio/ontology/p2pserver/message/types"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	secureProtocolName = "ontology-p2p-secure-v1"
+	secureKeyLen       = 32
+)
+
+const (
+	roleClient = "client"
+	roleServer = "server"
+)
+
+//the secure transport is a noise style handshake: both side exchange ephemeral x25519 keys after version,
+//derive per-direction keys from the shared secret and the transcript, then prove the ownership of the
+//kad key id by signing the transcript inside the encrypted channel
+type secureSession struct {
+	transcript [sha256.Size]byte
+	clientKey  []byte // key of client to server direction
+	serverKey  []byte // key of server to client direction
+}
+
+func useSecureTransport(local, remote *types.Version) bool {
+	// symmetric as useDHT, both side must reach the same decision from the two version messages
+	return supportSecureTransport(local) && supportSecureTransport(remote)
+}
+
+func supportSecureTransport(version *types.Version) bool {
+	if version.P.Cap[common.SECURE_TRANSPORT_FLAG] == 0 || version.P.SoftVersion == "" {
+		return false
+	}
+	v1, err := semver.ParseTolerant(version.P.SoftVersion)
+	if err != nil {
+		return false
+	}
+	min, err := semver.ParseTolerant(common.MIN_VERSION_FOR_SECURE_TRANSPORT)
+	if err != nil {
+		panic(err) // enforced by testcase
+	}
+
+	return v1.GTE(min)
+}
+
+func secureClient(conn net.Conn, selfId *common.PeerKeyId, local, remote *types.Version) (net.Conn, common.PeerId, error) {
+	private, public, err := newEphemeralKey()
+	if err != nil {
+		return nil, common.PeerId{}, err
+	}
+	if err = sendMsg(conn, &types.SecureHello{EphemeralKey: public}); err != nil {
+		return nil, common.PeerId{}, err
+	}
+	hello, err := readSecureHello(conn)
+	if err != nil {
+		return nil, common.PeerId{}, err
+	}
+	session, err := newSecureSession(private, hello.EphemeralKey, local, remote, public, hello.EphemeralKey)
+	if err != nil {
+		return nil, common.PeerId{}, err
+	}
+	secConn, err := newSecureConn(conn, session.clientKey, session.serverKey)
+	if err != nil {
+		return nil, common.PeerId{}, err
+	}
+
+	if err = sendPeerKeyAuth(secConn, selfId, session, roleClient); err != nil {
+		return nil, common.PeerId{}, err
+	}
+	kid, err := readPeerKeyAuth(secConn, session, roleServer)
+	if err != nil {
+		return nil, common.PeerId{}, err
+	}
+
+	return secConn, kid, nil
+}
+
+func secureServer(conn net.Conn, selfId *common.PeerKeyId, local, remote *types.Version) (net.Conn, common.PeerId, error) {
+	hello, err := readSecureHello(conn)
+	if err != nil {
+		return nil, common.PeerId{}, err
+	}
+	private, public, err := newEphemeralKey()
+	if err != nil {
+		return nil, common.PeerId{}, err
+	}
+	if err = sendMsg(conn, &types.SecureHello{EphemeralKey: public}); err != nil {
+		return nil, common.PeerId{}, err
+	}
+	session, err := newSecureSession(private, hello.EphemeralKey, remote, local, hello.EphemeralKey, public)
+	if err != nil {
+		return nil, common.PeerId{}, err
+	}
+	secConn, err := newSecureConn(conn, session.serverKey, session.clientKey)
+	if err != nil {
+		return nil, common.PeerId{}, err
+	}
+
+	kid, err := readPeerKeyAuth(secConn, session, roleClient)
+	if err != nil {
+		return nil, common.PeerId{}, err
+	}
+	if err = sendPeerKeyAuth(secConn, selfId, session, roleServer); err != nil {
+		return nil, common.PeerId{}, err
+	}
+
+	return secConn, kid, nil
+}
+
+func newEphemeralKey() (private, public [types.SECURE_EPHEMERAL_KEY_LEN]byte, err error) {
+	if _, err = io.ReadFull(rand.Reader, private[:]); err != nil {
+		return
+	}
+	pub, err := curve25519.X25519(private[:], curve25519.Basepoint)
+	if err != nil {
+		return
+	}
+	copy(public[:], pub)
+	return
+}
+
+//newSecureSession derive the session keys, the transcript binds the version messages and ephemeral keys of
+//both side, so the negotiation result can not be tampered without breaking the key id authentication
+func newSecureSession(private, remotePublic [types.SECURE_EPHEMERAL_KEY_LEN]byte, clientVer, serverVer *types.Version,
+	clientEphemeral, serverEphemeral [types.SECURE_EPHEMERAL_KEY_LEN]byte) (*secureSession, error) {
+	shared, err := curve25519.X25519(private[:], remotePublic[:])
+	if err != nil {
+		return nil, fmt.Errorf("[handshake] invalid ephemeral key: %s", err)
+	}
+
+	sink := common2.NewZeroCopySink(nil)
+	sink.WriteString(secureProtocolName)
+	clientVer.Serialization(sink)
+	serverVer.Serialization(sink)
+	sink.WriteBytes(clientEphemeral[:])
+	sink.WriteBytes(serverEphemeral[:])
+	session := &secureSession{
+		transcript: sha256.Sum256(sink.Bytes()),
+		clientKey:  make([]byte, secureKeyLen),
+		serverKey:  make([]byte, secureKeyLen),
+	}
+
+	kdf := hkdf.New(sha256.New, shared, session.transcript[:], []byte(secureProtocolName))
+	if _, err := io.ReadFull(kdf, session.clientKey); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(kdf, session.serverKey); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (self *secureSession) authData(role string) []byte {
+	sink := common2.NewZeroCopySink(nil)
+	sink.WriteString(secureProtocolName)
+	sink.WriteString(role)
+	sink.WriteBytes(self.transcript[:])
+	return sink.Bytes()
+}
+
+func readSecureHello(conn net.Conn) (*types.SecureHello, error) {
+	msg, _, err := types.ReadMessage(conn)
+	if err != nil {
+		return nil, err
+	}
+	hello, ok := msg.(*types.SecureHello)
+	if !ok {
+		return nil, fmt.Errorf("handshake failed, expect secure hello message, got %s", msg.CmdType())
+	}
+	return hello, nil
+}
+
+func sendPeerKeyAuth(conn net.Conn, selfId *common.PeerKeyId, session *secureSession, role string) error {
+	sig, err := selfId.Sign(session.authData(role))
+	if err != nil {
+		return err
+	}
+	return sendMsg(conn, &types.PeerKeyAuth{KadKeyId: selfId, Signature: sig})
+}
+
+func readPeerKeyAuth(conn net.Conn, session *secureSession, role string) (common.PeerId, error) {
+	msg, _, err := types.ReadMessage(conn)
+	if err != nil {
+		return common.PeerId{}, err
+	}
+	auth, ok := msg.(*types.PeerKeyAuth)
+	if !ok {
+		return common.PeerId{}, fmt.Errorf("handshake failed, expect peer key auth message, got %s", msg.CmdType())
+	}
+	if err := auth.KadKeyId.Verify(session.authData(role), auth.Signature); err != nil {
+		return common.PeerId{}, fmt.Errorf("handshake failed, invalid peer key signature: %s", err)
+	}
+	return auth.KadKeyId.Id, nil
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handshake
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"sync"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	secureFrameHdrLen     = 2
+	maxSecureFrameLen     = math.MaxUint16
+	maxSecureFramePayload = maxSecureFrameLen - chacha20poly1305.Overhead
+)
+
+var errNonceExhausted = errors.New("secure transport nonce exhausted")
+
+//secureConn encrypts the stream with per-direction keys, each frame is a 2 bytes big endian length
+//followed by the sealed payload. the nonce is the frame counter, so frames can not be replayed or reordered
+type secureConn struct {
+	net.Conn
+
+	writeLock sync.Mutex
+	sendAead  cipher.AEAD
+	sendNonce uint64
+
+	readLock  sync.Mutex
+	recvAead  cipher.AEAD
+	recvNonce uint64
+	readBuf   []byte // decrypted but unread payload
+}
+
+func newSecureConn(conn net.Conn, sendKey, recvKey []byte) (*secureConn, error) {
+	sendAead, err := chacha20poly1305.New(sendKey)
+	if err != nil {
+		return nil, err
+	}
+	recvAead, err := chacha20poly1305.New(recvKey)
+	if err != nil {
+		return nil, err
+	}
+	return &secureConn{
+		Conn:     conn,
+		sendAead: sendAead,
+		recvAead: recvAead,
+	}, nil
+}
+
+func frameNonce(counter uint64) []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.LittleEndian.PutUint64(nonce, counter)
+	return nonce
+}
+
+func (self *secureConn) Read(b []byte) (int, error) {
+	self.readLock.Lock()
+	defer self.readLock.Unlock()
+
+	if len(self.readBuf) == 0 {
+		if err := self.readFrame(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, self.readBuf)
+	self.readBuf = self.readBuf[n:]
+	return n, nil
+}
+
+func (self *secureConn) readFrame() error {
+	var hdr [secureFrameHdrLen]byte
+	if _, err := io.ReadFull(self.Conn, hdr[:]); err != nil {
+		return err
+	}
+	frame := make([]byte, binary.BigEndian.Uint16(hdr[:]))
+	if _, err := io.ReadFull(self.Conn, frame); err != nil {
+		return err
+	}
+	if self.recvNonce == math.MaxUint64 {
+		return errNonceExhausted
+	}
+	payload, err := self.recvAead.Open(frame[:0], frameNonce(self.recvNonce), frame, hdr[:])
+	if err != nil {
+		return err
+	}
+	self.recvNonce += 1
+	self.readBuf = payload
+	return nil
+}
+
+func (self *secureConn) Write(b []byte) (int, error) {
+	self.writeLock.Lock()
+	defer self.writeLock.Unlock()
+
+	written := 0
+	for written < len(b) {
+		end := written + maxSecureFramePayload
+		if end > len(b) {
+			end = len(b)
+		}
+		if err := self.writeFrame(b[written:end]); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return written, nil
+}
+
+func (self *secureConn) writeFrame(payload []byte) error {
+	if self.sendNonce == math.MaxUint64 {
+		return errNonceExhausted
+	}
+	frame := make([]byte, secureFrameHdrLen, secureFrameHdrLen+len(payload)+chacha20poly1305.Overhead)
+	binary.BigEndian.PutUint16(frame, uint16(len(payload)+chacha20poly1305.Overhead))
+	frame = self.sendAead.Seal(frame, frameNonce(self.sendNonce), payload, frame[:secureFrameHdrLen])
+	self.sendNonce += 1
+	_, err := self.Conn.Write(frame)
+	return err
+}
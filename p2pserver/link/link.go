@@ -20,6 +20,7 @@ package link
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"net"
@@ -29,6 +30,7 @@ import (
 
 	comm "github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/common/metrics"
 	"github.com/ontio/ontology/p2pserver/common"
 	"github.com/ontio/ontology/p2pserver/message/types"
 )
@@ -43,6 +45,8 @@ type Link struct {
 	time      int64                  // The latest time the node activity
 	recvChan  chan *types.MsgPayload //msgpayload channel
 	reqRecord map[string]int64       //Map RequestId to Timestamp, using for rejecting duplicate request in specific time
+
+	malformedHandler func(err error) //called before the link closed by a malformed message
 }
 
 func NewLink(id common.PeerId, c net.Conn, msgChan chan *types.MsgPayload) *Link {
@@ -77,6 +81,11 @@ func (this *Link) SetConn(conn net.Conn) {
 	this.conn = conn
 }
 
+//set the handler called when the peer sends a malformed message, must be set before Rx
+func (this *Link) SetMalformedHandler(handler func(err error)) {
+	this.malformedHandler = handler
+}
+
 //record latest message time
 func (this *Link) UpdateRXTime(t time.Time) {
 	atomic.StoreInt64(&this.time, t.UnixNano())
@@ -99,13 +108,18 @@ func (this *Link) Rx() {
 		msg, payloadSize, err := types.ReadMessage(reader)
 		if err != nil {
 			log.Infof("[p2p]error read from %s :%s", this.GetAddr(), err.Error())
+			if errors.Is(err, types.ErrMalformedMessage) && this.malformedHandler != nil {
+				this.malformedHandler(err)
+			}
 			break
 		}
 
 		if unknown, ok := msg.(*types.UnknownMessage); ok {
 			log.Infof("skip handle unknown msg type:%s from:%d", unknown.CmdType(), this.id)
+			countMessage("unknown", metrics.DIRECTION_IN, payloadSize)
 			continue
 		}
+		countMessage(msg.CmdType(), metrics.DIRECTION_IN, payloadSize)
 
 		t := time.Now()
 		this.UpdateRXTime(t)
@@ -165,10 +179,19 @@ func (this *Link) SendRaw(rawPacket []byte) error {
 		this.CloseConn()
 		return err
 	}
+	if nByteCnt >= common.MSG_HDR_LEN {
+		cmd := bytes.TrimRight(rawPacket[comm.UINT32_SIZE:comm.UINT32_SIZE+common.MSG_CMD_LEN], "\x00")
+		countMessage(string(cmd), metrics.DIRECTION_OUT, uint32(nByteCnt-common.MSG_HDR_LEN))
+	}
 
 	return nil
 }
 
+func countMessage(cmd string, direction string, payloadSize uint32) {
+	metrics.P2PMessages.WithLabelValues(cmd, direction).Inc()
+	metrics.P2PMessageBytes.WithLabelValues(cmd, direction).Add(float64(payloadSize))
+}
+
 //needSendMsg check whether the msg is needed to push to channel
 func (this *Link) needSendMsg(msg types.Message) bool {
 	if msg.CmdType() != common.GET_DATA_TYPE {
@@ -20,6 +20,7 @@ package types
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 
@@ -28,6 +29,9 @@ import (
 	"github.com/ontio/ontology/p2pserver/common"
 )
 
+//ErrMalformedMessage is wrapped by the errors of messages which can not be decoded, while io errors are not
+var ErrMalformedMessage = errors.New("malformed message")
+
 type Message interface {
 	Serialization(sink *comm.ZeroCopySink)
 	Deserialization(source *comm.ZeroCopySource) error
@@ -127,12 +131,12 @@ func ReadMessage(reader io.Reader) (Message, uint32, error) {
 
 	magic := config.DefConfig.P2PNode.NetworkMagic
 	if hdr.Magic != magic {
-		return nil, 0, fmt.Errorf("unmatched magic number %d, expected %d", hdr.Magic, magic)
+		return nil, 0, fmt.Errorf("%w: unmatched magic number %d, expected %d", ErrMalformedMessage, hdr.Magic, magic)
 	}
 
 	if hdr.Length > common.MAX_PAYLOAD_LEN {
-		return nil, 0, fmt.Errorf("msg payload length:%d exceed max payload size: %d",
-			hdr.Length, common.MAX_PAYLOAD_LEN)
+		return nil, 0, fmt.Errorf("%w: msg payload length:%d exceed max payload size: %d",
+			ErrMalformedMessage, hdr.Length, common.MAX_PAYLOAD_LEN)
 	}
 
 	buf := make([]byte, hdr.Length)
@@ -143,7 +147,7 @@ func ReadMessage(reader io.Reader) (Message, uint32, error) {
 
 	checksum := common.Checksum(buf)
 	if checksum != hdr.Checksum {
-		return nil, 0, fmt.Errorf("message checksum mismatch: %x != %x ", hdr.Checksum, checksum)
+		return nil, 0, fmt.Errorf("%w: message checksum mismatch: %x != %x ", ErrMalformedMessage, hdr.Checksum, checksum)
 	}
 
 	cmdType := string(bytes.TrimRight(hdr.CMD[:], string(rune(0))))
@@ -153,7 +157,7 @@ func ReadMessage(reader io.Reader) (Message, uint32, error) {
 	source := comm.NewZeroCopySource(buf)
 	err = msg.Deserialization(source)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, fmt.Errorf("%w: %s: %s", ErrMalformedMessage, cmdType, err)
 	}
 
 	return msg, hdr.Length, nil
@@ -197,6 +201,10 @@ func makeEmptyMessage(cmdType string) Message {
 		return &FindNodeResp{}
 	case common.UPDATE_KADID_TYPE:
 		return &UpdatePeerKeyId{}
+	case common.SECURE_HELLO_TYPE:
+		return &SecureHello{}
+	case common.PEER_KEY_AUTH_TYPE:
+		return &PeerKeyAuth{}
 	case common.GET_SUBNET_MEMBERS_TYPE:
 		return &SubnetMembersRequest{}
 	case common.SUBNET_MEMBERS_TYPE:
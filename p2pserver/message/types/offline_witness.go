@@ -141,8 +141,9 @@ func (self *OfflineWitnessMsg) Hash() common.Uint256 {
 }
 
 func (self *OfflineWitnessMsg) AddProposeSig(acct *account.Account) error {
-	hash := self.Hash()
-	sig, err := signature.Sign(acct, hash[:])
+	sink := common.NewZeroCopySink(nil)
+	self.serializeUnsigned(sink)
+	sig, err := signature.SignPayload(acct, signature.PAYLOAD_WITNESS, sink.Bytes())
 	if err != nil {
 		return err
 	}
@@ -155,8 +156,7 @@ func (self *OfflineWitnessMsg) VoteFor(acct *account.Account, index []uint8) err
 	sink := common.NewZeroCopySink(nil)
 	self.serializeUnsigned(sink)
 	sink.WriteVarBytes(index)
-	hash := common.Uint256(sha256.Sum256(sink.Bytes()))
-	sig, err := signature.Sign(acct, hash[:])
+	sig, err := signature.SignPayload(acct, signature.PAYLOAD_WITNESS, sink.Bytes())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"io"
+
+	common2 "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/p2pserver/common"
+)
+
+const SECURE_EPHEMERAL_KEY_LEN = 32
+
+//SecureHello carry the ephemeral key of secure transport handshake
+type SecureHello struct {
+	EphemeralKey [SECURE_EPHEMERAL_KEY_LEN]byte
+}
+
+//Serialize message payload
+func (this *SecureHello) Serialization(sink *common2.ZeroCopySink) {
+	sink.WriteBytes(this.EphemeralKey[:])
+}
+
+func (this *SecureHello) Deserialization(source *common2.ZeroCopySource) error {
+	buf, eof := source.NextBytes(SECURE_EPHEMERAL_KEY_LEN)
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	copy(this.EphemeralKey[:], buf)
+	return nil
+}
+
+func (this *SecureHello) CmdType() string {
+	return common.SECURE_HELLO_TYPE
+}
+
+//PeerKeyAuth prove the ownership of kad key id by signing the handshake transcript
+type PeerKeyAuth struct {
+	KadKeyId  *common.PeerKeyId
+	Signature []byte
+}
+
+//Serialize message payload
+func (this *PeerKeyAuth) Serialization(sink *common2.ZeroCopySink) {
+	this.KadKeyId.Serialization(sink)
+	sink.WriteVarBytes(this.Signature)
+}
+
+func (this *PeerKeyAuth) Deserialization(source *common2.ZeroCopySource) error {
+	this.KadKeyId = &common.PeerKeyId{}
+	if err := this.KadKeyId.Deserialization(source); err != nil {
+		return err
+	}
+	sig, _, irregular, eof := source.NextVarBytes()
+	if irregular {
+		return common2.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Signature = sig
+	return nil
+}
+
+func (this *PeerKeyAuth) CmdType() string {
+	return common.PEER_KEY_AUTH_TYPE
+}
@@ -77,7 +77,7 @@ func NewMembersRequest(from, to common.PeerId, acc *account.Account) (*SubnetMem
 		PubKey:    acc.PublicKey,
 	}
 
-	sig, err := signature.Sign(acc, request.sigdata())
+	sig, err := signature.SignPayload(acc, signature.PAYLOAD_SUBNET, request.sigdata())
 	if err != nil {
 		return nil, err
 	}
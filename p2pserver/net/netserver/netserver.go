@@ -33,7 +33,8 @@ import (
 )
 
 //NewNetServer return the net object in p2p
-func NewNetServer(protocol p2p.Protocol, conf *config.P2PNodeConfig, reserveAddrFilter p2p.AddressFilter) (*NetServer, error) {
+func NewNetServer(protocol p2p.Protocol, conf *config.P2PNodeConfig, reserveAddrFilter p2p.AddressFilter,
+	banFilter p2p.AddressFilter) (*NetServer, error) {
 	nodePort := conf.NodePort
 	if nodePort == 0 {
 		nodePort = config.DEFAULT_NODE_PORT
@@ -47,6 +48,7 @@ func NewNetServer(protocol p2p.Protocol, conf *config.P2PNodeConfig, reserveAddr
 	if err != nil {
 		return nil, err
 	}
+	option = option.WithBanFilter(banFilter)
 
 	listener, err := connect_controller.NewListener(nodePort, conf)
 	if err != nil {
@@ -210,6 +212,7 @@ func (this *NetServer) connect(addr string) error {
 	remotePeer := peer.NewPeer(peerInfo, conn, this.NetChan)
 
 	this.ReplacePeer(remotePeer)
+	this.watchMalformedMessage(remotePeer)
 	go remotePeer.Link.Rx()
 
 	this.protocol.HandleSystemMessage(this, p2p.PeerConnected{Info: remotePeer.Info})
@@ -220,6 +223,12 @@ func (this *NetServer) notifyPeerConnected(p *peer.PeerInfo) {
 	this.protocol.HandleSystemMessage(this, p2p.PeerConnected{Info: p})
 }
 
+func (this *NetServer) watchMalformedMessage(p *peer.Peer) {
+	p.Link.SetMalformedHandler(func(err error) {
+		this.protocol.HandleSystemMessage(this, p2p.MalformedMessageReceived{Info: p.Info, Err: err})
+	})
+}
+
 func (this *NetServer) notifyPeerDisconnected(p *peer.PeerInfo) {
 	this.protocol.HandleSystemMessage(this, p2p.PeerDisConnected{Info: p})
 }
@@ -245,6 +254,7 @@ func (this *NetServer) handleClientConnection(conn net.Conn) error {
 	}
 	remotePeer := peer.NewPeer(peerInfo, conn, this.NetChan)
 	this.ReplacePeer(remotePeer)
+	this.watchMalformedMessage(remotePeer)
 
 	go remotePeer.Link.Rx()
 	this.protocol.HandleSystemMessage(this, p2p.PeerConnected{Info: remotePeer.Info})
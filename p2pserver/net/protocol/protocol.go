@@ -63,6 +63,13 @@ type PeerDisConnected struct {
 	implSystemMessage
 }
 
+//MalformedMessageReceived is notified before the link to peer is closed for the malformed message
+type MalformedMessageReceived struct {
+	Info *peer.PeerInfo
+	Err  error
+	implSystemMessage
+}
+
 type NetworkStart struct {
 	implSystemMessage
 }
@@ -58,7 +58,7 @@ func NewServer(acct *account.Account, txpool common2.TxPoolService) (*P2PServer,
 	protocol := protocols.NewMsgHandler(acct, connect_controller.NewStaticReserveFilter(recRsv), db, txpool, common.NewGlobalLoggerWrapper())
 	reserved := protocol.GetReservedAddrFilter(len(rsv) != 0)
 	reservedPeers := p2p.CombineAddrFilter(staticFilter, reserved)
-	n, err := netserver.NewNetServer(protocol, conf, reservedPeers)
+	n, err := netserver.NewNetServer(protocol, conf, reservedPeers, protocol.GetBanFilter())
 	if err != nil {
 		return nil, err
 	}
@@ -116,10 +116,21 @@ func (self *P2PServer) reachMinConnection() bool {
 		minCount = config.SOLO_MIN_NODE_NUM
 	case "vbft":
 		minCount = self.getVbftGovNodeCount()
+	case "sbft":
+		minCount = getSbftQuorum()
 	}
 	return self.network.GetConnectionCnt()+1 >= minCount
 }
 
+//getSbftQuorum returns the quorum of the fixed sbft validators in genesis config
+func getSbftQuorum() uint32 {
+	count := uint32(len(config.DefConfig.Genesis.SBFT.Bookkeepers))
+	if count < config.SBFT_MIN_NODE_NUM {
+		count = config.SBFT_MIN_NODE_NUM
+	}
+	return count - (count-1)/3
+}
+
 func (self *P2PServer) getVbftGovNodeCount() uint32 {
 	view, err := utils.GetGovernanceView(self.db)
 	if err != nil {
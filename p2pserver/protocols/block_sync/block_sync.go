@@ -19,6 +19,7 @@
 package block_sync
 
 import (
+	"errors"
 	"math"
 	"sort"
 	"sync"
@@ -28,11 +29,13 @@ import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/ledger"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/types"
 	p2pComm "github.com/ontio/ontology/p2pserver/common"
 	msgpack "github.com/ontio/ontology/p2pserver/message/msg_pack"
 	p2p "github.com/ontio/ontology/p2pserver/net/protocol"
 	"github.com/ontio/ontology/p2pserver/peer"
+	"github.com/ontio/ontology/p2pserver/protocols/peer_score"
 )
 
 const (
@@ -249,10 +252,11 @@ type BlockSyncMgr struct {
 	ledger         *ledger.Ledger                       //ledger
 	lock           sync.RWMutex                         //lock
 	nodeWeights    map[p2pComm.PeerId]*NodeWeight       //Map NodeID => NodeStatus, using for getNextNode
+	peerScore      *peer_score.PeerScoreService         //Report the invalid headers/blocks from peers
 }
 
 //NewBlockSyncMgr return a BlockSyncMgr instance
-func NewBlockSyncMgr(server p2p.P2P, ld *ledger.Ledger) *BlockSyncMgr {
+func NewBlockSyncMgr(server p2p.P2P, ld *ledger.Ledger, peerScore *peer_score.PeerScoreService) *BlockSyncMgr {
 	return &BlockSyncMgr{
 		flightBlocks:  make(map[common.Uint256][]*SyncFlightInfo),
 		flightHeaders: make(map[uint32]*SyncFlightInfo),
@@ -261,6 +265,7 @@ func NewBlockSyncMgr(server p2p.P2P, ld *ledger.Ledger) *BlockSyncMgr {
 		ledger:        ld,
 		exitCh:        make(chan interface{}, 1),
 		nodeWeights:   make(map[p2pComm.PeerId]*NodeWeight),
+		peerScore:     peerScore,
 	}
 }
 
@@ -574,6 +579,9 @@ func (this *BlockSyncMgr) OnHeaderReceive(fromID p2pComm.PeerId, headers []*type
 		if n != nil && n.GetErrorRespCnt() >= SYNC_MAX_ERROR_RESP_TIMES {
 			this.delNode(fromID)
 		}
+		if errors.Is(err, scom.ErrInvalidBlock) {
+			this.reportPeer(fromID, peer_score.InvalidHeader)
+		}
 		log.Warnf("[block-sync] OnHeaderReceive AddHeaders error:%s", err)
 		return
 	}
@@ -641,6 +649,28 @@ func (this *BlockSyncMgr) OnBlockReceive(fromID p2pComm.PeerId, blockSize uint32
 	this.syncBlock()
 }
 
+//OnNotFound handle the notfound response, which is useless when the block was not requested from the node
+func (this *BlockSyncMgr) OnNotFound(fromID p2pComm.PeerId, addr string, blockHash common.Uint256) {
+	if this.getFlightBlock(blockHash, fromID) != nil {
+		return
+	}
+	if this.peerScore != nil {
+		this.peerScore.Report(fromID, addr, peer_score.UselessNotFound)
+	}
+}
+
+//reportPeer report the behavior of a sync node to peer score
+func (this *BlockSyncMgr) reportPeer(nodeId p2pComm.PeerId, behavior peer_score.Behavior) {
+	if this.peerScore == nil {
+		return
+	}
+	addr := ""
+	if p := this.server.GetPeer(nodeId); p != nil {
+		addr = p.GetAddr()
+	}
+	this.peerScore.Report(nodeId, addr, behavior)
+}
+
 //OnAddPeer to node list when a new node added
 func (this *BlockSyncMgr) OnAddNode(nodeId p2pComm.PeerId) {
 	log.Debugf("[block-sync] OnAddNode:%s", nodeId.ToHexString())
@@ -755,6 +785,10 @@ func (this *BlockSyncMgr) saveBlock() {
 			if n != nil && n.GetErrorRespCnt() >= SYNC_MAX_ERROR_RESP_TIMES {
 				this.delNode(fromID)
 			}
+			//only the invalid data of the peer counts, local store errors are not its fault
+			if errors.Is(err, scom.ErrInvalidBlock) {
+				this.reportPeer(fromID, peer_score.InvalidBlock)
+			}
 			log.Warnf("[block-sync] saveBlock Height:%d AddBlock error:%s", nextBlockHeight, err)
 			reqNode := this.getNextNode(nextBlockHeight)
 			if reqNode == nil {
@@ -771,6 +805,9 @@ func (this *BlockSyncMgr) saveBlock() {
 			}
 			return
 		}
+		if nextBlock.Header.TransactionsRoot != common.UINT256_EMPTY {
+			this.reportPeer(fromID, peer_score.ValidBlock)
+		}
 		nextBlockHeight++
 		this.pingOutsyncNodes(nextBlockHeight - 1)
 	}
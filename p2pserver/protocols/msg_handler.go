@@ -38,6 +38,7 @@ import (
 	"github.com/ontio/ontology/p2pserver/protocols/bootstrap"
 	"github.com/ontio/ontology/p2pserver/protocols/discovery"
 	"github.com/ontio/ontology/p2pserver/protocols/heatbeat"
+	"github.com/ontio/ontology/p2pserver/protocols/peer_score"
 	"github.com/ontio/ontology/p2pserver/protocols/recent_peers"
 	"github.com/ontio/ontology/p2pserver/protocols/reconnect"
 	"github.com/ontio/ontology/p2pserver/protocols/subnet"
@@ -60,6 +61,7 @@ type MsgHandler struct {
 	heatBeat                 *heatbeat.HeartBeat
 	bootstrap                *bootstrap.BootstrapService
 	persistRecentPeerService *recent_peers.PersistRecentPeerService
+	peerScore                *peer_score.PeerScoreService
 	subnet                   *subnet.SubNet
 	ledger                   *ledger.Ledger
 	acct                     *account.Account // nil if conenesus is not enabled
@@ -76,13 +78,21 @@ func NewMsgHandler(acct *account.Account, staticReserveFilter p2p.AddressFilter,
 		panic(fmt.Errorf("invalid seed list； %v", invalid))
 	}
 	subNet := subnet.NewSubNet(acct, seeds, gov, logger)
-	return &MsgHandler{ledger: ld, seeds: seeds, subnet: subNet, acct: acct, txPoolService: txPool, staticReserveFilter: staticReserveFilter}
+	// the reserved and consensus peers are never banned
+	exempt := p2p.CombineAddrFilter(staticReserveFilter, subNet.GetMemberIpFilter())
+	peerScore := peer_score.NewPeerScoreService(msgCommon.BANNED_PEERS_FILE_NAME, exempt)
+	return &MsgHandler{ledger: ld, seeds: seeds, subnet: subNet, acct: acct, txPoolService: txPool,
+		staticReserveFilter: staticReserveFilter, peerScore: peerScore}
 }
 
 func (self *MsgHandler) GetReservedAddrFilter(staticFilterEnabled bool) p2p.AddressFilter {
 	return self.subnet.GetReservedAddrFilter(staticFilterEnabled)
 }
 
+func (self *MsgHandler) GetBanFilter() p2p.AddressFilter {
+	return self.peerScore
+}
+
 func (self *MsgHandler) GetMaskAddrFilter() p2p.AddressFilter {
 	return self.subnet.GetMaskAddrFilter()
 }
@@ -92,7 +102,7 @@ func (self *MsgHandler) GetSubnetMembersInfo() []msgCommon.SubnetMemberInfo {
 }
 
 func (self *MsgHandler) start(net p2p.P2P) {
-	self.blockSync = block_sync.NewBlockSyncMgr(net, self.ledger)
+	self.blockSync = block_sync.NewBlockSyncMgr(net, self.ledger, self.peerScore)
 	self.reconnect = reconnect.NewReconectService(net, self.staticReserveFilter)
 	maskFilter := self.subnet.GetMaskAddrFilter()
 	self.discovery = discovery.NewDiscovery(net, config.DefConfig.P2PNode.ReservedCfg.MaskPeers, maskFilter, 0)
@@ -106,8 +116,10 @@ func (self *MsgHandler) start(net p2p.P2P) {
 	go self.heatBeat.Start()
 	go self.bootstrap.Start()
 	go self.subnet.Start(net)
+	go self.peerScore.Start(net)
 
 	RegisterProposeOfflineVote(self.subnet)
+	RegisterPeerScoreApi(self.peerScore)
 }
 
 func (self *MsgHandler) stop() {
@@ -118,6 +130,7 @@ func (self *MsgHandler) stop() {
 	self.heatBeat.Stop()
 	self.bootstrap.Stop()
 	self.subnet.Stop()
+	self.peerScore.Stop()
 }
 
 func (self *MsgHandler) HandleSystemMessage(net p2p.P2P, msg p2p.SystemMessage) {
@@ -142,6 +155,8 @@ func (self *MsgHandler) HandleSystemMessage(net p2p.P2P, msg p2p.SystemMessage)
 		self.stop()
 	case p2p.HostAddrDetected:
 		self.subnet.OnHostAddrDetected(m.ListenAddr)
+	case p2p.MalformedMessageReceived:
+		self.peerScore.Report(m.Info.Id, m.Info.Addr, peer_score.MalformedMessage)
 	}
 }
 
@@ -165,7 +180,7 @@ func (self *MsgHandler) HandlePeerMessage(ctx *p2p.Context, msg msgTypes.Message
 	case *msgTypes.Block:
 		self.blockHandle(ctx, m)
 	case *msgTypes.Consensus:
-		ConsensusHandle(ctx, m)
+		self.consensusHandle(ctx, m)
 	case *msgTypes.Trn:
 		self.transactionHandle(ctx, m)
 	case *msgTypes.Addr:
@@ -182,6 +197,7 @@ func (self *MsgHandler) HandlePeerMessage(ctx *p2p.Context, msg msgTypes.Message
 		self.subnet.OnOfflineWitnessMsg(ctx, m)
 	case *msgTypes.NotFound:
 		log.Debug("[p2p]receive notFound message, hash is ", m.Hash)
+		self.blockSync.OnNotFound(ctx.Sender().GetID(), ctx.Sender().GetAddr(), m.Hash)
 	default:
 		msgType := msg.CmdType()
 		if msgType == msgCommon.VERACK_TYPE || msgType == msgCommon.VERSION_TYPE {
@@ -216,6 +232,7 @@ func (self *MsgHandler) blockHandle(ctx *p2p.Context, block *msgTypes.Block) {
 	stateHashHeight := config.GetStateHashCheckHeight(config.DefConfig.P2PNode.NetworkId)
 	if block.Blk.Header.Height >= stateHashHeight && block.MerkleRoot == common.UINT256_EMPTY {
 		remotePeer := ctx.Sender()
+		self.peerScore.Report(remotePeer.GetID(), remotePeer.GetAddr(), peer_score.InvalidBlock)
 		remotePeer.Close()
 		return
 	}
@@ -223,12 +240,13 @@ func (self *MsgHandler) blockHandle(ctx *p2p.Context, block *msgTypes.Block) {
 	self.blockSync.OnBlockReceive(ctx.Sender().GetID(), ctx.MsgSize, block.Blk, block.CCMsg, block.MerkleRoot)
 }
 
-// ConsensusHandle handles the consensus message from peer
-func ConsensusHandle(ctx *p2p.Context, consensus *msgTypes.Consensus) {
+// consensusHandle handles the consensus message from peer
+func (self *MsgHandler) consensusHandle(ctx *p2p.Context, consensus *msgTypes.Consensus) {
 	cpid := actor.GetConsensusPid()
 	if cpid != nil {
 		if err := consensus.Cons.Verify(); err != nil {
 			log.Warn(err)
+			self.peerScore.Report(ctx.Sender().GetID(), ctx.Sender().GetAddr(), peer_score.InvalidConsensusMsg)
 			return
 		}
 		consensus.Cons.PeerId = ctx.Sender().GetID()
@@ -238,6 +256,7 @@ func ConsensusHandle(ctx *p2p.Context, consensus *msgTypes.Consensus) {
 
 // TransactionHandle handles the transaction message from peer
 func (self *MsgHandler) transactionHandle(ctx *p2p.Context, trn *msgTypes.Trn) {
+	self.peerScore.OnTxReceived(ctx.Sender().GetID(), ctx.Sender().GetAddr())
 	if !txCache.Contains(trn.Txn.Hash()) {
 		txCache.Add(trn.Txn.Hash(), nil)
 		self.txPoolService.AppendTransactionAsync(common2.NetSender, trn.Txn)
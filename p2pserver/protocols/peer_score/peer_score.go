@@ -0,0 +1,359 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package peer_score
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	common2 "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/p2pserver/common"
+	p2p "github.com/ontio/ontology/p2pserver/net/protocol"
+)
+
+//Behavior is the peer action which changes its score
+type Behavior int
+
+const (
+	InvalidBlock Behavior = iota
+	InvalidHeader
+	InvalidConsensusMsg
+	MalformedMessage
+	UselessNotFound
+	TxFlood
+	ValidBlock
+)
+
+var behaviorScores = map[Behavior]float64{
+	InvalidBlock:        -50,
+	InvalidHeader:       -50,
+	InvalidConsensusMsg: -20,
+	MalformedMessage:    -50,
+	UselessNotFound:     -5,
+	TxFlood:             -30,
+	ValidBlock:          1,
+}
+
+var behaviorNames = map[Behavior]string{
+	InvalidBlock:        "invalid_block",
+	InvalidHeader:       "invalid_header",
+	InvalidConsensusMsg: "invalid_consensus_msg",
+	MalformedMessage:    "malformed_message",
+	UselessNotFound:     "useless_notfound",
+	TxFlood:             "tx_flood",
+	ValidBlock:          "valid_block",
+}
+
+func (self Behavior) String() string {
+	if name, ok := behaviorNames[self]; ok {
+		return name
+	}
+	return fmt.Sprintf("behavior_%d", int(self))
+}
+
+//PeerScore is the reputation record of a peer
+type PeerScore struct {
+	Id         string            `json:"id"`
+	Addr       string            `json:"addr"`
+	Score      float64           `json:"score"`
+	Behaviors  map[string]uint64 `json:"behaviors"`
+	LastUpdate int64             `json:"last_update"`
+
+	txWindowStart int64
+	txCount       uint64
+}
+
+//BanRecord is a banned ip, persisted across restarts. Id is the last peer banned from the ip, only for diagnosis,
+//since the peer id is regenerated when the node restarts
+type BanRecord struct {
+	Id     string `json:"id"`
+	Ip     string `json:"ip"`
+	Reason string `json:"reason"`
+	Until  int64  `json:"until"`
+}
+
+//PeerScoreService tracks the peer behaviors, disconnects the peers whose score drops below threshold and bans
+//their ips. The misbehaviors of the exempted peers, such as reserved and consensus peers, are ignored
+type PeerScoreService struct {
+	net     p2p.P2P
+	quit    chan bool
+	banFile string
+	exempt  p2p.AddressFilter
+
+	lock   sync.RWMutex
+	scores map[common.PeerId]*PeerScore
+	bans   map[string]*BanRecord //ip -> ban
+}
+
+func NewPeerScoreService(banFile string, exempt p2p.AddressFilter) *PeerScoreService {
+	this := &PeerScoreService{
+		quit:    make(chan bool),
+		banFile: banFile,
+		exempt:  exempt,
+		scores:  make(map[common.PeerId]*PeerScore),
+		bans:    make(map[string]*BanRecord),
+	}
+	this.loadBans()
+	return this
+}
+
+func (this *PeerScoreService) Start(net p2p.P2P) {
+	this.lock.Lock()
+	this.net = net
+	this.lock.Unlock()
+
+	t := time.NewTicker(time.Second * common.PEER_SCORE_DECAY_INTERVAL)
+	for {
+		select {
+		case <-t.C:
+			this.decay()
+		case <-this.quit:
+			t.Stop()
+			return
+		}
+	}
+}
+
+func (this *PeerScoreService) Stop() {
+	close(this.quit)
+}
+
+//Report record a behavior of peer, the peer is disconnected and banned when its score reaches the ban threshold
+func (this *PeerScoreService) Report(id common.PeerId, addr string, behavior Behavior) {
+	if behaviorScores[behavior] < 0 && addr != "" && this.exempt.Contains(addr) {
+		log.Debugf("[p2p] ignore %s of exempted peer %s %s", behavior, id.ToHexString(), addr)
+		return
+	}
+	this.lock.Lock()
+	score := this.getOrNewScore(id, addr)
+	score.Score = math.Min(score.Score+behaviorScores[behavior], common.PEER_SCORE_MAX)
+	score.Behaviors[behavior.String()] += 1
+	score.LastUpdate = time.Now().Unix()
+	banned := score.Score <= common.PEER_SCORE_BAN_THRESHOLD
+	if banned {
+		this.banLocked(id, score.Addr, behavior.String())
+		delete(this.scores, id)
+	}
+	net := this.net
+	this.lock.Unlock()
+
+	if behaviorScores[behavior] < 0 {
+		log.Debugf("[p2p] peer %s %s reported %s", id.ToHexString(), addr, behavior)
+	}
+	if banned {
+		log.Warnf("[p2p] ban peer %s %s for %s", id.ToHexString(), addr, behavior)
+		this.saveBans()
+		if net != nil {
+			if p := net.GetPeer(id); p != nil {
+				p.Close()
+			}
+		}
+	}
+}
+
+//OnTxReceived count the transactions relayed by peer and report flooding when exceed the rate limit
+func (this *PeerScoreService) OnTxReceived(id common.PeerId, addr string) {
+	now := time.Now().Unix()
+	this.lock.Lock()
+	score := this.getOrNewScore(id, addr)
+	if now-score.txWindowStart >= common.PEER_TX_RATE_WINDOW {
+		score.txWindowStart = now
+		score.txCount = 0
+	}
+	score.txCount += 1
+	flood := score.txCount == common.PEER_TX_RATE_LIMIT+1
+	this.lock.Unlock()
+
+	if flood {
+		this.Report(id, addr, TxFlood)
+	}
+}
+
+func (this *PeerScoreService) getOrNewScore(id common.PeerId, addr string) *PeerScore {
+	score, ok := this.scores[id]
+	if !ok {
+		score = &PeerScore{
+			Id:        id.ToHexString(),
+			Behaviors: make(map[string]uint64),
+		}
+		this.scores[id] = score
+	}
+	if addr != "" {
+		score.Addr = addr
+	}
+	return score
+}
+
+func (this *PeerScoreService) banLocked(id common.PeerId, addr string, reason string) {
+	ip, err := common.ParseIPAddr(addr)
+	if err != nil || ip == "" {
+		log.Warnf("[p2p] can not ban peer %s with address %q", id.ToHexString(), addr)
+		return
+	}
+	this.bans[ip] = &BanRecord{
+		Id:     id.ToHexString(),
+		Ip:     ip,
+		Reason: reason,
+		Until:  time.Now().Unix() + common.PEER_BAN_DURATION,
+	}
+}
+
+//decay move the scores toward zero, so that old misbehaviors are forgiven gradually
+func (this *PeerScoreService) decay() {
+	now := time.Now().Unix()
+	expired := false
+	this.lock.Lock()
+	for id, score := range this.scores {
+		score.Score *= common.PEER_SCORE_DECAY_RATE
+		if math.Abs(score.Score) < 1 && (this.net == nil || this.net.GetPeer(id) == nil) {
+			delete(this.scores, id)
+		}
+	}
+	for ip, ban := range this.bans {
+		if ban.Until <= now {
+			delete(this.bans, ip)
+			expired = true
+		}
+	}
+	this.lock.Unlock()
+
+	if expired {
+		this.saveBans()
+	}
+}
+
+//Contains implement p2p.AddressFilter, addr format : ip:port
+func (this *PeerScoreService) Contains(addr string) bool {
+	ip, err := common.ParseIPAddr(addr)
+	if err != nil {
+		return false
+	}
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	ban, ok := this.bans[ip]
+	return ok && ban.Until > time.Now().Unix()
+}
+
+//GetScores return the score table ordered by score, lowest first
+func (this *PeerScoreService) GetScores() []*PeerScore {
+	this.lock.RLock()
+	scores := make([]*PeerScore, 0, len(this.scores))
+	for _, score := range this.scores {
+		s := *score
+		s.Behaviors = make(map[string]uint64, len(score.Behaviors))
+		for k, v := range score.Behaviors {
+			s.Behaviors[k] = v
+		}
+		scores = append(scores, &s)
+	}
+	this.lock.RUnlock()
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score < scores[j].Score
+		}
+		return scores[i].Id < scores[j].Id
+	})
+	return scores
+}
+
+//GetBans return the active bans ordered by expiration
+func (this *PeerScoreService) GetBans() []*BanRecord {
+	now := time.Now().Unix()
+	this.lock.RLock()
+	bans := make([]*BanRecord, 0, len(this.bans))
+	for _, ban := range this.bans {
+		if ban.Until > now {
+			b := *ban
+			bans = append(bans, &b)
+		}
+	}
+	this.lock.RUnlock()
+
+	sort.Slice(bans, func(i, j int) bool {
+		if bans[i].Until != bans[j].Until {
+			return bans[i].Until < bans[j].Until
+		}
+		return bans[i].Ip < bans[j].Ip
+	})
+	return bans
+}
+
+//Unban remove the ban of ip
+func (this *PeerScoreService) Unban(ip string) bool {
+	this.lock.Lock()
+	_, removed := this.bans[ip]
+	delete(this.bans, ip)
+	this.lock.Unlock()
+
+	if removed {
+		this.saveBans()
+	}
+	return removed
+}
+
+func (this *PeerScoreService) saveBans() {
+	if this.banFile == "" {
+		return
+	}
+	bans := this.GetBans()
+	buf, err := json.Marshal(bans)
+	if err != nil {
+		log.Warn("[p2p]package banned peers fail: ", err)
+		return
+	}
+	err = ioutil.WriteFile(this.banFile, buf, os.ModePerm)
+	if err != nil {
+		log.Warn("[p2p]write banned peers fail: ", err)
+	}
+}
+
+func (this *PeerScoreService) loadBans() {
+	if this.banFile == "" || !common2.FileExisted(this.banFile) {
+		return
+	}
+	buf, err := ioutil.ReadFile(this.banFile)
+	if err != nil {
+		log.Warnf("[p2p]read %s fail:%s", this.banFile, err)
+		return
+	}
+	var bans []*BanRecord
+	err = json.Unmarshal(buf, &bans)
+	if err != nil {
+		log.Warn("[p2p]parse banned peers file fail: ", err)
+		return
+	}
+
+	now := time.Now().Unix()
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	for _, ban := range bans {
+		if ban.Ip == "" || ban.Until <= now {
+			continue
+		}
+		this.bans[ban.Ip] = ban
+	}
+}
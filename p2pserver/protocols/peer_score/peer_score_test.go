@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package peer_score
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ontio/ontology/p2pserver/common"
+	p2p "github.com/ontio/ontology/p2pserver/net/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportBan(t *testing.T) {
+	service := NewPeerScoreService("", p2p.NoneAddrFilter())
+	id := common.PseudoPeerIdFromUint64(1)
+	addr := "127.0.0.1:20338"
+
+	service.Report(id, addr, InvalidBlock)
+	assert.False(t, service.Contains(addr))
+	scores := service.GetScores()
+	assert.Equal(t, 1, len(scores))
+	assert.Equal(t, float64(-50), scores[0].Score)
+	assert.Equal(t, uint64(1), scores[0].Behaviors[InvalidBlock.String()])
+
+	service.Report(id, addr, InvalidHeader)
+	assert.True(t, service.Contains(addr))
+	assert.True(t, service.Contains("127.0.0.1:30338"))
+	assert.False(t, service.Contains("127.0.0.2:20338"))
+	assert.Equal(t, 0, len(service.GetScores()))
+	bans := service.GetBans()
+	assert.Equal(t, 1, len(bans))
+	assert.Equal(t, "127.0.0.1", bans[0].Ip)
+	assert.Equal(t, InvalidHeader.String(), bans[0].Reason)
+
+	// a reconnect with a regenerated peer id is still banned
+	assert.True(t, service.Contains("127.0.0.1:20339"))
+	assert.False(t, service.Unban(id.ToHexString()))
+	assert.True(t, service.Unban("127.0.0.1"))
+	assert.False(t, service.Contains(addr))
+}
+
+func TestReportExempt(t *testing.T) {
+	reserved := "127.0.0.1:20338"
+	service := NewPeerScoreService("", exemptFilter{ip: "127.0.0.1"})
+	id := common.PseudoPeerIdFromUint64(1)
+	for i := 0; i < 5; i++ {
+		service.Report(id, reserved, InvalidBlock)
+	}
+	assert.False(t, service.Contains(reserved))
+	assert.Equal(t, 0, len(service.GetBans()))
+
+	other := "127.0.0.2:20338"
+	service.Report(common.PseudoPeerIdFromUint64(2), other, InvalidBlock)
+	service.Report(common.PseudoPeerIdFromUint64(2), other, InvalidBlock)
+	assert.True(t, service.Contains(other))
+}
+
+type exemptFilter struct {
+	ip string
+}
+
+func (self exemptFilter) Contains(addr string) bool {
+	ip, _, err := net.SplitHostPort(addr)
+	return err == nil && ip == self.ip
+}
+
+func TestValidBlockScoreCapped(t *testing.T) {
+	service := NewPeerScoreService("", p2p.NoneAddrFilter())
+	id := common.PseudoPeerIdFromUint64(1)
+	for i := 0; i < common.PEER_SCORE_MAX+10; i++ {
+		service.Report(id, "127.0.0.1:20338", ValidBlock)
+	}
+	assert.Equal(t, float64(common.PEER_SCORE_MAX), service.GetScores()[0].Score)
+}
+
+func TestTxFlood(t *testing.T) {
+	service := NewPeerScoreService("", p2p.NoneAddrFilter())
+	id := common.PseudoPeerIdFromUint64(1)
+	for i := 0; i < common.PEER_TX_RATE_LIMIT; i++ {
+		service.OnTxReceived(id, "127.0.0.1:20338")
+	}
+	assert.Equal(t, float64(0), service.GetScores()[0].Score)
+
+	service.OnTxReceived(id, "127.0.0.1:20338")
+	service.OnTxReceived(id, "127.0.0.1:20338")
+	scores := service.GetScores()
+	assert.Equal(t, float64(-30), scores[0].Score)
+	assert.Equal(t, uint64(1), scores[0].Behaviors[TxFlood.String()])
+}
+
+func TestDecay(t *testing.T) {
+	service := NewPeerScoreService("", p2p.NoneAddrFilter())
+	id := common.PseudoPeerIdFromUint64(1)
+	service.Report(id, "127.0.0.1:20338", InvalidConsensusMsg)
+	service.decay()
+	assert.InDelta(t, -20*common.PEER_SCORE_DECAY_RATE, service.GetScores()[0].Score, 1e-9)
+
+	service.Report(id, "127.0.0.1:20338", MalformedMessage)
+	service.Report(id, "127.0.0.1:20338", MalformedMessage)
+	assert.True(t, service.Contains("127.0.0.1:20338"))
+	service.lock.Lock()
+	service.bans["127.0.0.1"].Until = 0
+	service.lock.Unlock()
+	service.decay()
+	assert.False(t, service.Contains("127.0.0.1:20338"))
+	assert.Equal(t, 0, len(service.GetBans()))
+}
+
+func TestBanFilePersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peer_score")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	banFile := filepath.Join(dir, common.BANNED_PEERS_FILE_NAME)
+
+	service := NewPeerScoreService(banFile, p2p.NoneAddrFilter())
+	id := common.PseudoPeerIdFromUint64(1)
+	service.Report(id, "127.0.0.1:20338", InvalidBlock)
+	service.Report(id, "127.0.0.1:20338", InvalidBlock)
+	assert.True(t, service.Contains("127.0.0.1:20338"))
+
+	reloaded := NewPeerScoreService(banFile, p2p.NoneAddrFilter())
+	assert.True(t, reloaded.Contains("127.0.0.1:20338"))
+
+	assert.True(t, reloaded.Unban("127.0.0.1"))
+	assert.False(t, NewPeerScoreService(banFile, p2p.NoneAddrFilter()).Contains("127.0.0.1:20338"))
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/ontio/ontology/http/base/error"
 	"github.com/ontio/ontology/http/base/rpc"
 	"github.com/ontio/ontology/http/localrpc"
+	"github.com/ontio/ontology/p2pserver/protocols/peer_score"
 	"github.com/ontio/ontology/p2pserver/protocols/subnet"
 )
 
@@ -53,3 +54,29 @@ func RegisterProposeOfflineVote(subnet *subnet.SubNet) {
 		return rpc.ResponseSuccess(votes)
 	})
 }
+
+func RegisterPeerScoreApi(score *peer_score.PeerScoreService) {
+	// curl http://localhost:20337/local -v -d '{"method":"getPeerScores", "params":[]}'
+	localrpc.LocalRpcMux.HandleFunc("getPeerScores", func(params []interface{}) map[string]interface{} {
+		return rpc.ResponseSuccess(map[string]interface{}{
+			"scores": score.GetScores(),
+			"bans":   score.GetBans(),
+		})
+	})
+
+	// curl http://localhost:20337/local -v -d '{"method":"unbanPeer", "params":["ip"]}'
+	localrpc.LocalRpcMux.HandleFunc("unbanPeer", func(params []interface{}) map[string]interface{} {
+		if len(params) < 1 {
+			return rpc.ResponsePack(error.INVALID_PARAMS, "")
+		}
+		ip, ok := params[0].(string)
+		if !ok {
+			return rpc.ResponsePack(error.INVALID_PARAMS, "")
+		}
+		if !score.Unban(ip) {
+			return rpc.ResponsePack(error.INVALID_PARAMS, "peer is not banned")
+		}
+
+		return rpc.ResponseSuccess(true)
+	})
+}
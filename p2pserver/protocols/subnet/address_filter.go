@@ -56,3 +56,16 @@ func (self *SubNetMaskAddrFilter) Contains(addr string) bool {
 
 	return ok
 }
+
+//SubNetMemberIpFilter contains the addresses whose ip is of the subnet members, which are the consensus nodes
+type SubNetMemberIpFilter struct {
+	subnet *SubNet
+}
+
+func (self *SubNetMemberIpFilter) Contains(addr string) bool {
+	ip, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	return self.subnet.IpInMembers(ip)
+}
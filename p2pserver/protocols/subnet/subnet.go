@@ -398,6 +398,12 @@ func (self *SubNet) GetReservedAddrFilter(staticFilterEnabled bool) p2p.AddressF
 	}
 }
 
+func (self *SubNet) GetMemberIpFilter() p2p.AddressFilter {
+	return &SubNetMemberIpFilter{
+		subnet: self,
+	}
+}
+
 func (self *SubNet) GetMaskAddrFilter() p2p.AddressFilter {
 	return &SubNetMaskAddrFilter{
 		subnet: self,
@@ -22,6 +22,14 @@ import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/event"
+	vm "github.com/ontio/ontology/vm/neovm"
+)
+
+// vm type of the traced contract call
+const (
+	VM_NEOVM  = "neovm"
+	VM_NATIVE = "native"
+	VM_WASMVM = "wasmvm"
 )
 
 // ContextRef is a interface of smart context
@@ -46,6 +54,15 @@ type ContextRef interface {
 	SetInternalErr()
 	IsInternalErr() bool
 	PutCrossStateHashes(hashes []common.Uint256)
+	GetTracer() Tracer
+}
+
+// Tracer is notified of the contract calls across neovm, native and wasmvm, and the execution steps of neovm.
+// output of CaptureExit is the result of the call converted to json compatible value
+type Tracer interface {
+	vm.Tracer
+	CaptureEnter(vmType string, address common.Address, method string, input []byte, gas uint64)
+	CaptureExit(output interface{}, gasUsed uint64, err error)
 }
 
 type Engine interface {
@@ -35,12 +35,13 @@ const (
 )
 
 // PushSmartCodeEvent push event content to socket.io
-func PushSmartCodeEvent(txHash common.Uint256, errcode int64, action string, result interface{}) {
+func PushSmartCodeEvent(txHash common.Uint256, height uint32, errcode int64, action string, result interface{}) {
 	if events.DefActorPublisher == nil {
 		return
 	}
 	smartCodeEvt := &types.SmartCodeEvent{
 		TxHash: txHash,
+		Height: height,
 		Action: action,
 		Result: result,
 		Error:  errcode,
@@ -252,6 +252,11 @@ func (st *StateTransition) TransitionDb() (*types.ExecutionResult, error) {
 	// 5. there is no overflow when calculating intrinsic gas
 	// 6. caller has enough balance to cover asset transfer for **topmost** call
 
+	if cfg := st.evm.Config(); cfg.Debug {
+		if tracer, ok := cfg.Tracer.(evm.TxTracer); ok {
+			tracer.CaptureTxStart(st.evm, st.msg.From(), st.msg.To())
+		}
+	}
 	// Check clauses 1-3, buy gas if everything is correct
 	adjustedGas, err := st.preCheck()
 	if err != nil {
@@ -0,0 +1,202 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+	vbftconfig "github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+)
+
+const (
+	//faulty evidence type
+	FaultyProposal uint32 = iota + 1 //two conflicting block proposals of the same proposer
+	FaultyEndorse                    //two endorsements for different blocks of the same height
+	FaultyCommit                     //two commitments for different blocks of the same height
+)
+
+//vbft msg types of the endorse and commit msgs, keep the same with consensus/vbft
+const (
+	vbftEndorseMsg = 1
+	vbftCommitMsg  = 2
+)
+
+type vbftMsgPayload struct {
+	Type    uint8  `json:"type"`
+	Len     uint32 `json:"len"`
+	Payload []byte `json:"payload"`
+}
+
+//vbftVoteMsg contains the fields of vbft endorse and commit msg to check the equivocation
+type vbftVoteMsg struct {
+	Endorser          uint32         `json:"endorser"`
+	EndorsedBlockHash common.Uint256 `json:"endorsed_block_hash"`
+	EndorseForEmpty   bool           `json:"endorse_for_empty"`
+	Committer         uint32         `json:"committer"`
+	CommitBlockHash   common.Uint256 `json:"commit_block_hash"`
+	CommitForEmpty    bool           `json:"commit_for_empty"`
+	BlockNum          uint32         `json:"block_num"`
+}
+
+//ConflictingProposals checks whether the two block headers are different proposals of the same height.
+//The block and the empty block of one proposal only differ in transactions, they are not conflicting.
+func ConflictingProposals(h1, h2 *types.Header) bool {
+	if h1.Height != h2.Height || h1.Hash() == h2.Hash() {
+		return false
+	}
+	return h1.PrevBlockHash != h2.PrevBlockHash || h1.Timestamp != h2.Timestamp ||
+		!bytes.Equal(h1.ConsensusPayload, h2.ConsensusPayload)
+}
+
+//VerifyFaultyEvidence verifies the conflicting msgs signed by the peer, and returns the block height of them.
+//The proposal msgs are the serialized block headers signed by proposer, the endorse and commit msgs are
+//the unsigned consensus payloads signed by endorser and committer.
+func VerifyFaultyEvidence(param *ReportFaultyParam, peerIndex uint32) (uint32, error) {
+	pubkeyBytes, err := hex.DecodeString(param.PeerPubkey)
+	if err != nil {
+		return 0, fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
+	}
+	pubkey, err := keypair.DeserializePublicKey(pubkeyBytes)
+	if err != nil {
+		return 0, fmt.Errorf("deserialize peerPubkey error: %v", err)
+	}
+
+	switch param.Type {
+	case FaultyProposal:
+		h1, err := parseSignedProposal(pubkey, param.Msg1, param.Sig1, peerIndex)
+		if err != nil {
+			return 0, err
+		}
+		h2, err := parseSignedProposal(pubkey, param.Msg2, param.Sig2, peerIndex)
+		if err != nil {
+			return 0, err
+		}
+		if !ConflictingProposals(h1, h2) {
+			return 0, fmt.Errorf("proposals are not conflicting")
+		}
+		return h1.Height, nil
+	case FaultyEndorse, FaultyCommit:
+		v1, err := parseSignedVote(pubkey, param.Type, param.Msg1, param.Sig1, peerIndex)
+		if err != nil {
+			return 0, err
+		}
+		v2, err := parseSignedVote(pubkey, param.Type, param.Msg2, param.Sig2, peerIndex)
+		if err != nil {
+			return 0, err
+		}
+		if v1.BlockNum != v2.BlockNum {
+			return 0, fmt.Errorf("votes are not of the same height")
+		}
+		if param.Type == FaultyEndorse {
+			// one endorsement for block and one for empty block are allowed in a round
+			if v1.EndorseForEmpty != v2.EndorseForEmpty || v1.EndorsedBlockHash == v2.EndorsedBlockHash {
+				return 0, fmt.Errorf("endorsements are not conflicting")
+			}
+		} else if v1.CommitForEmpty != v2.CommitForEmpty || v1.CommitBlockHash == v2.CommitBlockHash {
+			return 0, fmt.Errorf("commitments are not conflicting")
+		}
+		return v1.BlockNum, nil
+	default:
+		return 0, fmt.Errorf("unknown faulty type: %d", param.Type)
+	}
+}
+
+//VerifyFaultyMsg verifies one consensus msg signed by the peer in the same format as the faulty evidence,
+//and returns the block height of it.
+func VerifyFaultyMsg(faultyType uint32, pubkey keypair.PublicKey, msg, sig []byte, peerIndex uint32) (uint32, error) {
+	switch faultyType {
+	case FaultyProposal:
+		header, err := parseSignedProposal(pubkey, msg, sig, peerIndex)
+		if err != nil {
+			return 0, err
+		}
+		return header.Height, nil
+	case FaultyEndorse, FaultyCommit:
+		vote, err := parseSignedVote(pubkey, faultyType, msg, sig, peerIndex)
+		if err != nil {
+			return 0, err
+		}
+		return vote.BlockNum, nil
+	default:
+		return 0, fmt.Errorf("unknown faulty type: %d", faultyType)
+	}
+}
+
+func parseSignedProposal(pubkey keypair.PublicKey, msg, sig []byte, peerIndex uint32) (*types.Header, error) {
+	header, err := types.HeaderFromRawBytes(msg)
+	if err != nil {
+		return nil, fmt.Errorf("deserialize block header error: %v", err)
+	}
+	hash := header.Hash()
+	if err := signature.Verify(pubkey, hash[:], sig); err != nil {
+		return nil, fmt.Errorf("verify proposal signature error: %v", err)
+	}
+	info, err := vbftconfig.VbftBlock(header)
+	if err != nil {
+		return nil, err
+	}
+	if info.Proposer != peerIndex {
+		return nil, fmt.Errorf("proposer %d of block is not the peer %d", info.Proposer, peerIndex)
+	}
+	return header, nil
+}
+
+func parseSignedVote(pubkey keypair.PublicKey, faultyType uint32, msg, sig []byte, peerIndex uint32) (*vbftVoteMsg, error) {
+	if err := signature.Verify(pubkey, msg, sig); err != nil {
+		return nil, fmt.Errorf("verify consensus payload signature error: %v", err)
+	}
+	// skip version, prev hash, height, bookkeeper index and timestamp of consensus payload
+	source := common.NewZeroCopySource(msg)
+	if eof := source.Skip(4 + common.UINT256_SIZE + 4 + 2 + 4); eof {
+		return nil, fmt.Errorf("deserialize consensus payload error: unexpected eof")
+	}
+	data, _, irregular, eof := source.NextVarBytes()
+	if irregular || eof || source.Len() != 0 {
+		return nil, fmt.Errorf("deserialize consensus payload data error")
+	}
+
+	payload := &vbftMsgPayload{}
+	if err := json.Unmarshal(data, payload); err != nil {
+		return nil, fmt.Errorf("unmarshal consensus msg payload error: %v", err)
+	}
+	vote := &vbftVoteMsg{}
+	if err := json.Unmarshal(payload.Payload, vote); err != nil {
+		return nil, fmt.Errorf("unmarshal consensus msg error: %v", err)
+	}
+	switch {
+	case faultyType == FaultyEndorse && payload.Type == vbftEndorseMsg:
+		if vote.Endorser != peerIndex {
+			return nil, fmt.Errorf("endorser %d is not the peer %d", vote.Endorser, peerIndex)
+		}
+	case faultyType == FaultyCommit && payload.Type == vbftCommitMsg:
+		if vote.Committer != peerIndex {
+			return nil, fmt.Errorf("committer %d is not the peer %d", vote.Committer, peerIndex)
+		}
+	default:
+		return nil, fmt.Errorf("consensus msg type %d mismatch with faulty type %d", payload.Type, faultyType)
+	}
+	return vote, nil
+}
@@ -61,6 +61,7 @@ const (
 	REJECT_CANDIDATE                 = "rejectCandidate"
 	BLACK_NODE                       = "blackNode"
 	WHITE_NODE                       = "whiteNode"
+	REPORT_FAULTY                    = "reportFaulty"
 	QUIT_NODE                        = "quitNode"
 	WITHDRAW                         = "withdraw"
 	WITHDRAW_ONG                     = "withdrawOng"
@@ -103,6 +104,7 @@ const (
 	PROMISE_POS       = "promisePos"
 	PRE_CONFIG        = "preConfig"
 	GAS_ADDRESS       = "gasAddress"
+	FAULTY_REPORT     = "faultyReport"
 
 	//global
 	PRECISE            = 1000000
@@ -153,6 +155,7 @@ func RegisterGovernanceContract(native *native.NativeService) {
 	native.Register(REJECT_CANDIDATE, RejectCandidate)
 	native.Register(BLACK_NODE, BlackNode)
 	native.Register(WHITE_NODE, WhiteNode)
+	native.Register(REPORT_FAULTY, ReportFaulty)
 	native.Register(COMMIT_DPOS, CommitDpos)
 	native.Register(UPDATE_CONFIG, UpdateConfig)
 	native.Register(UPDATE_GLOBAL_PARAM, UpdateGlobalParam)
@@ -619,6 +622,24 @@ func BlackNode(native *native.NativeService) ([]byte, error) {
 	}
 	contract := native.ContextRef.CurrentContext().ContractAddress
 
+	if err := blackNodes(native, contract, params.PeerPubkeyList); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//Report the equivocation of a consensus node with two conflicting consensus msgs signed by it.
+//The node will be put into black list and punished the same as blackNode, anyone can report.
+func ReportFaulty(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetReportFaultyHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("reportFaulty, method is not activated at height %d", native.Height)
+	}
+	params := new(ReportFaultyParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("deserialize, contract params deserialize error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
 	//get current view
 	view, err := GetView(native, contract)
 	if err != nil {
@@ -629,15 +650,61 @@ func BlackNode(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
 	}
+	peerPoolItem, ok := peerPoolMap.PeerPoolMap[params.PeerPubkey]
+	if !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("reportFaulty, peerPubkey is not in peerPoolMap")
+	}
+	if peerPoolItem.Status != CandidateStatus && peerPoolItem.Status != ConsensusStatus {
+		return utils.BYTE_FALSE, fmt.Errorf("reportFaulty, peer status is not candidate or consensus")
+	}
+
+	height, err := VerifyFaultyEvidence(params, peerPoolItem.Index)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportFaulty, verify evidence error: %v", err)
+	}
+
+	//the evidence can only be used once, even if the peer is removed from black list
+	peerPubkeyPrefix, err := hex.DecodeString(params.PeerPubkey)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
+	}
+	reportKey := utils.ConcatKey(contract, []byte(FAULTY_REPORT), peerPubkeyPrefix, GetUint32Bytes(height))
+	reported, err := native.CacheDB.Get(reportKey)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportFaulty, get faulty report error: %v", err)
+	}
+	if reported != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportFaulty, faulty of height %d is already reported", height)
+	}
+	native.CacheDB.Put(reportKey, cstates.GenRawStorageItem(utils.BYTE_TRUE))
+
+	if err := blackNodes(native, contract, []string{params.PeerPubkey}); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//put the peers into black list, and commit dpos if any of them is consensus node
+func blackNodes(native *native.NativeService, contract common.Address, peerPubkeyList []string) error {
+	//get current view
+	view, err := GetView(native, contract)
+	if err != nil {
+		return fmt.Errorf("getView, get view error: %v", err)
+	}
+	//get peerPoolMap
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
 	commit := false
-	for _, peerPubkey := range params.PeerPubkeyList {
+	for _, peerPubkey := range peerPubkeyList {
 		peerPubkeyPrefix, err := hex.DecodeString(peerPubkey)
 		if err != nil {
-			return utils.BYTE_FALSE, fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
+			return fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
 		}
 		peerPoolItem, ok := peerPoolMap.PeerPoolMap[peerPubkey]
 		if !ok {
-			return utils.BYTE_FALSE, fmt.Errorf("blackNode, peerPubkey is not in peerPoolMap")
+			return fmt.Errorf("blackNode, peerPubkey is not in peerPoolMap")
 		}
 
 		blackListItem := &BlackListItem{
@@ -656,17 +723,17 @@ func BlackNode(native *native.NativeService) ([]byte, error) {
 	}
 	err = putPeerPoolMap(native, contract, view, peerPoolMap)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("putPeerPoolMap, put peerPoolMap error: %v", err)
+		return fmt.Errorf("putPeerPoolMap, put peerPoolMap error: %v", err)
 	}
 
 	//commitDpos
 	if commit {
 		err = executeCommitDpos(native, contract)
 		if err != nil {
-			return utils.BYTE_FALSE, fmt.Errorf("executeCommitDpos, executeCommitDpos error: %v", err)
+			return fmt.Errorf("executeCommitDpos, executeCommitDpos error: %v", err)
 		}
 	}
-	return utils.BYTE_TRUE, nil
+	return nil
 }
 
 //Remove a node from black list, allow it to be registered
@@ -187,6 +187,59 @@ func (this *BlackNodeParam) Deserialization(source *common.ZeroCopySource) error
 	return nil
 }
 
+type ReportFaultyParam struct {
+	Type       uint32 //faulty evidence type
+	PeerPubkey string //pubkey of the faulty peer
+	Msg1       []byte //the first conflicting msg
+	Sig1       []byte //signature of the first msg by the faulty peer
+	Msg2       []byte //the second conflicting msg
+	Sig2       []byte //signature of the second msg by the faulty peer
+}
+
+func (this *ReportFaultyParam) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeVarUint(sink, uint64(this.Type))
+	sink.WriteString(this.PeerPubkey)
+	sink.WriteVarBytes(this.Msg1)
+	sink.WriteVarBytes(this.Sig1)
+	sink.WriteVarBytes(this.Msg2)
+	sink.WriteVarBytes(this.Sig2)
+}
+
+func (this *ReportFaultyParam) Deserialization(source *common.ZeroCopySource) error {
+	faultyType, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("utils.DecodeVarUint, deserialize type error: %v", err)
+	}
+	if faultyType > math.MaxUint32 {
+		return fmt.Errorf("type larger than max of uint32")
+	}
+	peerPubkey, err := utils.DecodeString(source)
+	if err != nil {
+		return fmt.Errorf("utils.DecodeString, deserialize peerPubkey error: %v", err)
+	}
+	msg1, err := utils.DecodeVarBytes(source)
+	if err != nil {
+		return fmt.Errorf("utils.DecodeVarBytes, deserialize msg1 error: %v", err)
+	}
+	sig1, err := utils.DecodeVarBytes(source)
+	if err != nil {
+		return fmt.Errorf("utils.DecodeVarBytes, deserialize sig1 error: %v", err)
+	}
+	msg2, err := utils.DecodeVarBytes(source)
+	if err != nil {
+		return fmt.Errorf("utils.DecodeVarBytes, deserialize msg2 error: %v", err)
+	}
+	sig2, err := utils.DecodeVarBytes(source)
+	if err != nil {
+		return fmt.Errorf("utils.DecodeVarBytes, deserialize sig2 error: %v", err)
+	}
+	this.Type = uint32(faultyType)
+	this.PeerPubkey = peerPubkey
+	this.Msg1, this.Sig1 = msg1, sig1
+	this.Msg2, this.Sig2 = msg2, sig2
+	return nil
+}
+
 type WhiteNodeParam struct {
 	PeerPubkey string
 }
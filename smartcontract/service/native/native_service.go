@@ -19,6 +19,7 @@
 package native
 
 import (
+	"encoding/hex"
 	"fmt"
 
 	"github.com/ontio/ontology/common"
@@ -64,6 +65,20 @@ func (this *NativeService) Register(methodName string, handler Handler) {
 }
 
 func (this *NativeService) Invoke() ([]byte, error) {
+	tracer := this.ContextRef.GetTracer()
+	if tracer == nil {
+		return this.invoke()
+	}
+	contract := this.InvokeParam
+	gasLeft, _ := this.ContextRef.GetGasInfo()
+	tracer.CaptureEnter(context.VM_NATIVE, contract.Address, contract.Method, contract.Args, gasLeft)
+	result, err := this.invoke()
+	remain, _ := this.ContextRef.GetGasInfo()
+	tracer.CaptureExit(hex.EncodeToString(result), gasLeft-remain, err)
+	return result, err
+}
+
+func (this *NativeService) invoke() ([]byte, error) {
 	contract := this.InvokeParam
 	services, ok := Contracts[contract.Address]
 	if !ok {
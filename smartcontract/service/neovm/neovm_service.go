@@ -139,6 +139,25 @@ type NeoVmService struct {
 
 // Invoke a smart contract
 func (this *NeoVmService) Invoke() (interface{}, error) {
+	tracer := this.ContextRef.GetTracer()
+	if tracer == nil {
+		return this.invoke()
+	}
+	gasLeft, _ := this.ContextRef.GetGasInfo()
+	tracer.CaptureEnter(context.VM_NEOVM, scommon.AddressFromVmCode(this.Code), "", this.Code, gasLeft)
+	result, err := this.invoke()
+	var output interface{}
+	if result != nil {
+		if val, ok := result.(*vmty.VmValue); ok {
+			output, _ = val.ConvertNeoVmValueHexString()
+		}
+	}
+	remain, _ := this.ContextRef.GetGasInfo()
+	tracer.CaptureExit(output, gasLeft-remain, err)
+	return result, err
+}
+
+func (this *NeoVmService) invoke() (interface{}, error) {
 	if len(this.Code) == 0 {
 		return nil, ERR_EXECUTE_CODE
 	}
@@ -155,6 +174,7 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 		if this.Engine.Context.GetInstructionPointer() >= len(this.Engine.Context.Code) {
 			break
 		}
+		pc := this.Engine.Context.GetInstructionPointer()
 		opCode, eof := this.Engine.Context.ReadOpCode()
 		if eof {
 			return nil, io.EOF
@@ -174,6 +194,10 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 			gasTable[opCode] = price
 		}
 
+		if this.Engine.Tracer != nil {
+			gasLeft, _ := this.ContextRef.GetGasInfo()
+			this.Engine.Tracer.CaptureState(this.Engine, pc, opCode, gasLeft, price)
+		}
 		if !this.ContextRef.CheckUseGas(price) {
 			return nil, ERR_GAS_INSUFFICIENT
 		}
@@ -271,6 +295,10 @@ func (this *NeoVmService) SystemCall(engine *vm.Executor) error {
 	if err != nil {
 		return err
 	}
+	if engine.Tracer != nil {
+		gasLeft, _ := this.ContextRef.GetGasInfo()
+		engine.Tracer.CaptureSyscall(engine, serviceName, gasLeft, price)
+	}
 	if !this.ContextRef.CheckUseGas(price) {
 		return ERR_GAS_INSUFFICIENT
 	}
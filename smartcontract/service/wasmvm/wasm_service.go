@@ -18,6 +18,7 @@
 package wasmvm
 
 import (
+	"encoding/hex"
 	"fmt"
 	"sync"
 
@@ -123,6 +124,27 @@ func unregisterWasmVmService(index uint64) {
 }
 
 func (this *WasmVmService) Invoke() (interface{}, error) {
+	tracer := this.ContextRef.GetTracer()
+	if tracer == nil {
+		return this.invoke()
+	}
+	contract := &states.WasmContractParam{}
+	if err := contract.Deserialization(common.NewZeroCopySource(this.Code)); err != nil {
+		return this.invoke()
+	}
+	gasLeft, _ := this.ContextRef.GetGasInfo()
+	tracer.CaptureEnter(context.VM_WASMVM, contract.Address, "", contract.Args, gasLeft)
+	result, err := this.invoke()
+	var output interface{}
+	if res, ok := result.([]byte); ok {
+		output = hex.EncodeToString(res)
+	}
+	remain, _ := this.ContextRef.GetGasInfo()
+	tracer.CaptureExit(output, gasLeft-remain, err)
+	return result, err
+}
+
+func (this *WasmVmService) invoke() (interface{}, error) {
 	if len(this.Code) == 0 {
 		return nil, ERR_EXECUTE_CODE
 	}
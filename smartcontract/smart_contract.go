@@ -53,6 +53,7 @@ type SmartContract struct {
 	PreExec       bool
 	internelErr   bool
 	CrossHashes   []common.Uint256
+	Tracer        context.Tracer // trace the contract calls if not nil
 }
 
 // Config describe smart contract need parameters configuration
@@ -130,6 +131,10 @@ func (this *SmartContract) PutCrossStateHashes(hashes []common.Uint256) {
 	this.CrossHashes = append(this.CrossHashes, hashes...)
 }
 
+func (this *SmartContract) GetTracer() context.Tracer {
+	return this.Tracer
+}
+
 func (this *SmartContract) checkContexts() bool {
 	if len(this.Contexts) > MAX_EXECUTE_ENGINE {
 		return false
@@ -157,6 +162,10 @@ func (this *SmartContract) NewExecuteEngine(code []byte, txtype ctypes.Transacti
 	switch txtype {
 	case ctypes.InvokeNeo:
 		feature := NewVmFeatureFlag(this.Config.Height)
+		engine := vm.NewExecutor(code, feature)
+		if this.Tracer != nil {
+			engine.Tracer = this.Tracer
+		}
 		service = &neovm.NeoVmService{
 			Store:      this.Store,
 			CacheDB:    this.CacheDB,
@@ -167,7 +176,7 @@ func (this *SmartContract) NewExecuteEngine(code []byte, txtype ctypes.Transacti
 			Time:       this.Config.Time,
 			Height:     this.Config.Height,
 			BlockHash:  this.Config.BlockHash,
-			Engine:     vm.NewExecutor(code, feature),
+			Engine:     engine,
 			PreExec:    this.PreExec,
 		}
 	case ctypes.InvokeWasm:
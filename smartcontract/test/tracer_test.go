@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/context"
+	"github.com/ontio/ontology/smartcontract/tracers"
+	"github.com/ontio/ontology/vm/neovm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallTracer(t *testing.T) {
+	syscall := "System.Runtime.GetTime"
+	byteCode := []byte{byte(neovm.PUSH1), byte(neovm.PUSH2), byte(neovm.ADD), byte(neovm.SYSCALL), byte(len(syscall))}
+	byteCode = append(byteCode, []byte(syscall)...)
+	byteCode = append(byteCode, byte(neovm.DROP))
+
+	run := func(byteCode []byte, cfg *tracers.LogConfig) *tracers.CallFrame {
+		tracer := tracers.NewCallTracer(cfg)
+		sc := smartcontract.SmartContract{
+			Config: &smartcontract.Config{Time: 10, Height: 10, Tx: &types.Transaction{}},
+			Gas:    100000,
+			Tracer: tracer,
+		}
+		engine, err := sc.NewExecuteEngine(byteCode, types.InvokeNeo)
+		assert.Nil(t, err)
+		_, err = engine.Invoke()
+		assert.Nil(t, err)
+
+		res, err := tracer.GetResult()
+		assert.Nil(t, err)
+		frame := &tracers.CallFrame{}
+		assert.Nil(t, json.Unmarshal(res, frame))
+		return frame
+	}
+
+	frame := run(byteCode, nil)
+	assert.Equal(t, context.VM_NEOVM, frame.Type)
+	address := common.AddressFromVmCode(byteCode)
+	assert.Equal(t, address.ToHexString(), frame.Address)
+	assert.Equal(t, "03", frame.Output)
+	assert.NotZero(t, frame.GasUsed)
+	assert.Equal(t, 5, len(frame.Steps))
+	assert.Equal(t, []string{"PUSH1", "PUSH2", "ADD", "SYSCALL", "DROP"},
+		[]string{frame.Steps[0].Op, frame.Steps[1].Op, frame.Steps[2].Op, frame.Steps[3].Op, frame.Steps[4].Op})
+	assert.Nil(t, frame.Steps[2].Stack)
+	assert.Equal(t, syscall, frame.Steps[3].Syscall)
+	assert.Equal(t, 3+len(syscall)+2, frame.Steps[4].Pc)
+	assert.Equal(t, frame.Steps[0].Gas-frame.Steps[0].GasCost, frame.Steps[1].Gas)
+
+	frame = run(byteCode, &tracers.LogConfig{EnableStack: true, Limit: 3})
+	assert.Equal(t, 3, len(frame.Steps))
+	assert.Equal(t, 2, len(frame.Steps[2].Stack))
+
+	frame = run(byteCode, &tracers.LogConfig{DisableSteps: true})
+	assert.Nil(t, frame.Steps)
+
+	// the steps are limited by default, and the limit is capped
+	nops := make([]byte, tracers.MAX_STEP_LIMIT+1)
+	for i := range nops {
+		nops[i] = byte(neovm.NOP)
+	}
+	frame = run(nops, nil)
+	assert.Equal(t, tracers.DEFAULT_STEP_LIMIT, len(frame.Steps))
+	frame = run(nops, &tracers.LogConfig{Limit: tracers.MAX_STEP_LIMIT + 1})
+	assert.Equal(t, tracers.MAX_STEP_LIMIT, len(frame.Steps))
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package tracers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/ontio/ontology/common"
+	vm "github.com/ontio/ontology/vm/neovm"
+)
+
+const (
+	DEFAULT_STEP_LIMIT = 1000  //steps recorded if the limit is not given
+	MAX_STEP_LIMIT     = 10000 //max steps recorded by a tracer
+)
+
+//LogConfig configures the step logs of CallTracer
+type LogConfig struct {
+	EnableStack  bool `json:"enableStack"`  // enable the eval stack snapshot of steps
+	DisableSteps bool `json:"disableSteps"` // only trace the contract calls
+	Limit        int  `json:"limit"`        // maximum number of steps to record, zero means DEFAULT_STEP_LIMIT
+}
+
+//StepLog is the log of a single neovm opcode execution
+type StepLog struct {
+	Pc      int      `json:"pc"`
+	Op      string   `json:"op"`
+	Gas     uint64   `json:"gas"`
+	GasCost uint64   `json:"gasCost"`
+	Depth   int      `json:"depth"`
+	Syscall string   `json:"syscall,omitempty"`
+	Stack   []string `json:"stack,omitempty"`
+}
+
+//CallFrame is a contract call in the call tree of a transaction
+type CallFrame struct {
+	Type    string       `json:"type"`
+	Address string       `json:"address"`
+	Method  string       `json:"method,omitempty"`
+	Input   string       `json:"input"`
+	Gas     uint64       `json:"gas"`
+	GasUsed uint64       `json:"gasUsed"`
+	Output  interface{}  `json:"output,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Steps   []*StepLog   `json:"steps,omitempty"`
+	Calls   []*CallFrame `json:"calls,omitempty"`
+}
+
+//CallTracer records the call tree across neovm, native and wasmvm contracts, with the step logs of neovm
+type CallTracer struct {
+	cfg       LogConfig
+	root      *CallFrame
+	callstack []*CallFrame
+	steps     int
+}
+
+func NewCallTracer(cfg *LogConfig) *CallTracer {
+	t := &CallTracer{}
+	if cfg != nil {
+		t.cfg = *cfg
+	}
+	if t.cfg.Limit <= 0 {
+		t.cfg.Limit = DEFAULT_STEP_LIMIT
+	} else if t.cfg.Limit > MAX_STEP_LIMIT {
+		t.cfg.Limit = MAX_STEP_LIMIT
+	}
+	return t
+}
+
+//CaptureEnter is called when a contract of any vm type is invoked
+func (t *CallTracer) CaptureEnter(vmType string, address common.Address, method string, input []byte, gas uint64) {
+	call := &CallFrame{
+		Type:    vmType,
+		Address: address.ToHexString(),
+		Method:  method,
+		Input:   hex.EncodeToString(input),
+		Gas:     gas,
+	}
+	if len(t.callstack) == 0 {
+		if t.root == nil {
+			t.root = call
+		}
+	} else {
+		parent := t.callstack[len(t.callstack)-1]
+		parent.Calls = append(parent.Calls, call)
+	}
+	t.callstack = append(t.callstack, call)
+}
+
+//CaptureExit is called when the contract invoked by the latest CaptureEnter returns
+func (t *CallTracer) CaptureExit(output interface{}, gasUsed uint64, err error) {
+	size := len(t.callstack)
+	if size == 0 {
+		return
+	}
+	call := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+
+	call.GasUsed = gasUsed
+	if err != nil {
+		call.Error = err.Error()
+	} else {
+		call.Output = output
+	}
+}
+
+//CaptureState implements the neovm Tracer interface to record a single step
+func (t *CallTracer) CaptureState(engine *vm.Executor, pc int, opcode vm.OpCode, gas, cost uint64) {
+	call := t.current()
+	if call == nil {
+		return
+	}
+	step := &StepLog{
+		Pc:      pc,
+		Op:      vm.OpCodeName(opcode),
+		Gas:     gas,
+		GasCost: cost,
+		Depth:   len(t.callstack),
+	}
+	if t.cfg.EnableStack {
+		step.Stack = dumpStack(engine)
+	}
+	call.Steps = append(call.Steps, step)
+	t.steps += 1
+}
+
+//CaptureSyscall implements the neovm Tracer interface to record the interop service called by the latest step
+func (t *CallTracer) CaptureSyscall(engine *vm.Executor, name string, gas, cost uint64) {
+	call := t.current()
+	if call == nil || len(call.Steps) == 0 {
+		return
+	}
+	step := call.Steps[len(call.Steps)-1]
+	step.Syscall = name
+	step.GasCost += cost
+}
+
+//current return the call frame to record steps in, or nil if steps should not be recorded
+func (t *CallTracer) current() *CallFrame {
+	if t.cfg.DisableSteps || len(t.callstack) == 0 {
+		return nil
+	}
+	if t.steps >= t.cfg.Limit {
+		return nil
+	}
+	return t.callstack[len(t.callstack)-1]
+}
+
+//GetResult returns the json-encoded call tree of the traced transaction
+func (t *CallTracer) GetResult() (json.RawMessage, error) {
+	if t.root == nil {
+		return nil, errors.New("no contract call traced")
+	}
+	res, err := json.Marshal(t.root)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), nil
+}
+
+//dumpStack return the eval stack of engine from bottom to top
+func dumpStack(engine *vm.Executor) []string {
+	if engine == nil || engine.EvalStack == nil {
+		return nil
+	}
+	count := engine.EvalStack.Count()
+	stack := make([]string, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		val, err := engine.EvalStack.Peek(int64(i))
+		if err != nil {
+			break
+		}
+		stack = append(stack, val.Dump())
+	}
+	return stack
+}
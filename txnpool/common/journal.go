@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/types"
+)
+
+// TxJournal records the accepted transactions to a file, so that they can be
+// re-submitted after the node restarts. Each record is the accepted unix time
+// followed by the raw transaction.
+type TxJournal struct {
+	sync.Mutex
+	path   string
+	maxAge time.Duration
+	writer *os.File
+	times  map[common.Uint256]int64 // accepted time of the journaled txs
+}
+
+func NewTxJournal(path string, maxAge time.Duration) *TxJournal {
+	return &TxJournal{
+		path:   path,
+		maxAge: maxAge,
+		times:  make(map[common.Uint256]int64),
+	}
+}
+
+func (self *TxJournal) expired(accepted int64, now time.Time) bool {
+	return self.maxAge > 0 && now.Sub(time.Unix(accepted, 0)) > self.maxAge
+}
+
+// Load reads the unexpired txs from the journal file, and opens it for appending
+// the new accepted txs. The damaged tail of the file is ignored.
+func (self *TxJournal) Load() ([]*types.Transaction, error) {
+	self.Lock()
+	defer self.Unlock()
+	var txs []*types.Transaction
+	data, err := ioutil.ReadFile(self.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	now := time.Now()
+	source := common.NewZeroCopySource(data)
+	for source.Len() > 0 {
+		accepted, eof := source.NextUint64()
+		raw, _, irregular, eof2 := source.NextVarBytes()
+		if eof || eof2 || irregular {
+			log.Warnf("tx journal: ignore the damaged data at offset %d", source.Pos())
+			break
+		}
+		tx, err := types.TransactionFromRawBytes(raw)
+		if err != nil {
+			log.Warnf("tx journal: ignore the invalid tx: %s", err)
+			continue
+		}
+		if self.expired(int64(accepted), now) {
+			continue
+		}
+		if _, ok := self.times[tx.Hash()]; !ok {
+			self.times[tx.Hash()] = int64(accepted)
+			txs = append(txs, tx)
+		}
+	}
+
+	self.writer, err = os.OpenFile(self.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+func writeJournalRecord(w io.Writer, tx *types.Transaction, accepted int64) error {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteUint64(uint64(accepted))
+	sink.WriteVarBytes(tx.ToArray())
+	_, err := w.Write(sink.Bytes())
+	return err
+}
+
+// Insert appends the accepted tx to the journal file
+func (self *TxJournal) Insert(tx *types.Transaction) error {
+	self.Lock()
+	defer self.Unlock()
+	if self.writer == nil {
+		return nil
+	}
+	if _, ok := self.times[tx.Hash()]; ok {
+		return nil
+	}
+	accepted := time.Now().Unix()
+	self.times[tx.Hash()] = accepted
+	return writeJournalRecord(self.writer, tx, accepted)
+}
+
+// Rotate rewrites the journal file with the txs still in tx pool, the expired ones are dropped
+func (self *TxJournal) Rotate(txs []*types.Transaction) error {
+	self.Lock()
+	defer self.Unlock()
+	if self.writer == nil {
+		return nil
+	}
+	file, err := os.OpenFile(self.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	times := make(map[common.Uint256]int64, len(txs))
+	writer := bufio.NewWriter(file)
+	for _, tx := range txs {
+		accepted, ok := self.times[tx.Hash()]
+		if !ok {
+			accepted = now.Unix()
+		}
+		if self.expired(accepted, now) {
+			continue
+		}
+		if err = writeJournalRecord(writer, tx, accepted); err != nil {
+			break
+		}
+		times[tx.Hash()] = accepted
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	self.writer.Close()
+	if err = os.Rename(self.path+".new", self.path); err != nil {
+		self.writer = nil
+		return err
+	}
+	self.writer, err = os.OpenFile(self.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		self.writer = nil
+		return err
+	}
+	self.times = times
+	log.Infof("tx journal rotated, %d transactions journaled", len(times))
+	return nil
+}
+
+// Close closes the journal file
+func (self *TxJournal) Close() error {
+	self.Lock()
+	defer self.Unlock()
+	if self.writer == nil {
+		return nil
+	}
+	err := self.writer.Close()
+	self.writer = nil
+	return err
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), JOURNAL_FILE_NAME)
+	journal := NewTxJournal(path, time.Hour)
+	txs, err := journal.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(txs))
+
+	tx1, tx2, tx3 := genNativeTx(1, 1, 2500), genNativeTx(1, 2, 2500), genNativeTx(2, 1, 2500)
+	for _, tx := range []*types.Transaction{tx1, tx2, tx2, tx3} {
+		assert.Nil(t, journal.Insert(tx))
+	}
+	assert.Nil(t, journal.Close())
+
+	journal = NewTxJournal(path, time.Hour)
+	txs, err = journal.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, []*types.Transaction{tx1, tx2, tx3}, txs)
+
+	// the txs not in pool are dropped by rotation
+	journal.times[tx3.Hash()] = time.Now().Add(-2 * time.Hour).Unix()
+	assert.Nil(t, journal.Rotate([]*types.Transaction{tx2, tx3}))
+	assert.Nil(t, journal.Close())
+
+	// the damaged tail is ignored
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	assert.Nil(t, err)
+	_, err = file.Write([]byte{1, 2, 3})
+	assert.Nil(t, err)
+	file.Close()
+
+	journal = NewTxJournal(path, time.Hour)
+	txs, err = journal.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, tx2.Hash(), txs[0].Hash())
+	assert.Nil(t, journal.Close())
+}
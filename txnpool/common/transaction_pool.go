@@ -22,10 +22,12 @@ package common
 import (
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/common/metrics"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/errors"
 	vt "github.com/ontio/ontology/validator/types"
@@ -46,6 +48,8 @@ type VerifiedTx struct {
 	Tx             *types.Transaction // transaction which has been verified
 	VerifiedHeight uint32
 	Nonce          uint64
+	Sender         SenderType // which sender the tx is from
+	Received       time.Time  // when the tx is received by txpool
 }
 
 func (self *VerifiedTx) IsVerfiyExpired(height uint32) bool {
@@ -71,22 +75,41 @@ type UserNonceInfo struct {
 	Nonce  uint64
 }
 
+// ReplacedTx records the native tx evicted by another one with the same payer and nonce
+type ReplacedTx struct {
+	By     common.Uint256
+	Height uint32
+}
+
 // TXPool contains all currently valid transactions. Transactions
 // enter the pool when they are valid from the network,
 // consensus or submitted. They exit the pool when they are included
 // in the ledger.
 type TXPool struct {
 	sync.RWMutex
-	validTxMap            map[common.Uint256]*VerifiedTx    // Transactions which have been verified
-	eipTxPool             map[common.Address]*txSortedMap   // The tx pool that holds the valid transaction
-	userLatestEiptxHeight map[common.Address]*UserNonceInfo // record last block height user commit eiptx
+	validTxMap            map[common.Uint256]*VerifiedTx               // Transactions which have been verified
+	eipTxPool             map[common.Address]*txSortedMap              // The executable eip txs of each payer
+	eipTxQueue            map[common.Address]*txSortedMap              // The eip txs waiting for a missing nonce
+	userLatestEiptxHeight map[common.Address]*UserNonceInfo            // record last block height user commit eiptx
+	payerTxs              map[common.Address]int                       // The number of txs in validTxMap of each payer
+	queued                int                                          // The number of txs in eipTxQueue
+	nativeTxs             map[common.Address]map[uint32]common.Uint256 // The native txs indexed by payer and nonce
+	replacedTxs           map[common.Uint256]*ReplacedTx               // The evicted native txs, kept to report their status
+	priced                *txPricedList                                // The eviction candidates sorted by gas price
+	limit                 config.TxPoolConfig
 }
 
 func NewTxPool() *TXPool {
 	return &TXPool{
 		validTxMap:            make(map[common.Uint256]*VerifiedTx),
 		eipTxPool:             make(map[common.Address]*txSortedMap),
+		eipTxQueue:            make(map[common.Address]*txSortedMap),
 		userLatestEiptxHeight: make(map[common.Address]*UserNonceInfo),
+		payerTxs:              make(map[common.Address]int),
+		nativeTxs:             make(map[common.Address]map[uint32]common.Uint256),
+		replacedTxs:           make(map[common.Uint256]*ReplacedTx),
+		priced:                newTxPricedList(),
+		limit:                 *config.DefConfig.TxPool,
 	}
 }
 
@@ -98,11 +121,18 @@ func (s *TXPool) CleanStaledEIPTx(height uint32) {
 			if height >= v.Height+EIPTX_EXPIRATION_BLOCKS {
 				if list := s.eipTxPool[addr]; list != nil {
 					for _, txn := range list.items {
-						delete(s.validTxMap, txn.Hash())
+						s.deleteTxLocked(txn.Hash())
 					}
 
 					delete(s.eipTxPool, addr)
 				}
+				if queue := s.eipTxQueue[addr]; queue != nil {
+					for _, txn := range queue.items {
+						s.deleteTxLocked(txn.Hash())
+					}
+					s.queued -= queue.Len()
+					delete(s.eipTxQueue, addr)
+				}
 				delete(s.userLatestEiptxHeight, addr)
 			}
 		}
@@ -141,23 +171,283 @@ func (s *TXPool) getTxListByAddr(addr common.Address) *txSortedMap {
 	return s.eipTxPool[addr]
 }
 
-func (s *TXPool) addEIPTxPool(trans *types.Transaction) (replaced *types.Transaction, err errors.ErrCode) {
-	list := s.getTxListByAddr(trans.Payer)
+func (s *TXPool) getTxQueueByAddr(addr common.Address) *txSortedMap {
+	if _, ok := s.eipTxQueue[addr]; !ok {
+		s.eipTxQueue[addr] = newTxSortedMap()
+	}
+
+	return s.eipTxQueue[addr]
+}
+
+// putTxLocked adds the tx to validTxMap and counts it for the payer
+func (s *TXPool) putTxLocked(txEntry *VerifiedTx) {
+	tx := txEntry.Tx
+	s.validTxMap[tx.Hash()] = txEntry
+	s.payerTxs[tx.Payer]++
+	delete(s.replacedTxs, tx.Hash())
+	if !tx.IsEipTx() {
+		if s.nativeTxs[tx.Payer] == nil {
+			s.nativeTxs[tx.Payer] = make(map[uint32]common.Uint256)
+		}
+		s.nativeTxs[tx.Payer][tx.Nonce] = tx.Hash()
+		s.priced.Put(tx)
+	}
+	if s.priced.Len() > 2*len(s.validTxMap)+PRICED_STALE_LIMIT {
+		s.reheapLocked()
+	}
+}
+
+// putLastEipTxLocked adds the executable eip tx with the highest nonce of the payer to the eviction
+// candidates, it should be called after the executable list of payer is changed
+func (s *TXPool) putLastEipTxLocked(payer common.Address) {
+	if list := s.eipTxPool[payer]; list != nil {
+		if last := list.Last(); last != nil {
+			s.priced.Put(last)
+		}
+	}
+}
+
+// evictableLocked checks whether the tx from the priced list is still an eviction candidate
+func (s *TXPool) evictableLocked(tx *types.Transaction) bool {
+	if !tx.IsEipTx() {
+		_, ok := s.validTxMap[tx.Hash()]
+		return ok
+	}
+	if list := s.eipTxPool[tx.Payer]; list != nil {
+		last := list.Last()
+		return last != nil && last.Hash() == tx.Hash()
+	}
+	return false
+}
+
+// reheapLocked rebuilds the priced list from the candidates in pool, to drop the stale ones
+func (s *TXPool) reheapLocked() {
+	candidates := make(Transactions, 0, len(s.validTxMap))
+	for _, txEntry := range s.validTxMap {
+		if !txEntry.Tx.IsEipTx() {
+			candidates = append(candidates, txEntry.Tx)
+		}
+	}
+	for _, list := range s.eipTxPool {
+		if last := list.Last(); last != nil {
+			candidates = append(candidates, last)
+		}
+	}
+	s.priced.Reset(candidates)
+}
+
+// deleteTxLocked removes the tx from validTxMap, the eip tx lists should be updated by caller
+func (s *TXPool) deleteTxLocked(hash common.Uint256) bool {
+	txEntry, ok := s.validTxMap[hash]
+	if !ok {
+		return false
+	}
+	delete(s.validTxMap, hash)
+	payer := txEntry.Tx.Payer
+	if s.payerTxs[payer] <= 1 {
+		delete(s.payerTxs, payer)
+	} else {
+		s.payerTxs[payer]--
+	}
+	if nonces := s.nativeTxs[payer]; nonces != nil && nonces[txEntry.Tx.Nonce] == hash {
+		delete(nonces, txEntry.Tx.Nonce)
+		if len(nonces) == 0 {
+			delete(s.nativeTxs, payer)
+		}
+	}
+	return true
+}
+
+// replaceableTxLocked returns the native tx in pool with the same payer and nonce which
+// can be evicted by tx, nil if the replacement is disabled or the gas price is not bumped
+func (s *TXPool) replaceableTxLocked(tx *types.Transaction) *VerifiedTx {
+	if !s.limit.EnableNativeReplace {
+		return nil
+	}
+	if hash, ok := s.nativeTxs[tx.Payer][tx.Nonce]; ok {
+		if old := s.validTxMap[hash]; old != nil && IsPriceBumped(old.Tx, tx, s.limit.PriceBump) {
+			return old
+		}
+	}
+	return nil
+}
+
+func (s *TXPool) markReplacedLocked(hash, by common.Uint256, height uint32) {
+	s.replacedTxs[hash] = &ReplacedTx{By: by, Height: height}
+	ShowTraceLog("tx %s is replaced by %s", hash.ToHexString(), by.ToHexString())
+}
+
+// removeEipTxLocked removes the eip tx from the executable list or the queue of its payer
+func (s *TXPool) removeEipTxLocked(tx *types.Transaction) bool {
+	nonce := uint64(tx.Nonce)
+	if list := s.eipTxPool[tx.Payer]; list != nil && list.Get(nonce) != nil && list.Get(nonce).Hash() == tx.Hash() {
+		list.Remove(nonce)
+		if list.Len() == 0 {
+			delete(s.eipTxPool, tx.Payer)
+		}
+		s.putLastEipTxLocked(tx.Payer)
+		return true
+	}
+	if queue := s.eipTxQueue[tx.Payer]; queue != nil && queue.Get(nonce) != nil && queue.Get(nonce).Hash() == tx.Hash() {
+		queue.Remove(nonce)
+		s.queued--
+		if queue.Len() == 0 {
+			delete(s.eipTxQueue, tx.Payer)
+		}
+		return true
+	}
+	return false
+}
+
+// executableTxsLocked returns the number of the executable txs of the payer
+func (s *TXPool) executableTxsLocked(payer common.Address) int {
+	count := s.payerTxs[payer]
+	if queue := s.eipTxQueue[payer]; queue != nil {
+		count -= queue.Len()
+	}
+	return count
+}
+
+// nextEipNonceLocked returns the nonce following the executable eip txs of the payer
+func (s *TXPool) nextEipNonceLocked(payer common.Address, ledgerNonce uint64) uint64 {
+	if list := s.eipTxPool[payer]; list != nil {
+		if heading := list.Heading(); len(heading) > 0 {
+			return uint64(heading[len(heading)-1].Nonce) + 1
+		}
+	}
+	return ledgerNonce
+}
+
+// promoteQueuedLocked moves the queued eip txs which become executable to the eip tx pool
+func (s *TXPool) promoteQueuedLocked(payer common.Address, next uint64) {
+	queue := s.eipTxQueue[payer]
+	if queue == nil {
+		return
+	}
+	for tx := queue.Get(next); tx != nil; tx = queue.Get(next) {
+		queue.Remove(next)
+		s.queued--
+		s.getTxListByAddr(payer).Put(tx)
+		ShowTraceLog("promote queued eip tx %s with nonce %d", tx.Hash().ToHexString(), next)
+		next++
+	}
+	if queue.Len() == 0 {
+		delete(s.eipTxQueue, payer)
+	}
+	s.putLastEipTxLocked(payer)
+}
+
+// makeRoomLocked checks the slots limits for a new executable tx, and evicts the cheapest tx of
+// other payers when the pool is full
+func (s *TXPool) makeRoomLocked(tx *types.Transaction) errors.ErrCode {
+	if s.limit.AccountSlots > 0 && s.executableTxsLocked(tx.Payer) >= int(s.limit.AccountSlots) {
+		return errors.ErrTxPoolAccountLimit
+	}
+	if s.limit.GlobalSlots > 0 && len(s.validTxMap)-s.queued >= int(s.limit.GlobalSlots) {
+		if !s.evictLocked(tx) {
+			return errors.ErrTxPoolUnderpriced
+		}
+	}
+	return errors.ErrNoError
+}
+
+// evictLocked drops the executable tx with the lowest gas price which is lower than the new tx.
+// The eip txs are only dropped from the highest nonce, so that the others are still executable
+func (s *TXPool) evictLocked(tx *types.Transaction) bool {
+	var cheapest *types.Transaction
+	var skipped Transactions
+	for cheapest == nil {
+		top := s.priced.Pop()
+		if top == nil {
+			break
+		}
+		if !s.evictableLocked(top) {
+			continue
+		}
+		if top.Payer == tx.Payer {
+			skipped = append(skipped, top)
+			continue
+		}
+		cheapest = top
+	}
+	for _, skip := range skipped {
+		s.priced.Put(skip)
+	}
+	if cheapest == nil {
+		return false
+	}
+	if cheapest.GasPrice >= tx.GasPrice {
+		s.priced.Put(cheapest)
+		return false
+	}
+
+	if cheapest.IsEipTx() {
+		s.removeEipTxLocked(cheapest)
+	}
+	s.deleteTxLocked(cheapest.Hash())
+	metrics.TxPoolEvicted.Inc()
+	ShowTraceLog("evict tx %s with gas price %d for tx %s with gas price %d", cheapest.Hash().ToHexString(),
+		cheapest.GasPrice, tx.Hash().ToHexString(), tx.GasPrice)
+	return true
+}
+
+// addEipTx adds the executable eip tx to the eip tx pool, and the one with nonce gap to the queue
+func (s *TXPool) addEipTx(txEntry *VerifiedTx) errors.ErrCode {
+	trans := txEntry.Tx
+	nonce := uint64(trans.Nonce)
+	//check the new tx nonce should not be greater than latest nonce + 1000
+	if nonce >= txEntry.Nonce+EIPTX_NONCE_MAX_GAP {
+		return errors.ErrETHTxNonceToobig
+	}
 
 	// does the same nonce exist?
-	old := list.Get(uint64(trans.Nonce))
-	if old == nil {
-		s.eipTxPool[trans.Payer].Put(trans)
-		return nil, errors.ErrNoError
+	list := s.eipTxPool[trans.Payer]
+	if list == nil || list.Get(nonce) == nil {
+		list = s.eipTxQueue[trans.Payer]
+	}
+	if list != nil && list.Get(nonce) != nil {
+		old := list.Get(nonce)
+		if trans.GasPrice <= old.GasPrice*101/100 {
+			return errors.ErrSameNonceExist
+		}
+		log.Infof("replace transaction %s with higher gas fee", old.Hash().ToHexString())
+		s.deleteTxLocked(old.Hash())
+		list.Put(trans)
+		s.putTxLocked(txEntry)
+		s.putLastEipTxLocked(trans.Payer)
+		return errors.ErrNoError
 	}
 
-	if trans.GasPrice > old.GasPrice*101/100 {
-		log.Infof("replace transaction %s with lower gas fee", old.Hash().ToHexString())
-		s.eipTxPool[trans.Payer].Put(trans)
-		return old, errors.ErrNoError
+	next := s.nextEipNonceLocked(trans.Payer, txEntry.Nonce)
+	if nonce <= next {
+		if code := s.makeRoomLocked(trans); !code.Success() {
+			return code
+		}
+		s.getTxListByAddr(trans.Payer).Put(trans)
+		s.putTxLocked(txEntry)
+		s.promoteQueuedLocked(trans.Payer, s.nextEipNonceLocked(trans.Payer, txEntry.Nonce))
+		s.putLastEipTxLocked(trans.Payer)
+	} else {
+		if s.limit.AccountQueue > 0 && s.eipTxQueue[trans.Payer] != nil &&
+			s.eipTxQueue[trans.Payer].Len() >= int(s.limit.AccountQueue) {
+			return errors.ErrTxPoolQueueFull
+		}
+		if s.limit.GlobalQueue > 0 && s.queued >= int(s.limit.GlobalQueue) {
+			return errors.ErrTxPoolQueueFull
+		}
+		s.getTxQueueByAddr(trans.Payer).Put(trans)
+		s.queued++
+		s.putTxLocked(txEntry)
+		ShowTraceLog("queue eip tx %s with nonce %d, want %d", trans.Hash().ToHexString(), nonce, next)
 	}
 
-	return nil, errors.ErrSameNonceExist
+	if s.userLatestEiptxHeight[trans.Payer] == nil {
+		s.userLatestEiptxHeight[trans.Payer] = &UserNonceInfo{
+			Height: txEntry.VerifiedHeight,
+			Nonce:  txEntry.Nonce,
+		}
+	}
+	return errors.ErrNoError
 }
 
 // AddTxList adds a valid transaction to the transaction pool. If the
@@ -168,33 +458,36 @@ func (tp *TXPool) AddTxList(txEntry *VerifiedTx) errors.ErrCode {
 	tp.Lock()
 	defer tp.Unlock()
 	txHash := txEntry.Tx.Hash()
-	if txEntry.Tx.IsEipTx() {
-		//check the new tx nonce should not be greater than latest nonce + 1000
-		if uint64(txEntry.Tx.Nonce) >= txEntry.Nonce+EIPTX_NONCE_MAX_GAP {
-			return errors.ErrETHTxNonceToobig
-		}
-
-		repalced, code := tp.addEIPTxPool(txEntry.Tx)
-		if repalced != nil {
-			delete(tp.validTxMap, repalced.Hash())
-		}
-		if !code.Success() {
-			return code
-		}
-		if tp.userLatestEiptxHeight[txEntry.Tx.Payer] == nil {
-			tp.userLatestEiptxHeight[txEntry.Tx.Payer] = &UserNonceInfo{
-				Height: txEntry.VerifiedHeight,
-				Nonce:  txEntry.Nonce,
-			}
-		}
-	}
-
 	if _, ok := tp.validTxMap[txHash]; ok {
 		ShowTraceLog("AddTxList: transaction %x is already in the pool", txHash)
 		return errors.ErrDuplicatedTx
 	}
 
-	tp.validTxMap[txHash] = txEntry
+	if txEntry.Tx.IsEipTx() {
+		return tp.addEipTx(txEntry)
+	}
+
+	// native nonces are not unique on chain, so the replacement is only a best effort local
+	// eviction, the tx with the same payer and nonce is kept if the gas price is not bumped
+	if old := tp.replaceableTxLocked(txEntry.Tx); old != nil {
+		log.Infof("replace transaction %s with higher gas fee", old.Tx.Hash().ToHexString())
+		tp.deleteTxLocked(old.Tx.Hash())
+		tp.markReplacedLocked(old.Tx.Hash(), txHash, txEntry.VerifiedHeight)
+	} else if code := tp.makeRoomLocked(txEntry.Tx); !code.Success() {
+		return code
+	}
+	tp.putTxLocked(txEntry)
+	return errors.ErrNoError
+}
+
+// CheckLimit rejects the tx before verifying it, if its payer has used up the slots
+func (tp *TXPool) CheckLimit(tx *types.Transaction) errors.ErrCode {
+	tp.RLock()
+	defer tp.RUnlock()
+	// the eip tx may replace a tx with the same nonce or wait in the queue
+	if !tx.IsEipTx() && tp.limit.AccountSlots > 0 && tp.executableTxsLocked(tx.Payer) >= int(tp.limit.AccountSlots) {
+		return errors.ErrTxPoolAccountLimit
+	}
 	return errors.ErrNoError
 }
 
@@ -202,18 +495,32 @@ func (tp *TXPool) AddTxList(txEntry *VerifiedTx) errors.ErrCode {
 func (s *TXPool) cleanCompletedEipTxPool(txs []*types.Transaction, height uint32) []*types.Transaction {
 	var cleaned []*types.Transaction
 	for _, tx := range txs {
-		if tx.IsEipTx() {
-			if _, ok := s.eipTxPool[tx.Payer]; ok {
-				cleaned = append(cleaned, s.eipTxPool[tx.Payer].Forward(uint64(tx.Nonce+1))...)
-				if s.eipTxPool[tx.Payer].Len() == 0 {
-					delete(s.eipTxPool, tx.Payer)
-					delete(s.userLatestEiptxHeight, tx.Payer)
-				} else {
-					s.userLatestEiptxHeight[tx.Payer] = &UserNonceInfo{
-						Height: height,
-						Nonce:  uint64(tx.Nonce) + 1,
-					}
-				}
+		if !tx.IsEipTx() {
+			continue
+		}
+		next := uint64(tx.Nonce) + 1
+		if list := s.eipTxPool[tx.Payer]; list != nil {
+			cleaned = append(cleaned, list.Forward(next)...)
+			if list.Len() == 0 {
+				delete(s.eipTxPool, tx.Payer)
+			}
+		}
+		if queue := s.eipTxQueue[tx.Payer]; queue != nil {
+			forwarded := queue.Forward(next)
+			s.queued -= len(forwarded)
+			cleaned = append(cleaned, forwarded...)
+			if queue.Len() == 0 {
+				delete(s.eipTxQueue, tx.Payer)
+			}
+		}
+		s.promoteQueuedLocked(tx.Payer, s.nextEipNonceLocked(tx.Payer, next))
+
+		if s.eipTxPool[tx.Payer] == nil && s.eipTxQueue[tx.Payer] == nil {
+			delete(s.userLatestEiptxHeight, tx.Payer)
+		} else {
+			s.userLatestEiptxHeight[tx.Payer] = &UserNonceInfo{
+				Height: height,
+				Nonce:  next,
 			}
 		}
 	}
@@ -229,12 +536,16 @@ func (tp *TXPool) CleanCompletedTransactionList(txs []*types.Transaction, height
 	cleanedEips := tp.cleanCompletedEipTxPool(txs, height)
 	txs = append(txs, cleanedEips...)
 	for _, tx := range txs {
-		if _, ok := tp.validTxMap[tx.Hash()]; ok {
-			delete(tp.validTxMap, tx.Hash())
+		if tp.deleteTxLocked(tx.Hash()) {
 			cleaned++
 			ShowTraceLog("transaction cleaned: %s", tx.Hash().ToHexString())
 		}
 	}
+	for hash, replaced := range tp.replacedTxs {
+		if height >= replaced.Height+REPLACED_TX_EXPIRATION {
+			delete(tp.replacedTxs, hash)
+		}
+	}
 
 	ShowTraceLog("clean txes: total %d, cleaned %d, remains %d in TxPool", txsNum, cleaned, len(tp.validTxMap))
 }
@@ -280,7 +591,7 @@ func (tp *TXPool) selectSortEIP155WithLock(eiptxs []Transactions) []*VerifiedTx
 // gets the transaction lists from the pool for the consensus,
 // if the byCount is marked, return the configured number at most; if the
 // the byCount is not marked, return all of the current transaction pool.
-func (tp *TXPool) GetTxPool(byCount bool, height uint32) ([]*VerifiedTx, []*types.Transaction) {
+func (tp *TXPool) GetTxPool(byCount bool, height uint32) ([]*VerifiedTx, []*VerifiedTx) {
 	tp.RLock()
 
 	eiplst := make([]Transactions, 0, len(tp.eipTxPool))
@@ -315,10 +626,10 @@ func (tp *TXPool) GetTxPool(byCount bool, height uint32) ([]*VerifiedTx, []*type
 	}
 
 	validList := make([]*VerifiedTx, 0, count)
-	oldTxList := make([]*types.Transaction, 0)
+	oldTxList := make([]*VerifiedTx, 0)
 	for _, txEntry := range orderByFeeList {
 		if txEntry.IsVerfiyExpired(height) {
-			oldTxList = append(oldTxList, txEntry.Tx)
+			oldTxList = append(oldTxList, txEntry)
 			continue
 		}
 		if len(validList) < count {
@@ -327,10 +638,11 @@ func (tp *TXPool) GetTxPool(byCount bool, height uint32) ([]*VerifiedTx, []*type
 	}
 
 	tp.Lock()
-	for _, tx := range oldTxList {
-		delete(tp.validTxMap, tx.Hash())
+	for _, txEntry := range oldTxList {
+		tx := txEntry.Tx
+		tp.deleteTxLocked(tx.Hash())
 		if tx.IsEipTx() {
-			removed := tp.eipTxPool[tx.Payer].Remove(uint64(tx.Nonce))
+			removed := tp.removeEipTxLocked(tx)
 			if !removed {
 				log.Errorf("transaction not in eip pool: %s, impossible", tx.Hash().ToHexString())
 			}
@@ -361,6 +673,10 @@ func (tp *TXPool) GetTxStatus(hash common.Uint256) *TxStatus {
 	defer tp.RUnlock()
 	txEntry, ok := tp.validTxMap[hash]
 	if !ok {
+		if replaced := tp.replacedTxs[hash]; replaced != nil {
+			by := replaced.By
+			return &TxStatus{Hash: hash, ReplacedBy: &by}
+		}
 		return nil
 	}
 	ret := &TxStatus{
@@ -377,6 +693,44 @@ func (tp *TXPool) GetTransactionCount() int {
 	return len(tp.validTxMap)
 }
 
+// GetTransactions returns all the txs in the pool.
+func (tp *TXPool) GetTransactions() []*types.Transaction {
+	tp.RLock()
+	defer tp.RUnlock()
+	ret := make([]*types.Transaction, 0, len(tp.validTxMap))
+	for _, txEntry := range tp.validTxMap {
+		ret = append(ret, txEntry.Tx)
+	}
+	return ret
+}
+
+// GetPoolTxs returns the txs in the pool sorted by payer and nonce, only the
+// txs of the payer are returned if payer is not nil.
+func (tp *TXPool) GetPoolTxs(payer *common.Address) []*PoolTxInfo {
+	tp.RLock()
+	defer tp.RUnlock()
+	ret := make([]*PoolTxInfo, 0)
+	for _, txEntry := range tp.validTxMap {
+		tx := txEntry.Tx
+		if payer != nil && tx.Payer != *payer {
+			continue
+		}
+		queued := false
+		if queue := tp.eipTxQueue[tx.Payer]; queue != nil && tx.IsEipTx() {
+			old := queue.Get(uint64(tx.Nonce))
+			queued = old != nil && old.Hash() == tx.Hash()
+		}
+		ret = append(ret, &PoolTxInfo{
+			Tx:       tx,
+			Sender:   txEntry.Sender,
+			Received: txEntry.Received,
+			Queued:   queued,
+		})
+	}
+	SortPoolTxs(ret)
+	return ret
+}
+
 // GetTransactionCount returns the tx number of the pool.
 func (tp *TXPool) GetTransactionHashList() []common.Uint256 {
 	tp.RLock()
@@ -429,9 +783,9 @@ func (tp *TXPool) RemoveTxsBelowGasPrice(gasPrice uint64) {
 	for _, txEntry := range tp.validTxMap {
 		tx := txEntry.Tx
 		if tx.GasPrice < gasPrice {
-			delete(tp.validTxMap, tx.Hash())
+			tp.deleteTxLocked(tx.Hash())
 			if tx.IsEipTx() {
-				tp.eipTxPool[tx.Payer].Remove(uint64(tx.Nonce))
+				tp.removeEipTxLocked(tx)
 			}
 			ShowTraceLog("tx %s cleaned because of lower gas: %d, want: %d", tx.Hash().ToHexString(), txEntry.Tx.GasPrice, gasPrice)
 		}
@@ -439,15 +793,18 @@ func (tp *TXPool) RemoveTxsBelowGasPrice(gasPrice uint64) {
 }
 
 // returns the remaining tx list to cleanup
-func (tp *TXPool) Remain() []*types.Transaction {
+func (tp *TXPool) Remain() []*VerifiedTx {
 	tp.Lock()
 	defer tp.Unlock()
 
 	tp.eipTxPool = make(map[common.Address]*txSortedMap) // clean all eip tx
-	txList := make([]*types.Transaction, 0, len(tp.validTxMap))
+	tp.eipTxQueue = make(map[common.Address]*txSortedMap)
+	tp.queued = 0
+	tp.priced = newTxPricedList()
+	txList := make([]*VerifiedTx, 0, len(tp.validTxMap))
 	for _, txEntry := range tp.validTxMap {
-		txList = append(txList, txEntry.Tx)
-		delete(tp.validTxMap, txEntry.Tx.Hash())
+		txList = append(txList, txEntry)
+		tp.deleteTxLocked(txEntry.Tx.Hash())
 		ShowTraceLog("pool remain: remove tx: %s from pool", txEntry.Tx.Hash().ToHexString())
 	}
 
@@ -19,12 +19,20 @@
 package common
 
 import (
+	"math/big"
 	"testing"
 	"time"
 
+	ethcomm "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/constants"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -94,3 +102,181 @@ func TestTxPool(t *testing.T) {
 
 	txPool.CleanCompletedTransactionList([]*types.Transaction{txn}, 0)
 }
+
+func genTxWithPayer(payer byte, gasPrice uint64) *types.Transaction {
+	return genNativeTx(payer, uint32(time.Now().UnixNano()), gasPrice)
+}
+
+func genNativeTx(payer byte, nonce uint32, gasPrice uint64) *types.Transaction {
+	mutable := &types.MutableTransaction{
+		TxType:   types.InvokeNeo,
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Payer:    common.Address{payer},
+		Payload:  &payload.InvokeCode{Code: []byte{}},
+	}
+	tx, _ := mutable.IntoImmutable()
+	return tx
+}
+
+func genEipTx(t *testing.T, nonce uint64) *types.Transaction {
+	config.DefConfig.P2PNode.EVMChainId = 12345
+	privateKey, _ := crypto.HexToECDSA("fad9c8855b740a0b7ed4c221dbad0f33a83a49cad6b3fe8d5817ac83d38b6a19")
+	tx := ethtypes.NewTransaction(nonce, ethcomm.Address{}, big.NewInt(1), 21000, big.NewInt(2500*constants.GWei), nil)
+	signedTx, err := ethtypes.SignTx(tx, ethtypes.NewEIP155Signer(big.NewInt(12345)), privateKey)
+	assert.Nil(t, err)
+	otx, err := types.TransactionFromEIP155(signedTx)
+	assert.Nil(t, err)
+	return otx
+}
+
+func TestTxPoolLimit(t *testing.T) {
+	txPool := NewTxPool()
+	txPool.limit = config.TxPoolConfig{GlobalSlots: 2, AccountSlots: 1}
+
+	tx1 := genTxWithPayer(1, 1)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx1}))
+	assert.Equal(t, errors.ErrTxPoolAccountLimit, txPool.CheckLimit(genTxWithPayer(1, 5)))
+	assert.Equal(t, errors.ErrTxPoolAccountLimit, txPool.AddTxList(&VerifiedTx{Tx: genTxWithPayer(1, 5)}))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: genTxWithPayer(2, 2)}))
+
+	// pool is full, only the tx with higher gas price than the lowest one is accepted
+	assert.Equal(t, errors.ErrTxPoolUnderpriced, txPool.AddTxList(&VerifiedTx{Tx: genTxWithPayer(3, 1)}))
+	tx3 := genTxWithPayer(3, 3)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx3}))
+	assert.Nil(t, txPool.GetTransaction(tx1.Hash()))
+	assert.NotNil(t, txPool.GetTransaction(tx3.Hash()))
+	assert.Equal(t, 2, txPool.GetTransactionCount())
+
+	// the slot of payer is released after the tx is committed
+	txPool.CleanCompletedTransactionList([]*types.Transaction{tx3}, 1)
+	assert.Equal(t, errors.ErrNoError, txPool.CheckLimit(genTxWithPayer(3, 3)))
+}
+
+func TestTxPoolEvict(t *testing.T) {
+	txPool := NewTxPool()
+	txPool.limit = config.TxPoolConfig{GlobalSlots: 3}
+
+	tx1, tx2, tx3 := genTxWithPayer(1, 5), genTxWithPayer(2, 2), genTxWithPayer(3, 4)
+	for _, tx := range []*types.Transaction{tx1, tx2, tx3} {
+		assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx}))
+	}
+	// the committed tx left in the priced heap is skipped
+	txPool.CleanCompletedTransactionList([]*types.Transaction{tx2}, 1)
+	tx4 := genTxWithPayer(4, 3)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx4}))
+	tx5 := genTxWithPayer(5, 6)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx5}))
+	assert.Nil(t, txPool.GetTransaction(tx4.Hash()))
+
+	// the txs of the same payer are not evicted
+	assert.Equal(t, errors.ErrTxPoolUnderpriced, txPool.AddTxList(&VerifiedTx{Tx: genTxWithPayer(3, 5)}))
+	tx6 := genTxWithPayer(6, 5)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx6}))
+	assert.Nil(t, txPool.GetTransaction(tx3.Hash()))
+	assert.NotNil(t, txPool.GetTransaction(tx1.Hash()))
+
+	// only the eip tx with the highest nonce is evicted
+	txPool = NewTxPool()
+	txPool.limit = config.TxPoolConfig{GlobalSlots: 2}
+	eip0, eip1 := genEipTx(t, 0), genEipTx(t, 1)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: eip0}))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: eip1}))
+	tx7 := genTxWithPayer(7, eip1.GasPrice+1)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx7}))
+	assert.Nil(t, txPool.GetTransaction(eip1.Hash()))
+	assert.NotNil(t, txPool.GetTransaction(eip0.Hash()))
+	assert.Equal(t, 1, txPool.eipTxPool[eip0.Payer].Len())
+}
+
+func TestEipTxQueue(t *testing.T) {
+	txPool := NewTxPool()
+	txPool.limit = config.TxPoolConfig{AccountQueue: 2}
+
+	tx0, tx1, tx2, tx3 := genEipTx(t, 0), genEipTx(t, 1), genEipTx(t, 2), genEipTx(t, 3)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx2}))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx3}))
+	assert.Equal(t, errors.ErrTxPoolQueueFull, txPool.AddTxList(&VerifiedTx{Tx: genEipTx(t, 4)}))
+	txs, _ := txPool.GetTxPool(false, 0)
+	assert.Equal(t, 0, len(txs))
+
+	// the queued txs are promoted when the missing nonce arrives
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx0}))
+	assert.Equal(t, uint64(1), txPool.NextNonce(tx0.Payer))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx1}))
+	assert.Equal(t, uint64(4), txPool.NextNonce(tx0.Payer))
+	assert.Equal(t, 0, txPool.queued)
+
+	txPool.CleanCompletedTransactionList([]*types.Transaction{tx0, tx1}, 1)
+	assert.Equal(t, 2, txPool.GetTransactionCount())
+	assert.Equal(t, uint64(4), txPool.NextNonce(tx0.Payer))
+}
+
+func TestNativeTxReplace(t *testing.T) {
+	// the txs with the same payer and nonce are all kept by default
+	txPool := NewTxPool()
+	txPool.limit = config.TxPoolConfig{PriceBump: 10}
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: genNativeTx(1, 7, 100)}))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: genNativeTx(1, 7, 200)}))
+	assert.Equal(t, 2, txPool.GetTransactionCount())
+
+	txPool = NewTxPool()
+	txPool.limit = config.TxPoolConfig{PriceBump: 10, EnableNativeReplace: true}
+	tx1 := genNativeTx(1, 7, 100)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx1}))
+	// the underpriced one is not rejected
+	tx2 := genNativeTx(1, 7, 105)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx2}))
+	assert.Equal(t, 2, txPool.GetTransactionCount())
+
+	tx3 := genNativeTx(1, 7, 120)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx3, VerifiedHeight: 1}))
+	assert.Nil(t, txPool.GetTransaction(tx2.Hash()))
+	assert.Equal(t, tx3.Hash(), *txPool.GetTxStatus(tx2.Hash()).ReplacedBy)
+	assert.Equal(t, 2, txPool.GetTransactionCount())
+
+	// the evicted one is accepted again if it is sent again
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx2}))
+	assert.Nil(t, txPool.GetTxStatus(tx2.Hash()).ReplacedBy)
+
+	tx4 := genNativeTx(1, 8, 100)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx4}))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: genNativeTx(1, 8, 110), VerifiedHeight: 1}))
+	txPool.CleanCompletedTransactionList(nil, REPLACED_TX_EXPIRATION)
+	assert.NotNil(t, txPool.GetTxStatus(tx4.Hash()))
+	txPool.CleanCompletedTransactionList(nil, 1+REPLACED_TX_EXPIRATION)
+	assert.Nil(t, txPool.GetTxStatus(tx4.Hash()))
+}
+
+func TestIsPriceBumped(t *testing.T) {
+	old := genNativeTx(1, 1, 2500)
+	assert.False(t, IsPriceBumped(old, genNativeTx(1, 1, 2500), 0))
+	assert.True(t, IsPriceBumped(old, genNativeTx(1, 1, 2501), 0))
+	assert.False(t, IsPriceBumped(old, genNativeTx(1, 1, 2749), 10))
+	assert.True(t, IsPriceBumped(old, genNativeTx(1, 1, 2750), 10))
+}
+
+func TestGetPoolTxs(t *testing.T) {
+	txPool := NewTxPool()
+
+	native := genNativeTx(1, 9, 100)
+	received := time.Now()
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: native, Sender: HttpSender, Received: received}))
+	tx0, tx2 := genEipTx(t, 0), genEipTx(t, 2)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx2, Sender: NetSender}))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTxList(&VerifiedTx{Tx: tx0, Sender: NetSender}))
+
+	txs := txPool.GetPoolTxs(&tx0.Payer)
+	assert.Equal(t, 2, len(txs))
+	assert.Equal(t, tx0.Hash(), txs[0].Tx.Hash())
+	assert.False(t, txs[0].Queued)
+	assert.Equal(t, tx2.Hash(), txs[1].Tx.Hash())
+	assert.True(t, txs[1].Queued)
+	assert.Equal(t, "net", txs[1].Sender.String())
+
+	txs = txPool.GetPoolTxs(&native.Payer)
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, HttpSender, txs[0].Sender)
+	assert.Equal(t, received, txs[0].Received)
+	assert.Equal(t, 3, len(txPool.GetPoolTxs(nil)))
+}
@@ -129,7 +129,85 @@ func (m *txSortedMap) Heading() Transactions {
 	return heading
 }
 
+// Last returns the transaction with the highest nonce.
+func (m *txSortedMap) Last() *types.Transaction {
+	var last *types.Transaction
+	for _, tx := range m.items {
+		if last == nil || tx.Nonce > last.Nonce {
+			last = tx
+		}
+	}
+	return last
+}
+
 // Len returns the length of the transaction map.
 func (m *txSortedMap) Len() int {
 	return len(m.items)
 }
+
+// priceHeap is a heap.Interface implementation over transactions for retrieving
+// the cheapest one first.
+type priceHeap []*types.Transaction
+
+func (h priceHeap) Len() int           { return len(h) }
+func (h priceHeap) Less(i, j int) bool { return h[i].GasPrice < h[j].GasPrice }
+func (h priceHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *priceHeap) Push(x interface{}) {
+	*h = append(*h, x.(*types.Transaction))
+}
+
+func (h *priceHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
+
+// txPricedList is a gas price sorted heap of the eviction candidates. The candidates
+// removed from pool are left in the heap and dropped when they reach the top, so the
+// caller should check whether the returned one is still a candidate.
+type txPricedList struct {
+	items *priceHeap
+}
+
+// newTxPricedList creates a new price-sorted transaction heap.
+func newTxPricedList() *txPricedList {
+	return &txPricedList{
+		items: new(priceHeap),
+	}
+}
+
+// Put inserts a new candidate into the heap.
+func (l *txPricedList) Put(tx *types.Transaction) {
+	heap.Push(l.items, tx)
+}
+
+// Peek returns the cheapest transaction without removing it.
+func (l *txPricedList) Peek() *types.Transaction {
+	if l.items.Len() == 0 {
+		return nil
+	}
+	return (*l.items)[0]
+}
+
+// Pop removes and returns the cheapest transaction.
+func (l *txPricedList) Pop() *types.Transaction {
+	if l.items.Len() == 0 {
+		return nil
+	}
+	return heap.Pop(l.items).(*types.Transaction)
+}
+
+// Reset replaces the heap with the candidates, to drop the stale ones.
+func (l *txPricedList) Reset(txs Transactions) {
+	items := priceHeap(txs)
+	heap.Init(&items)
+	l.items = &items
+}
+
+// Len returns the number of transactions in the heap, including the stale ones.
+func (l *txPricedList) Len() int {
+	return l.items.Len()
+}
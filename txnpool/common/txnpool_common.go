@@ -19,8 +19,11 @@
 package common
 
 import (
+	"bytes"
 	"math/big"
+	"sort"
 	"sync/atomic"
+	"time"
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
@@ -34,24 +37,45 @@ import (
 )
 
 const (
-	MAX_CAPACITY            = 100140      // The tx pool's capacity that holds the verified txs
+	MAX_CAPACITY            = 110140      // The tx pool's capacity that holds the verified txs, checked before verifying
 	MAX_PENDING_TXN         = 4096 * 10   // The max length of pending txs
 	MAX_LIMITATION          = 10000       // The length of pending tx from net and http
 	UPDATE_FREQUENCY        = 100         // The frequency to update gas price from global params
 	MAX_TX_SIZE             = 1024 * 1024 // The max size of a transaction to prevent DOS attacks
 	EIPTX_EXPIRATION_BLOCKS = 50          // eip pending nonce tx expire block count
 	EIPTX_NONCE_MAX_GAP     = 1000        // max nonce gap from new tx to tx pool
+	REPLACED_TX_EXPIRATION  = 1000        // block count to keep the replaced native tx records
+	PRICED_STALE_LIMIT      = 1024        // The stale eviction candidates allowed before rebuilding the heap
+	JOURNAL_ROTATE_INTERVAL = time.Hour   // The interval to rewrite tx journal with the txs in pool
+	JOURNAL_FILE_NAME       = "txpool.journal"
 )
 
 // SenderType enumerates the kind of tx submitter
 type SenderType uint8
 
 const (
-	NilSender  SenderType = iota
-	NetSender             // Net sends tx req
-	HttpSender            // Http sends tx req
+	NilSender       SenderType = iota
+	NetSender                  // Net sends tx req
+	HttpSender                 // Http sends tx req
+	JournalSender              // Tx journal re-submits tx after restart
+	ConsensusSender            // Consensus submits tx, such as faulty report
 )
 
+func (self SenderType) String() string {
+	switch self {
+	case NetSender:
+		return "net"
+	case HttpSender:
+		return "http"
+	case JournalSender:
+		return "journal"
+	case ConsensusSender:
+		return "consensus"
+	default:
+		return "nil"
+	}
+}
+
 // CheckBlkResult contains a verifed tx list,
 // an unverified tx list and an old tx list
 // to be re-verifed
@@ -63,8 +87,28 @@ type CheckBlkResult struct {
 
 // TxStatus contains the attributes of a transaction
 type TxStatus struct {
-	Hash  common.Uint256 // transaction hash
-	Attrs []*TXAttr      // transaction's status
+	Hash       common.Uint256  // transaction hash
+	Attrs      []*TXAttr       // transaction's status
+	ReplacedBy *common.Uint256 // the tx replaced this one, nil if not replaced
+}
+
+// PoolTxInfo contains the details of a transaction in txpool for the mempool query api
+type PoolTxInfo struct {
+	Tx        *types.Transaction
+	Sender    SenderType // which sender the tx is from
+	Received  time.Time  // when the tx is received by txpool
+	Queued    bool       // eip tx waiting for a missing nonce
+	Verifying bool       // tx is still being verified
+}
+
+// SortPoolTxs sorts the txs by payer and nonce
+func SortPoolTxs(txs []*PoolTxInfo) {
+	sort.Slice(txs, func(i, j int) bool {
+		if txs[i].Tx.Payer != txs[j].Tx.Payer {
+			return bytes.Compare(txs[i].Tx.Payer[:], txs[j].Tx.Payer[:]) < 0
+		}
+		return txs[i].Tx.Nonce < txs[j].Tx.Nonce
+	})
 }
 
 type TxResult struct {
@@ -131,6 +175,12 @@ type GetTxnPoolReq struct {
 	Height  uint32
 }
 
+// AppendTxReq submits a transaction to txpool without waiting for the result.
+type AppendTxReq struct {
+	Sender SenderType
+	Tx     *types.Transaction
+}
+
 // GetTxnPoolRsp returns a transaction list for GetTxnPoolReq.
 type GetTxnPoolRsp struct {
 	TxnPool []*VerifiedTx
@@ -141,6 +191,7 @@ type TxPoolService interface {
 	GetTransactionStatus(hash common.Uint256) *TxStatus
 	GetTxAmount() []uint32
 	GetTxList() []common.Uint256
+	GetPoolTxs(payer *common.Address) []*PoolTxInfo
 	GetGasPrice() uint64
 	AppendTransaction(sender SenderType, txn *types.Transaction) *TxResult
 	AppendTransactionAsync(sender SenderType, txn *types.Transaction)
@@ -172,6 +223,19 @@ func (n OrderByNetWorkFee) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
 
 func (n OrderByNetWorkFee) Less(i, j int) bool { return n[j].Tx.GasPrice < n[i].Tx.GasPrice }
 
+// IsPriceBumped checks whether the gas price of tx is bumped enough to replace the old one
+func IsPriceBumped(old, tx *types.Transaction, bump uint) bool {
+	if tx.GasPrice <= old.GasPrice {
+		return false
+	}
+	delta, overflow := common.SafeMul(old.GasPrice, uint64(bump))
+	if overflow {
+		return false
+	}
+	threshold, overflow := common.SafeAdd(old.GasPrice, delta/100)
+	return !overflow && tx.GasPrice >= threshold
+}
+
 func GetOngBalance(account common.Address) (*big.Int, error) {
 	cache := ledger.DefLedger.GetStore().GetCacheDB()
 	balanceKey := ont.GenBalanceKey(utils.OngContractAddress, account)
@@ -159,6 +159,13 @@ func (ta *TxPoolService) handleTransaction(sender tc.SenderType, txn *tx.Transac
 		return
 	}
 
+	if errCode := ta.server.txPool.CheckLimit(txn); !errCode.Success() {
+		log.Debugf("handleTransaction: tx %x rejected by tx pool limit: %s", txn.Hash(), errCode.Error())
+
+		replyTxResult(txResultCh, txn.Hash(), errCode, errCode.Error())
+		return
+	}
+
 	if _, overflow := common.SafeMul(txn.GasLimit, txn.GasPrice); overflow {
 		replyTxResult(txResultCh, txn.Hash(), errors.ErrUnknown,
 			fmt.Sprintf("gasLimit %d * gasPrice %d overflow", txn.GasLimit, txn.GasPrice))
@@ -250,6 +257,10 @@ func (ta *TxPoolService) GetTxList() []common.Uint256 {
 	return ta.server.getTxHashList()
 }
 
+func (ta *TxPoolService) GetPoolTxs(payer *common.Address) []*tc.PoolTxInfo {
+	return ta.server.GetPoolTxs(payer)
+}
+
 func (ta *TxPoolService) GetGasPrice() uint64 {
 	return ta.server.GetGasPrice()
 }
@@ -298,6 +309,12 @@ func (tpa *TxPoolActor) Receive(context actor.Context) {
 
 		tpa.server.verifyBlock(msg, sender)
 
+	case *tc.AppendTxReq:
+		log.Debugf("txpool actor receives append tx req: %s", msg.Tx.Hash().ToHexString())
+
+		// handleTransaction may wait for a free slot, do not block the actor
+		go NewTxPoolService(tpa.server).AppendTransactionAsync(msg.Sender, msg.Tx)
+
 	case *message.SaveBlockCompleteMsg:
 		sender := context.Sender()
 
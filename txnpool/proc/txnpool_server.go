@@ -30,6 +30,7 @@ import (
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/common/metrics"
 	"github.com/ontio/ontology/core/ledger"
 	txtypes "github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/errors"
@@ -48,6 +49,8 @@ type serverPendingTx struct {
 	sender         tc.SenderType        // Indicate which sender tx is from
 	ch             chan *tc.TxResult    // channel to send tx result
 	checkingStatus *tc.CheckingStatus
+	received       time.Time // when the tx is received by txpool
+	reverify       bool      // tx from pool to be re-verified, no need to broadcast again
 }
 
 // TXPoolServer contains all api to external modules
@@ -68,6 +71,7 @@ type TXPoolServer struct {
 	stateful  *stateful.ValidatorPool
 	rspCh     chan *types.CheckResponse // The channel of verified response
 	stopCh    chan bool                 // stop routine
+	journal   *tc.TxJournal             // The journal of accepted txs, nil if disabled
 }
 
 // NewTxPoolServer creates a new tx pool server to schedule workers to
@@ -148,7 +152,16 @@ func (s *TXPoolServer) movePendingTxToPool(txEntry *tc.VerifiedTx) { //solve the
 	defer s.mu.Unlock()
 
 	errCode := s.txPool.AddTxList(txEntry)
+	if errCode == errors.ErrNoError && s.journal != nil {
+		if err := s.journal.Insert(txEntry.Tx); err != nil {
+			log.Warnf("failed to journal tx %s: %s", txEntry.Tx.Hash().ToHexString(), err)
+		}
+	}
+	if errCode == errors.ErrNoError && events.DefActorPublisher != nil {
+		events.DefActorPublisher.Publish(message.TOPIC_TX_POOL_ADDED, &message.TxPoolAddedMsg{Tx: txEntry.Tx})
+	}
 	s.removePendingTxLocked(txEntry.Tx.Hash(), errCode)
+	metrics.TxPoolTxs.Set(float64(s.txPool.GetTransactionCount()))
 	tc.ShowTraceLog("tx moved from pending pool to tx pool: %s, err: %s", txEntry.Tx.Hash().ToHexString(), errCode.Error())
 }
 
@@ -164,7 +177,11 @@ func (s *TXPoolServer) removePendingTx(hash common.Uint256, err errors.ErrCode)
 }
 
 func (s *TXPoolServer) broadcastTx(pt *serverPendingTx) {
-	if (pt.sender == tc.HttpSender) || (pt.sender == tc.NetSender && !s.disableBroadcastNetTx) {
+	if pt.reverify {
+		return
+	}
+	if (pt.sender == tc.HttpSender || pt.sender == tc.JournalSender || pt.sender == tc.ConsensusSender) ||
+		(pt.sender == tc.NetSender && !s.disableBroadcastNetTx) {
 		if s.Net != nil {
 			msg := msgpack.NewTxn(pt.tx)
 			go s.Net.Broadcast(msg)
@@ -188,49 +205,61 @@ func (s *TXPoolServer) removePendingTxLocked(hash common.Uint256, err errors.Err
 
 	s.handleRemovedPendingTx(pt, err)
 	delete(s.allPendingTxs, hash)
+	metrics.TxPoolPendingTxs.Set(float64(len(s.allPendingTxs)))
+	if err != errors.ErrNoError {
+		metrics.TxPoolRejected.WithLabelValues(err.Error()).Inc()
+	}
 	if len(s.allPendingTxs) < tc.MAX_LIMITATION {
-		select {
-		case s.slots <- struct{}{}:
-		default:
-			log.Debug("removePendingTx: slots is full")
-		}
+		s.releaseSlot()
+	}
+}
+
+// releaseSlot returns the slot taken by a new transaction
+func (s *TXPoolServer) releaseSlot() {
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		log.Debug("releaseSlot: slots is full")
 	}
 }
 
 // adds a transaction to the pending list, if the
 // transaction is already in the pending list, just return false.
-func (s *TXPoolServer) setPendingTx(tx *txtypes.Transaction, sender tc.SenderType, ch chan *tc.TxResult) *serverPendingTx {
+func (s *TXPoolServer) setPendingTx(tx *txtypes.Transaction, sender tc.SenderType, ch chan *tc.TxResult) (*serverPendingTx, errors.ErrCode) {
+	return s.addPendingTx(&serverPendingTx{tx: tx, sender: sender, ch: ch, received: time.Now()})
+}
+
+func (s *TXPoolServer) addPendingTx(pt *serverPendingTx) (*serverPendingTx, errors.ErrCode) {
+	tx := pt.tx
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if ok := s.allPendingTxs[tx.Hash()]; ok != nil {
 		log.Debugf("setPendingTx: transaction %x already in the verifying process", tx.Hash())
-		return nil
+		return nil, errors.ErrDuplicateInput
 	}
 
-	pt := &serverPendingTx{
-		tx:     tx,
-		sender: sender,
-		ch:     ch,
-		checkingStatus: &tc.CheckingStatus{
-			PassedStateless: 0,
-			PassedStateful:  0,
-			CheckHeight:     0,
-		},
+	pt.checkingStatus = &tc.CheckingStatus{
+		PassedStateless: 0,
+		PassedStateful:  0,
+		CheckHeight:     0,
 	}
 
 	s.allPendingTxs[tx.Hash()] = pt
+	metrics.TxPoolPendingTxs.Set(float64(len(s.allPendingTxs)))
 	if ethTx, err := tx.GetEIP155Tx(); err == nil && events.DefActorPublisher != nil {
 		events.DefActorPublisher.Publish(message.TOPIC_PENDING_TX_EVENT,
 			&message.PendingTxMsg{Event: []*ethtype.Transaction{ethTx}})
 	}
-	return pt
+	return pt, errors.ErrNoError
 }
 
 func (s *TXPoolServer) startTxVerify(tx *txtypes.Transaction, sender tc.SenderType, txResultCh chan *tc.TxResult) bool {
-	pt := s.setPendingTx(tx, sender, txResultCh)
+	pt, errCode := s.setPendingTx(tx, sender, txResultCh)
 	if pt == nil {
-		replyTxResult(txResultCh, tx.Hash(), errors.ErrDuplicateInput, "duplicated transaction input detected")
+		metrics.TxPoolRejected.WithLabelValues(errCode.Error()).Inc()
+		replyTxResult(txResultCh, tx.Hash(), errCode, errCode.Error())
+		s.releaseSlot()
 		return false
 	}
 
@@ -268,6 +297,62 @@ func (s *TXPoolServer) Stop() {
 	close(s.slots)
 }
 
+// StartJournal re-submits the txs in journal, and records the accepted txs to it
+func (s *TXPoolServer) StartJournal(path string, maxAge time.Duration) error {
+	journal := tc.NewTxJournal(path, maxAge)
+	txs, err := journal.Load()
+	if err != nil {
+		return err
+	}
+	s.journal = journal
+	log.Infof("tx pool: re-submit %d transactions from journal %s", len(txs), path)
+	go func() {
+		service := NewTxPoolService(s)
+		for _, tx := range txs {
+			service.AppendTransactionAsync(tc.JournalSender, tx)
+		}
+	}()
+	go s.rotateJournalLoop()
+	return nil
+}
+
+func (s *TXPoolServer) rotateJournalLoop() {
+	ticker := time.NewTicker(tc.JOURNAL_ROTATE_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.rotateJournal()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// rotateJournal rewrites the journal with the txs in pool and the ones under verifying
+func (s *TXPoolServer) rotateJournal() {
+	txs := s.txPool.GetTransactions()
+	s.mu.RLock()
+	for _, pt := range s.allPendingTxs {
+		txs = append(txs, pt.tx)
+	}
+	s.mu.RUnlock()
+	if err := s.journal.Rotate(txs); err != nil {
+		log.Errorf("failed to rotate tx journal: %s", err)
+	}
+}
+
+// StopJournal saves the txs to journal before the node exits
+func (s *TXPoolServer) StopJournal() {
+	if s.journal == nil {
+		return
+	}
+	s.rotateJournal()
+	if err := s.journal.Close(); err != nil {
+		log.Errorf("failed to close tx journal: %s", err)
+	}
+}
+
 // returns a transaction with the transaction hash.
 func (s *TXPoolServer) getTransaction(hash common.Uint256) *txtypes.Transaction {
 	return s.txPool.GetTransaction(hash)
@@ -279,8 +364,8 @@ func (s *TXPoolServer) getTxPool(byCount bool, height uint32) []*tc.VerifiedTx {
 	avlTxList, oldTxList := s.txPool.GetTxPool(byCount, height)
 
 	for _, t := range oldTxList {
-		s.reVerifyStateful(t, tc.NilSender)
-		tc.ShowTraceLog("reverify transaction : %s", t.Hash().ToHexString())
+		s.reVerifyStateful(t)
+		tc.ShowTraceLog("reverify transaction : %s", t.Tx.Hash().ToHexString())
 	}
 
 	log.Infof("get tx pool valid: %d, expired: %d", len(avlTxList), len(oldTxList))
@@ -316,6 +401,30 @@ func (s *TXPoolServer) getTxHashList() []common.Uint256 {
 	return ret
 }
 
+// GetPoolTxs returns the txs in pool and the ones being verified, sorted by payer and nonce
+func (s *TXPoolServer) GetPoolTxs(payer *common.Address) []*tc.PoolTxInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ret := s.txPool.GetPoolTxs(payer)
+	existed := make(map[common.Uint256]bool, len(ret))
+	for _, info := range ret {
+		existed[info.Tx.Hash()] = true
+	}
+	for hash, pt := range s.allPendingTxs {
+		if existed[hash] || (payer != nil && pt.tx.Payer != *payer) {
+			continue
+		}
+		ret = append(ret, &tc.PoolTxInfo{
+			Tx:        pt.tx,
+			Sender:    pt.sender,
+			Received:  pt.received,
+			Verifying: true,
+		})
+	}
+	tc.SortPoolTxs(ret)
+	return ret
+}
+
 // cleanTransactionList cleans the txs in the block from the ledger
 func (s *TXPoolServer) cleanTransactionList(txs []*txtypes.Transaction, height uint32) {
 	s.txPool.CleanCompletedTransactionList(txs, height)
@@ -341,13 +450,14 @@ func (s *TXPoolServer) cleanTransactionList(txs []*txtypes.Transaction, height u
 	if !s.disablePreExec && len(txs) != 0 {
 		remain := s.txPool.Remain()
 		for _, t := range remain {
-			if ok, _ := preExecCheck(t); !ok {
-				log.Infof("cleanTransactionList: preExecCheck tx %x failed", t.Hash())
+			if ok, _ := preExecCheck(t.Tx); !ok {
+				log.Infof("cleanTransactionList: preExecCheck tx %x failed", t.Tx.Hash())
 				continue
 			}
-			s.reVerifyStateful(t, tc.NilSender)
+			s.reVerifyStateful(t)
 		}
 	}
+	metrics.TxPoolTxs.Set(float64(s.txPool.GetTransactionCount()))
 }
 
 // getTxStatusReq returns a transaction's status with the transaction hash.
@@ -368,8 +478,9 @@ func (s *TXPoolServer) getTransactionCount() int {
 }
 
 // re-verify a transaction's stateful data.
-func (s *TXPoolServer) reVerifyStateful(tx *txtypes.Transaction, sender tc.SenderType) {
-	pt := s.setPendingTx(tx, sender, nil)
+func (s *TXPoolServer) reVerifyStateful(txEntry *tc.VerifiedTx) {
+	tx := txEntry.Tx
+	pt, _ := s.addPendingTx(&serverPendingTx{tx: tx, sender: txEntry.Sender, received: txEntry.Received, reverify: true})
 	if pt == nil {
 		return
 	}
@@ -523,6 +634,8 @@ func (server *TXPoolServer) handleRsp(rsp *types.CheckResponse) {
 			Tx:             pt.tx,
 			VerifiedHeight: pt.checkingStatus.CheckHeight,
 			Nonce:          pt.checkingStatus.Nonce,
+			Sender:         pt.sender,
+			Received:       pt.received,
 		}
 
 		server.movePendingTxToPool(txEntry)
@@ -120,6 +120,12 @@ type Tracer interface {
 	CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error)
 }
 
+// TxTracer is an optional interface of Tracer. CaptureTxStart is called before the
+// message is applied, when the gas is not bought and the nonce is not increased yet.
+type TxTracer interface {
+	CaptureTxStart(env *EVM, from common.Address, to *common.Address)
+}
+
 // StructLogger is an EVM state logger and implements Tracer.
 //
 // StructLogger can capture state based on the given Log configuration and also keeps
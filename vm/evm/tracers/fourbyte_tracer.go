@@ -0,0 +1,130 @@
+// Copyright (C) 2021 The Ontology Authors
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ontio/ontology/vm/evm"
+)
+
+// FourByteTracer searches for 4byte-identifiers, and collects them for post-processing.
+// It collects the methods identifiers along with the size of the supplied data, so
+// a reversed signature can be matched against the size of the data.
+// The result maps "0x<4byte id>-<data size>" to the count, like {"0x27dc297e-128": 1}.
+type FourByteTracer struct {
+	env               *evm.EVM
+	ids               map[string]int   // ids aggregates the 4byte ids found
+	interrupt         uint32           // Atomic flag to signal execution interruption
+	reason            error            // Textual reason for the interruption
+	activePrecompiles []common.Address // Updated on CaptureStart based on given rules
+}
+
+func NewFourByteTracer() *FourByteTracer {
+	return &FourByteTracer{ids: make(map[string]int)}
+}
+
+// isPrecompiled returns whether the addr is a precompile. Logic borrowed from newJsTracer in eth/tracers/js/tracer.go
+func (t *FourByteTracer) isPrecompiled(addr common.Address) bool {
+	for _, p := range t.activePrecompiles {
+		if p == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// store saves the given identifier and datasize.
+func (t *FourByteTracer) store(id []byte, size int) {
+	key := bytesToHex(id) + "-" + strconv.Itoa(size)
+	t.ids[key] += 1
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *FourByteTracer) CaptureStart(env *evm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	// Update list of precompiles based on current block
+	t.activePrecompiles = env.ActivePrecompiles()
+
+	// Save the outer calldata also
+	if len(input) >= 4 {
+		t.store(input[0:4], len(input)-4)
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *FourByteTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *FourByteTracer) CaptureState(env *evm.EVM, pc uint64, op evm.OpCode, gas, cost uint64, memory *evm.Memory, stack *evm.Stack,
+	rStack *evm.ReturnStack, rData []byte, contract *evm.Contract, depth int, err error) {
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *FourByteTracer) CaptureFault(env *evm.EVM, pc uint64, op evm.OpCode, gas, cost uint64, memory *evm.Memory,
+	stack *evm.Stack, rStack *evm.ReturnStack, contract *evm.Contract, depth int, err error) {
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *FourByteTracer) CaptureEnter(op evm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Skip if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.env.Cancel()
+		return
+	}
+	if len(input) < 4 {
+		return
+	}
+	// primarily we want to avoid CREATE/CREATE2/SELFDESTRUCT
+	if op != evm.DELEGATECALL && op != evm.STATICCALL &&
+		op != evm.CALL && op != evm.CALLCODE {
+		return
+	}
+	// Skip any pre-compile invocations, those are just fancy opcodes
+	if t.isPrecompiled(to) {
+		return
+	}
+	t.store(input[0:4], len(input)-4)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *FourByteTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+}
+
+// GetResult returns the json-encoded 4byte identifiers and their counts, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *FourByteTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.ids)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *FourByteTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
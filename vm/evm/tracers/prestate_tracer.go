@@ -0,0 +1,173 @@
+// Copyright (C) 2021 The Ontology Authors
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ontio/ontology/vm/evm"
+)
+
+type PrestateAccount struct {
+	Balance string                      `json:"balance"`
+	Nonce   uint64                      `json:"nonce"`
+	Code    string                      `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// PrestateTracer collects the accounts and storage slots touched by the transaction,
+// with the values before the transaction was executed.
+type PrestateTracer struct {
+	env       *evm.EVM
+	prestate  map[common.Address]*PrestateAccount
+	create    bool
+	to        common.Address
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+func NewPrestateTracer() *PrestateTracer {
+	return &PrestateTracer{prestate: make(map[common.Address]*PrestateAccount)}
+}
+
+// CaptureTxStart implements the TxTracer interface to look up the sender and recipient
+// before the gas is bought and the nonce is increased.
+func (t *PrestateTracer) CaptureTxStart(env *evm.EVM, from common.Address, to *common.Address) {
+	t.env = env
+	t.lookupAccount(from)
+	if to != nil {
+		t.lookupAccount(*to)
+	}
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *PrestateTracer) CaptureStart(env *evm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.create = create
+	t.to = to
+	// the accounts are already looked up by CaptureTxStart if the message is applied by state transition
+	t.lookupAccount(from)
+	if !create {
+		t.lookupAccount(to)
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *PrestateTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *PrestateTracer) CaptureState(env *evm.EVM, pc uint64, op evm.OpCode, gas, cost uint64, memory *evm.Memory, stack *evm.Stack,
+	rStack *evm.ReturnStack, rData []byte, contract *evm.Contract, depth int, err error) {
+	if err != nil {
+		return
+	}
+	// Skip if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		env.Cancel()
+		return
+	}
+	stackLen := len(stack.Data())
+	switch {
+	case stackLen >= 1 && (op == evm.SLOAD || op == evm.SSTORE):
+		t.lookupStorage(contract.Address(), common.Hash(stack.Back(0).Bytes32()))
+	case stackLen >= 1 && (op == evm.EXTCODECOPY || op == evm.EXTCODEHASH || op == evm.EXTCODESIZE ||
+		op == evm.BALANCE || op == evm.SELFDESTRUCT):
+		t.lookupAccount(common.Address(stack.Back(0).Bytes20()))
+	case stackLen >= 5 && (op == evm.DELEGATECALL || op == evm.CALL || op == evm.STATICCALL || op == evm.CALLCODE):
+		t.lookupAccount(common.Address(stack.Back(1).Bytes20()))
+	case op == evm.CREATE:
+		addr := contract.Address()
+		t.lookupAccount(crypto.CreateAddress(addr, env.StateDB.GetNonce(addr)))
+	case stackLen >= 4 && op == evm.CREATE2:
+		offset, size := stack.Back(1), stack.Back(2)
+		initCode := memory.GetCopy(int64(offset.Uint64()), int64(size.Uint64()))
+		salt := stack.Back(3).Bytes32()
+		t.lookupAccount(crypto.CreateAddress2(contract.Address(), salt, crypto.Keccak256(initCode)))
+	}
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *PrestateTracer) CaptureFault(env *evm.EVM, pc uint64, op evm.OpCode, gas, cost uint64, memory *evm.Memory,
+	stack *evm.Stack, rStack *evm.ReturnStack, contract *evm.Contract, depth int, err error) {
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *PrestateTracer) CaptureEnter(typ evm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *PrestateTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+}
+
+// GetResult returns the json-encoded prestate of the touched accounts, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *PrestateTracer) GetResult() (json.RawMessage, error) {
+	if t.create {
+		// the contract created by the transaction did not exist before
+		delete(t.prestate, t.to)
+	}
+	res, err := json.Marshal(t.prestate)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *PrestateTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// lookupAccount fetches details of an account and adds it to the prestate
+// if it doesn't exist there.
+func (t *PrestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.prestate[addr]; ok {
+		return
+	}
+	account := &PrestateAccount{
+		Balance: bigToHex(t.env.StateDB.GetBalance(addr)),
+		Nonce:   t.env.StateDB.GetNonce(addr),
+	}
+	if code := t.env.StateDB.GetCode(addr); len(code) != 0 {
+		account.Code = bytesToHex(code)
+	}
+	t.prestate[addr] = account
+}
+
+// lookupStorage fetches the requested storage slot and adds
+// it to the prestate of the given contract. It assumes `lookupAccount`
+// has been performed on the contract before.
+func (t *PrestateTracer) lookupStorage(addr common.Address, key common.Hash) {
+	t.lookupAccount(addr)
+	account := t.prestate[addr]
+	if account.Storage == nil {
+		account.Storage = make(map[common.Hash]common.Hash)
+	}
+	if _, ok := account.Storage[key]; ok {
+		return
+	}
+	account.Storage[key] = t.env.StateDB.GetState(addr, key)
+}
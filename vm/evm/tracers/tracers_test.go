@@ -0,0 +1,90 @@
+// Copyright (C) 2021 The Ontology Authors
+// This file is part of The ontology library.
+//
+// The ontology is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The ontology is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/smartcontract/service/native/ong"
+	"github.com/ontio/ontology/smartcontract/storage"
+	"github.com/ontio/ontology/vm/evm"
+	"github.com/ontio/ontology/vm/evm/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	callerAddr = common.HexToAddress("0x0a")
+	calleeAddr = common.HexToAddress("0x0b")
+	slot       = common.HexToHash("0x01")
+	slotValue  = common.HexToHash("0x2a")
+)
+
+// traceCall runs the caller contract, which loads slot 1 and calls the callee with
+// the 4byte id 0xdeadbeef and 32 bytes argument, and modifies slot 1 after the call.
+func traceCall(t *testing.T, tracer evm.Tracer) {
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(leveldbstore.NewMemLevelDBStore()))
+	statedb := storage.NewStateDB(db, common.Hash{}, common.Hash{}, ong.OngBalanceHandle{})
+	statedb.SetCode(callerAddr, []byte{
+		byte(evm.PUSH1), 0x01, byte(evm.SLOAD), byte(evm.POP),
+		byte(evm.PUSH4), 0xde, 0xad, 0xbe, 0xef, byte(evm.PUSH1), 0xe0, byte(evm.SHL),
+		byte(evm.PUSH1), 0x00, byte(evm.MSTORE),
+		byte(evm.PUSH1), 0x00, byte(evm.PUSH1), 0x00, byte(evm.PUSH1), 36, byte(evm.PUSH1), 0x00,
+		byte(evm.PUSH1), 0x00, byte(evm.PUSH1), 0x0b, byte(evm.GAS), byte(evm.CALL), byte(evm.POP),
+		byte(evm.PUSH1), 0x07, byte(evm.PUSH1), 0x01, byte(evm.SSTORE),
+		byte(evm.STOP),
+	})
+	statedb.SetState(callerAddr, slot, slotValue)
+	statedb.SetCode(calleeAddr, []byte{byte(evm.STOP)})
+
+	_, _, err := runtime.Call(callerAddr, nil, &runtime.Config{State: statedb,
+		GasLimit: 100000,
+		EVMConfig: evm.Config{
+			Debug:  true,
+			Tracer: tracer,
+		}})
+	require.NoError(t, err)
+}
+
+func TestPrestateTracer(t *testing.T) {
+	tracer := NewPrestateTracer()
+	traceCall(t, tracer)
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+
+	var prestate map[common.Address]*PrestateAccount
+	require.NoError(t, json.Unmarshal(res, &prestate))
+	require.Len(t, prestate, 3)
+	require.Contains(t, prestate, common.Address{})
+	require.Equal(t, slotValue, prestate[callerAddr].Storage[slot])
+	require.Equal(t, "0x00", prestate[calleeAddr].Code)
+	require.Nil(t, prestate[calleeAddr].Storage)
+}
+
+func TestFourByteTracer(t *testing.T) {
+	tracer := NewFourByteTracer()
+	traceCall(t, tracer)
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+
+	var ids map[string]int
+	require.NoError(t, json.Unmarshal(res, &ids))
+	require.Equal(t, map[string]int{"0xdeadbeef-32": 1}, ids)
+}
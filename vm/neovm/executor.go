@@ -55,6 +55,7 @@ type Executor struct {
 	Features  VmFeatureFlag
 	Callers   []*ExecutionContext
 	Context   *ExecutionContext
+	Tracer    Tracer //notified of every step if not nil
 }
 
 func (self *Executor) PopContext() (*ExecutionContext, error) {
@@ -85,10 +86,14 @@ func (self *Executor) Execute() error {
 			break
 		}
 
+		pc := self.Context.GetInstructionPointer()
 		opcode, eof := self.Context.ReadOpCode()
 		if eof {
 			break
 		}
+		if self.Tracer != nil {
+			self.Tracer.CaptureState(self, pc, opcode, 0, 0)
+		}
 
 		var err error
 		self.State, err = self.ExecuteOp(opcode, self.Context)
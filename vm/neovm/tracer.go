@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import "fmt"
+
+//Tracer is notified of the execution steps of neovm
+type Tracer interface {
+	//CaptureState is called before the opcode at pc of the current context is executed,
+	//gas is the gas left before the cost of the opcode is charged
+	CaptureState(engine *Executor, pc int, opcode OpCode, gas, cost uint64)
+	//CaptureSyscall is called before the interop service of SYSCALL is invoked
+	CaptureSyscall(engine *Executor, name string, gas, cost uint64)
+}
+
+//OpCodeName return the readable name of opcode
+func OpCodeName(opcode OpCode) string {
+	if opcode >= PUSHBYTES1 && opcode <= PUSHBYTES75 {
+		return fmt.Sprintf("PUSHBYTES%d", opcode)
+	}
+	if name := OpExecList[opcode].Name; name != "" {
+		return name
+	}
+	return fmt.Sprintf("0x%02x", byte(opcode))
+}
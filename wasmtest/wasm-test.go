@@ -47,7 +47,6 @@ import (
 	"github.com/ontio/ontology/core/genesis"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/payload"
-	"github.com/ontio/ontology/core/signature"
 	"github.com/ontio/ontology/core/store/ledgerstore"
 	"github.com/ontio/ontology/core/types"
 	utils2 "github.com/ontio/ontology/core/utils"
@@ -547,9 +546,7 @@ func makeBlock(acc *account.Account, txs []*types.Transaction) (*types.Block, er
 		Transactions: txs,
 	}
 
-	blockHash := block.Hash()
-
-	sig, err := signature.Sign(acc, blockHash[:])
+	sig, err := types.SignHeader(acc, header)
 	if err != nil {
 		return nil, fmt.Errorf("signature, Sign error:%s", err)
 	}